@@ -7,6 +7,7 @@ package api
 
 import (
 	"log"
+	"sync"
 	"time"
 
 	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
@@ -22,6 +23,9 @@ type APICommsClientImpl struct {
 	bootstrapData *BootstrapData
 	apiClient     *galasaapi.APIClient
 	authenticator spi.Authenticator
+
+	// Guards the apiClient, so commands can be run from more than one goroutine at once.
+	apiClientMutex sync.Mutex
 }
 
 // APICommsClient acts as a smarter client for API server communications with the ability to retry
@@ -100,13 +104,10 @@ func (commsClient *APICommsClientImpl) RunAuthenticatedCommandWithRateLimitRetri
 
 	for !isDone {
 
-		var apiClientErr error
-		if commsClient.apiClient == nil {
-			commsClient.apiClient, apiClientErr = commsClient.authenticator.GetAuthenticatedAPIClient()
-		}
+		apiClient, apiClientErr := commsClient.getAuthenticatedAPIClient()
 
 		if apiClientErr == nil {
-			err = commandExecutionFunc(commsClient.apiClient)
+			err = commandExecutionFunc(apiClient)
 		} else {
 			err = apiClientErr
 		}
@@ -128,7 +129,7 @@ func (commsClient *APICommsClientImpl) RunAuthenticatedCommandWithRateLimitRetri
 
 					// Unset the API client so that we re-authenticate at the start of the next loop
 					// and can then cope with rate limit or auth issues after re-authenticating
-					commsClient.apiClient = nil
+					commsClient.clearAuthenticatedAPIClient(apiClient)
 					isRetryRequired = true
 
 				} else if galasaError.IsRateLimitedRetryRequired() {
@@ -148,6 +149,30 @@ func (commsClient *APICommsClientImpl) RunAuthenticatedCommandWithRateLimitRetri
 	return err
 }
 
+// Gets the API client, logging in to get one if we don't have one yet.
+func (commsClient *APICommsClientImpl) getAuthenticatedAPIClient() (*galasaapi.APIClient, error) {
+	var err error
+
+	commsClient.apiClientMutex.Lock()
+	defer commsClient.apiClientMutex.Unlock()
+
+	if commsClient.apiClient == nil {
+		commsClient.apiClient, err = commsClient.authenticator.GetAuthenticatedAPIClient()
+	}
+	return commsClient.apiClient, err
+}
+
+// Forgets the API client which was rejected, unless another goroutine has already
+// logged in again and replaced it.
+func (commsClient *APICommsClientImpl) clearAuthenticatedAPIClient(rejectedApiClient *galasaapi.APIClient) {
+	commsClient.apiClientMutex.Lock()
+	defer commsClient.apiClientMutex.Unlock()
+
+	if commsClient.apiClient == rejectedApiClient {
+		commsClient.apiClient = nil
+	}
+}
+
 // RunCommandWithRateLimitRetries keeps trying until we've tried enough, it worked,
 // or it's failed too many times with rate limit issues.
 func (commsClient *APICommsClientImpl) RunCommandWithRateLimitRetries(
@@ -39,6 +39,14 @@ const (
 	COMMAND_NAME_PROJECT_CREATE           = "project create"
 	COMMAND_NAME_LOCAL                    = "local"
 	COMMAND_NAME_LOCAL_INIT               = "local init"
+	COMMAND_NAME_LOCAL_COVERAGE           = "local coverage"
+	COMMAND_NAME_LOCAL_COVERAGE_REPORT    = "local coverage report"
+	COMMAND_NAME_LOCAL_RUNS               = "local runs"
+	COMMAND_NAME_LOCAL_RUNS_GET           = "local runs get"
+	COMMAND_NAME_LOCAL_RUNS_DOWNLOAD      = "local runs download"
+	COMMAND_NAME_LOCAL_RAS                = "local ras"
+	COMMAND_NAME_LOCAL_RAS_PRUNE          = "local ras prune"
+	COMMAND_NAME_LOCAL_PREFETCH           = "local prefetch"
 	COMMAND_NAME_MONITORS                 = "monitors"
 	COMMAND_NAME_MONITORS_GET             = "monitors get"
 	COMMAND_NAME_MONITORS_SET             = "monitors set"
@@ -57,6 +65,16 @@ const (
 	COMMAND_NAME_RUNS_RESET               = "runs reset"
 	COMMAND_NAME_RUNS_CANCEL              = "runs cancel"
 	COMMAND_NAME_RUNS_DELETE              = "runs delete"
+	COMMAND_NAME_RUNS_FLAKY               = "runs flaky"
+	COMMAND_NAME_RUNS_LOG                 = "runs log"
+	COMMAND_NAME_RUNS_ARTIFACTS           = "runs artifacts"
+	COMMAND_NAME_RUNS_ARTIFACTS_LIST      = "runs artifacts list"
+	COMMAND_NAME_RUNS_TERMINALS           = "runs terminals"
+	COMMAND_NAME_RUNS_TERMINALS_SEARCH    = "runs terminals search"
+	COMMAND_NAME_RUNS_TERMINALS_DIFF      = "runs terminals diff"
+	COMMAND_NAME_RUNS_RERUN               = "runs rerun"
+	COMMAND_NAME_RUNS_COMPARE             = "runs compare"
+	COMMAND_NAME_RUNS_VIEW                = "runs view"
 	COMMAND_NAME_RESOURCES                = "resources"
 	COMMAND_NAME_RESOURCES_APPLY          = "resources apply"
 	COMMAND_NAME_RESOURCES_CREATE         = "resources create"
@@ -243,15 +261,59 @@ func (commands *commandCollectionImpl) addLocalCommands(factory spi.Factory, roo
 	var err error
 	var localCommand spi.GalasaCommand
 	var localInitCommand spi.GalasaCommand
+	var localCoverageCommand spi.GalasaCommand
+	var localCoverageReportCommand spi.GalasaCommand
+	var localRunsCommand spi.GalasaCommand
+	var localRunsGetCommand spi.GalasaCommand
+	var localRunsDownloadCommand spi.GalasaCommand
+	var localRasCommand spi.GalasaCommand
+	var localRasPruneCommand spi.GalasaCommand
+	var localPrefetchCommand spi.GalasaCommand
 
 	localCommand, err = NewLocalCommand(rootCommand)
 	if err == nil {
 		localInitCommand, err = NewLocalInitCommand(factory, localCommand, rootCommand)
 	}
 
+	if err == nil {
+		localCoverageCommand, err = NewLocalCoverageCommand(localCommand)
+		if err == nil {
+			localCoverageReportCommand, err = NewLocalCoverageReportCommand(factory, localCoverageCommand, rootCommand)
+		}
+	}
+
+	if err == nil {
+		localRunsCommand, err = NewLocalRunsCommand(localCommand)
+		if err == nil {
+			localRunsGetCommand, err = NewLocalRunsGetCommand(factory, localRunsCommand, rootCommand)
+		}
+		if err == nil {
+			localRunsDownloadCommand, err = NewLocalRunsDownloadCommand(factory, localRunsCommand, rootCommand)
+		}
+	}
+
+	if err == nil {
+		localRasCommand, err = NewLocalRasCommand(localCommand)
+		if err == nil {
+			localRasPruneCommand, err = NewLocalRasPruneCommand(factory, localRasCommand, rootCommand)
+		}
+	}
+
+	if err == nil {
+		localPrefetchCommand, err = NewLocalPrefetchCommand(factory, localCommand, rootCommand)
+	}
+
 	if err == nil {
 		commands.commandMap[localCommand.Name()] = localCommand
 		commands.commandMap[localInitCommand.Name()] = localInitCommand
+		commands.commandMap[localCoverageCommand.Name()] = localCoverageCommand
+		commands.commandMap[localCoverageReportCommand.Name()] = localCoverageReportCommand
+		commands.commandMap[localRunsCommand.Name()] = localRunsCommand
+		commands.commandMap[localRunsGetCommand.Name()] = localRunsGetCommand
+		commands.commandMap[localRunsDownloadCommand.Name()] = localRunsDownloadCommand
+		commands.commandMap[localRasCommand.Name()] = localRasCommand
+		commands.commandMap[localRasPruneCommand.Name()] = localRasPruneCommand
+		commands.commandMap[localPrefetchCommand.Name()] = localPrefetchCommand
 	}
 	return err
 }
@@ -336,6 +398,16 @@ func (commands *commandCollectionImpl) addRunsCommands(factory spi.Factory, root
 	var runsResetCommand spi.GalasaCommand
 	var runsCancelCommand spi.GalasaCommand
 	var runsDeleteCommand spi.GalasaCommand
+	var runsFlakyCommand spi.GalasaCommand
+	var runsLogCommand spi.GalasaCommand
+	var runsRerunCommand spi.GalasaCommand
+	var runsCompareCommand spi.GalasaCommand
+	var runsViewCommand spi.GalasaCommand
+	var runsArtifactsCommand spi.GalasaCommand
+	var runsArtifactsListCommand spi.GalasaCommand
+	var runsTerminalsCommand spi.GalasaCommand
+	var runsTerminalsSearchCommand spi.GalasaCommand
+	var runsTerminalsDiffCommand spi.GalasaCommand
 
 	runsCommand, err = NewRunsCmd(rootCommand, commsFlagSet)
 	if err == nil {
@@ -363,6 +435,43 @@ func (commands *commandCollectionImpl) addRunsCommands(factory spi.Factory, root
 		}
 	}
 
+	if err == nil {
+		runsFlakyCommand, err = NewRunsFlakyCommand(factory, runsCommand, commsFlagSet)
+	}
+
+	if err == nil {
+		runsLogCommand, err = NewRunsLogCommand(factory, runsCommand, commsFlagSet)
+	}
+
+	if err == nil {
+		runsRerunCommand, err = NewRunsRerunCommand(factory, runsCommand, commsFlagSet)
+	}
+
+	if err == nil {
+		runsCompareCommand, err = NewRunsCompareCommand(factory, runsCommand, commsFlagSet)
+	}
+
+	if err == nil {
+		runsViewCommand, err = NewRunsViewCommand(factory, runsCommand, commsFlagSet)
+	}
+
+	if err == nil {
+		runsArtifactsCommand, err = NewRunsArtifactsCommand(runsCommand)
+		if err == nil {
+			runsArtifactsListCommand, err = NewRunsArtifactsListCommand(factory, runsArtifactsCommand, commsFlagSet)
+		}
+	}
+
+	if err == nil {
+		runsTerminalsCommand, err = NewRunsTerminalsCommand(runsCommand)
+		if err == nil {
+			runsTerminalsSearchCommand, err = NewRunsTerminalsSearchCommand(factory, runsTerminalsCommand, commsFlagSet)
+		}
+		if err == nil {
+			runsTerminalsDiffCommand, err = NewRunsTerminalsDiffCommand(factory, runsTerminalsCommand, commsFlagSet)
+		}
+	}
+
 	if err == nil {
 		commands.commandMap[runsCommand.Name()] = runsCommand
 		commands.commandMap[runsDownloadCommand.Name()] = runsDownloadCommand
@@ -373,6 +482,16 @@ func (commands *commandCollectionImpl) addRunsCommands(factory spi.Factory, root
 		commands.commandMap[runsResetCommand.Name()] = runsResetCommand
 		commands.commandMap[runsCancelCommand.Name()] = runsCancelCommand
 		commands.commandMap[runsDeleteCommand.Name()] = runsDeleteCommand
+		commands.commandMap[runsFlakyCommand.Name()] = runsFlakyCommand
+		commands.commandMap[runsLogCommand.Name()] = runsLogCommand
+		commands.commandMap[runsRerunCommand.Name()] = runsRerunCommand
+		commands.commandMap[runsCompareCommand.Name()] = runsCompareCommand
+		commands.commandMap[runsViewCommand.Name()] = runsViewCommand
+		commands.commandMap[runsArtifactsCommand.Name()] = runsArtifactsCommand
+		commands.commandMap[runsArtifactsListCommand.Name()] = runsArtifactsListCommand
+		commands.commandMap[runsTerminalsCommand.Name()] = runsTerminalsCommand
+		commands.commandMap[runsTerminalsSearchCommand.Name()] = runsTerminalsSearchCommand
+		commands.commandMap[runsTerminalsDiffCommand.Name()] = runsTerminalsDiffCommand
 	}
 
 	return err
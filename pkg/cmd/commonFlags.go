@@ -6,6 +6,10 @@
 package cmd
 
 import (
+	"strconv"
+	"strings"
+
+	"github.com/galasa-dev/cli/pkg/images"
 	"github.com/spf13/pflag"
 )
 
@@ -36,3 +40,45 @@ func addRateLimitRetryFlags(flagSet *pflag.FlagSet, maxRetries *int, retryBackof
 	flagSet.Float64Var(retryBackoffSeconds, "rate-limit-retry-backoff-secs", float64(1),
 		"The amount of time in seconds to wait before retrying a command if it failed due to rate limits being exceeded. Defaults to 1 second.")
 }
+
+// Used by the commands which render the 3270 terminal screens of test runs into images.
+func addAnimationFlags(flagSet *pflag.FlagSet, animationFormat *string, frameDelayMillis *int) {
+	flagSet.StringVar(animationFormat, "animation", "",
+		"Optional. As well as an image of each 3270 terminal screen, create one animated image of each terminal which plays back its screens in order. "+
+			"Supported formats are: "+strings.Join(images.GetSupportedAnimationFormats(), ", ")+". "+
+			"Each screen is marked to show whether it was received from the host or sent to it, and which key was pressed to send it. "+
+			"The animations are written next to the images, for example: 'zos3270/images/term1/term1.gif'")
+
+	flagSet.IntVar(frameDelayMillis, "animation-delay", images.DEFAULT_ANIMATION_FRAME_DELAY_MILLIS,
+		"Optional. The number of milliseconds each screen is shown for in an animation created using --animation. "+
+			"Defaults to "+strconv.Itoa(images.DEFAULT_ANIMATION_FRAME_DELAY_MILLIS)+" milliseconds")
+}
+
+func addTerminalTextFlag(flagSet *pflag.FlagSet, terminalTextFormat *string) {
+	flagSet.StringVar(terminalTextFormat, "terminal-text", "",
+		"Optional. As well as an image of each 3270 terminal screen, write the screen out as a fixed-width text file, so it can be searched. "+
+			"Supported values are: "+strings.Join(images.GetSupportedTerminalTextFormats(), ", ")+". "+
+			"'"+images.TERMINAL_TEXT_FORMAT_ANNOTATED+"' adds a second grid under the screen, which marks whether each character is in a protected, unprotected, numeric or hidden field, and where the cursor is. "+
+			"The text files are written next to the images, for example: 'zos3270/images/term1/term1-00001.txt'")
+}
+
+// Used by the commands which render the 3270 terminal screens of test runs into images.
+func addTerminalRenderFlags(flagSet *pflag.FlagSet, theme *string, fontSize *float64, isShowingHiddenFields *bool) {
+	flagSet.StringVar(theme, "terminal-theme", images.THEME_CLASSIC,
+		"Optional. The colours the images of the 3270 terminal screens are drawn in. "+
+			"Supported values are: "+strings.Join(images.GetSupportedThemes(), ", ")+". Defaults to '"+images.THEME_CLASSIC+"'")
+
+	flagSet.Float64Var(fontSize, "terminal-font-size", images.DEFAULT_FONT_SIZE,
+		"Optional. The size of the characters in the images of the 3270 terminal screens, in points. "+
+			"Must be between "+strconv.Itoa(images.MIN_FONT_SIZE)+" and "+strconv.Itoa(images.MAX_FONT_SIZE)+". "+
+			"Defaults to "+strconv.Itoa(images.DEFAULT_FONT_SIZE))
+
+	addTerminalShowHiddenFlag(flagSet, isShowingHiddenFields)
+}
+
+// Used by every command which shows what is on the 3270 terminal screens of test runs, whether as images or as text.
+func addTerminalShowHiddenFlag(flagSet *pflag.FlagSet, isShowingHiddenFields *bool) {
+	flagSet.BoolVar(isShowingHiddenFields, "terminal-show-hidden", false,
+		"Optional. Show the contents of non-display fields, such as passwords, in the 3270 terminal screens. "+
+			"By default they are left blank, as they were on the terminal.")
+}
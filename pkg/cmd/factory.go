@@ -18,6 +18,7 @@ import (
 type RealFactory struct {
 	stdOutConsole spi.Console
 	stdErrConsole spi.Console
+	stdIn         spi.StdIn
 }
 
 func NewRealFactory() spi.Factory {
@@ -52,6 +53,14 @@ func (factory *RealFactory) GetStdErrConsole() spi.Console {
 	return factory.stdErrConsole
 }
 
+// There is only one stdin, so reads are buffered by a single object.
+func (factory *RealFactory) GetStdIn() spi.StdIn {
+	if factory.stdIn == nil {
+		factory.stdIn = utils.NewRealStdIn()
+	}
+	return factory.stdIn
+}
+
 func (*RealFactory) GetTimeService() spi.TimeService {
 	return utils.NewRealTimeService()
 }
@@ -0,0 +1,73 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    local coverage ...
+
+type LocalCoverageCmdValues struct {
+}
+
+type LocalCoverageCommand struct {
+	values       *LocalCoverageCmdValues
+	cobraCommand *cobra.Command
+}
+
+// ------------------------------------------------------------------------------------------------
+// Constructors methods
+// ------------------------------------------------------------------------------------------------
+func NewLocalCoverageCommand(localCommand spi.GalasaCommand) (spi.GalasaCommand, error) {
+	cmd := new(LocalCoverageCommand)
+	err := cmd.init(localCommand)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalCoverageCommand) Name() string {
+	return COMMAND_NAME_LOCAL_COVERAGE
+}
+
+func (cmd *LocalCoverageCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *LocalCoverageCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalCoverageCommand) init(localCommand spi.GalasaCommand) error {
+	var err error
+	cmd.values = &LocalCoverageCmdValues{}
+	cmd.cobraCommand, err = cmd.createCobraCommand(localCommand)
+	return err
+}
+
+func (cmd *LocalCoverageCommand) createCobraCommand(localCommand spi.GalasaCommand) (*cobra.Command, error) {
+
+	var err error
+
+	localCoverageCobraCmd := &cobra.Command{
+		Use:   "coverage",
+		Short: "Reports on the code coverage of local test runs",
+		Long: "Allows interaction with the code coverage collected from local test runs which were submitted " +
+			"using 'galasactl runs submit local --coverage'",
+		Args: cobra.NoArgs,
+	}
+
+	localCommand.CobraCommand().AddCommand(localCoverageCobraCmd)
+
+	return localCoverageCobraCmd, err
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"log"
+
+	"github.com/galasa-dev/cli/pkg/embedded"
+	"github.com/galasa-dev/cli/pkg/launcher"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    local coverage report --classes target/classes --html coverage
+// And then see how much of their code the local test runs in the RAS folder ran.
+
+// Variables set by cobra's command-line parsing.
+type LocalCoverageReportCmdValues struct {
+	coverageReportParams launcher.CoverageReportParameters
+}
+
+type LocalCoverageReportCommand struct {
+	values       *LocalCoverageReportCmdValues
+	cobraCommand *cobra.Command
+}
+
+// ------------------------------------------------------------------------------------------------
+// Constructors
+// ------------------------------------------------------------------------------------------------
+func NewLocalCoverageReportCommand(factory spi.Factory, localCoverageCommand spi.GalasaCommand, rootCmd spi.GalasaCommand) (spi.GalasaCommand, error) {
+	cmd := new(LocalCoverageReportCommand)
+	err := cmd.init(factory, localCoverageCommand, rootCmd)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalCoverageReportCommand) Name() string {
+	return COMMAND_NAME_LOCAL_COVERAGE_REPORT
+}
+
+func (cmd *LocalCoverageReportCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *LocalCoverageReportCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalCoverageReportCommand) init(factory spi.Factory, localCoverageCommand spi.GalasaCommand, rootCmd spi.GalasaCommand) error {
+	var err error
+	cmd.values = &LocalCoverageReportCmdValues{}
+	cmd.cobraCommand = cmd.createCobraCommand(factory, localCoverageCommand, rootCmd)
+	return err
+}
+
+func (cmd *LocalCoverageReportCommand) createCobraCommand(
+	factory spi.Factory,
+	localCoverageCommand spi.GalasaCommand,
+	rootCmd spi.GalasaCommand,
+) *cobra.Command {
+
+	localCoverageReportCobraCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Reports on the merged code coverage of local test runs",
+		Long: "Merges the code coverage collected from local test runs which were submitted using 'galasactl runs submit local --coverage', " +
+			"then writes an XML and/or HTML report of how much of the given classes those runs ran. " +
+			"The report is created using the JaCoCo command-line tool, which is run using the JVM in JAVA_HOME.",
+		Args: cobra.NoArgs,
+		RunE: func(cobraCommand *cobra.Command, args []string) error {
+			return cmd.executeLocalCoverageReport(factory, rootCmd.Values().(*RootCmdValues))
+		},
+	}
+
+	params := &cmd.values.coverageReportParams
+	localCoverageReportCobraCmd.Flags().StringVar(&params.RasFolderPath, "ras", "",
+		"Optional. The RAS folder the local test runs saved their results into. Defaults to the 'ras' folder in the Galasa home folder.")
+	localCoverageReportCobraCmd.Flags().StringSliceVar(&params.RunNames, "run", []string{},
+		"Optional. The names of the local test runs whose code coverage is merged. "+
+			"Can be a comma-separated list, or the flag can be repeated. Defaults to every run in the RAS folder which has code coverage.")
+	localCoverageReportCobraCmd.Flags().StringArrayVar(&params.ClassFilePaths, "classes", []string{},
+		"A folder or jar holding the compiled classes to report on, such as 'target/classes'. The flag can be repeated.")
+	localCoverageReportCobraCmd.Flags().StringArrayVar(&params.SourceFilePaths, "sources", []string{},
+		"Optional. A folder holding the source files of the classes, such as 'src/main/java', so the HTML report can show which lines were run. The flag can be repeated.")
+	localCoverageReportCobraCmd.Flags().StringVar(&params.XmlFilePath, "xml", "", "The file to write an XML report into.")
+	localCoverageReportCobraCmd.Flags().StringVar(&params.HtmlFolderPath, "html", "", "The folder to write an HTML report into.")
+
+	localCoverageReportCobraCmd.MarkFlagRequired("classes")
+	localCoverageReportCobraCmd.MarkFlagsOneRequired("xml", "html")
+
+	localCoverageCommand.CobraCommand().AddCommand(localCoverageReportCobraCmd)
+
+	return localCoverageReportCobraCmd
+}
+
+func (cmd *LocalCoverageReportCommand) executeLocalCoverageReport(factory spi.Factory, rootCmdValues *RootCmdValues) error {
+
+	var err error
+
+	// Operations on the file system will all be relative to the current folder.
+	fileSystem := factory.GetFileSystem()
+
+	err = utils.CaptureLog(fileSystem, rootCmdValues.logFileName)
+	if err == nil {
+		rootCmdValues.isCapturingLogs = true
+
+		log.Println("Galasa CLI - Report on the code coverage of local test runs")
+
+		var galasaHome spi.GalasaHome
+		galasaHome, err = utils.NewGalasaHome(fileSystem, factory.GetEnvironment(), rootCmdValues.CmdParamGalasaHomePath)
+		if err == nil {
+			err = launcher.ReportCoverage(
+				factory,
+				embedded.GetReadOnlyFileSystem(),
+				galasaHome,
+				launcher.NewRealProcessFactory(),
+				cmd.values.coverageReportParams,
+			)
+		}
+	}
+	return err
+}
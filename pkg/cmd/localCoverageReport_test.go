@@ -0,0 +1,88 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/launcher"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalCoverageReportCommandInCommandCollection(t *testing.T) {
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	localCoverageReportCommand, err := commands.GetCommand(COMMAND_NAME_LOCAL_COVERAGE_REPORT)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, localCoverageReportCommand)
+	assert.Equal(t, COMMAND_NAME_LOCAL_COVERAGE_REPORT, localCoverageReportCommand.Name())
+	assert.NotNil(t, localCoverageReportCommand.Values())
+	assert.IsType(t, &LocalCoverageReportCmdValues{}, localCoverageReportCommand.Values())
+	assert.NotNil(t, localCoverageReportCommand.CobraCommand())
+}
+
+func TestLocalCoverageReportAllFlagsReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_LOCAL_COVERAGE_REPORT, factory, t)
+
+	var args []string = []string{"local", "coverage", "report",
+		"--ras", "/my/ras", "--run", "L1,L2", "--run", "L3",
+		"--classes", "bank/target/classes", "--classes", "payments/target/classes",
+		"--sources", "bank/src/main/java",
+		"--xml", "coverage.xml", "--html", "coverage-html"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	assert.Equal(t, launcher.CoverageReportParameters{
+		RasFolderPath:   "/my/ras",
+		RunNames:        []string{"L1", "L2", "L3"},
+		ClassFilePaths:  []string{"bank/target/classes", "payments/target/classes"},
+		SourceFilePaths: []string{"bank/src/main/java"},
+		XmlFilePath:     "coverage.xml",
+		HtmlFolderPath:  "coverage-html",
+	}, cmd.Values().(*LocalCoverageReportCmdValues).coverageReportParams)
+}
+
+func TestLocalCoverageReportWithoutClassesReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_LOCAL_COVERAGE_REPORT, factory, t)
+
+	var args []string = []string{"local", "coverage", "report", "--xml", "coverage.xml"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "required flag(s) \"classes\" not set")
+}
+
+func TestLocalCoverageReportWithoutXmlOrHtmlReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_LOCAL_COVERAGE_REPORT, factory, t)
+
+	var args []string = []string{"local", "coverage", "report", "--classes", "target/classes"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "at least one of the flags in the group [xml html] is required")
+}
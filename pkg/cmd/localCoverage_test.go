@@ -0,0 +1,42 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalCoverageCommandInCommandCollection(t *testing.T) {
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	localCoverageCommand, err := commands.GetCommand(COMMAND_NAME_LOCAL_COVERAGE)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, localCoverageCommand)
+	assert.Equal(t, COMMAND_NAME_LOCAL_COVERAGE, localCoverageCommand.Name())
+	assert.NotNil(t, localCoverageCommand.Values())
+	assert.IsType(t, &LocalCoverageCmdValues{}, localCoverageCommand.Values())
+	assert.NotNil(t, localCoverageCommand.CobraCommand())
+}
+
+func TestLocalCoverageNoCommandsProducesUsageReport(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	var args []string = []string{"local", "coverage"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("Usage:\n  galasactl local coverage [command]", "", factory, t)
+}
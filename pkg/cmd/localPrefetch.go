@@ -0,0 +1,137 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"log"
+
+	"github.com/galasa-dev/cli/pkg/embedded"
+	"github.com/galasa-dev/cli/pkg/launcher"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    local prefetch --obr mvn:a/b/c/obr [--galasaVersion 0.38.0] [--localMaven file:///...] [--remoteMaven https://...]
+// And then galasactl downloads everything needed to run the tests into the local Maven repository,
+// so that 'runs submit local --offline' can be used later.
+
+// Variables set by cobra's command-line parsing.
+type LocalPrefetchCmdValues struct {
+	obrs          []string
+	galasaVersion string
+	localMaven    string
+	remoteMaven   string
+}
+
+type LocalPrefetchCommand struct {
+	values       *LocalPrefetchCmdValues
+	cobraCommand *cobra.Command
+}
+
+// ------------------------------------------------------------------------------------------------
+// Constructors
+// ------------------------------------------------------------------------------------------------
+func NewLocalPrefetchCommand(factory spi.Factory, localCommand spi.GalasaCommand, rootCmd spi.GalasaCommand) (spi.GalasaCommand, error) {
+	cmd := new(LocalPrefetchCommand)
+	err := cmd.init(factory, localCommand, rootCmd)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalPrefetchCommand) Name() string {
+	return COMMAND_NAME_LOCAL_PREFETCH
+}
+
+func (cmd *LocalPrefetchCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *LocalPrefetchCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalPrefetchCommand) init(factory spi.Factory, localCommand spi.GalasaCommand, rootCmd spi.GalasaCommand) error {
+	var err error
+	cmd.values = &LocalPrefetchCmdValues{}
+	cmd.cobraCommand = cmd.createCobraCommand(factory, localCommand, rootCmd)
+	return err
+}
+
+func (cmd *LocalPrefetchCommand) createCobraCommand(
+	factory spi.Factory,
+	localCommand spi.GalasaCommand,
+	rootCmd spi.GalasaCommand,
+) *cobra.Command {
+
+	localPrefetchCobraCmd := &cobra.Command{
+		Use:   "prefetch",
+		Short: "Download everything needed to run tests locally into the local Maven repository",
+		Long: "Download the OBRs given, the uber OBR of the Galasa version given, every bundle which they refer to, " +
+			"and the test catalogs of the OBRs, into the local Maven repository. The SHA-1 checksum of each is verified " +
+			"against the checksum published with it. Anything already in the local Maven repository, with a checksum which matches, " +
+			"isn't downloaded again. Tests can then be run using 'galasactl runs submit local --offline', " +
+			"without going to the remote Maven repository.",
+		Args: cobra.NoArgs,
+		RunE: func(cobraCommand *cobra.Command, args []string) error {
+			return cmd.executeLocalPrefetch(factory, rootCmd.Values().(*RootCmdValues))
+		},
+	}
+
+	localPrefetchCobraCmd.Flags().StringSliceVar(&cmd.values.obrs, "obr", make([]string, 0),
+		"The maven coordinates of the obr bundle(s) which refer to your test bundles. "+
+			"The format of this parameter is 'mvn:${TEST_OBR_GROUP_ID}/${TEST_OBR_ARTIFACT_ID}/${TEST_OBR_VERSION}/obr' "+
+			"Multiple instances of this flag can be used to describe multiple obr bundles.")
+
+	currentGalasaVersion, _ := embedded.GetGalasaVersion()
+	localPrefetchCobraCmd.Flags().StringVar(&cmd.values.galasaVersion, "galasaVersion", currentGalasaVersion,
+		"the version of galasa you want to use to run your tests. "+
+			"This should match the version of the galasa obr you built your test bundles against.")
+
+	localPrefetchCobraCmd.Flags().StringVar(&cmd.values.localMaven, "localMaven", "",
+		"The url of the local maven repository to download into. Defaults to your home .m2/repository file. "+
+			"Please note that this should be in a URL form e.g. 'file:///Users/myuserid/.m2/repository', or 'file://C:/Users/myuserid/.m2/repository'")
+
+	localPrefetchCobraCmd.Flags().StringVar(&cmd.values.remoteMaven, "remoteMaven", "https://repo.maven.apache.org/maven2",
+		"the url of the remote maven where galasa bundles can be loaded from. "+
+			"Defaults to maven central.")
+
+	localCommand.CobraCommand().AddCommand(localPrefetchCobraCmd)
+
+	return localPrefetchCobraCmd
+}
+
+func (cmd *LocalPrefetchCommand) executeLocalPrefetch(factory spi.Factory, rootCmdValues *RootCmdValues) error {
+
+	var err error
+
+	// Operations on the file system will all be relative to the current folder.
+	fileSystem := factory.GetFileSystem()
+
+	err = utils.CaptureLog(fileSystem, rootCmdValues.logFileName)
+	if err == nil {
+		rootCmdValues.isCapturingLogs = true
+
+		log.Println("Galasa CLI - Prefetch the OBRs and bundles of local runs")
+
+		// Call to process the command in a unit-testable way.
+		err = launcher.PrefetchLocalRunDependencies(
+			fileSystem,
+			cmd.values.obrs,
+			cmd.values.galasaVersion,
+			cmd.values.localMaven,
+			cmd.values.remoteMaven,
+			factory.GetStdOutConsole(),
+		)
+	}
+	return err
+}
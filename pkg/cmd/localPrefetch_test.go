@@ -0,0 +1,69 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalPrefetchCommandInCommandCollection(t *testing.T) {
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	localPrefetchCommand, err := commands.GetCommand(COMMAND_NAME_LOCAL_PREFETCH)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, localPrefetchCommand)
+	assert.Equal(t, COMMAND_NAME_LOCAL_PREFETCH, localPrefetchCommand.Name())
+	assert.NotNil(t, localPrefetchCommand.Values())
+	assert.IsType(t, &LocalPrefetchCmdValues{}, localPrefetchCommand.Values())
+	assert.NotNil(t, localPrefetchCommand.CobraCommand())
+}
+
+func TestLocalPrefetchAllFlagsReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_LOCAL_PREFETCH, factory, t)
+
+	var args []string = []string{"local", "prefetch",
+		"--obr", "mvn:a/b/c/obr", "--obr", "mvn:d/e/f/obr", "--galasaVersion", "0.38.0",
+		"--localMaven", "file:///my/repo", "--remoteMaven", "https://my.maven/repo"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	assert.Equal(t, &LocalPrefetchCmdValues{
+		obrs:          []string{"mvn:a/b/c/obr", "mvn:d/e/f/obr"},
+		galasaVersion: "0.38.0",
+		localMaven:    "file:///my/repo",
+		remoteMaven:   "https://my.maven/repo",
+	}, cmd.Values().(*LocalPrefetchCmdValues))
+}
+
+func TestLocalPrefetchDefaultsToMavenCentral(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_LOCAL_PREFETCH, factory, t)
+
+	var args []string = []string{"local", "prefetch", "--obr", "mvn:a/b/c/obr"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "https://repo.maven.apache.org/maven2", cmd.Values().(*LocalPrefetchCmdValues).remoteMaven)
+	assert.Equal(t, "", cmd.Values().(*LocalPrefetchCmdValues).localMaven)
+}
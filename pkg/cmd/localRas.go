@@ -0,0 +1,73 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    local ras ...
+
+type LocalRasCmdValues struct {
+}
+
+type LocalRasCommand struct {
+	values       *LocalRasCmdValues
+	cobraCommand *cobra.Command
+}
+
+// ------------------------------------------------------------------------------------------------
+// Constructors methods
+// ------------------------------------------------------------------------------------------------
+func NewLocalRasCommand(localCommand spi.GalasaCommand) (spi.GalasaCommand, error) {
+	cmd := new(LocalRasCommand)
+	err := cmd.init(localCommand)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalRasCommand) Name() string {
+	return COMMAND_NAME_LOCAL_RAS
+}
+
+func (cmd *LocalRasCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *LocalRasCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalRasCommand) init(localCommand spi.GalasaCommand) error {
+	var err error
+	cmd.values = &LocalRasCmdValues{}
+	cmd.cobraCommand, err = cmd.createCobraCommand(localCommand)
+	return err
+}
+
+func (cmd *LocalRasCommand) createCobraCommand(localCommand spi.GalasaCommand) (*cobra.Command, error) {
+
+	var err error
+
+	localRasCobraCmd := &cobra.Command{
+		Use:   "ras",
+		Short: "Manages the local RAS folder",
+		Long: "Allows the local RAS folder, which test runs submitted using 'galasactl runs submit local' " +
+			"save their results into, to be looked after",
+		Args: cobra.NoArgs,
+	}
+
+	localCommand.CobraCommand().AddCommand(localRasCobraCmd)
+
+	return localRasCobraCmd, err
+}
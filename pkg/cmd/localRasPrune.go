@@ -0,0 +1,149 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"log"
+
+	"github.com/galasa-dev/cli/pkg/launcher"
+	"github.com/galasa-dev/cli/pkg/runs"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    local ras prune [--older-than 14d] [--keep-last 10] [--result Passed] [--max-size 5GB] [--dry-run] [--ras folder]
+// And then galasactl deletes the folders of the old local runs which match every policy given.
+
+// Variables set by cobra's command-line parsing.
+type LocalRasPruneCmdValues struct {
+	rasFolderPath string
+	olderThan     string
+	keepLast      int
+	result        string
+	maxSize       string
+	isDryRun      bool
+}
+
+type LocalRasPruneCommand struct {
+	values       *LocalRasPruneCmdValues
+	cobraCommand *cobra.Command
+}
+
+// ------------------------------------------------------------------------------------------------
+// Constructors
+// ------------------------------------------------------------------------------------------------
+func NewLocalRasPruneCommand(factory spi.Factory, localRasCommand spi.GalasaCommand, rootCmd spi.GalasaCommand) (spi.GalasaCommand, error) {
+	cmd := new(LocalRasPruneCommand)
+	err := cmd.init(factory, localRasCommand, rootCmd)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalRasPruneCommand) Name() string {
+	return COMMAND_NAME_LOCAL_RAS_PRUNE
+}
+
+func (cmd *LocalRasPruneCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *LocalRasPruneCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalRasPruneCommand) init(factory spi.Factory, localRasCommand spi.GalasaCommand, rootCmd spi.GalasaCommand) error {
+	var err error
+	cmd.values = &LocalRasPruneCmdValues{}
+	cmd.cobraCommand = cmd.createCobraCommand(factory, localRasCommand, rootCmd)
+	return err
+}
+
+func (cmd *LocalRasPruneCommand) createCobraCommand(
+	factory spi.Factory,
+	localRasCommand spi.GalasaCommand,
+	rootCmd spi.GalasaCommand,
+) *cobra.Command {
+
+	localRasPruneCobraCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete the results of old local test runs",
+		Long: "Delete the folders which test runs submitted using 'galasactl runs submit local' saved into the local RAS folder, " +
+			"for the runs which match every policy given. Runs which haven't finished are never deleted. " +
+			"The same policies can be set in the galasactl.properties file in the Galasa home folder, " +
+			"so that the RAS folder is pruned after each 'galasactl runs submit local'.",
+		Args: cobra.NoArgs,
+		RunE: func(cobraCommand *cobra.Command, args []string) error {
+			return cmd.executeLocalRasPrune(factory, rootCmd.Values().(*RootCmdValues))
+		},
+	}
+
+	localRasPruneCobraCmd.Flags().StringVar(&cmd.values.rasFolderPath, "ras", "",
+		"Optional. The RAS folder the local test runs saved their results into. Defaults to the 'ras' folder in the Galasa home folder.")
+	localRasPruneCobraCmd.Flags().StringVar(&cmd.values.olderThan, "older-than", "",
+		"Optional. Only the local test runs queued longer ago than this are deleted. It is made up of an integer and a time-unit qualifier. "+
+			"Supported time-units are "+runs.GetTimeUnitsForErrorMessage()+". For example '--older-than 14d'.")
+	localRasPruneCobraCmd.Flags().IntVar(&cmd.values.keepLast, "keep-last", 0,
+		"Optional. The number of the most recent local test runs which are never deleted. For example '--keep-last 10'.")
+	localRasPruneCobraCmd.Flags().StringVar(&cmd.values.result, "result", "",
+		"Optional. Only the local test runs with one of these results are deleted. Case insensitive."+
+			" Value can be a single value or a comma-separated list. For example \"--result Passed,Ignored\".")
+	localRasPruneCobraCmd.Flags().StringVar(&cmd.values.maxSize, "max-size", "",
+		"Optional. Only as many of the oldest local test runs are deleted as are needed for the RAS folder to be no bigger than this. "+
+			"Supported units are B, KB, MB, GB and TB. For example '--max-size 5GB'.")
+	localRasPruneCobraCmd.Flags().BoolVar(&cmd.values.isDryRun, "dry-run", false,
+		"Optional. List the local test runs which would be deleted, and the space each uses, without deleting anything.")
+
+	localRasPruneCobraCmd.MarkFlagsOneRequired("older-than", "keep-last", "result", "max-size")
+
+	localRasCommand.CobraCommand().AddCommand(localRasPruneCobraCmd)
+
+	return localRasPruneCobraCmd
+}
+
+func (cmd *LocalRasPruneCommand) executeLocalRasPrune(factory spi.Factory, rootCmdValues *RootCmdValues) error {
+
+	var err error
+
+	// Operations on the file system will all be relative to the current folder.
+	fileSystem := factory.GetFileSystem()
+
+	err = utils.CaptureLog(fileSystem, rootCmdValues.logFileName)
+	if err == nil {
+		rootCmdValues.isCapturingLogs = true
+
+		log.Println("Galasa CLI - Prune the results of local runs")
+
+		var galasaHome spi.GalasaHome
+		galasaHome, err = utils.NewGalasaHome(fileSystem, factory.GetEnvironment(), rootCmdValues.CmdParamGalasaHomePath)
+
+		var policy *runs.LocalRasPrunePolicy
+		if err == nil {
+			policy, err = runs.NewLocalRasPrunePolicy(cmd.values.olderThan, cmd.values.keepLast, cmd.values.result, cmd.values.maxSize)
+		}
+
+		if err == nil {
+			rasFolderPath := launcher.GetLocalRasFolderPath(fileSystem, galasaHome, cmd.values.rasFolderPath)
+
+			// Call to process the command in a unit-testable way.
+			err = runs.PruneLocalRas(
+				policy,
+				rasFolderPath,
+				cmd.values.isDryRun,
+				fileSystem,
+				factory.GetTimeService(),
+				factory.GetStdOutConsole(),
+			)
+		}
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalRasPruneCommandInCommandCollection(t *testing.T) {
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	localRasPruneCommand, err := commands.GetCommand(COMMAND_NAME_LOCAL_RAS_PRUNE)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, localRasPruneCommand)
+	assert.Equal(t, COMMAND_NAME_LOCAL_RAS_PRUNE, localRasPruneCommand.Name())
+	assert.NotNil(t, localRasPruneCommand.Values())
+	assert.IsType(t, &LocalRasPruneCmdValues{}, localRasPruneCommand.Values())
+	assert.NotNil(t, localRasPruneCommand.CobraCommand())
+}
+
+func TestLocalRasPruneAllFlagsReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_LOCAL_RAS_PRUNE, factory, t)
+
+	var args []string = []string{"local", "ras", "prune",
+		"--ras", "/my/ras", "--older-than", "14d", "--keep-last", "10", "--result", "Passed", "--max-size", "5GB", "--dry-run"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	assert.Equal(t, &LocalRasPruneCmdValues{
+		rasFolderPath: "/my/ras",
+		olderThan:     "14d",
+		keepLast:      10,
+		result:        "Passed",
+		maxSize:       "5GB",
+		isDryRun:      true,
+	}, cmd.Values().(*LocalRasPruneCmdValues))
+}
+
+func TestLocalRasPruneWithoutAPolicyReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_LOCAL_RAS_PRUNE, factory, t)
+
+	var args []string = []string{"local", "ras", "prune", "--dry-run"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "at least one of the flags in the group [older-than keep-last result max-size] is required")
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalRasCommandInCommandCollection(t *testing.T) {
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	localRasCommand, err := commands.GetCommand(COMMAND_NAME_LOCAL_RAS)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, localRasCommand)
+	assert.Equal(t, COMMAND_NAME_LOCAL_RAS, localRasCommand.Name())
+	assert.NotNil(t, localRasCommand.Values())
+	assert.IsType(t, &LocalRasCmdValues{}, localRasCommand.Values())
+	assert.NotNil(t, localRasCommand.CobraCommand())
+}
+
+func TestLocalRasNoCommandsProducesUsageReport(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	var args []string = []string{"local", "ras"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("Usage:\n  galasactl local ras [command]", "", factory, t)
+}
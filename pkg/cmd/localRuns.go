@@ -0,0 +1,73 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    local runs ...
+
+type LocalRunsCmdValues struct {
+}
+
+type LocalRunsCommand struct {
+	values       *LocalRunsCmdValues
+	cobraCommand *cobra.Command
+}
+
+// ------------------------------------------------------------------------------------------------
+// Constructors methods
+// ------------------------------------------------------------------------------------------------
+func NewLocalRunsCommand(localCommand spi.GalasaCommand) (spi.GalasaCommand, error) {
+	cmd := new(LocalRunsCommand)
+	err := cmd.init(localCommand)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalRunsCommand) Name() string {
+	return COMMAND_NAME_LOCAL_RUNS
+}
+
+func (cmd *LocalRunsCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *LocalRunsCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalRunsCommand) init(localCommand spi.GalasaCommand) error {
+	var err error
+	cmd.values = &LocalRunsCmdValues{}
+	cmd.cobraCommand, err = cmd.createCobraCommand(localCommand)
+	return err
+}
+
+func (cmd *LocalRunsCommand) createCobraCommand(localCommand spi.GalasaCommand) (*cobra.Command, error) {
+
+	var err error
+
+	localRunsCobraCmd := &cobra.Command{
+		Use:   "runs",
+		Short: "Browses the results of local test runs",
+		Long: "Allows interaction with the results which test runs submitted using 'galasactl runs submit local' " +
+			"saved into a local RAS folder",
+		Args: cobra.NoArgs,
+	}
+
+	localCommand.CobraCommand().AddCommand(localRunsCobraCmd)
+
+	return localRunsCobraCmd, err
+}
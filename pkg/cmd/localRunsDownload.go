@@ -0,0 +1,168 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/galasa-dev/cli/pkg/images"
+	"github.com/galasa-dev/cli/pkg/launcher"
+	"github.com/galasa-dev/cli/pkg/runs"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    local runs download [--name L12] [--age 1d] [--result Failed] [--destination folder] [--force] [--ras folder]
+// And then galasactl copies the results of the local runs which match out of the RAS folder,
+// rendering the 3270 terminal screens into images, unless --no-images is used.
+
+// Variables set by cobra's command-line parsing.
+type LocalRunsDownloadCmdValues struct {
+	query                   runs.LocalRunsQuery
+	runForceDownload        bool
+	runDownloadTargetFolder string
+	animationFormat         string
+	animationDelayMillis    int
+	terminalTextFormat      string
+	terminalTheme           string
+	terminalFontSize        float64
+	isShowingHiddenFields   bool
+	renderConcurrency       int
+	isSkippingImages        bool
+}
+
+type LocalRunsDownloadCommand struct {
+	values       *LocalRunsDownloadCmdValues
+	cobraCommand *cobra.Command
+}
+
+// ------------------------------------------------------------------------------------------------
+// Constructors
+// ------------------------------------------------------------------------------------------------
+func NewLocalRunsDownloadCommand(factory spi.Factory, localRunsCommand spi.GalasaCommand, rootCmd spi.GalasaCommand) (spi.GalasaCommand, error) {
+	cmd := new(LocalRunsDownloadCommand)
+	err := cmd.init(factory, localRunsCommand, rootCmd)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalRunsDownloadCommand) Name() string {
+	return COMMAND_NAME_LOCAL_RUNS_DOWNLOAD
+}
+
+func (cmd *LocalRunsDownloadCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *LocalRunsDownloadCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalRunsDownloadCommand) init(factory spi.Factory, localRunsCommand spi.GalasaCommand, rootCmd spi.GalasaCommand) error {
+	var err error
+	cmd.values = &LocalRunsDownloadCmdValues{}
+	cmd.cobraCommand = cmd.createCobraCommand(factory, localRunsCommand, rootCmd)
+	return err
+}
+
+func (cmd *LocalRunsDownloadCommand) createCobraCommand(
+	factory spi.Factory,
+	localRunsCommand spi.GalasaCommand,
+	rootCmd spi.GalasaCommand,
+) *cobra.Command {
+
+	localRunsDownloadCobraCmd := &cobra.Command{
+		Use:   "download",
+		Short: "Download the artifacts of test runs which ran locally",
+		Long: "Copy the artifacts of test runs which were submitted using 'galasactl runs submit local' out of the local RAS folder, " +
+			"into a folder for each run within the destination folder. The 3270 terminal screens of each run are rendered into images as they are copied.",
+		Args: cobra.NoArgs,
+		RunE: func(cobraCommand *cobra.Command, args []string) error {
+			return cmd.executeLocalRunsDownload(factory, rootCmd.Values().(*RootCmdValues))
+		},
+	}
+
+	addLocalRunsQueryFlags(localRunsDownloadCobraCmd, &cmd.values.query)
+	localRunsDownloadCobraCmd.Flags().BoolVar(&cmd.values.runForceDownload, "force", false, "force artifacts to be overwritten if they already exist")
+	localRunsDownloadCobraCmd.Flags().StringVar(&cmd.values.runDownloadTargetFolder, "destination", ".",
+		"The folder we want to download test run artifacts into. Sub-folders will be created within this location",
+	)
+
+	addAnimationFlags(localRunsDownloadCobraCmd.Flags(), &cmd.values.animationFormat, &cmd.values.animationDelayMillis)
+	addTerminalTextFlag(localRunsDownloadCobraCmd.Flags(), &cmd.values.terminalTextFormat)
+	addTerminalRenderFlags(localRunsDownloadCobraCmd.Flags(), &cmd.values.terminalTheme, &cmd.values.terminalFontSize, &cmd.values.isShowingHiddenFields)
+	localRunsDownloadCobraCmd.Flags().IntVar(&cmd.values.renderConcurrency, "render-concurrency", runs.DEFAULT_RENDER_CONCURRENCY,
+		"Optional. The number of 3270 terminal files rendered into images at the same time. "+
+			"Defaults to "+strconv.Itoa(runs.DEFAULT_RENDER_CONCURRENCY))
+	localRunsDownloadCobraCmd.Flags().BoolVar(&cmd.values.isSkippingImages, "no-images", false,
+		"Optional. Don't render the 3270 terminal screens into images. "+
+			"The terminal files themselves are still copied, and the text of each screen is still written out if --terminal-text is used. "+
+			"Cannot be used in conjunction with --animation")
+
+	localRunsDownloadCobraCmd.MarkFlagsMutuallyExclusive("no-images", "animation")
+
+	localRunsCommand.CobraCommand().AddCommand(localRunsDownloadCobraCmd)
+
+	return localRunsDownloadCobraCmd
+}
+
+func (cmd *LocalRunsDownloadCommand) executeLocalRunsDownload(factory spi.Factory, rootCmdValues *RootCmdValues) error {
+
+	var err error
+
+	// Operations on the file system will all be relative to the current folder.
+	fileSystem := factory.GetFileSystem()
+
+	err = utils.CaptureLog(fileSystem, rootCmdValues.logFileName)
+	if err == nil {
+		rootCmdValues.isCapturingLogs = true
+
+		log.Println("Galasa CLI - Download artifacts of local runs")
+
+		var galasaHome spi.GalasaHome
+		galasaHome, err = utils.NewGalasaHome(fileSystem, factory.GetEnvironment(), rootCmdValues.CmdParamGalasaHomePath)
+
+		var renderOptions *images.RenderOptions
+		if err == nil {
+			renderOptions, err = images.NewRenderOptions(cmd.values.terminalTheme, cmd.values.terminalFontSize, cmd.values.isShowingHiddenFields)
+		}
+
+		var terminalOutputOptions *images.TerminalOutputOptions
+		if err == nil {
+			terminalOutputOptions, err = images.NewTerminalOutputOptions(cmd.values.animationFormat, cmd.values.animationDelayMillis, cmd.values.terminalTextFormat, renderOptions)
+		}
+
+		var renderingOptions *runs.ImageRenderingOptions
+		if err == nil {
+			renderingOptions, err = runs.NewImageRenderingOptions(cmd.values.renderConcurrency, cmd.values.isSkippingImages, terminalOutputOptions)
+		}
+
+		if err == nil {
+			query := cmd.values.query
+			query.RasFolderPath = launcher.GetLocalRasFolderPath(fileSystem, galasaHome, query.RasFolderPath)
+
+			// Call to process the command in a unit-testable way.
+			err = runs.DownloadLocalRuns(
+				query,
+				cmd.values.runForceDownload,
+				fileSystem,
+				factory.GetTimeService(),
+				factory.GetStdOutConsole(),
+				cmd.values.runDownloadTargetFolder,
+				renderingOptions,
+			)
+		}
+	}
+	return err
+}
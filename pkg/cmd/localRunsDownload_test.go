@@ -0,0 +1,72 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/runs"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalRunsDownloadCommandInCommandCollection(t *testing.T) {
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	localRunsDownloadCommand, err := commands.GetCommand(COMMAND_NAME_LOCAL_RUNS_DOWNLOAD)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, localRunsDownloadCommand)
+	assert.Equal(t, COMMAND_NAME_LOCAL_RUNS_DOWNLOAD, localRunsDownloadCommand.Name())
+	assert.NotNil(t, localRunsDownloadCommand.Values())
+	assert.IsType(t, &LocalRunsDownloadCmdValues{}, localRunsDownloadCommand.Values())
+	assert.NotNil(t, localRunsDownloadCommand.CobraCommand())
+}
+
+func TestLocalRunsDownloadAllFlagsReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_LOCAL_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"local", "runs", "download",
+		"--ras", "/my/ras", "--result", "Failed", "--age", "1d",
+		"--destination", "results", "--force", "--no-images"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*LocalRunsDownloadCmdValues)
+	assert.Equal(t, runs.LocalRunsQuery{
+		RasFolderPath: "/my/ras",
+		Age:           "1d",
+		Result:        "Failed",
+	}, values.query)
+	assert.Equal(t, "results", values.runDownloadTargetFolder)
+	assert.True(t, values.runForceDownload)
+	assert.True(t, values.isSkippingImages)
+}
+
+func TestLocalRunsDownloadNoImagesAndAnimationReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_LOCAL_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"local", "runs", "download", "--no-images", "--animation", "gif"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "if any flags in the group [no-images animation] are set none of the others can be")
+}
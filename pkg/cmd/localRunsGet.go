@@ -0,0 +1,136 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"log"
+
+	"github.com/galasa-dev/cli/pkg/launcher"
+	"github.com/galasa-dev/cli/pkg/runs"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    local runs get [--name L12] [--age 1d] [--result Failed] [--format details] [--ras folder]
+// And then see the results of the test runs which ran locally, without needing an ecosystem.
+
+// Variables set by cobra's command-line parsing.
+type LocalRunsGetCmdValues struct {
+	query              runs.LocalRunsQuery
+	outputFormatString string
+}
+
+type LocalRunsGetCommand struct {
+	values       *LocalRunsGetCmdValues
+	cobraCommand *cobra.Command
+}
+
+// ------------------------------------------------------------------------------------------------
+// Constructors
+// ------------------------------------------------------------------------------------------------
+func NewLocalRunsGetCommand(factory spi.Factory, localRunsCommand spi.GalasaCommand, rootCmd spi.GalasaCommand) (spi.GalasaCommand, error) {
+	cmd := new(LocalRunsGetCommand)
+	err := cmd.init(factory, localRunsCommand, rootCmd)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalRunsGetCommand) Name() string {
+	return COMMAND_NAME_LOCAL_RUNS_GET
+}
+
+func (cmd *LocalRunsGetCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *LocalRunsGetCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *LocalRunsGetCommand) init(factory spi.Factory, localRunsCommand spi.GalasaCommand, rootCmd spi.GalasaCommand) error {
+	var err error
+	cmd.values = &LocalRunsGetCmdValues{}
+	cmd.cobraCommand = cmd.createCobraCommand(factory, localRunsCommand, rootCmd)
+	return err
+}
+
+func (cmd *LocalRunsGetCommand) createCobraCommand(
+	factory spi.Factory,
+	localRunsCommand spi.GalasaCommand,
+	rootCmd spi.GalasaCommand,
+) *cobra.Command {
+
+	localRunsGetCobraCmd := &cobra.Command{
+		Use:   "get",
+		Short: "Get the details of test runs which ran locally",
+		Long: "Get the details of test runs which were submitted using 'galasactl runs submit local', " +
+			"read from the structure.json file each run saved into the local RAS folder, displaying the results to the caller.",
+		Args: cobra.NoArgs,
+		RunE: func(cobraCommand *cobra.Command, args []string) error {
+			return cmd.executeLocalRunsGet(factory, rootCmd.Values().(*RootCmdValues))
+		},
+	}
+
+	formatters := runs.GetFormatterNamesString(runs.CreateFormatters())
+	addLocalRunsQueryFlags(localRunsGetCobraCmd, &cmd.values.query)
+	localRunsGetCobraCmd.Flags().StringVar(&cmd.values.outputFormatString, "format", "summary",
+		"output format for the data returned. Supported formats are: "+formatters+".")
+
+	localRunsCommand.CobraCommand().AddCommand(localRunsGetCobraCmd)
+
+	return localRunsGetCobraCmd
+}
+
+// The flags which select local test runs are shared by the 'local runs' commands.
+func addLocalRunsQueryFlags(cobraCommand *cobra.Command, query *runs.LocalRunsQuery) {
+	cobraCommand.Flags().StringVar(&query.RasFolderPath, "ras", "",
+		"Optional. The RAS folder the local test runs saved their results into. Defaults to the 'ras' folder in the Galasa home folder.")
+	cobraCommand.Flags().StringVar(&query.RunName, "name", "", "Optional. The name of the local test run wanted.")
+	cobraCommand.Flags().StringVar(&query.Age, "age", "", "Optional. The age of the local test runs wanted. Supported formats are: 'FROM' or 'FROM:TO', where FROM and TO are each ages,"+
+		" made up of an integer and a time-unit qualifier. Supported time-units are "+runs.GetTimeUnitsForErrorMessage()+". If missing, the TO part is defaulted to '0h'."+
+		" Examples: '--age 1d', '--age 6h:1h' (local test runs which were queued from 6 hours ago to 1 hour ago).")
+	cobraCommand.Flags().StringVar(&query.Result, "result", "", "Optional. Only the local test runs with one of these results are wanted. Case insensitive."+
+		" Value can be a single value or a comma-separated list. For example \"--result Failed,Hung\".")
+}
+
+func (cmd *LocalRunsGetCommand) executeLocalRunsGet(factory spi.Factory, rootCmdValues *RootCmdValues) error {
+
+	var err error
+
+	// Operations on the file system will all be relative to the current folder.
+	fileSystem := factory.GetFileSystem()
+
+	err = utils.CaptureLog(fileSystem, rootCmdValues.logFileName)
+	if err == nil {
+		rootCmdValues.isCapturingLogs = true
+
+		log.Println("Galasa CLI - Get info about local runs")
+
+		var galasaHome spi.GalasaHome
+		galasaHome, err = utils.NewGalasaHome(fileSystem, factory.GetEnvironment(), rootCmdValues.CmdParamGalasaHomePath)
+		if err == nil {
+			query := cmd.values.query
+			query.RasFolderPath = launcher.GetLocalRasFolderPath(fileSystem, galasaHome, query.RasFolderPath)
+
+			// Call to process the command in a unit-testable way.
+			err = runs.GetLocalRuns(
+				query,
+				cmd.values.outputFormatString,
+				fileSystem,
+				factory.GetTimeService(),
+				factory.GetStdOutConsole(),
+			)
+		}
+	}
+	return err
+}
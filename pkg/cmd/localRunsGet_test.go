@@ -0,0 +1,70 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/runs"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalRunsGetCommandInCommandCollection(t *testing.T) {
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	localRunsGetCommand, err := commands.GetCommand(COMMAND_NAME_LOCAL_RUNS_GET)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, localRunsGetCommand)
+	assert.Equal(t, COMMAND_NAME_LOCAL_RUNS_GET, localRunsGetCommand.Name())
+	assert.NotNil(t, localRunsGetCommand.Values())
+	assert.IsType(t, &LocalRunsGetCmdValues{}, localRunsGetCommand.Values())
+	assert.NotNil(t, localRunsGetCommand.CobraCommand())
+}
+
+func TestLocalRunsGetAllFlagsReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_LOCAL_RUNS_GET, factory, t)
+
+	var args []string = []string{"local", "runs", "get",
+		"--ras", "/my/ras", "--name", "L12", "--age", "2d:1d", "--result", "Failed,Hung", "--format", "details"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	assert.Equal(t, runs.LocalRunsQuery{
+		RasFolderPath: "/my/ras",
+		RunName:       "L12",
+		Age:           "2d:1d",
+		Result:        "Failed,Hung",
+	}, cmd.Values().(*LocalRunsGetCmdValues).query)
+	assert.Equal(t, "details", cmd.Values().(*LocalRunsGetCmdValues).outputFormatString)
+}
+
+func TestLocalRunsGetWithoutFlagsDefaultsToSummaryFormat(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_LOCAL_RUNS_GET, factory, t)
+
+	var args []string = []string{"local", "runs", "get"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, runs.LocalRunsQuery{}, cmd.Values().(*LocalRunsGetCmdValues).query)
+	assert.Equal(t, "summary", cmd.Values().(*LocalRunsGetCmdValues).outputFormatString)
+}
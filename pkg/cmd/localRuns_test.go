@@ -0,0 +1,42 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalRunsCommandInCommandCollection(t *testing.T) {
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	localRunsCommand, err := commands.GetCommand(COMMAND_NAME_LOCAL_RUNS)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, localRunsCommand)
+	assert.Equal(t, COMMAND_NAME_LOCAL_RUNS, localRunsCommand.Name())
+	assert.NotNil(t, localRunsCommand.Values())
+	assert.IsType(t, &LocalRunsCmdValues{}, localRunsCommand.Values())
+	assert.NotNil(t, localRunsCommand.CobraCommand())
+}
+
+func TestLocalRunsNoCommandsProducesUsageReport(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	var args []string = []string{"local", "runs"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("Usage:\n  galasactl local runs [command]", "", factory, t)
+}
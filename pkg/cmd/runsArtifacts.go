@@ -0,0 +1,73 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    runs artifacts ...
+
+type RunsArtifactsCmdValues struct {
+}
+
+type RunsArtifactsCommand struct {
+	values       *RunsArtifactsCmdValues
+	cobraCommand *cobra.Command
+}
+
+// ------------------------------------------------------------------------------------------------
+// Constructors methods
+// ------------------------------------------------------------------------------------------------
+func NewRunsArtifactsCommand(runsCommand spi.GalasaCommand) (spi.GalasaCommand, error) {
+	cmd := new(RunsArtifactsCommand)
+	err := cmd.init(runsCommand)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *RunsArtifactsCommand) Name() string {
+	return COMMAND_NAME_RUNS_ARTIFACTS
+}
+
+func (cmd *RunsArtifactsCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *RunsArtifactsCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *RunsArtifactsCommand) init(runsCommand spi.GalasaCommand) error {
+	var err error
+	cmd.values = &RunsArtifactsCmdValues{}
+	cmd.cobraCommand, err = cmd.createCobraCommand(runsCommand)
+	return err
+}
+
+func (cmd *RunsArtifactsCommand) createCobraCommand(runsCommand spi.GalasaCommand) (*cobra.Command, error) {
+
+	var err error
+
+	runsArtifactsCobraCmd := &cobra.Command{
+		Use:     "artifacts",
+		Short:   "Queries the artifacts of a test run",
+		Long:    "Allows interaction with the artifacts stored in a Galasa Ecosystem's RAS for a test run",
+		Aliases: []string{COMMAND_NAME_RUNS_ARTIFACTS},
+		Args:    cobra.NoArgs,
+	}
+
+	runsCommand.CobraCommand().AddCommand(runsArtifactsCobraCmd)
+
+	return runsArtifactsCobraCmd, err
+}
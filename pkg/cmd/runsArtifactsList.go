@@ -0,0 +1,142 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"log"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/runs"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    runs artifacts list --name U1234
+// And then show the tree of artifacts stored for that run, with their sizes and content types.
+
+// Variables set by cobra's command-line parsing.
+type RunsArtifactsListCmdValues struct {
+	runName string
+}
+
+type RunsArtifactsListCommand struct {
+	values       *RunsArtifactsListCmdValues
+	cobraCommand *cobra.Command
+}
+
+func NewRunsArtifactsListCommand(factory spi.Factory, runsArtifactsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) (spi.GalasaCommand, error) {
+	cmd := new(RunsArtifactsListCommand)
+	err := cmd.init(factory, runsArtifactsCommand, commsFlagSet)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *RunsArtifactsListCommand) Name() string {
+	return COMMAND_NAME_RUNS_ARTIFACTS_LIST
+}
+
+func (cmd *RunsArtifactsListCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *RunsArtifactsListCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+
+func (cmd *RunsArtifactsListCommand) init(factory spi.Factory, runsArtifactsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) error {
+	var err error
+	cmd.values = &RunsArtifactsListCmdValues{}
+	cmd.cobraCommand, err = cmd.createCobraCommand(factory, runsArtifactsCommand, commsFlagSet.Values().(*CommsFlagSetValues))
+	return err
+}
+
+func (cmd *RunsArtifactsListCommand) createCobraCommand(
+	factory spi.Factory,
+	runsArtifactsCommand spi.GalasaCommand,
+	commsFlagSetValues *CommsFlagSetValues,
+) (*cobra.Command, error) {
+
+	var err error
+
+	runsArtifactsListCobraCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the artifacts of a test run.",
+		Long: "Lists the artifacts stored in the ecosystem's RAS for a named test run, as a tree of folders and files," +
+			" along with the content type and size in bytes of each. Nothing is downloaded.",
+		Args:    cobra.NoArgs,
+		Aliases: []string{COMMAND_NAME_RUNS_ARTIFACTS_LIST},
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.executeRunsArtifactsList(factory, commsFlagSetValues)
+		},
+	}
+
+	runsArtifactsListCobraCmd.Flags().StringVar(&cmd.values.runName, "name", "", "the name of the test run whose artifacts should be listed. If the test has been re-run, the artifacts of the latest attempt are listed.")
+	runsArtifactsListCobraCmd.MarkFlagRequired("name")
+
+	runsArtifactsCommand.CobraCommand().AddCommand(runsArtifactsListCobraCmd)
+
+	return runsArtifactsListCobraCmd, err
+}
+
+func (cmd *RunsArtifactsListCommand) executeRunsArtifactsList(
+	factory spi.Factory,
+	commsFlagSetValues *CommsFlagSetValues,
+) error {
+
+	var err error
+
+	// Operations on the file system will all be relative to the current folder.
+	fileSystem := factory.GetFileSystem()
+
+	err = utils.CaptureLog(fileSystem, commsFlagSetValues.logFileName)
+	if err == nil {
+		commsFlagSetValues.isCapturingLogs = true
+
+		log.Println("Galasa CLI - List the artifacts of a test run")
+
+		// Get the ability to query environment variables.
+		env := factory.GetEnvironment()
+
+		var galasaHome spi.GalasaHome
+		galasaHome, err = utils.NewGalasaHome(fileSystem, env, commsFlagSetValues.CmdParamGalasaHomePath)
+		if err == nil {
+
+			var commsClient api.APICommsClient
+			commsClient, err = api.NewAPICommsClient(
+				commsFlagSetValues.bootstrap,
+				commsFlagSetValues.maxRetries,
+				commsFlagSetValues.retryBackoffSeconds,
+				factory,
+				galasaHome,
+			)
+
+			if err == nil {
+
+				var console = factory.GetStdOutConsole()
+				timeService := factory.GetTimeService()
+
+				// Call to process the command in a unit-testable way.
+				err = runs.ListArtifacts(
+					cmd.values.runName,
+					timeService,
+					console,
+					commsClient,
+				)
+			}
+		}
+	}
+
+	log.Printf("executeRunsArtifactsList returning %v", err)
+	return err
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsArtifactsListCommandInCommandCollection(t *testing.T) {
+
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	runsArtifactsListCommand, err := commands.GetCommand(COMMAND_NAME_RUNS_ARTIFACTS_LIST)
+	assert.Nil(t, err)
+
+	assert.Equal(t, COMMAND_NAME_RUNS_ARTIFACTS_LIST, runsArtifactsListCommand.Name())
+	assert.NotNil(t, runsArtifactsListCommand.Values())
+	assert.IsType(t, &RunsArtifactsListCmdValues{}, runsArtifactsListCommand.Values())
+	assert.NotNil(t, runsArtifactsListCommand.CobraCommand())
+}
+
+func TestRunsArtifactsListHelpFlagSetCorrectly(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "artifacts", "list", "--help"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("Displays the options for the 'runs artifacts list' command.", "", factory, t)
+}
+
+func TestRunsArtifactsListNoFlagsReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "artifacts", "list"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.NotNil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "Error: required flag(s) \"name\" not set", factory, t)
+}
+
+func TestRunsArtifactsListNameFlagReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_ARTIFACTS_LIST, factory, t)
+
+	var args []string = []string{"runs", "artifacts", "list", "--name", "U123"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	checkOutput("", "", factory, t)
+
+	assert.Equal(t, "U123", cmd.Values().(*RunsArtifactsListCmdValues).runName)
+}
+
+func TestRunsArtifactsListUnknownParameterReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_ARTIFACTS_LIST, factory, t)
+
+	var args []string = []string{"runs", "artifacts", "list", "--name", "U123", "--random", "random"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown flag: --random")
+
+	// Check what the user saw was reasonable
+	checkOutput("", "Error: unknown flag: --random", factory, t)
+}
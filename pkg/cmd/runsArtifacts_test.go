@@ -0,0 +1,58 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsArtifactsCommandInCommandCollection(t *testing.T) {
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	runsArtifactsCommand, err := commands.GetCommand(COMMAND_NAME_RUNS_ARTIFACTS)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, runsArtifactsCommand)
+	assert.Equal(t, COMMAND_NAME_RUNS_ARTIFACTS, runsArtifactsCommand.Name())
+	assert.NotNil(t, runsArtifactsCommand.Values())
+	assert.IsType(t, &RunsArtifactsCmdValues{}, runsArtifactsCommand.Values())
+	assert.NotNil(t, runsArtifactsCommand.CobraCommand())
+}
+
+func TestRunsArtifactsHelpFlagSetCorrectly(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "artifacts", "--help"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	// Check what the user saw is reasonable.
+	checkOutput("Displays the options for the 'runs artifacts' command.", "", factory, t)
+
+	assert.Nil(t, err)
+}
+
+func TestRunsArtifactsNoCommandsProducesUsageReport(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	var args []string = []string{"runs", "artifacts"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("Usage:\n  galasactl runs artifacts [command]", "", factory, t)
+}
@@ -17,6 +17,8 @@ import (
 
 // Objective: Allow the user to do this:
 //    runs cancel --name U123
+// or
+//    runs cancel --group nightly [--requestor myuserid] [--active] [--dry-run]
 // And then galasactl cancels the run by abandoning it.
 
 type RunsCancelCommand struct {
@@ -25,7 +27,11 @@ type RunsCancelCommand struct {
 }
 
 type RunsCancelCmdValues struct {
-	runName string
+	runName   string
+	group     string
+	requestor string
+	isActive  bool
+	isDryRun  bool
 }
 
 // ------------------------------------------------------------------------------------------------
@@ -76,7 +82,8 @@ func (cmd *RunsCancelCommand) createRunsCancelCobraCmd(factory spi.Factory,
 	runsCancelCmd := &cobra.Command{
 		Use:     "cancel",
 		Short:   "cancel an active run in the ecosystem",
-		Long:    "Cancel an active test run in the ecosystem if it is stuck or looping.",
+		Long: "Cancel an active test run in the ecosystem if it is stuck or looping. " +
+			"Many active test runs can be cancelled at once by selecting them using --group, --requestor or --active.",
 		Args:    cobra.NoArgs,
 		Aliases: []string{"runs cancel"},
 		RunE: func(cobraCmd *cobra.Command, args []string) error {
@@ -85,8 +92,20 @@ func (cmd *RunsCancelCommand) createRunsCancelCobraCmd(factory spi.Factory,
 	}
 
 	runsCancelCmd.PersistentFlags().StringVar(&cmd.values.runName, "name", "", "the name of the test run to cancel")
-
-	runsCancelCmd.MarkPersistentFlagRequired("name")
+	runsCancelCmd.PersistentFlags().StringVar(&cmd.values.group, "group", "", "cancel all the active test runs submitted under this group."+
+		" Cannot be used in conjunction with --name")
+	runsCancelCmd.PersistentFlags().StringVar(&cmd.values.requestor, "requestor", "", "cancel all the active test runs submitted by this requestor."+
+		" Cannot be used in conjunction with --name")
+	runsCancelCmd.PersistentFlags().BoolVar(&cmd.values.isActive, "active", false, "cancel all the active test runs, unless --group or --requestor narrow them down."+
+		" Cannot be used in conjunction with --name")
+	runsCancelCmd.PersistentFlags().BoolVar(&cmd.values.isDryRun, "dry-run", false, "list the active test runs which would be cancelled, without changing any of them."+
+		" Cannot be used in conjunction with --name")
+
+	runsCancelCmd.MarkFlagsOneRequired("name", "group", "requestor", "active")
+	runsCancelCmd.MarkFlagsMutuallyExclusive("name", "group")
+	runsCancelCmd.MarkFlagsMutuallyExclusive("name", "requestor")
+	runsCancelCmd.MarkFlagsMutuallyExclusive("name", "active")
+	runsCancelCmd.MarkFlagsMutuallyExclusive("name", "dry-run")
 
 	runsCommand.CobraCommand().AddCommand(runsCancelCmd)
 
@@ -131,12 +150,24 @@ func (cmd *RunsCancelCommand) executeCancel(
 				timeService := factory.GetTimeService()
 
 				// Call to process command in unit-testable way.
-				err = runs.CancelRun(
-					cmd.values.runName,
-					timeService,
-					console,
-					commsClient,
-				)
+				if cmd.values.runName != "" {
+					err = runs.CancelRun(
+						cmd.values.runName,
+						timeService,
+						console,
+						commsClient,
+					)
+				} else {
+					err = runs.CancelRunsByQuery(
+						cmd.values.group,
+						cmd.values.requestor,
+						cmd.values.isActive,
+						cmd.values.isDryRun,
+						timeService,
+						console,
+						commsClient,
+					)
+				}
 			}
 		}
 	}
@@ -55,7 +55,7 @@ func TestRunsCancelNoFlagsReturnsError(t *testing.T) {
 	assert.NotNil(t, err)
 
 	// Check what the user saw is reasonable.
-	checkOutput("", "Error: required flag(s) \"name\" not set", factory, t)
+	checkOutput("", "Error: at least one of the flags in the group [name group requestor active] is required", factory, t)
 }
 
 func TestRunsCancelNameFlagReturnsOk(t *testing.T) {
@@ -130,3 +130,56 @@ func TestRunsCancelNameTwiceOverridesToLatestValue(t *testing.T) {
 
 	assert.Contains(t, cmd.Values().(*RunsCancelCmdValues).runName, "name2")
 }
+
+func TestRunsCancelGroupRequestorActiveDryRunReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_CANCEL, factory, t)
+
+	var args []string = []string{"runs", "cancel", "--group", "badPortfolio", "--requestor", "myuser", "--active", "--dry-run"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw was reasonable
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsCancelCmdValues)
+	assert.Equal(t, "badPortfolio", values.group)
+	assert.Equal(t, "myuser", values.requestor)
+	assert.Equal(t, true, values.isActive)
+	assert.Equal(t, true, values.isDryRun)
+}
+
+func TestRunsCancelNameAndGroupReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_CANCEL, factory, t)
+
+	var args []string = []string{"runs", "cancel", "--name", "U1", "--group", "badPortfolio"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "if any flags in the group [name group] are set none of the others can be")
+}
+
+func TestRunsCancelNameAndDryRunReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_CANCEL, factory, t)
+
+	var args []string = []string{"runs", "cancel", "--name", "U1", "--dry-run"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "if any flags in the group [name dry-run] are set none of the others can be")
+}
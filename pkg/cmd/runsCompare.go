@@ -0,0 +1,150 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"log"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/runs"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    runs compare --name U1234 --name U1235
+// And then show how the two test runs differ.
+
+// Variables set by cobra's command-line parsing.
+type RunsCompareCmdValues struct {
+	runNames     []string
+	outputFormat string
+}
+
+type RunsCompareCommand struct {
+	values       *RunsCompareCmdValues
+	cobraCommand *cobra.Command
+}
+
+func NewRunsCompareCommand(factory spi.Factory, runsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) (spi.GalasaCommand, error) {
+	cmd := new(RunsCompareCommand)
+	err := cmd.init(factory, runsCommand, commsFlagSet)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *RunsCompareCommand) Name() string {
+	return COMMAND_NAME_RUNS_COMPARE
+}
+
+func (cmd *RunsCompareCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *RunsCompareCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+
+func (cmd *RunsCompareCommand) init(factory spi.Factory, runsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) error {
+	var err error
+	cmd.values = &RunsCompareCmdValues{}
+	cmd.cobraCommand, err = cmd.createCobraCommand(factory, runsCommand, commsFlagSet.Values().(*CommsFlagSetValues))
+	return err
+}
+
+func (cmd *RunsCompareCommand) createCobraCommand(
+	factory spi.Factory,
+	runsCommand spi.GalasaCommand,
+	commsFlagSetValues *CommsFlagSetValues,
+) (*cobra.Command, error) {
+
+	var err error
+
+	runsCompareCobraCmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Show how two test runs differ.",
+		Long: "Compares two test runs from the ecosystem's RAS, showing the result and duration of each test method, the bundle, test and stream of each run," +
+			" the CPS properties which differ between the runs, and a unified diff of the run logs." +
+			" Timestamps, UUIDs and the names and IDs of the runs are ignored when the run logs are compared, so only the real differences are shown.",
+		Args:    cobra.NoArgs,
+		Aliases: []string{"runs compare"},
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.executeRunsCompare(factory, commsFlagSetValues)
+		},
+	}
+
+	runsCompareCobraCmd.Flags().StringSliceVar(&cmd.values.runNames, "name", make([]string, 0),
+		"the name of a test run to compare. Use this flag twice, once for each test run. For example: --name U1234 --name U1235."+
+			" If a test has been re-run, the latest attempt is used.")
+	runsCompareCobraCmd.Flags().StringVar(&cmd.values.outputFormat, "format", runs.COMPARE_FORMAT_SUMMARY,
+		"the output format of the comparison. Supported formats are: "+runs.COMPARE_FORMAT_SUMMARY+", "+runs.COMPARE_FORMAT_JSON+".")
+
+	runsCompareCobraCmd.MarkFlagRequired("name")
+
+	runsCommand.CobraCommand().AddCommand(runsCompareCobraCmd)
+
+	return runsCompareCobraCmd, err
+}
+
+func (cmd *RunsCompareCommand) executeRunsCompare(
+	factory spi.Factory,
+	commsFlagSetValues *CommsFlagSetValues,
+) error {
+
+	var err error
+
+	// Operations on the file system will all be relative to the current folder.
+	fileSystem := factory.GetFileSystem()
+
+	err = utils.CaptureLog(fileSystem, commsFlagSetValues.logFileName)
+	if err == nil {
+		commsFlagSetValues.isCapturingLogs = true
+
+		log.Println("Galasa CLI - Compare two test runs")
+
+		// Get the ability to query environment variables.
+		env := factory.GetEnvironment()
+
+		var galasaHome spi.GalasaHome
+		galasaHome, err = utils.NewGalasaHome(fileSystem, env, commsFlagSetValues.CmdParamGalasaHomePath)
+		if err == nil {
+
+			var commsClient api.APICommsClient
+			commsClient, err = api.NewAPICommsClient(
+				commsFlagSetValues.bootstrap,
+				commsFlagSetValues.maxRetries,
+				commsFlagSetValues.retryBackoffSeconds,
+				factory,
+				galasaHome,
+			)
+
+			if err == nil {
+
+				var console = factory.GetStdOutConsole()
+				timeService := factory.GetTimeService()
+
+				// Call to process the command in a unit-testable way.
+				err = runs.RunsCompare(
+					cmd.values.runNames,
+					cmd.values.outputFormat,
+					timeService,
+					console,
+					commsClient,
+				)
+			}
+		}
+	}
+
+	log.Printf("executeRunsCompare returning %v", err)
+	return err
+}
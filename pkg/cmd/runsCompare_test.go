@@ -0,0 +1,97 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsCompareCommandInCommandCollection(t *testing.T) {
+
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	runsCompareCommand, err := commands.GetCommand(COMMAND_NAME_RUNS_COMPARE)
+	assert.Nil(t, err)
+
+	assert.Equal(t, COMMAND_NAME_RUNS_COMPARE, runsCompareCommand.Name())
+	assert.NotNil(t, runsCompareCommand.Values())
+	assert.IsType(t, &RunsCompareCmdValues{}, runsCompareCommand.Values())
+	assert.NotNil(t, runsCompareCommand.CobraCommand())
+}
+
+func TestRunsCompareHelpFlagSetCorrectly(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "compare", "--help"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("Displays the options for the 'runs compare' command.", "", factory, t)
+}
+
+func TestRunsCompareNoFlagsReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "compare"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.NotNil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "Error: required flag(s) \"name\" not set", factory, t)
+}
+
+func TestRunsCompareTwoNamesReturnsOkWithDefaultFormat(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_COMPARE, factory, t)
+
+	var args []string = []string{"runs", "compare", "--name", "U1", "--name", "U2"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsCompareCmdValues)
+	assert.Equal(t, []string{"U1", "U2"}, values.runNames)
+	assert.Equal(t, "summary", values.outputFormat)
+}
+
+func TestRunsCompareJsonFormatReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_COMPARE, factory, t)
+
+	var args []string = []string{"runs", "compare", "--name", "U1,U2", "--format", "json"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	values := cmd.Values().(*RunsCompareCmdValues)
+	assert.Equal(t, []string{"U1", "U2"}, values.runNames)
+	assert.Equal(t, "json", values.outputFormat)
+}
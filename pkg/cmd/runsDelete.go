@@ -17,11 +17,20 @@ import (
 
 // Objective: Allow the user to do this:
 //    runs delete --name 12345
+// or
+//    runs delete --age 30d --requestor myuserid [--result Passed] [--group nightly] [--dry-run] [--yes] [--parallel 8]
 // And then show the results in a human-readable form.
 
 // Variables set by cobra's command-line parsing.
 type RunsDeleteCmdValues struct {
-	runName string
+	runName       string
+	group         string
+	requestor     string
+	result        string
+	age           string
+	parallelCount int
+	isDryRun      bool
+	isConfirmed   bool
 }
 
 type RunsDeleteCommand struct {
@@ -71,8 +80,9 @@ func (cmd *RunsDeleteCommand) createCobraCommand(
 
 	runsDeleteCobraCmd := &cobra.Command{
 		Use:     "delete",
-		Short:   "Delete a named test run.",
-		Long:    "Delete a named test run.",
+		Short:   "Delete a named test run, or all the test runs which match a query.",
+		Long: "Delete a named test run, or all the test runs which match a query. " +
+			"When test runs are selected by a query, you are asked to confirm the deletion first, unless --yes is used.",
 		Args:    cobra.NoArgs,
 		Aliases: []string{"runs delete"},
 		RunE: func(cobraCmd *cobra.Command, args []string) error {
@@ -82,7 +92,33 @@ func (cmd *RunsDeleteCommand) createCobraCommand(
 
 	runsDeleteCobraCmd.Flags().StringVar(&cmd.values.runName, "name", "", "the name of the test run we want to delete.")
 
-	runsDeleteCobraCmd.MarkFlagRequired("name")
+	runsDeleteCobraCmd.Flags().StringVar(&cmd.values.group, "group", "", "delete all the test runs submitted under this group."+
+		" Cannot be used in conjunction with --name")
+	runsDeleteCobraCmd.Flags().StringVar(&cmd.values.requestor, "requestor", "", "only delete the test runs submitted by this requestor."+
+		" Cannot be used in conjunction with --name")
+	runsDeleteCobraCmd.Flags().StringVar(&cmd.values.result, "result", "", "only delete the test runs with one of these results. Case insensitive."+
+		" Value can be a single value or a comma-separated list. For example \"--result Passed,Ignored\"."+
+		" Cannot be used in conjunction with --name")
+	runsDeleteCobraCmd.Flags().StringVar(&cmd.values.age, "age", "", "delete all the test runs of this age."+
+		" Supported formats are: 'FROM' or 'FROM:TO', where FROM and TO are each ages,"+
+		" made up of an integer and a time-unit qualifier. Supported time-units are "+runs.GetTimeUnitsForErrorMessage()+"."+
+		" For example: '--age 90d:30d'. Cannot be used in conjunction with --name")
+	runsDeleteCobraCmd.Flags().IntVar(&cmd.values.parallelCount, "parallel", runs.DEFAULT_DELETE_PARALLEL_COUNT,
+		"the number of test runs to delete at the same time. Cannot be used in conjunction with --name")
+	runsDeleteCobraCmd.Flags().BoolVar(&cmd.values.isDryRun, "dry-run", false,
+		"list the test runs which match the query, without deleting any of them. Cannot be used in conjunction with --name")
+	runsDeleteCobraCmd.Flags().BoolVar(&cmd.values.isConfirmed, "yes", false,
+		"delete the test runs which match the query without asking for confirmation first. Cannot be used in conjunction with --name")
+
+	runsDeleteCobraCmd.MarkFlagsOneRequired("name", "group", "age")
+	runsDeleteCobraCmd.MarkFlagsMutuallyExclusive("name", "group")
+	runsDeleteCobraCmd.MarkFlagsMutuallyExclusive("name", "requestor")
+	runsDeleteCobraCmd.MarkFlagsMutuallyExclusive("name", "result")
+	runsDeleteCobraCmd.MarkFlagsMutuallyExclusive("name", "age")
+	runsDeleteCobraCmd.MarkFlagsMutuallyExclusive("name", "parallel")
+	runsDeleteCobraCmd.MarkFlagsMutuallyExclusive("name", "dry-run")
+	runsDeleteCobraCmd.MarkFlagsMutuallyExclusive("name", "yes")
+	runsDeleteCobraCmd.MarkFlagsMutuallyExclusive("dry-run", "yes")
 
 	runsCommand.CobraCommand().AddCommand(runsDeleteCobraCmd)
 
@@ -128,13 +164,30 @@ func (cmd *RunsDeleteCommand) executeRunsDelete(
 				timeService := factory.GetTimeService()
 
 				// Call to process the command in a unit-testable way.
-				err = runs.RunsDelete(
-					cmd.values.runName,
-					console,
-					commsClient,
-					timeService,
-					byteReader,
-				)
+				if cmd.values.runName != "" {
+					err = runs.RunsDelete(
+						cmd.values.runName,
+						console,
+						commsClient,
+						timeService,
+						byteReader,
+					)
+				} else {
+					err = runs.RunsDeleteByQuery(
+						cmd.values.group,
+						cmd.values.requestor,
+						cmd.values.result,
+						cmd.values.age,
+						cmd.values.parallelCount,
+						cmd.values.isDryRun,
+						cmd.values.isConfirmed,
+						console,
+						factory.GetStdIn(),
+						commsClient,
+						timeService,
+						byteReader,
+					)
+				}
 			}
 		}
 	}
@@ -0,0 +1,151 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsDeleteCommandInCommandCollection(t *testing.T) {
+
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	runsDeleteCommand, err := commands.GetCommand(COMMAND_NAME_RUNS_DELETE)
+	assert.Nil(t, err)
+
+	assert.Equal(t, COMMAND_NAME_RUNS_DELETE, runsDeleteCommand.Name())
+	assert.NotNil(t, runsDeleteCommand.Values())
+	assert.IsType(t, &RunsDeleteCmdValues{}, runsDeleteCommand.Values())
+	assert.NotNil(t, runsDeleteCommand.CobraCommand())
+}
+
+func TestRunsDeleteNoFlagsReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "delete"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.NotNil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "Error: at least one of the flags in the group [name group age] is required", factory, t)
+}
+
+func TestRunsDeleteNameFlagReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_DELETE, factory, t)
+
+	var args []string = []string{"runs", "delete", "--name", "U1"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	checkOutput("", "", factory, t)
+
+	assert.Equal(t, "U1", cmd.Values().(*RunsDeleteCmdValues).runName)
+}
+
+func TestRunsDeleteQueryFlagsReturnOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_DELETE, factory, t)
+
+	var args []string = []string{"runs", "delete", "--age", "90d:30d", "--requestor", "myuser",
+		"--result", "Passed", "--group", "experiments", "--parallel", "8", "--yes"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsDeleteCmdValues)
+	assert.Equal(t, "90d:30d", values.age)
+	assert.Equal(t, "myuser", values.requestor)
+	assert.Equal(t, "Passed", values.result)
+	assert.Equal(t, "experiments", values.group)
+	assert.Equal(t, 8, values.parallelCount)
+	assert.Equal(t, true, values.isConfirmed)
+	assert.Equal(t, false, values.isDryRun)
+}
+
+func TestRunsDeleteAgeDefaultsParallelCountAndAsksForConfirmation(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_DELETE, factory, t)
+
+	var args []string = []string{"runs", "delete", "--age", "30d", "--dry-run"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	values := cmd.Values().(*RunsDeleteCmdValues)
+	assert.Equal(t, 4, values.parallelCount)
+	assert.Equal(t, false, values.isConfirmed)
+	assert.Equal(t, true, values.isDryRun)
+}
+
+func TestRunsDeleteNameAndAgeReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_DELETE, factory, t)
+
+	var args []string = []string{"runs", "delete", "--name", "U1", "--age", "30d"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "if any flags in the group [name age] are set none of the others can be")
+}
+
+func TestRunsDeleteNameAndYesReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_DELETE, factory, t)
+
+	var args []string = []string{"runs", "delete", "--name", "U1", "--yes"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "if any flags in the group [name yes] are set none of the others can be")
+}
+
+func TestRunsDeleteDryRunAndYesReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_DELETE, factory, t)
+
+	var args []string = []string{"runs", "delete", "--age", "30d", "--dry-run", "--yes"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "if any flags in the group [dry-run yes] are set none of the others can be")
+}
@@ -7,8 +7,10 @@ package cmd
 
 import (
 	"log"
+	"strconv"
 
 	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/images"
 	"github.com/galasa-dev/cli/pkg/runs"
 	"github.com/galasa-dev/cli/pkg/spi"
 	"github.com/galasa-dev/cli/pkg/utils"
@@ -16,8 +18,12 @@ import (
 )
 
 // Objective: Allow the user to do this:
-//    runs download --name U123 [--force]
+//    runs download --name U123 [--force | --incremental] [--include glob] [--exclude glob] [--archive out.tar.gz]
 // And then galasactl downloads the artifacts for the given run.
+// Or this:
+//    runs download --group G [--result Failed] [--age 1d] [--requestor R] [--parallel 4]
+// And then galasactl downloads the artifacts of all the runs which match, several at a time.
+// The 3270 terminal screens are rendered into images while the artifacts download, unless --no-images is used.
 
 type RunsDownloadCommand struct {
 	values       *RunsDownloadCmdValues
@@ -29,6 +35,23 @@ type RunsDownloadCmdValues struct {
 	runNameDownload         string
 	runForceDownload        bool
 	runDownloadTargetFolder string
+	includePatterns         []string
+	excludePatterns         []string
+	group                   string
+	requestor               string
+	result                  string
+	age                     string
+	parallelCount           int
+	isIncremental           bool
+	archivePath             string
+	animationFormat         string
+	animationDelayMillis    int
+	terminalTextFormat      string
+	terminalTheme           string
+	terminalFontSize        float64
+	isShowingHiddenFields   bool
+	renderConcurrency       int
+	isSkippingImages        bool
 }
 
 // ------------------------------------------------------------------------------------------------
@@ -90,10 +113,61 @@ func (cmd *RunsDownloadCommand) createRunsDownloadCobraCmd(
 
 	runsDownloadCobraCmd.PersistentFlags().StringVar(&cmd.values.runNameDownload, "name", "", "the name of the test run we want information about")
 	runsDownloadCobraCmd.PersistentFlags().BoolVar(&cmd.values.runForceDownload, "force", false, "force artifacts to be overwritten if they already exist")
-	runsDownloadCobraCmd.MarkPersistentFlagRequired("name")
 	runsDownloadCobraCmd.PersistentFlags().StringVar(&cmd.values.runDownloadTargetFolder, "destination", ".",
 		"The folder we want to download test run artifacts into. Sub-folders will be created within this location",
 	)
+	runsDownloadCobraCmd.PersistentFlags().StringSliceVar(&cmd.values.includePatterns, "include", []string{},
+		"Optional. Only download the artifacts whose paths match one of these glob patterns. "+
+			"'*' matches within a folder name or file name, '**' matches across folders, and a pattern without a '/' matches file names in any folder. "+
+			"Can be a comma-separated list, or the flag can be used more than once. "+
+			"For example: '--include framework/cps_record.properties' or '--include \"zos3270/**\",\"*.log\"'",
+	)
+	runsDownloadCobraCmd.PersistentFlags().StringSliceVar(&cmd.values.excludePatterns, "exclude", []string{},
+		"Optional. Do not download the artifacts whose paths match any of these glob patterns, even if they match an --include pattern. "+
+			"Uses the same pattern syntax as --include. For example: '--exclude \"**/*.gz\"'",
+	)
+
+	runsDownloadCobraCmd.PersistentFlags().StringVar(&cmd.values.group, "group", "", "download the artifacts of all the test runs submitted under this group."+
+		" Cannot be used in conjunction with --name")
+	runsDownloadCobraCmd.PersistentFlags().StringVar(&cmd.values.requestor, "requestor", "", "only download the artifacts of test runs submitted by this requestor."+
+		" Cannot be used in conjunction with --name")
+	runsDownloadCobraCmd.PersistentFlags().StringVar(&cmd.values.result, "result", "", "only download the artifacts of test runs with one of these results. Case insensitive."+
+		" Value can be a single value or a comma-separated list. For example \"--result Failed,EnvFail\"."+
+		" Cannot be used in conjunction with --name")
+	runsDownloadCobraCmd.PersistentFlags().StringVar(&cmd.values.age, "age", "", "download the artifacts of all the test runs of this age."+
+		" Supported formats are: 'FROM' or 'FROM:TO', where FROM and TO are each ages,"+
+		" made up of an integer and a time-unit qualifier. Supported time-units are "+runs.GetTimeUnitsForErrorMessage()+"."+
+		" For example: '--age 1d'. Cannot be used in conjunction with --name")
+	runsDownloadCobraCmd.PersistentFlags().IntVar(&cmd.values.parallelCount, "parallel", runs.DEFAULT_DOWNLOAD_PARALLEL_COUNT,
+		"the number of test runs to download at the same time, when test runs are selected using --group or --age")
+
+	runsDownloadCobraCmd.PersistentFlags().BoolVar(&cmd.values.isIncremental, "incremental", false,
+		"only download the artifacts which are missing or have changed since a previous download into the same folder, "+
+			"and finish off any which were only partly downloaded. Artifacts are checked using the download manifest written by the previous download. "+
+			"Cannot be used in conjunction with --force")
+	runsDownloadCobraCmd.PersistentFlags().StringVar(&cmd.values.archivePath, "archive", "",
+		"Optional. Once downloaded, bundle the artifacts into a single archive file as well, so they can be shared more easily. "+
+			"The file name must end with '.tar.gz', '.tgz' or '.zip', which decides the type of archive created. For example: '--archive C1234.zip'")
+
+	addAnimationFlags(runsDownloadCobraCmd.PersistentFlags(), &cmd.values.animationFormat, &cmd.values.animationDelayMillis)
+	addTerminalTextFlag(runsDownloadCobraCmd.PersistentFlags(), &cmd.values.terminalTextFormat)
+	addTerminalRenderFlags(runsDownloadCobraCmd.PersistentFlags(), &cmd.values.terminalTheme, &cmd.values.terminalFontSize, &cmd.values.isShowingHiddenFields)
+	runsDownloadCobraCmd.PersistentFlags().IntVar(&cmd.values.renderConcurrency, "render-concurrency", runs.DEFAULT_RENDER_CONCURRENCY,
+		"Optional. The number of 3270 terminal files rendered into images at the same time. "+
+			"Each terminal file is rendered as soon as it is downloaded, while the rest of the artifacts are still downloading. "+
+			"Defaults to "+strconv.Itoa(runs.DEFAULT_RENDER_CONCURRENCY))
+	runsDownloadCobraCmd.PersistentFlags().BoolVar(&cmd.values.isSkippingImages, "no-images", false,
+		"Optional. Don't render the 3270 terminal screens into images, which makes downloading runs with many screens much quicker. "+
+			"The terminal files themselves are still downloaded, and the text of each screen is still written out if --terminal-text is used. "+
+			"Cannot be used in conjunction with --animation")
+
+	runsDownloadCobraCmd.MarkFlagsMutuallyExclusive("force", "incremental")
+	runsDownloadCobraCmd.MarkFlagsOneRequired("name", "group", "age")
+	runsDownloadCobraCmd.MarkFlagsMutuallyExclusive("name", "group")
+	runsDownloadCobraCmd.MarkFlagsMutuallyExclusive("name", "requestor")
+	runsDownloadCobraCmd.MarkFlagsMutuallyExclusive("name", "result")
+	runsDownloadCobraCmd.MarkFlagsMutuallyExclusive("name", "age")
+	runsDownloadCobraCmd.MarkFlagsMutuallyExclusive("no-images", "animation")
 
 	runsCommand.CobraCommand().AddCommand(runsDownloadCobraCmd)
 
@@ -137,16 +211,57 @@ func (cmd *RunsDownloadCommand) executeRunsDownload(
 				var console = factory.GetStdOutConsole()
 				timeService := factory.GetTimeService()
 	
-				// Call to process the command in a unit-testable way.
-				err = runs.DownloadArtifacts(
-					cmd.values.runNameDownload,
-					cmd.values.runForceDownload,
-					fileSystem,
-					timeService,
-					console,
-					commsClient,
-					cmd.values.runDownloadTargetFolder,
-				)
+				artifactFilter := runs.NewArtifactFilter(cmd.values.includePatterns, cmd.values.excludePatterns)
+
+				var renderOptions *images.RenderOptions
+				renderOptions, err = images.NewRenderOptions(cmd.values.terminalTheme, cmd.values.terminalFontSize, cmd.values.isShowingHiddenFields)
+
+				var terminalOutputOptions *images.TerminalOutputOptions
+				if err == nil {
+					terminalOutputOptions, err = images.NewTerminalOutputOptions(cmd.values.animationFormat, cmd.values.animationDelayMillis, cmd.values.terminalTextFormat, renderOptions)
+				}
+
+				var renderingOptions *runs.ImageRenderingOptions
+				if err == nil {
+					renderingOptions, err = runs.NewImageRenderingOptions(cmd.values.renderConcurrency, cmd.values.isSkippingImages, terminalOutputOptions)
+				}
+
+				if err == nil {
+					// Call to process the command in a unit-testable way.
+					if cmd.values.runNameDownload != "" {
+						err = runs.DownloadArtifacts(
+							cmd.values.runNameDownload,
+							cmd.values.runForceDownload,
+							cmd.values.isIncremental,
+							fileSystem,
+							timeService,
+							console,
+							commsClient,
+							cmd.values.runDownloadTargetFolder,
+							artifactFilter,
+							renderingOptions,
+							cmd.values.archivePath,
+						)
+					} else {
+						err = runs.DownloadArtifactsByQuery(
+							cmd.values.group,
+							cmd.values.requestor,
+							cmd.values.result,
+							cmd.values.age,
+							cmd.values.parallelCount,
+							cmd.values.runForceDownload,
+							cmd.values.isIncremental,
+							fileSystem,
+							timeService,
+							console,
+							commsClient,
+							cmd.values.runDownloadTargetFolder,
+							artifactFilter,
+							renderingOptions,
+							cmd.values.archivePath,
+						)
+					}
+				}
 			}
 		}
 	}
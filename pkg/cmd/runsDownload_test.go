@@ -55,7 +55,7 @@ func TestRunsDownloadNoFlagsReturnsError(t *testing.T) {
 	assert.NotNil(t, err)
 
 	// Check what the user saw is reasonable.
-	checkOutput("", "Error: required flag(s) \"name\" not set", factory, t)
+	checkOutput("", "Error: at least one of the flags in the group [name group age] is required", factory, t)
 }
 
 func TestRunsDownloadNameFlagReturnsOk(t *testing.T) {
@@ -106,10 +106,10 @@ func TestRunsDownloadDestinationReturnsError(t *testing.T) {
 
 	// Then...
 	assert.NotNil(t, err)
-	assert.Contains(t, err.Error(), "required flag(s) \"name\" not set")
+	assert.Contains(t, err.Error(), "at least one of the flags in the group [name group age] is required")
 
 	// Check what the user saw was reasonable
-	checkOutput("", "Error: required flag(s) \"name\" not set", factory, t)
+	checkOutput("", "Error: at least one of the flags in the group [name group age] is required", factory, t)
 }
 
 func TestRunsDownloadNameDestinationReturnsOk(t *testing.T) {
@@ -191,3 +191,311 @@ func TestRunsDownloadNameTwiceOverridesToLatestValue(t *testing.T) {
 
 	assert.Contains(t, cmd.Values().(*RunsDownloadCmdValues).runNameDownload, "chemicals")
 }
+
+func TestRunsDownloadNameIncludeExcludeReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--name", "human1",
+		"--include", "framework/cps_record.properties", "--include", "zos/**,*.log",
+		"--exclude", "**/*.gz"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsDownloadCmdValues)
+	assert.Equal(t, []string{"framework/cps_record.properties", "zos/**", "*.log"}, values.includePatterns)
+	assert.Equal(t, []string{"**/*.gz"}, values.excludePatterns)
+}
+
+func TestRunsDownloadNameWithoutIncludeExcludeDefaultsToEmpty(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--name", "human1"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	values := cmd.Values().(*RunsDownloadCmdValues)
+	assert.Empty(t, values.includePatterns)
+	assert.Empty(t, values.excludePatterns)
+}
+
+func TestRunsDownloadGroupResultAgeRequestorParallelReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--group", "nightly", "--result", "Failed", "--age", "1d",
+		"--requestor", "someone", "--parallel", "8"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsDownloadCmdValues)
+	assert.Equal(t, "nightly", values.group)
+	assert.Equal(t, "Failed", values.result)
+	assert.Equal(t, "1d", values.age)
+	assert.Equal(t, "someone", values.requestor)
+	assert.Equal(t, 8, values.parallelCount)
+}
+
+func TestRunsDownloadAgeDefaultsParallelCount(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--age", "6h"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	checkOutput("", "", factory, t)
+
+	assert.Equal(t, 4, cmd.Values().(*RunsDownloadCmdValues).parallelCount)
+}
+
+func TestRunsDownloadRequestorOnlyReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--requestor", "someone"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "at least one of the flags in the group [name group age] is required")
+}
+
+func TestRunsDownloadNameAndGroupReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--name", "U1", "--group", "nightly"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "if any flags in the group [name group] are set none of the others can be")
+}
+
+func TestRunsDownloadNameAndResultReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--name", "U1", "--result", "Failed"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "if any flags in the group [name result] are set none of the others can be")
+}
+
+func TestRunsDownloadNameIncrementalArchiveReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--name", "U1", "--incremental", "--archive", "defects/U1.tar.gz"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsDownloadCmdValues)
+	assert.Equal(t, true, values.isIncremental)
+	assert.Equal(t, false, values.runForceDownload)
+	assert.Equal(t, "defects/U1.tar.gz", values.archivePath)
+}
+
+func TestRunsDownloadForceAndIncrementalReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--name", "U1", "--force", "--incremental"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "if any flags in the group [force incremental] are set none of the others can be")
+}
+
+func TestRunsDownloadAnimationFlagsReturnOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--name", "U1", "--animation", "apng", "--animation-delay", "250"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsDownloadCmdValues)
+	assert.Equal(t, "apng", values.animationFormat)
+	assert.Equal(t, 250, values.animationDelayMillis)
+}
+
+func TestRunsDownloadAnimationDelayDefaultsToOneSecond(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--name", "U1", "--animation", "gif"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	values := cmd.Values().(*RunsDownloadCmdValues)
+	assert.Equal(t, "gif", values.animationFormat)
+	assert.Equal(t, 1000, values.animationDelayMillis)
+}
+
+func TestRunsDownloadTerminalTextFlagReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--name", "U123", "--terminal-text", "annotated"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	assert.Equal(t, "annotated", cmd.Values().(*RunsDownloadCmdValues).terminalTextFormat)
+}
+
+func TestRunsDownloadTerminalRenderFlagsReturnOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--name", "U123", "--terminal-theme", "light", "--terminal-font-size", "16", "--terminal-show-hidden"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	values := cmd.Values().(*RunsDownloadCmdValues)
+	assert.Equal(t, "light", values.terminalTheme)
+	assert.Equal(t, float64(16), values.terminalFontSize)
+	assert.True(t, values.isShowingHiddenFields)
+}
+
+func TestRunsDownloadTerminalRenderFlagsHaveDefaults(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--name", "U123"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	values := cmd.Values().(*RunsDownloadCmdValues)
+	assert.Equal(t, "classic", values.terminalTheme)
+	assert.Equal(t, float64(12), values.terminalFontSize)
+	assert.False(t, values.isShowingHiddenFields)
+}
+
+func TestRunsDownloadRenderFlagsReturnOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--name", "U123", "--render-concurrency", "8", "--no-images"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	values := cmd.Values().(*RunsDownloadCmdValues)
+	assert.Equal(t, 8, values.renderConcurrency)
+	assert.True(t, values.isSkippingImages)
+}
+
+func TestRunsDownloadRenderFlagsHaveDefaults(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--name", "U123"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	values := cmd.Values().(*RunsDownloadCmdValues)
+	assert.Equal(t, 4, values.renderConcurrency)
+	assert.False(t, values.isSkippingImages)
+}
+
+func TestRunsDownloadNoImagesAndAnimationReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_DOWNLOAD, factory, t)
+
+	var args []string = []string{"runs", "download", "--name", "U1", "--no-images", "--animation", "gif"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "if any flags in the group [no-images animation] are set none of the others can be")
+}
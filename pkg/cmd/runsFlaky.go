@@ -0,0 +1,165 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"log"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/runs"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    runs flaky --age 30d
+// And then show a ranked list of the test methods whose results flip between pass and fail.
+
+// Variables set by cobra's command-line parsing.
+type RunsFlakyCmdValues struct {
+	age               string
+	requestor         string
+	group             string
+	topCount          int
+	portfolioFilename string
+	stream            string
+}
+
+type RunsFlakyCommand struct {
+	values       *RunsFlakyCmdValues
+	cobraCommand *cobra.Command
+}
+
+func NewRunsFlakyCommand(factory spi.Factory, runsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) (spi.GalasaCommand, error) {
+	cmd := new(RunsFlakyCommand)
+	err := cmd.init(factory, runsCommand, commsFlagSet)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *RunsFlakyCommand) Name() string {
+	return COMMAND_NAME_RUNS_FLAKY
+}
+
+func (cmd *RunsFlakyCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *RunsFlakyCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+
+func (cmd *RunsFlakyCommand) init(factory spi.Factory, runsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) error {
+	var err error
+	cmd.values = &RunsFlakyCmdValues{}
+	cmd.cobraCommand, err = cmd.createCobraCommand(factory, runsCommand, commsFlagSet.Values().(*CommsFlagSetValues))
+	return err
+}
+
+func (cmd *RunsFlakyCommand) createCobraCommand(
+	factory spi.Factory,
+	runsCommand spi.GalasaCommand,
+	commsFlagSetValues *CommsFlagSetValues,
+) (*cobra.Command, error) {
+
+	var err error
+
+	runsFlakyCobraCmd := &cobra.Command{
+		Use:   "flaky",
+		Short: "Find tests whose results flip between pass and fail.",
+		Long: "Analyses the historic test runs in the ecosystem's RAS, and scores each test method by how often its result" +
+			" flips between pass and fail from one run to the next. The flakiest test methods are listed first, along with the" +
+			" names of the runs either side of each flip as evidence.",
+		Args:    cobra.NoArgs,
+		Aliases: []string{"runs flaky"},
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.executeRunsFlaky(factory, commsFlagSetValues)
+		},
+	}
+
+	units := runs.GetTimeUnitsForErrorMessage()
+	runsFlakyCobraCmd.Flags().StringVar(&cmd.values.age, "age", "", "the age of the test runs to analyse. Supported formats are: 'FROM' or 'FROM:TO', where FROM and TO are each ages,"+
+		" made up of an integer and a time-unit qualifier. Supported time-units are "+units+". If missing, the TO part is defaulted to '0h'. Examples: '--age 30d',"+
+		" '--age 14d:7d' (analyse test runs which happened from 14 days ago to 7 days ago).")
+	runsFlakyCobraCmd.Flags().StringVar(&cmd.values.requestor, "requestor", "", "only analyse the test runs submitted by this requestor.")
+	runsFlakyCobraCmd.Flags().StringVar(&cmd.values.group, "group", "", "only analyse the test runs submitted under this group.")
+	runsFlakyCobraCmd.Flags().IntVar(&cmd.values.topCount, "top", 0, "the maximum number of flaky tests to list, and to add to the portfolio. Defaults to 0, which lists all flaky tests.")
+	runsFlakyCobraCmd.Flags().StringVarP(&cmd.values.portfolioFilename, "portfolio", "p", "", "optional. A portfolio file to write the flaky test classes into, so they can be re-run for investigation"+
+		" using the 'runs submit' command.")
+	runsFlakyCobraCmd.Flags().StringVarP(&cmd.values.stream, "stream", "s", "", "optional. The test stream to record against each test class in the --portfolio file."+
+		" Defaults to the stream which each test class was found to be flaky on.")
+
+	runsFlakyCobraCmd.MarkFlagRequired("age")
+
+	runsCommand.CobraCommand().AddCommand(runsFlakyCobraCmd)
+
+	return runsFlakyCobraCmd, err
+}
+
+func (cmd *RunsFlakyCommand) executeRunsFlaky(
+	factory spi.Factory,
+	commsFlagSetValues *CommsFlagSetValues,
+) error {
+
+	var err error
+
+	// Operations on the file system will all be relative to the current folder.
+	fileSystem := factory.GetFileSystem()
+
+	err = utils.CaptureLog(fileSystem, commsFlagSetValues.logFileName)
+	if err == nil {
+		commsFlagSetValues.isCapturingLogs = true
+
+		log.Println("Galasa CLI - Find flaky tests")
+
+		// Get the ability to query environment variables.
+		env := factory.GetEnvironment()
+
+		var galasaHome spi.GalasaHome
+		galasaHome, err = utils.NewGalasaHome(fileSystem, env, commsFlagSetValues.CmdParamGalasaHomePath)
+		if err == nil {
+
+			var commsClient api.APICommsClient
+			commsClient, err = api.NewAPICommsClient(
+				commsFlagSetValues.bootstrap,
+				commsFlagSetValues.maxRetries,
+				commsFlagSetValues.retryBackoffSeconds,
+				factory,
+				galasaHome,
+			)
+
+			if err == nil {
+
+				var console = factory.GetStdOutConsole()
+				timeService := factory.GetTimeService()
+
+				// Call to process the command in a unit-testable way.
+				err = runs.FindFlakyTests(
+					cmd.values.age,
+					cmd.values.requestor,
+					cmd.values.group,
+					cmd.values.topCount,
+					cmd.values.portfolioFilename,
+					cmd.values.stream,
+					timeService,
+					console,
+					commsClient,
+					fileSystem,
+				)
+			}
+		}
+	}
+
+	log.Printf("executeRunsFlaky returning %v", err)
+	return err
+}
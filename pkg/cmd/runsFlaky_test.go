@@ -0,0 +1,120 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsFlakyCommandInCommandCollection(t *testing.T) {
+
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	runsFlakyCommand, err := commands.GetCommand(COMMAND_NAME_RUNS_FLAKY)
+	assert.Nil(t, err)
+
+	assert.Equal(t, COMMAND_NAME_RUNS_FLAKY, runsFlakyCommand.Name())
+	assert.NotNil(t, runsFlakyCommand.Values())
+	assert.IsType(t, &RunsFlakyCmdValues{}, runsFlakyCommand.Values())
+	assert.NotNil(t, runsFlakyCommand.CobraCommand())
+}
+
+func TestRunsFlakyHelpFlagSetCorrectly(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "flaky", "--help"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("Displays the options for the 'runs flaky' command.", "", factory, t)
+}
+
+func TestRunsFlakyNoFlagsReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "flaky"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.NotNil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "Error: required flag(s) \"age\" not set", factory, t)
+}
+
+func TestRunsFlakyAgeFlagReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_FLAKY, factory, t)
+
+	var args []string = []string{"runs", "flaky", "--age", "30d"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsFlakyCmdValues)
+	assert.Equal(t, "30d", values.age)
+	assert.Equal(t, 0, values.topCount)
+	assert.Equal(t, "", values.portfolioFilename)
+}
+
+func TestRunsFlakyAllFlagsReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_FLAKY, factory, t)
+
+	var args []string = []string{"runs", "flaky", "--age", "14d:7d", "--requestor", "me", "--group", "myGroup",
+		"--top", "5", "--portfolio", "flaky.yaml", "--stream", "myStream"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsFlakyCmdValues)
+	assert.Equal(t, "14d:7d", values.age)
+	assert.Equal(t, "me", values.requestor)
+	assert.Equal(t, "myGroup", values.group)
+	assert.Equal(t, 5, values.topCount)
+	assert.Equal(t, "flaky.yaml", values.portfolioFilename)
+	assert.Equal(t, "myStream", values.stream)
+}
+
+func TestRunsFlakyTopNotANumberReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_FLAKY, factory, t)
+
+	var args []string = []string{"runs", "flaky", "--age", "30d", "--top", "lots"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid argument \"lots\" for \"--top\" flag")
+}
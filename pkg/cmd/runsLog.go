@@ -0,0 +1,164 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/runs"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    runs log --name U1234
+// And then show the run log of that test run, optionally following it while the run is active.
+
+// Variables set by cobra's command-line parsing.
+type RunsLogCmdValues struct {
+	runName             string
+	shouldFollow        bool
+	tailLineCount       int
+	grepPattern         string
+	shouldHighlight     bool
+	pollIntervalSeconds int
+}
+
+type RunsLogCommand struct {
+	values       *RunsLogCmdValues
+	cobraCommand *cobra.Command
+}
+
+func NewRunsLogCommand(factory spi.Factory, runsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) (spi.GalasaCommand, error) {
+	cmd := new(RunsLogCommand)
+	err := cmd.init(factory, runsCommand, commsFlagSet)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *RunsLogCommand) Name() string {
+	return COMMAND_NAME_RUNS_LOG
+}
+
+func (cmd *RunsLogCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *RunsLogCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+
+func (cmd *RunsLogCommand) init(factory spi.Factory, runsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) error {
+	var err error
+	cmd.values = &RunsLogCmdValues{}
+	cmd.cobraCommand, err = cmd.createCobraCommand(factory, runsCommand, commsFlagSet.Values().(*CommsFlagSetValues))
+	return err
+}
+
+func (cmd *RunsLogCommand) createCobraCommand(
+	factory spi.Factory,
+	runsCommand spi.GalasaCommand,
+	commsFlagSetValues *CommsFlagSetValues,
+) (*cobra.Command, error) {
+
+	var err error
+
+	runsLogCobraCmd := &cobra.Command{
+		Use:   "log",
+		Short: "Show the run log of a test run.",
+		Long: "Gets the run log of a named test run from the ecosystem's RAS, without downloading any of the run's other artifacts." +
+			" If the run is still active, the --follow flag can be used to keep showing new lines of the log as they are written, until the run finishes.",
+		Args:    cobra.NoArgs,
+		Aliases: []string{"runs log"},
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.executeRunsLog(factory, commsFlagSetValues)
+		},
+	}
+
+	runsLogCobraCmd.Flags().StringVar(&cmd.values.runName, "name", "", "the name of the test run whose log should be shown. If the test has been re-run, the log of the latest attempt is shown.")
+	runsLogCobraCmd.Flags().BoolVarP(&cmd.values.shouldFollow, "follow", "f", false, "keep polling the run log of an active test run, showing new lines as they are written, until the test run finishes.")
+	runsLogCobraCmd.Flags().IntVar(&cmd.values.tailLineCount, "tail", 0, "only show this many lines from the end of the run log. If used with --follow, all new lines are shown after that."+
+		" Defaults to 0, which shows the whole run log.")
+	runsLogCobraCmd.Flags().StringVar(&cmd.values.grepPattern, "grep", "", "only show the lines of the run log which match this regular expression. For example: '--grep \"ERROR|WARN\"'.")
+	runsLogCobraCmd.Flags().BoolVar(&cmd.values.shouldHighlight, "highlight", true, "highlight the parts of each line which match the --grep pattern. Use '--highlight=false' to turn highlighting off,"+
+		" for example when the output is being sent to a file.")
+	runsLogCobraCmd.Flags().IntVar(&cmd.values.pollIntervalSeconds, "poll", runs.DEFAULT_RUN_LOG_POLL_INTERVAL_SECONDS,
+		"Optional. The interval time in seconds between successive polls of the run log when using --follow. "+
+			"Defaults to "+strconv.Itoa(runs.DEFAULT_RUN_LOG_POLL_INTERVAL_SECONDS)+" seconds. "+
+			"If less than 1, then default value is used.")
+
+	runsLogCobraCmd.MarkFlagRequired("name")
+
+	runsCommand.CobraCommand().AddCommand(runsLogCobraCmd)
+
+	return runsLogCobraCmd, err
+}
+
+func (cmd *RunsLogCommand) executeRunsLog(
+	factory spi.Factory,
+	commsFlagSetValues *CommsFlagSetValues,
+) error {
+
+	var err error
+
+	// Operations on the file system will all be relative to the current folder.
+	fileSystem := factory.GetFileSystem()
+
+	err = utils.CaptureLog(fileSystem, commsFlagSetValues.logFileName)
+	if err == nil {
+		commsFlagSetValues.isCapturingLogs = true
+
+		log.Println("Galasa CLI - Show the run log of a test run")
+
+		// Get the ability to query environment variables.
+		env := factory.GetEnvironment()
+
+		var galasaHome spi.GalasaHome
+		galasaHome, err = utils.NewGalasaHome(fileSystem, env, commsFlagSetValues.CmdParamGalasaHomePath)
+		if err == nil {
+
+			var commsClient api.APICommsClient
+			commsClient, err = api.NewAPICommsClient(
+				commsFlagSetValues.bootstrap,
+				commsFlagSetValues.maxRetries,
+				commsFlagSetValues.retryBackoffSeconds,
+				factory,
+				galasaHome,
+			)
+
+			if err == nil {
+
+				var console = factory.GetStdOutConsole()
+				timeService := factory.GetTimeService()
+
+				// Call to process the command in a unit-testable way.
+				err = runs.RunsLog(
+					cmd.values.runName,
+					cmd.values.shouldFollow,
+					cmd.values.tailLineCount,
+					cmd.values.grepPattern,
+					cmd.values.shouldHighlight,
+					cmd.values.pollIntervalSeconds,
+					timeService,
+					console,
+					commsClient,
+				)
+			}
+		}
+	}
+
+	log.Printf("executeRunsLog returning %v", err)
+	return err
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/runs"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsLogCommandInCommandCollection(t *testing.T) {
+
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	runsLogCommand, err := commands.GetCommand(COMMAND_NAME_RUNS_LOG)
+	assert.Nil(t, err)
+
+	assert.Equal(t, COMMAND_NAME_RUNS_LOG, runsLogCommand.Name())
+	assert.NotNil(t, runsLogCommand.Values())
+	assert.IsType(t, &RunsLogCmdValues{}, runsLogCommand.Values())
+	assert.NotNil(t, runsLogCommand.CobraCommand())
+}
+
+func TestRunsLogHelpFlagSetCorrectly(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "log", "--help"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("Displays the options for the 'runs log' command.", "", factory, t)
+}
+
+func TestRunsLogNoFlagsReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "log"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.NotNil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "Error: required flag(s) \"name\" not set", factory, t)
+}
+
+func TestRunsLogNameFlagReturnsOkWithDefaults(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_LOG, factory, t)
+
+	var args []string = []string{"runs", "log", "--name", "U123"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsLogCmdValues)
+	assert.Equal(t, "U123", values.runName)
+	assert.False(t, values.shouldFollow)
+	assert.Equal(t, 0, values.tailLineCount)
+	assert.Equal(t, "", values.grepPattern)
+	assert.True(t, values.shouldHighlight)
+	assert.Equal(t, runs.DEFAULT_RUN_LOG_POLL_INTERVAL_SECONDS, values.pollIntervalSeconds)
+}
+
+func TestRunsLogAllFlagsReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_LOG, factory, t)
+
+	var args []string = []string{"runs", "log", "--name", "U123", "--follow", "--tail", "50",
+		"--grep", "ERROR|WARN", "--highlight=false", "--poll", "2"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsLogCmdValues)
+	assert.Equal(t, "U123", values.runName)
+	assert.True(t, values.shouldFollow)
+	assert.Equal(t, 50, values.tailLineCount)
+	assert.Equal(t, "ERROR|WARN", values.grepPattern)
+	assert.False(t, values.shouldHighlight)
+	assert.Equal(t, 2, values.pollIntervalSeconds)
+}
+
+func TestRunsLogShortFollowFlagReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_LOG, factory, t)
+
+	var args []string = []string{"runs", "log", "--name", "U123", "-f"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	assert.True(t, cmd.Values().(*RunsLogCmdValues).shouldFollow)
+}
+
+func TestRunsLogUnknownParameterReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_LOG, factory, t)
+
+	var args []string = []string{"runs", "log", "--name", "U123", "--random", "random"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown flag: --random")
+
+	// Check what the user saw was reasonable
+	checkOutput("", "Error: unknown flag: --random", factory, t)
+}
@@ -24,6 +24,11 @@ type RunsPrepareCmdValues struct {
 	prepareAppend        *bool
 
 	prepareSelectionFlags *utils.TestSelectionFlagValues
+
+	// Tests for local runs are prepared from the test catalogs of these OBRs, rather than from a stream.
+	obrs        []string
+	localMaven  string
+	remoteMaven string
 }
 
 type RunsPrepareCommand struct {
@@ -93,6 +98,19 @@ func (cmd *RunsPrepareCommand) createCobraCommand(
 
 	runs.AddCommandFlags(runsPrepareCobraCmd, cmd.values.prepareSelectionFlags)
 
+	runsPrepareCobraCmd.Flags().StringSliceVar(&cmd.values.obrs, "obr", make([]string, 0),
+		"The maven coordinates of the obr bundle(s) whose test catalogs the tests are selected from, to be run locally. "+
+			"The format of this parameter is 'mvn:${TEST_OBR_GROUP_ID}/${TEST_OBR_ARTIFACT_ID}/${TEST_OBR_VERSION}/obr' "+
+			"Multiple instances of this flag can be used to describe multiple obr bundles.")
+	runsPrepareCobraCmd.Flags().StringVar(&cmd.values.localMaven, "localMaven", "",
+		"The url of a local maven repository where the test catalogs of the obr bundles can be found. Defaults to your home .m2/repository file. "+
+			"Please note that this should be in a URL form e.g. 'file:///Users/myuserid/.m2/repository'")
+	runsPrepareCobraCmd.Flags().StringVar(&cmd.values.remoteMaven, "remoteMaven", "https://repo.maven.apache.org/maven2",
+		"the url of the remote maven where the test catalogs of the obr bundles can be found if they are not in the local maven repository. "+
+			"Defaults to maven central.")
+
+	runsPrepareCobraCmd.MarkFlagsMutuallyExclusive("stream", "obr")
+
 	runsCommand.CobraCommand().AddCommand(runsPrepareCobraCmd)
 
 	return runsPrepareCobraCmd, err
@@ -140,19 +158,11 @@ func (cmd *RunsPrepareCommand) executeAssemble(
 
 			if err == nil {
 
-				var commsClient api.APICommsClient
-				commsClient, err = api.NewAPICommsClient(
-					commsFlagSetValues.bootstrap,
-					commsFlagSetValues.maxRetries,
-					commsFlagSetValues.retryBackoffSeconds,
-					factory,
-					galasaHome,
-				)
+				var launcherInstance launcher.TestCatalogProvider
+				var validator runs.TestSelectionFlagValidator
+				launcherInstance, validator, err = cmd.getTestCatalogProvider(factory, fileSystem, galasaHome, commsFlagSetValues)
 
 				if err == nil {
-					launcherInstance := launcher.NewRemoteLauncher(commsClient)
-
-					validator := runs.NewStreamBasedValidator()
 					err = validator.Validate(cmd.values.prepareSelectionFlags)
 					if err == nil {
 
@@ -201,3 +211,36 @@ func (cmd *RunsPrepareCommand) executeAssemble(
 	}
 	return err
 }
+
+// Tests are selected from the test catalogs of the OBRs if any were given, so nothing needs to be
+// contacted but the maven repositories. Otherwise they are selected from a stream of the ecosystem.
+func (cmd *RunsPrepareCommand) getTestCatalogProvider(
+	factory spi.Factory,
+	fileSystem spi.FileSystem,
+	galasaHome spi.GalasaHome,
+	commsFlagSetValues *CommsFlagSetValues,
+) (launcher.TestCatalogProvider, runs.TestSelectionFlagValidator, error) {
+	var err error
+	var provider launcher.TestCatalogProvider
+	var validator runs.TestSelectionFlagValidator
+
+	if len(cmd.values.obrs) > 0 {
+		provider = launcher.NewObrTestCatalogProvider(fileSystem, cmd.values.obrs, cmd.values.localMaven, cmd.values.remoteMaven)
+		validator = runs.NewObrBasedValidator(cmd.values.obrs)
+	} else {
+		var commsClient api.APICommsClient
+		commsClient, err = api.NewAPICommsClient(
+			commsFlagSetValues.bootstrap,
+			commsFlagSetValues.maxRetries,
+			commsFlagSetValues.retryBackoffSeconds,
+			factory,
+			galasaHome,
+		)
+
+		if err == nil {
+			provider = launcher.NewRemoteLauncher(commsClient)
+			validator = runs.NewStreamBasedValidator()
+		}
+	}
+	return provider, validator, err
+}
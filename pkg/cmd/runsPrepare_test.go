@@ -449,3 +449,62 @@ func TestRunsPrepareAllFlagsReturnOk(t *testing.T) {
 	assert.Equal(t, *cmd.Values().(*RunsPrepareCmdValues).prepareSelectionFlags.RegexSelect, true)
 	assert.Contains(t, cmd.Values().(*RunsPrepareCmdValues).prepareSelectionFlags.Stream, "stream")
 }
+
+func TestRunsPrepareObrFlagsReturnOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_PREPARE, factory, t)
+
+	var args []string = []string{"runs", "prepare", "--portfolio", "portfolio.file", "--package", "my.pkg",
+		"--obr", "mvn:my.group/my.obr/0.0.1/obr", "--localMaven", "file:///localrepo", "--remoteMaven", "https://my.maven.repo"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsPrepareCmdValues)
+	assert.Equal(t, []string{"mvn:my.group/my.obr/0.0.1/obr"}, values.obrs)
+	assert.Equal(t, "file:///localrepo", values.localMaven)
+	assert.Equal(t, "https://my.maven.repo", values.remoteMaven)
+}
+
+func TestRunsPrepareObrAndStreamFlagsReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "prepare", "--portfolio", "portfolio.file", "--stream", "myStream", "--obr", "mvn:my.group/my.obr/0.0.1/obr"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "if any flags in the group [stream obr] are set none of the others can be")
+}
+
+func TestRunsPrepareWithObrSelectsTestsFromItsTestCatalog(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	fs := factory.GetFileSystem()
+	fs.WriteTextFile("/localrepo/my/group/my.obr/0.0.1/my.obr-0.0.1-testcatalog.json",
+		`{"classes": {"my.bundle/my.pkg.MyTest": {"name": "my.pkg.MyTest", "bundle": "my.bundle", "package": "my.pkg"}},
+		"packages": {"my.pkg": ["my.pkg.MyTest"]}}`)
+
+	var args []string = []string{"runs", "prepare", "--portfolio", "portfolio.yaml", "--package", "my.pkg",
+		"--obr", "mvn:my.group/my.obr/0.0.1/obr", "--localMaven", "file:///localrepo", "--remoteMaven", ""}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+	portfolioText, err := fs.ReadTextFile("portfolio.yaml")
+	assert.Nil(t, err)
+	assert.Contains(t, portfolioText, "my.pkg.MyTest")
+	assert.Contains(t, portfolioText, "obr: mvn:my.group/my.obr/0.0.1/obr")
+}
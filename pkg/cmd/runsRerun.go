@@ -0,0 +1,272 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/embedded"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/images"
+	"github.com/galasa-dev/cli/pkg/launcher"
+	"github.com/galasa-dev/cli/pkg/runs"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+)
+
+// Flags which only make sense when the test is rerun in a local JVM.
+var localOnlyRerunFlagNames = []string{"obr", "remoteMaven", "localMaven", "galasaVersion"}
+
+// Variables set by cobra's command-line parsing.
+type RunsRerunCmdValues struct {
+	runName  string
+	stream   string
+	isLocal  bool
+	submit   *utils.RunsSubmitCmdValues
+	localJvm *launcher.RunsSubmitLocalCmdParameters
+}
+
+type RunsRerunCommand struct {
+	values       *RunsRerunCmdValues
+	cobraCommand *cobra.Command
+}
+
+// ------------------------------------------------------------------------------------------------
+// Constructors
+// ------------------------------------------------------------------------------------------------
+func NewRunsRerunCommand(factory spi.Factory, runsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) (spi.GalasaCommand, error) {
+	cmd := new(RunsRerunCommand)
+	err := cmd.init(factory, runsCommand, commsFlagSet)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *RunsRerunCommand) Name() string {
+	return COMMAND_NAME_RUNS_RERUN
+}
+
+func (cmd *RunsRerunCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *RunsRerunCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+
+func (cmd *RunsRerunCommand) init(factory spi.Factory, runsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) error {
+	var err error
+	cmd.values = &RunsRerunCmdValues{
+		submit:   &utils.RunsSubmitCmdValues{},
+		localJvm: &launcher.RunsSubmitLocalCmdParameters{},
+	}
+	cmd.cobraCommand, err = cmd.createCobraCommand(factory, runsCommand, commsFlagSet.Values().(*CommsFlagSetValues))
+	return err
+}
+
+func (cmd *RunsRerunCommand) createCobraCommand(
+	factory spi.Factory,
+	runsCommand spi.GalasaCommand,
+	commsFlagSetValues *CommsFlagSetValues,
+) (*cobra.Command, error) {
+
+	var err error
+
+	runsRerunCobraCmd := &cobra.Command{
+		Use:   "rerun",
+		Short: "Submit a finished test run again.",
+		Long: "Submits the test of a finished test run again, using the same bundle, class and test stream, then monitors it and waits for it to complete," +
+			" in the same way as 'galasactl runs submit'. The new run is put in the same group as the original run unless the --group flag is used." +
+			" The overrides which the original run was given are not recorded, so use the --override and --overridefile flags to supply them again." +
+			" With the --local flag, the test is run in a local JVM instead, in the same way as 'galasactl runs submit local'.",
+		Args:    cobra.NoArgs,
+		Aliases: []string{"runs rerun"},
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.executeRunsRerun(factory, cobraCmd, commsFlagSetValues)
+		},
+	}
+
+	runsRerunCobraCmd.Flags().StringVar(&cmd.values.runName, "name", "", "the name of the finished test run to submit again. If the test has been re-run before, the latest attempt is used.")
+	runsRerunCobraCmd.Flags().StringVarP(&cmd.values.stream, "stream", "s", "", "the test stream to run the test from. Defaults to the test stream which the original run used.")
+	runsRerunCobraCmd.Flags().BoolVar(&cmd.values.isLocal, "local", false, "run the test in a local JVM rather than submitting it to the ecosystem.")
+
+	runsRerunCobraCmd.Flags().StringVar(&cmd.values.submit.ReportYamlFilename, "reportyaml", "", "yaml file to record the final results in")
+	runsRerunCobraCmd.Flags().StringVar(&cmd.values.submit.ReportJsonFilename, "reportjson", "", "json file to record the final results in")
+	runsRerunCobraCmd.Flags().StringVar(&cmd.values.submit.ReportJunitFilename, "reportjunit", "", "junit xml file to record the final results in")
+	runsRerunCobraCmd.Flags().StringVarP(&cmd.values.submit.GroupName, "group", "g", "", "the group name to assign the test run to. Defaults to the group of the original run.")
+	runsRerunCobraCmd.Flags().StringVar(&cmd.values.submit.RequestType, "requesttype", "CLI", "the type of request, used to allocate a run name. Defaults to CLI.")
+
+	runsRerunCobraCmd.Flags().IntVar(&cmd.values.submit.PollIntervalSeconds, "poll", runs.DEFAULT_POLL_INTERVAL_SECONDS,
+		"Optional. The interval time in seconds between successive polls of the test run status. "+
+			"Defaults to "+strconv.Itoa(runs.DEFAULT_POLL_INTERVAL_SECONDS)+" seconds. "+
+			"If less than 1, then default value is used.")
+
+	runsRerunCobraCmd.Flags().IntVar(&cmd.values.submit.ProgressReportIntervalMinutes, "progress", runs.DEFAULT_PROGRESS_REPORT_INTERVAL_MINUTES,
+		"in minutes, how often the cli will report the progress of the test run. A value of 0 or less disables progress reporting.")
+
+	runsRerunCobraCmd.Flags().StringSliceVar(&cmd.values.submit.OverrideFilePaths, "overridefile", []string{},
+		"path to a properties file containing override properties. Defaults to overrides.properties in galasa home folder if that file exists. "+
+			"Overrides from --override options will take precedence over properties in this property file. "+
+			"A file path of '-' disables reading any properties file. "+
+			"Multiple files can be given in the same way as for 'galasactl runs submit'.")
+
+	runsRerunCobraCmd.Flags().StringSliceVar(&cmd.values.submit.Overrides, "override", make([]string, 0),
+		"overrides to be sent with the test. "+
+			"Each override is of the form 'name=value'. Multiple instances of this flag can be used. "+
+			"For example --override=prop1=val1 --override=prop2=val2")
+
+	runsRerunCobraCmd.Flags().BoolVar(&cmd.values.submit.Trace, "trace", false, "Trace to be enabled on the test run")
+	runsRerunCobraCmd.Flags().Lookup("trace").NoOptDefVal = "true"
+
+	runsRerunCobraCmd.Flags().BoolVar(&cmd.values.submit.NoExitCodeOnTestFailures, "noexitcodeontestfailures", false, "set to true if you don't want an exit code to be returned from galasactl if the test fails")
+
+	runsRerunCobraCmd.Flags().StringSliceVar(&cmd.values.localJvm.Obrs, "obr", make([]string, 0),
+		"Only used with --local. The maven coordinates of the obr bundle(s) which refer to the test bundle. "+
+			"The format of this parameter is 'mvn:${TEST_OBR_GROUP_ID}/${TEST_OBR_ARTIFACT_ID}/${TEST_OBR_VERSION}/obr' "+
+			"Defaults to the obr of the test stream which the original run used.")
+
+	runsRerunCobraCmd.Flags().StringVar(&cmd.values.localJvm.RemoteMaven, "remoteMaven",
+		"https://repo.maven.apache.org/maven2",
+		"Only used with --local. The url of the remote maven where galasa bundles can be loaded from. "+
+			"Defaults to maven central.")
+
+	runsRerunCobraCmd.Flags().StringVar(&cmd.values.localJvm.LocalMaven, "localMaven", "",
+		"Only used with --local. The url of a local maven repository where galasa bundles can be loaded from on your local file system. "+
+			"Defaults to your home .m2/repository file.")
+
+	currentGalasaVersion, _ := embedded.GetGalasaVersion()
+	runsRerunCobraCmd.Flags().StringVar(&cmd.values.localJvm.TargetGalasaVersion, "galasaVersion",
+		currentGalasaVersion,
+		"Only used with --local. The version of galasa you want to use to run the test.")
+
+	runsRerunCobraCmd.MarkFlagRequired("name")
+
+	runsCommand.CobraCommand().AddCommand(runsRerunCobraCmd)
+
+	return runsRerunCobraCmd, err
+}
+
+func (cmd *RunsRerunCommand) executeRunsRerun(
+	factory spi.Factory,
+	cobraCmd *cobra.Command,
+	commsFlagSetValues *CommsFlagSetValues,
+) error {
+
+	var err error
+
+	// Operations on the file system will all be relative to the current folder.
+	fileSystem := factory.GetFileSystem()
+
+	err = utils.CaptureLog(fileSystem, commsFlagSetValues.logFileName)
+	if err == nil {
+		commsFlagSetValues.isCapturingLogs = true
+
+		log.Println("Galasa CLI - Rerun a test run")
+
+		if !cmd.values.isLocal {
+			for _, flagName := range localOnlyRerunFlagNames {
+				if err == nil && cobraCmd.Flags().Changed(flagName) {
+					err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_RERUN_FLAG_NEEDS_LOCAL, flagName)
+				}
+			}
+		}
+
+		if err == nil {
+			// Get the ability to query environment variables.
+			env := factory.GetEnvironment()
+
+			var galasaHome spi.GalasaHome
+			galasaHome, err = utils.NewGalasaHome(fileSystem, env, commsFlagSetValues.CmdParamGalasaHomePath)
+			if err == nil {
+
+				var commsClient api.APICommsClient
+				commsClient, err = api.NewAPICommsClient(
+					commsFlagSetValues.bootstrap,
+					commsFlagSetValues.maxRetries,
+					commsFlagSetValues.retryBackoffSeconds,
+					factory,
+					galasaHome,
+				)
+
+				if err == nil {
+					timeService := factory.GetTimeService()
+
+					var originalRun *runs.RerunDetails
+					originalRun, err = runs.GetRerunDetails(cmd.values.runName, cmd.values.stream, cmd.values.localJvm.Obrs, cmd.values.isLocal, timeService, commsClient)
+					if err == nil {
+						if cmd.values.isLocal {
+							err = cmd.rerunLocally(factory, originalRun, galasaHome, commsClient, timeService)
+						} else {
+							err = cmd.rerunRemotely(factory, originalRun, galasaHome, commsClient, timeService)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return err
+}
+
+func (cmd *RunsRerunCommand) rerunRemotely(
+	factory spi.Factory,
+	originalRun *runs.RerunDetails,
+	galasaHome spi.GalasaHome,
+	commsClient api.APICommsClient,
+	timeService spi.TimeService,
+) error {
+	launcherInstance := launcher.NewRemoteLauncher(commsClient)
+
+	submitter := runs.NewSubmitter(galasaHome, factory.GetFileSystem(), launcherInstance, timeService, utils.NewRealTimedSleeper(),
+		factory.GetEnvironment(), factory.GetStdOutConsole(), images.NewImageExpanderNullImpl())
+
+	return submitter.ExecuteRerun(originalRun, cmd.values.submit)
+}
+
+func (cmd *RunsRerunCommand) rerunLocally(
+	factory spi.Factory,
+	originalRun *runs.RerunDetails,
+	galasaHome spi.GalasaHome,
+	commsClient api.APICommsClient,
+	timeService spi.TimeService,
+) error {
+	var err error
+	var launcherInstance launcher.Launcher
+
+	fileSystem := factory.GetFileSystem()
+	timedSleeper := utils.NewRealTimedSleeper()
+	embeddedFileSystem := embedded.GetReadOnlyFileSystem()
+	bootstrapData := commsClient.GetBootstrapData()
+
+	launcherInstance, err = launcher.NewJVMLauncher(
+		factory,
+		bootstrapData.Properties, embeddedFileSystem,
+		cmd.values.localJvm,
+		launcher.NewRealProcessFactory(), galasaHome, timedSleeper)
+
+	if err == nil {
+		renderer := images.NewImageRenderer(embeddedFileSystem)
+		expander := images.NewImageExpander(fileSystem, renderer, true)
+
+		submitter := runs.NewSubmitter(galasaHome, fileSystem, launcherInstance, timeService, timedSleeper,
+			factory.GetEnvironment(), factory.GetStdOutConsole(), expander)
+
+		err = submitter.ExecuteRerun(originalRun, cmd.values.submit)
+		if err == nil {
+			reportOnExpandedImages(expander)
+		}
+	}
+	return err
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/runs"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsRerunCommandInCommandCollection(t *testing.T) {
+
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	runsRerunCommand, err := commands.GetCommand(COMMAND_NAME_RUNS_RERUN)
+	assert.Nil(t, err)
+
+	assert.Equal(t, COMMAND_NAME_RUNS_RERUN, runsRerunCommand.Name())
+	assert.NotNil(t, runsRerunCommand.Values())
+	assert.IsType(t, &RunsRerunCmdValues{}, runsRerunCommand.Values())
+	assert.NotNil(t, runsRerunCommand.CobraCommand())
+}
+
+func TestRunsRerunHelpFlagSetCorrectly(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "rerun", "--help"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("Displays the options for the 'runs rerun' command.", "", factory, t)
+}
+
+func TestRunsRerunNoFlagsReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "rerun"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.NotNil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "Error: required flag(s) \"name\" not set", factory, t)
+}
+
+func TestRunsRerunNameFlagReturnsOkWithDefaults(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_RERUN, factory, t)
+
+	var args []string = []string{"runs", "rerun", "--name", "U123"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsRerunCmdValues)
+	assert.Equal(t, "U123", values.runName)
+	assert.Equal(t, "", values.stream)
+	assert.False(t, values.isLocal)
+	assert.Equal(t, "", values.submit.GroupName)
+	assert.Equal(t, "CLI", values.submit.RequestType)
+	assert.Equal(t, runs.DEFAULT_POLL_INTERVAL_SECONDS, values.submit.PollIntervalSeconds)
+	assert.Empty(t, values.submit.Overrides)
+	assert.Empty(t, values.localJvm.Obrs)
+}
+
+func TestRunsRerunWithOverridesAndReportsReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_RERUN, factory, t)
+
+	var args []string = []string{"runs", "rerun", "--name", "U123", "--stream", "myStream", "--group", "myGroup",
+		"--override", "a=b", "--override", "c=d", "--reportjson", "report.json", "--trace", "--noexitcodeontestfailures"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsRerunCmdValues)
+	assert.Equal(t, "myStream", values.stream)
+	assert.Equal(t, "myGroup", values.submit.GroupName)
+	assert.Equal(t, []string{"a=b", "c=d"}, values.submit.Overrides)
+	assert.Equal(t, "report.json", values.submit.ReportJsonFilename)
+	assert.True(t, values.submit.Trace)
+	assert.True(t, values.submit.NoExitCodeOnTestFailures)
+}
+
+func TestRunsRerunLocalWithObrReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_RERUN, factory, t)
+
+	var args []string = []string{"runs", "rerun", "--name", "U123", "--local", "--obr", "mvn:my.group/my.obr/0.0.1/obr"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsRerunCmdValues)
+	assert.True(t, values.isLocal)
+	assert.Equal(t, []string{"mvn:my.group/my.obr/0.0.1/obr"}, values.localJvm.Obrs)
+}
+
+func TestRunsRerunObrWithoutLocalReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "rerun", "--name", "U123", "--obr", "mvn:my.group/my.obr/0.0.1/obr"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1271E: The '--obr' flag can only be used with the '--local' flag.")
+}
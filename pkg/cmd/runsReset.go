@@ -17,6 +17,8 @@ import (
 
 // Objective: Allow the user to do this:
 //    runs reset --name U123
+// or
+//    runs reset --group nightly [--requestor myuserid] [--active] [--dry-run]
 // And then galasactl resets the run by requeuing it.
 
 type RunsResetCommand struct {
@@ -25,7 +27,11 @@ type RunsResetCommand struct {
 }
 
 type RunsResetCmdValues struct {
-	runName string
+	runName   string
+	group     string
+	requestor string
+	isActive  bool
+	isDryRun  bool
 }
 
 // ------------------------------------------------------------------------------------------------
@@ -76,7 +82,8 @@ func (cmd *RunsResetCommand) createRunsResetCobraCmd(factory spi.Factory,
 	runsResetCmd := &cobra.Command{
 		Use:     "reset",
 		Short:   "reset an active run in the ecosystem",
-		Long:    "Reset an active test run in the ecosystem if it is stuck or looping.",
+		Long: "Reset an active test run in the ecosystem if it is stuck or looping. " +
+			"Many active test runs can be reset at once by selecting them using --group, --requestor or --active.",
 		Args:    cobra.NoArgs,
 		Aliases: []string{"runs reset"},
 		RunE: func(cobraCmd *cobra.Command, args []string) error {
@@ -85,8 +92,20 @@ func (cmd *RunsResetCommand) createRunsResetCobraCmd(factory spi.Factory,
 	}
 
 	runsResetCmd.PersistentFlags().StringVar(&cmd.values.runName, "name", "", "the name of the test run to reset")
-
-	runsResetCmd.MarkPersistentFlagRequired("name")
+	runsResetCmd.PersistentFlags().StringVar(&cmd.values.group, "group", "", "reset all the active test runs submitted under this group."+
+		" Cannot be used in conjunction with --name")
+	runsResetCmd.PersistentFlags().StringVar(&cmd.values.requestor, "requestor", "", "reset all the active test runs submitted by this requestor."+
+		" Cannot be used in conjunction with --name")
+	runsResetCmd.PersistentFlags().BoolVar(&cmd.values.isActive, "active", false, "reset all the active test runs, unless --group or --requestor narrow them down."+
+		" Cannot be used in conjunction with --name")
+	runsResetCmd.PersistentFlags().BoolVar(&cmd.values.isDryRun, "dry-run", false, "list the active test runs which would be reset, without changing any of them."+
+		" Cannot be used in conjunction with --name")
+
+	runsResetCmd.MarkFlagsOneRequired("name", "group", "requestor", "active")
+	runsResetCmd.MarkFlagsMutuallyExclusive("name", "group")
+	runsResetCmd.MarkFlagsMutuallyExclusive("name", "requestor")
+	runsResetCmd.MarkFlagsMutuallyExclusive("name", "active")
+	runsResetCmd.MarkFlagsMutuallyExclusive("name", "dry-run")
 
 	runsCommand.CobraCommand().AddCommand(runsResetCmd)
 
@@ -131,12 +150,24 @@ func (cmd *RunsResetCommand) executeReset(
 				var console = factory.GetStdOutConsole()
 
 				// Call to process command in unit-testable way.
-				err = runs.ResetRun(
-					cmd.values.runName,
-					timeService,
-					console,
-					commsClient,
-				)
+				if cmd.values.runName != "" {
+					err = runs.ResetRun(
+						cmd.values.runName,
+						timeService,
+						console,
+						commsClient,
+					)
+				} else {
+					err = runs.ResetRunsByQuery(
+						cmd.values.group,
+						cmd.values.requestor,
+						cmd.values.isActive,
+						cmd.values.isDryRun,
+						timeService,
+						console,
+						commsClient,
+					)
+				}
 			}
 		}
 	}
@@ -55,7 +55,7 @@ func TestRunsResetNoFlagsReturnsError(t *testing.T) {
 	assert.NotNil(t, err)
 
 	// Check what the user saw is reasonable.
-	checkOutput("", "Error: required flag(s) \"name\" not set", factory, t)
+	checkOutput("", "Error: at least one of the flags in the group [name group requestor active] is required", factory, t)
 }
 
 func TestRunsResetNameFlagReturnsOk(t *testing.T) {
@@ -130,3 +130,56 @@ func TestRunsResetNameTwiceOverridesToLatestValue(t *testing.T) {
 
 	assert.Contains(t, cmd.Values().(*RunsResetCmdValues).runName, "name2")
 }
+
+func TestRunsResetGroupRequestorActiveDryRunReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_RESET, factory, t)
+
+	var args []string = []string{"runs", "reset", "--group", "badPortfolio", "--requestor", "myuser", "--active", "--dry-run"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw was reasonable
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsResetCmdValues)
+	assert.Equal(t, "badPortfolio", values.group)
+	assert.Equal(t, "myuser", values.requestor)
+	assert.Equal(t, true, values.isActive)
+	assert.Equal(t, true, values.isDryRun)
+}
+
+func TestRunsResetNameAndGroupReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_RESET, factory, t)
+
+	var args []string = []string{"runs", "reset", "--name", "U1", "--group", "badPortfolio"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "if any flags in the group [name group] are set none of the others can be")
+}
+
+func TestRunsResetNameAndDryRunReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_RESET, factory, t)
+
+	var args []string = []string{"runs", "reset", "--name", "U1", "--dry-run"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "if any flags in the group [name dry-run] are set none of the others can be")
+}
@@ -26,6 +26,12 @@ var ()
 type RunsSubmitLocalCmdValues struct {
 	runsSubmitLocalCmdParams  *launcher.RunsSubmitLocalCmdParameters
 	submitLocalSelectionFlags *utils.TestSelectionFlagValues
+	animationFormat           string
+	animationDelayMillis      int
+	terminalTextFormat        string
+	terminalTheme             string
+	terminalFontSize          float64
+	isShowingHiddenFields     bool
 }
 
 type RunsSubmitLocalCommand struct {
@@ -139,13 +145,64 @@ func (cmd *RunsSubmitLocalCommand) createRunsSubmitLocalCobraCmd(
 			"The connection is established using the --debugMode and --debugPort values.",
 	)
 
+	runsSubmitLocalCobraCmd.Flags().StringArrayVar(&cmd.values.runsSubmitLocalCmdParams.JvmArgs, "jvm-arg", make([]string, 0),
+		"An extra option for the JVM which runs each test, such as '-Xmx1g' or '-javaagent:/path/to/agent.jar'. "+
+			"These options follow any in the '"+api.BOOTSTRAP_PROPERTY_NAME_LOCAL_JVM_LAUNCH_OPTIONS+"' property in the bootstrap file, "+
+			"so take precedence over them. Multiple instances of this flag can be used.")
+
+	runsSubmitLocalCobraCmd.Flags().StringArrayVar(&cmd.values.runsSubmitLocalCmdParams.JvmSystemProperties, "jvm-system-property", make([]string, 0),
+		"A system property of the form key=value to set in the JVM which runs each test. "+
+			"These take precedence over any system property set using --jvm-arg or the bootstrap file. Multiple instances of this flag can be used.")
+
+	runsSubmitLocalCobraCmd.Flags().StringArrayVar(&cmd.values.runsSubmitLocalCmdParams.JvmEnvironment, "jvm-env", make([]string, 0),
+		"An environment variable of the form key=value to set for the JVM which runs each test, "+
+			"on top of those it inherits from galasactl. Multiple instances of this flag can be used.")
+
+	runsSubmitLocalCobraCmd.Flags().StringVar(&cmd.values.runsSubmitLocalCmdParams.Profile, "profile", "",
+		"Profile each test using the Java Flight Recorder. The value is 'jfr', or 'jfr:' followed by the recorder settings to use, "+
+			"such as 'jfr:default' or 'jfr:/path/to/my.jfc'. Defaults to the '"+launcher.DEFAULT_JFR_SETTINGS+"' settings. "+
+			"The recording is saved as ras/<runId>/"+launcher.PROFILING_FOLDER_NAME+"/<runId>.jfr and a summary of it is shown once the test is complete.")
+
+	runsSubmitLocalCobraCmd.Flags().BoolVar(&cmd.values.runsSubmitLocalCmdParams.IsCoverageEnabled, "coverage", false,
+		"Collect the code coverage of each test using the JaCoCo agent. The coverage is saved as ras/<runId>/"+launcher.COVERAGE_FOLDER_NAME+"/<runId>."+launcher.COVERAGE_FILE_EXTENSION+
+			" and the coverage of many runs can be merged and reported on using 'galasactl local coverage report'.")
+
+	runsSubmitLocalCobraCmd.Flags().BoolVar(&cmd.values.runsSubmitLocalCmdParams.IsStreamingOutput, "stream-output", false,
+		"Echo the output of the JVM which runs each test to the console as it arrives, with each line prefixed by the run it came from. "+
+			"Each run is shown in a colour of its own, unless the NO_COLOR environment variable is set. "+
+			"The output of each JVM is always saved as ras/<runId>/"+launcher.JVM_OUTPUT_FILE_NAME+", whether it is streamed or not.")
+
+	runsSubmitLocalCobraCmd.Flags().StringVar(&cmd.values.runsSubmitLocalCmdParams.OutputFilter, "output-filter", "",
+		"A regular expression which lines of JVM output must match to be echoed to the console by --stream-output. "+
+			"For example, '\\*\\*\\*' only shows the milestone lines which Galasa writes as each test method starts and ends. "+
+			"Every line is still saved into the RAS folder of the run.")
+
+	runsSubmitLocalCobraCmd.Flags().IntVar(&cmd.values.runsSubmitLocalCmdParams.InactivityTimeoutMinutes, "inactivity-timeout", 0,
+		"The number of minutes the JVM of a test can write no output for before it is treated as hung. "+
+			"A thread dump of a hung JVM is saved as ras/<runId>/"+launcher.THREAD_DUMP_FILE_NAME+", then the JVM is terminated "+
+			"and the run is given the result '"+launcher.RESULT_HUNG+"'. Defaults to 0, which means there is no limit.")
+
+	runsSubmitLocalCobraCmd.Flags().IntVar(&cmd.values.runsSubmitLocalCmdParams.MaxDurationMinutes, "max-duration", 0,
+		"The number of minutes the JVM of a test can run for before it is treated as hung, in the same way as with --inactivity-timeout. "+
+			"Defaults to 0, which means there is no limit.")
+
+	runsSubmitLocalCobraCmd.Flags().BoolVar(&cmd.values.runsSubmitLocalCmdParams.IsOffline, "offline", false,
+		"Run the tests using only the OBRs, bundles and test catalogs in the local Maven repository, without going to the remote Maven repository. "+
+			"If anything the tests need is missing, no tests are run. Use 'galasactl local prefetch' to download everything needed first.")
+
+	runs.AddCatalogSelectionFlags(runsSubmitLocalCobraCmd, cmd.values.submitLocalSelectionFlags)
+
 	runs.AddClassFlag(runsSubmitLocalCobraCmd, cmd.values.submitLocalSelectionFlags, false, "test class names."+
 		" The format of each entry is osgi-bundle-name/java-class-name. Java class names are fully qualified. No .class suffix is needed.")
 
 	runs.AddGherkinFlag(runsSubmitLocalCobraCmd, cmd.values.submitLocalSelectionFlags, false, "Gherkin feature file URL. Should start with 'file://'. ")
 
-	runsSubmitLocalCobraCmd.MarkFlagsRequiredTogether("class", "obr")
-	runsSubmitLocalCobraCmd.MarkFlagsOneRequired("class", "gherkin")
+	addAnimationFlags(runsSubmitLocalCobraCmd.Flags(), &cmd.values.animationFormat, &cmd.values.animationDelayMillis)
+	addTerminalTextFlag(runsSubmitLocalCobraCmd.Flags(), &cmd.values.terminalTextFormat)
+	addTerminalRenderFlags(runsSubmitLocalCobraCmd.Flags(), &cmd.values.terminalTheme, &cmd.values.terminalFontSize, &cmd.values.isShowingHiddenFields)
+
+	// Tests are selected by class, or from the test catalogs of the OBRs, or are gherkin features.
+	runsSubmitLocalCobraCmd.MarkFlagsOneRequired("class", "gherkin", "package", "bundle", "test", "tag")
 
 	runsSubmitCmd.CobraCommand().AddCommand(runsSubmitLocalCobraCmd)
 
@@ -175,6 +232,19 @@ func (cmd *RunsSubmitLocalCommand) executeSubmitLocal(
 		// Work out where galasa home is, only once.
 		var galasaHome spi.GalasaHome
 		galasaHome, err = utils.NewGalasaHome(fileSystem, env, commsFlagSetValues.CmdParamGalasaHomePath)
+
+		// A bad prune policy in galasactl.properties is reported before any tests are run.
+		var autoPrunePolicy *runs.LocalRasPrunePolicy
+		if err == nil {
+			autoPrunePolicy, err = runs.GetLocalRasAutoPrunePolicy(fileSystem, galasaHome)
+		}
+
+		if err == nil {
+			// Validate the test selection parameters before contacting anything.
+			validator := runs.NewObrBasedValidator(cmd.values.runsSubmitLocalCmdParams.Obrs)
+			err = validator.Validate(cmd.values.submitLocalSelectionFlags)
+		}
+
 		if err == nil {
 	
 			var commsClient api.APICommsClient
@@ -197,9 +267,16 @@ func (cmd *RunsSubmitLocalCommand) executeSubmitLocal(
 				// Something which can kick off new operating system processes
 				processFactory := launcher.NewRealProcessFactory()
 	
-				// Validate the test selection parameters.
-				validator := runs.NewObrBasedValidator()
-				err = validator.Validate(cmd.values.submitLocalSelectionFlags)
+				var renderOptions *images.RenderOptions
+				if err == nil {
+					renderOptions, err = images.NewRenderOptions(cmd.values.terminalTheme, cmd.values.terminalFontSize, cmd.values.isShowingHiddenFields)
+				}
+
+				var terminalOutputOptions *images.TerminalOutputOptions
+				if err == nil {
+					terminalOutputOptions, err = images.NewTerminalOutputOptions(cmd.values.animationFormat, cmd.values.animationDelayMillis, cmd.values.terminalTextFormat, renderOptions)
+				}
+
 				if err == nil {
 	
 					bootstrapData := commsClient.GetBootstrapData()
@@ -215,8 +292,8 @@ func (cmd *RunsSubmitLocalCommand) executeSubmitLocal(
 					if err == nil {
 						var console = factory.GetStdOutConsole()
 	
-						renderer := images.NewImageRenderer(embeddedFileSystem)
-						expander := images.NewImageExpander(fileSystem, renderer, true)
+						renderer := images.NewImageRendererWithOptions(embeddedFileSystem, renderOptions)
+						expander := images.NewImageExpanderWithOptions(fileSystem, renderer, true, terminalOutputOptions)
 	
 						// Do the launching of the tests.
 						submitter := runs.NewSubmitter(
@@ -238,6 +315,15 @@ func (cmd *RunsSubmitLocalCommand) executeSubmitLocal(
 						if err == nil {
 							reportOnExpandedImages(expander)
 						}
+
+						// The RAS folder is pruned even when some tests failed.
+						if autoPrunePolicy != nil {
+							rasFolderPath := launcher.GetLocalRasFolderPath(fileSystem, galasaHome, "")
+							pruneErr := runs.AutoPruneLocalRas(autoPrunePolicy, rasFolderPath, fileSystem, timeService, console)
+							if err == nil {
+								err = pruneErr
+							}
+						}
 					}
 				}
 			}
@@ -254,6 +340,8 @@ func reportOnExpandedImages(expander images.ImageExpander) error {
 
 	// Only bother writing out a message if any images have been expanded.
 	log.Printf("Expanded a total of %d images from .gz files.", count)
+	log.Printf("Created a total of %d terminal animations.", expander.GetAnimationFileCount())
+	log.Printf("Wrote the text of a total of %d terminal screens.", expander.GetTextFileCount())
 
 	return nil
 }
@@ -52,12 +52,22 @@ func TestRunsSubmitLocalWithoutObrWithClassErrors(t *testing.T) {
 	err := Execute(factory, args)
 
 	// Then...
-	// Check what the user saw was reasonable
-	checkOutput("", "if any flags in the group [class obr] are set they must all be set; missing [obr]", factory, t)
+	// Should throw an error asking for an obr to be set
+	assert.NotNil(t, err, "err should have been set!")
+	assert.Contains(t, err.Error(), "GAL1292E")
+}
 
-	// Should throw an error asking for flags to be set
+func TestRunsSubmitLocalWithoutObrWithPackageErrors(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	var args []string = []string{"runs", "submit", "local", "--package", "my.package"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
 	assert.NotNil(t, err, "err should have been set!")
-	assert.Contains(t, err.Error(), "if any flags in the group [class obr] are set they must all be set; missing [obr]")
+	assert.Contains(t, err.Error(), "GAL1292E")
 }
 
 func TestRunsSubmitLocalWithoutClassWithObrErrors(t *testing.T) {
@@ -70,11 +80,11 @@ func TestRunsSubmitLocalWithoutClassWithObrErrors(t *testing.T) {
 
 	// Then...
 	// Check what the user saw was reasonable
-	checkOutput("", "if any flags in the group [class obr] are set they must all be set; missing [class]", factory, t)
+	checkOutput("", "at least one of the flags in the group [class gherkin package bundle test tag] is required", factory, t)
 
 	// Should throw an error asking for flags to be set
 	assert.NotNil(t, err, "err should have been set!")
-	assert.Contains(t, err.Error(), "if any flags in the group [class obr] are set they must all be set; missing [class]")
+	assert.Contains(t, err.Error(), "at least one of the flags in the group [class gherkin package bundle test tag] is required")
 }
 
 func TestMultipleRequiredFlagsNotSetReturnsListInError(t *testing.T) {
@@ -87,11 +97,11 @@ func TestMultipleRequiredFlagsNotSetReturnsListInError(t *testing.T) {
 
 	// Then...
 	// Check what the user saw was reasonable
-	checkOutput("", "at least one of the flags in the group [class gherkin] is required", factory, t)
+	checkOutput("", "at least one of the flags in the group [class gherkin package bundle test tag] is required", factory, t)
 
 	// Should throw an error asking for flags to be set
 	assert.NotNil(t, err, "err should have been set!")
-	assert.Contains(t, err.Error(), "at least one of the flags in the group [class gherkin] is required")
+	assert.Contains(t, err.Error(), "at least one of the flags in the group [class gherkin package bundle test tag] is required")
 }
 
 func TestRunsSubmitLocalClassObrFlagReturnsOk(t *testing.T) {
@@ -114,6 +124,31 @@ func TestRunsSubmitLocalClassObrFlagReturnsOk(t *testing.T) {
 	assert.Contains(t, cmd.Values().(*RunsSubmitLocalCmdValues).runsSubmitLocalCmdParams.Obrs, "mvn:a.big.ol.obr")
 }
 
+func TestRunsSubmitLocalCatalogSelectionFlagsReturnOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_SUBMIT_LOCAL, factory, t)
+
+	var args []string = []string{"runs", "submit", "local", "--obr", "mvn:a.big.ol.obr",
+		"--package", "my.package", "--bundle", "my.bundle", "--test", "MyTest", "--tag", "myTag", "--regex"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	flags := cmd.Values().(*RunsSubmitLocalCmdValues).submitLocalSelectionFlags
+	assert.Contains(t, *flags.Packages, "my.package")
+	assert.Contains(t, *flags.Bundles, "my.bundle")
+	assert.Contains(t, *flags.Tests, "MyTest")
+	assert.Contains(t, *flags.Tags, "myTag")
+	assert.True(t, *flags.RegexSelect)
+}
+
 func TestRunsSubmitLocalDebugFlagReturnsOk(t *testing.T) {
 	// Given...
 	factory := utils.NewMockFactory()
@@ -307,3 +342,151 @@ func TestRunsSubmitLocaGherkinFlagsWork(t *testing.T) {
 	assert.Empty(t, cmd.Values().(*RunsSubmitLocalCmdValues).runsSubmitLocalCmdParams.Obrs)
 	assert.Empty(t, cmd.Values().(*RunsSubmitLocalCmdValues).submitLocalSelectionFlags.Classes)
 }
+
+func TestRunsSubmitLocalAnimationFlagsReturnOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_SUBMIT_LOCAL, factory, t)
+
+	var args []string = []string{"runs", "submit", "local",
+		"--class", "osgi.bundle/class.path",
+		"--obr", "mvn:a/b/c/obr",
+		"--animation", "gif",
+		"--animation-delay", "2000"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsSubmitLocalCmdValues)
+	assert.Equal(t, "gif", values.animationFormat)
+	assert.Equal(t, 2000, values.animationDelayMillis)
+}
+
+func TestRunsSubmitLocalJvmFlagsReturnOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_SUBMIT_LOCAL, factory, t)
+
+	var args []string = []string{"runs", "submit", "local", "--class", "my.class", "--obr", "mvn:a.big.ol.obr",
+		"--jvm-arg", "-Xmx1g", "--jvm-arg", "-javaagent:/my/agent.jar=opt1,opt2",
+		"--jvm-system-property", "my.prop=a,b",
+		"--jvm-env", "MY_VAR=myValue"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	// Commas are common in JVM options, so don't split the values.
+	params := cmd.Values().(*RunsSubmitLocalCmdValues).runsSubmitLocalCmdParams
+	assert.Equal(t, []string{"-Xmx1g", "-javaagent:/my/agent.jar=opt1,opt2"}, params.JvmArgs)
+	assert.Equal(t, []string{"my.prop=a,b"}, params.JvmSystemProperties)
+	assert.Equal(t, []string{"MY_VAR=myValue"}, params.JvmEnvironment)
+}
+
+func TestRunsSubmitLocalProfileFlagReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_SUBMIT_LOCAL, factory, t)
+
+	var args []string = []string{"runs", "submit", "local", "--class", "my.class", "--obr", "mvn:a.big.ol.obr", "--profile", "jfr:default"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	assert.Equal(t, "jfr:default", cmd.Values().(*RunsSubmitLocalCmdValues).runsSubmitLocalCmdParams.Profile)
+}
+
+func TestRunsSubmitLocalCoverageFlagReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_SUBMIT_LOCAL, factory, t)
+
+	var args []string = []string{"runs", "submit", "local", "--class", "my.class", "--obr", "mvn:a.big.ol.obr", "--coverage"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	assert.True(t, cmd.Values().(*RunsSubmitLocalCmdValues).runsSubmitLocalCmdParams.IsCoverageEnabled)
+}
+
+func TestRunsSubmitLocalStreamOutputFlagsReturnOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_SUBMIT_LOCAL, factory, t)
+
+	var args []string = []string{"runs", "submit", "local", "--class", "my.class", "--obr", "mvn:a.big.ol.obr", "--stream-output", "--output-filter", "\\*\\*\\*"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	assert.True(t, cmd.Values().(*RunsSubmitLocalCmdValues).runsSubmitLocalCmdParams.IsStreamingOutput)
+	assert.Equal(t, "\\*\\*\\*", cmd.Values().(*RunsSubmitLocalCmdValues).runsSubmitLocalCmdParams.OutputFilter)
+}
+
+func TestRunsSubmitLocalWatchdogFlagsReturnOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_SUBMIT_LOCAL, factory, t)
+
+	var args []string = []string{"runs", "submit", "local", "--class", "my.class", "--obr", "mvn:a.big.ol.obr", "--inactivity-timeout", "10", "--max-duration", "60"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	assert.Equal(t, 10, cmd.Values().(*RunsSubmitLocalCmdValues).runsSubmitLocalCmdParams.InactivityTimeoutMinutes)
+	assert.Equal(t, 60, cmd.Values().(*RunsSubmitLocalCmdValues).runsSubmitLocalCmdParams.MaxDurationMinutes)
+}
+
+func TestRunsSubmitLocalOfflineFlagReturnsOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_SUBMIT_LOCAL, factory, t)
+
+	var args []string = []string{"runs", "submit", "local", "--class", "my.class", "--obr", "mvn:a.big.ol.obr", "--offline"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "", factory, t)
+
+	assert.True(t, cmd.Values().(*RunsSubmitLocalCmdValues).runsSubmitLocalCmdParams.IsOffline)
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    runs terminals ...
+
+type RunsTerminalsCmdValues struct {
+}
+
+type RunsTerminalsCommand struct {
+	values       *RunsTerminalsCmdValues
+	cobraCommand *cobra.Command
+}
+
+// ------------------------------------------------------------------------------------------------
+// Constructors methods
+// ------------------------------------------------------------------------------------------------
+func NewRunsTerminalsCommand(runsCommand spi.GalasaCommand) (spi.GalasaCommand, error) {
+	cmd := new(RunsTerminalsCommand)
+	err := cmd.init(runsCommand)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *RunsTerminalsCommand) Name() string {
+	return COMMAND_NAME_RUNS_TERMINALS
+}
+
+func (cmd *RunsTerminalsCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *RunsTerminalsCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *RunsTerminalsCommand) init(runsCommand spi.GalasaCommand) error {
+	var err error
+	cmd.values = &RunsTerminalsCmdValues{}
+	cmd.cobraCommand, err = cmd.createCobraCommand(runsCommand)
+	return err
+}
+
+func (cmd *RunsTerminalsCommand) createCobraCommand(runsCommand spi.GalasaCommand) (*cobra.Command, error) {
+
+	var err error
+
+	runsTerminalsCobraCmd := &cobra.Command{
+		Use:     "terminals",
+		Short:   "Queries the 3270 terminals of test runs",
+		Long:    "Allows interaction with the screens of the 3270 terminals used by test runs",
+		Aliases: []string{COMMAND_NAME_RUNS_TERMINALS},
+		Args:    cobra.NoArgs,
+	}
+
+	runsCommand.CobraCommand().AddCommand(runsTerminalsCobraCmd)
+
+	return runsTerminalsCobraCmd, err
+}
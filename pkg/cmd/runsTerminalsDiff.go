@@ -0,0 +1,198 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"log"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/embedded"
+	"github.com/galasa-dev/cli/pkg/images"
+	"github.com/galasa-dev/cli/pkg/runs"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    runs terminals diff --name U1234 --name U1235
+// And then show which 3270 terminal screens differ between the two runs, and how.
+
+// Variables set by cobra's command-line parsing.
+type RunsTerminalsDiffCmdValues struct {
+	runNames              []string
+	terminalId            string
+	downloadFolderPath    string
+	terminalTheme         string
+	terminalFontSize      float64
+	isShowingHiddenFields bool
+}
+
+type RunsTerminalsDiffCommand struct {
+	values       *RunsTerminalsDiffCmdValues
+	cobraCommand *cobra.Command
+}
+
+func NewRunsTerminalsDiffCommand(factory spi.Factory, runsTerminalsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) (spi.GalasaCommand, error) {
+	cmd := new(RunsTerminalsDiffCommand)
+	err := cmd.init(factory, runsTerminalsCommand, commsFlagSet)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *RunsTerminalsDiffCommand) Name() string {
+	return COMMAND_NAME_RUNS_TERMINALS_DIFF
+}
+
+func (cmd *RunsTerminalsDiffCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *RunsTerminalsDiffCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+
+func (cmd *RunsTerminalsDiffCommand) init(factory spi.Factory, runsTerminalsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) error {
+	var err error
+	cmd.values = &RunsTerminalsDiffCmdValues{}
+	cmd.cobraCommand, err = cmd.createCobraCommand(factory, runsTerminalsCommand, commsFlagSet.Values().(*CommsFlagSetValues))
+	return err
+}
+
+func (cmd *RunsTerminalsDiffCommand) createCobraCommand(
+	factory spi.Factory,
+	runsTerminalsCommand spi.GalasaCommand,
+	commsFlagSetValues *CommsFlagSetValues,
+) (*cobra.Command, error) {
+
+	var err error
+
+	runsTerminalsDiffCobraCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show how the 3270 terminal screens of two test runs differ.",
+		Long: "Pairs up the screens of the 3270 terminals used by two test runs by terminal ID and screen sequence number, " +
+			"and shows which screens are the same, which differ, and which only one of the runs has. " +
+			"A text diff of each pair of screens which differ is shown, and an image of the two screens side by side, " +
+			"with the differences highlighted, is written into a 'terminals-diff-<run-A>-<run-B>' folder in the --destination folder. " +
+			"The first screen which differs is named at the end. " +
+			"If a run has already been downloaded into the --destination folder using 'galasactl runs download', its downloaded terminals are used " +
+			"without contacting the Galasa service. Otherwise the terminals are fetched from the Galasa service.",
+		Args:    cobra.NoArgs,
+		Aliases: []string{COMMAND_NAME_RUNS_TERMINALS_DIFF},
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.executeRunsTerminalsDiff(factory, commsFlagSetValues)
+		},
+	}
+
+	runsTerminalsDiffCobraCmd.Flags().StringSliceVar(&cmd.values.runNames, "name", make([]string, 0),
+		"the name of a test run whose terminal screens should be compared. Use this flag twice, once for each test run. For example: --name U1234 --name U1235."+
+			" If a test has been re-run, the latest attempt is used.")
+	runsTerminalsDiffCobraCmd.Flags().StringVar(&cmd.values.terminalId, "terminal", "", "Optional. Only compare the screens of the terminal with this ID. For example: '--terminal term1'")
+	runsTerminalsDiffCobraCmd.Flags().StringVar(&cmd.values.downloadFolderPath, "destination", ".",
+		"The folder 'galasactl runs download' downloaded the test runs into, and the folder the images of the differences are written into."+
+			" The artifacts of each run are looked for in a sub-folder named after the run.")
+	addTerminalRenderFlags(runsTerminalsDiffCobraCmd.Flags(), &cmd.values.terminalTheme, &cmd.values.terminalFontSize, &cmd.values.isShowingHiddenFields)
+	runsTerminalsDiffCobraCmd.MarkFlagRequired("name")
+
+	runsTerminalsCommand.CobraCommand().AddCommand(runsTerminalsDiffCobraCmd)
+
+	return runsTerminalsDiffCobraCmd, err
+}
+
+func (cmd *RunsTerminalsDiffCommand) executeRunsTerminalsDiff(
+	factory spi.Factory,
+	commsFlagSetValues *CommsFlagSetValues,
+) error {
+
+	var err error
+
+	// Operations on the file system will all be relative to the current folder.
+	fileSystem := factory.GetFileSystem()
+
+	err = utils.CaptureLog(fileSystem, commsFlagSetValues.logFileName)
+	if err == nil {
+		commsFlagSetValues.isCapturingLogs = true
+
+		log.Println("Galasa CLI - Compare the 3270 terminal screens of two test runs")
+
+		var renderOptions *images.RenderOptions
+		renderOptions, err = images.NewRenderOptions(cmd.values.terminalTheme, cmd.values.terminalFontSize, cmd.values.isShowingHiddenFields)
+
+		// Only talk to the Galasa service if one of the runs hasn't been downloaded.
+		// The wrong number of runs is reported without needing the service.
+		var commsClient api.APICommsClient
+		var isServiceNeeded bool
+		if err == nil {
+			isServiceNeeded, err = cmd.isAnyRunNotDownloaded(fileSystem)
+		}
+		if err == nil && isServiceNeeded && len(cmd.values.runNames) == 2 {
+			commsClient, err = cmd.createCommsClient(factory, commsFlagSetValues)
+		}
+
+		if err == nil {
+			renderer := images.NewImageRendererWithOptions(embedded.GetReadOnlyFileSystem(), renderOptions)
+
+			// Call to process the command in a unit-testable way.
+			err = runs.TerminalsDiff(
+				cmd.values.runNames,
+				cmd.values.terminalId,
+				cmd.values.isShowingHiddenFields,
+				cmd.values.downloadFolderPath,
+				fileSystem,
+				renderer,
+				factory.GetTimeService(),
+				factory.GetStdOutConsole(),
+				commsClient,
+			)
+		}
+	}
+
+	log.Printf("executeRunsTerminalsDiff returning %v", err)
+	return err
+}
+
+func (cmd *RunsTerminalsDiffCommand) isAnyRunNotDownloaded(fileSystem spi.FileSystem) (bool, error) {
+	var err error
+	isAnyRunNotDownloaded := false
+
+	for _, runName := range cmd.values.runNames {
+		if err == nil {
+			var isDownloaded bool
+			_, isDownloaded, err = runs.GetDownloadedRunFolder(fileSystem, cmd.values.downloadFolderPath, runName)
+			if !isDownloaded {
+				isAnyRunNotDownloaded = true
+			}
+		}
+	}
+	return isAnyRunNotDownloaded, err
+}
+
+func (cmd *RunsTerminalsDiffCommand) createCommsClient(factory spi.Factory, commsFlagSetValues *CommsFlagSetValues) (api.APICommsClient, error) {
+	var err error
+	var commsClient api.APICommsClient
+
+	// Get the ability to query environment variables.
+	env := factory.GetEnvironment()
+
+	var galasaHome spi.GalasaHome
+	galasaHome, err = utils.NewGalasaHome(factory.GetFileSystem(), env, commsFlagSetValues.CmdParamGalasaHomePath)
+	if err == nil {
+		commsClient, err = api.NewAPICommsClient(
+			commsFlagSetValues.bootstrap,
+			commsFlagSetValues.maxRetries,
+			commsFlagSetValues.retryBackoffSeconds,
+			factory,
+			galasaHome,
+		)
+	}
+	return commsClient, err
+}
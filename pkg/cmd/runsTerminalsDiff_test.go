@@ -0,0 +1,129 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsTerminalsDiffCommandInCommandCollection(t *testing.T) {
+
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	runsTerminalsDiffCommand, err := commands.GetCommand(COMMAND_NAME_RUNS_TERMINALS_DIFF)
+	assert.Nil(t, err)
+
+	assert.Equal(t, COMMAND_NAME_RUNS_TERMINALS_DIFF, runsTerminalsDiffCommand.Name())
+	assert.NotNil(t, runsTerminalsDiffCommand.Values())
+	assert.IsType(t, &RunsTerminalsDiffCmdValues{}, runsTerminalsDiffCommand.Values())
+	assert.NotNil(t, runsTerminalsDiffCommand.CobraCommand())
+}
+
+func TestRunsTerminalsDiffHelpFlagSetCorrectly(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "terminals", "diff", "--help"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("Displays the options for the 'runs terminals diff' command.", "", factory, t)
+}
+
+func TestRunsTerminalsDiffNoNameFlagReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "terminals", "diff"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.NotNil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "Error: required flag(s) \"name\" not set", factory, t)
+}
+
+func TestRunsTerminalsDiffFlagsReturnOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_TERMINALS_DIFF, factory, t)
+
+	var args []string = []string{"runs", "terminals", "diff", "--name", "U123", "--name", "U124", "--terminal", "term2", "--destination", "/downloads"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	values := cmd.Values().(*RunsTerminalsDiffCmdValues)
+	assert.Equal(t, []string{"U123", "U124"}, values.runNames)
+	assert.Equal(t, "term2", values.terminalId)
+	assert.Equal(t, "/downloads", values.downloadFolderPath)
+}
+
+func TestRunsTerminalsDiffWithOneNameReturnsErrorWithoutTheGalasaService(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "terminals", "diff", "--name", "U123"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1285E")
+}
+
+func TestRunsTerminalsDiffOfDownloadedRunsWorksWithoutTheGalasaService(t *testing.T) {
+	// Given...
+	gzContents, err := files.NewOSFileSystem().ReadBinaryFile("../images/testdata/gzipExample/term1-00001.gz")
+	assert.Nil(t, err)
+
+	factory := utils.NewMockFactory()
+	fs := factory.GetFileSystem()
+	fs.MkdirAll("/downloads/U123")
+	fs.MkdirAll("/downloads/U124")
+	fs.WriteBinaryFile("/downloads/U123/zos3270/terminals/term1/term1-00001.gz", gzContents)
+	fs.WriteBinaryFile("/downloads/U124/zos3270/terminals/term1/term1-00001.gz", gzContents)
+
+	var args []string = []string{"runs", "terminals", "diff", "--name", "U123", "--name", "U124", "--destination", "/downloads"}
+
+	// When...
+	err = Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+	checkOutput("GAL2523I: All 10 3270 terminal screens of run 'U124' are the same as those of run 'U123'.", "", factory, t)
+}
+
+func TestRunsTerminalsDiffWithBadThemeReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "terminals", "diff", "--name", "U123", "--name", "U124", "--terminal-theme", "amber"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1289E")
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"log"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/runs"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    runs terminals search --name U1234 "INVALID PASSWORD"
+// And then show the terminal, screen, row and column of every place the text appears.
+
+// Variables set by cobra's command-line parsing.
+type RunsTerminalsSearchCmdValues struct {
+	runName               string
+	terminalId            string
+	isIgnoringCase        bool
+	isShowingHiddenFields bool
+	downloadFolderPath    string
+}
+
+type RunsTerminalsSearchCommand struct {
+	values       *RunsTerminalsSearchCmdValues
+	cobraCommand *cobra.Command
+}
+
+func NewRunsTerminalsSearchCommand(factory spi.Factory, runsTerminalsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) (spi.GalasaCommand, error) {
+	cmd := new(RunsTerminalsSearchCommand)
+	err := cmd.init(factory, runsTerminalsCommand, commsFlagSet)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *RunsTerminalsSearchCommand) Name() string {
+	return COMMAND_NAME_RUNS_TERMINALS_SEARCH
+}
+
+func (cmd *RunsTerminalsSearchCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *RunsTerminalsSearchCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+
+func (cmd *RunsTerminalsSearchCommand) init(factory spi.Factory, runsTerminalsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) error {
+	var err error
+	cmd.values = &RunsTerminalsSearchCmdValues{}
+	cmd.cobraCommand, err = cmd.createCobraCommand(factory, runsTerminalsCommand, commsFlagSet.Values().(*CommsFlagSetValues))
+	return err
+}
+
+func (cmd *RunsTerminalsSearchCommand) createCobraCommand(
+	factory spi.Factory,
+	runsTerminalsCommand spi.GalasaCommand,
+	commsFlagSetValues *CommsFlagSetValues,
+) (*cobra.Command, error) {
+
+	var err error
+
+	runsTerminalsSearchCobraCmd := &cobra.Command{
+		Use:   "search [text]",
+		Short: "Search the 3270 terminal screens of a test run for some text.",
+		Long: "Searches the text on every screen of the 3270 terminals used by a test run, and lists the terminal, screen sequence number, " +
+			"row and column of every place the text was found. Rows and columns count from 0. " +
+			"If the run has already been downloaded into the --destination folder using 'galasactl runs download', the downloaded terminals are searched " +
+			"without contacting the Galasa service. Otherwise the terminals are fetched from the Galasa service.",
+		Args:    cobra.ExactArgs(1),
+		Aliases: []string{COMMAND_NAME_RUNS_TERMINALS_SEARCH},
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.executeRunsTerminalsSearch(factory, args[0], commsFlagSetValues)
+		},
+	}
+
+	runsTerminalsSearchCobraCmd.Flags().StringVar(&cmd.values.runName, "name", "", "the name of the test run whose terminal screens should be searched. If the test has been re-run, the latest attempt is searched.")
+	runsTerminalsSearchCobraCmd.Flags().StringVar(&cmd.values.terminalId, "terminal", "", "Optional. Only search the screens of the terminal with this ID. For example: '--terminal term1'")
+	runsTerminalsSearchCobraCmd.Flags().BoolVar(&cmd.values.isIgnoringCase, "ignore-case", false, "Optional. Find the text whether it is in upper case or lower case.")
+	addTerminalShowHiddenFlag(runsTerminalsSearchCobraCmd.Flags(), &cmd.values.isShowingHiddenFields)
+	runsTerminalsSearchCobraCmd.Flags().StringVar(&cmd.values.downloadFolderPath, "destination", ".",
+		"The folder 'galasactl runs download' downloaded the test run into. The run's artifacts are looked for in a sub-folder named after the run.")
+	runsTerminalsSearchCobraCmd.MarkFlagRequired("name")
+
+	runsTerminalsCommand.CobraCommand().AddCommand(runsTerminalsSearchCobraCmd)
+
+	return runsTerminalsSearchCobraCmd, err
+}
+
+func (cmd *RunsTerminalsSearchCommand) executeRunsTerminalsSearch(
+	factory spi.Factory,
+	searchText string,
+	commsFlagSetValues *CommsFlagSetValues,
+) error {
+
+	var err error
+
+	// Operations on the file system will all be relative to the current folder.
+	fileSystem := factory.GetFileSystem()
+
+	err = utils.CaptureLog(fileSystem, commsFlagSetValues.logFileName)
+	if err == nil {
+		commsFlagSetValues.isCapturingLogs = true
+
+		log.Println("Galasa CLI - Search the 3270 terminal screens of a test run")
+
+		var console = factory.GetStdOutConsole()
+
+		var folderPath string
+		var isDownloaded bool
+		folderPath, isDownloaded, err = runs.GetDownloadedRunFolder(fileSystem, cmd.values.downloadFolderPath, cmd.values.runName)
+		if err == nil {
+			if isDownloaded {
+				// Call to process the command in a unit-testable way.
+				err = runs.SearchDownloadedTerminals(
+					folderPath,
+					cmd.values.runName,
+					searchText,
+					cmd.values.terminalId,
+					cmd.values.isIgnoringCase,
+					cmd.values.isShowingHiddenFields,
+					fileSystem,
+					console,
+				)
+			} else {
+				err = cmd.searchTerminalsFromRestApi(factory, searchText, commsFlagSetValues, console)
+			}
+		}
+	}
+
+	log.Printf("executeRunsTerminalsSearch returning %v", err)
+	return err
+}
+
+func (cmd *RunsTerminalsSearchCommand) searchTerminalsFromRestApi(
+	factory spi.Factory,
+	searchText string,
+	commsFlagSetValues *CommsFlagSetValues,
+	console spi.Console,
+) error {
+	var err error
+
+	// Get the ability to query environment variables.
+	env := factory.GetEnvironment()
+	fileSystem := factory.GetFileSystem()
+
+	var galasaHome spi.GalasaHome
+	galasaHome, err = utils.NewGalasaHome(fileSystem, env, commsFlagSetValues.CmdParamGalasaHomePath)
+	if err == nil {
+
+		var commsClient api.APICommsClient
+		commsClient, err = api.NewAPICommsClient(
+			commsFlagSetValues.bootstrap,
+			commsFlagSetValues.maxRetries,
+			commsFlagSetValues.retryBackoffSeconds,
+			factory,
+			galasaHome,
+		)
+
+		if err == nil {
+			timeService := factory.GetTimeService()
+
+			// Call to process the command in a unit-testable way.
+			err = runs.SearchTerminalsFromRestApi(
+				cmd.values.runName,
+				searchText,
+				cmd.values.terminalId,
+				cmd.values.isIgnoringCase,
+				cmd.values.isShowingHiddenFields,
+				timeService,
+				console,
+				commsClient,
+			)
+		}
+	}
+	return err
+}
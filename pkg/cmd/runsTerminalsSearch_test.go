@@ -0,0 +1,115 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsTerminalsSearchCommandInCommandCollection(t *testing.T) {
+
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	runsTerminalsSearchCommand, err := commands.GetCommand(COMMAND_NAME_RUNS_TERMINALS_SEARCH)
+	assert.Nil(t, err)
+
+	assert.Equal(t, COMMAND_NAME_RUNS_TERMINALS_SEARCH, runsTerminalsSearchCommand.Name())
+	assert.NotNil(t, runsTerminalsSearchCommand.Values())
+	assert.IsType(t, &RunsTerminalsSearchCmdValues{}, runsTerminalsSearchCommand.Values())
+	assert.NotNil(t, runsTerminalsSearchCommand.CobraCommand())
+}
+
+func TestRunsTerminalsSearchHelpFlagSetCorrectly(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "terminals", "search", "--help"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("Displays the options for the 'runs terminals search' command.", "", factory, t)
+}
+
+func TestRunsTerminalsSearchNoNameFlagReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "terminals", "search", "READY"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.NotNil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("", "Error: required flag(s) \"name\" not set", factory, t)
+}
+
+func TestRunsTerminalsSearchNoTextReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_TERMINALS_SEARCH, factory, t)
+
+	var args []string = []string{"runs", "terminals", "search", "--name", "U123"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	checkOutput("", "Error: accepts 1 arg(s), received 0", factory, t)
+}
+
+func TestRunsTerminalsSearchFlagsReturnOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_TERMINALS_SEARCH, factory, t)
+
+	var args []string = []string{"runs", "terminals", "search", "--name", "U123", "--terminal", "term2", "--ignore-case", "--destination", "/downloads", "INVALID PASSWORD"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	values := cmd.Values().(*RunsTerminalsSearchCmdValues)
+	assert.Equal(t, "U123", values.runName)
+	assert.Equal(t, "term2", values.terminalId)
+	assert.True(t, values.isIgnoringCase)
+	assert.Equal(t, "/downloads", values.downloadFolderPath)
+}
+
+func TestRunsTerminalsSearchOfADownloadedRunWorksWithoutTheGalasaService(t *testing.T) {
+	// Given...
+	gzContents, err := files.NewOSFileSystem().ReadBinaryFile("../images/testdata/gzipExample/term1-00001.gz")
+	assert.Nil(t, err)
+
+	factory := utils.NewMockFactory()
+	fs := factory.GetFileSystem()
+	fs.MkdirAll("/downloads/U123")
+	fs.WriteBinaryFile("/downloads/U123/zos3270/terminals/term1/term1-00001.gz", gzContents)
+
+	var args []string = []string{"runs", "terminals", "search", "--name", "U123", "--destination", "/downloads", "LOGOFF"}
+
+	// When...
+	err = Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+	checkOutput("Total matches:8 in 4 of 10 screens", "", factory, t)
+}
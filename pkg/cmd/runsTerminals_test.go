@@ -0,0 +1,58 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsTerminalsCommandInCommandCollection(t *testing.T) {
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	runsTerminalsCommand, err := commands.GetCommand(COMMAND_NAME_RUNS_TERMINALS)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, runsTerminalsCommand)
+	assert.Equal(t, COMMAND_NAME_RUNS_TERMINALS, runsTerminalsCommand.Name())
+	assert.NotNil(t, runsTerminalsCommand.Values())
+	assert.IsType(t, &RunsTerminalsCmdValues{}, runsTerminalsCommand.Values())
+	assert.NotNil(t, runsTerminalsCommand.CobraCommand())
+}
+
+func TestRunsTerminalsHelpFlagSetCorrectly(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "terminals", "--help"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	// Check what the user saw is reasonable.
+	checkOutput("Displays the options for the 'runs terminals' command.", "", factory, t)
+
+	assert.Nil(t, err)
+}
+
+func TestRunsTerminalsNoCommandsProducesUsageReport(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	var args []string = []string{"runs", "terminals"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("Usage:\n  galasactl runs terminals [command]", "", factory, t)
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"log"
+
+	"github.com/galasa-dev/cli/pkg/runs"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Objective: Allow the user to do this:
+//    runs view ./U1234
+//    runs view --name U1234
+// And then browse the downloaded test run in a web browser, without needing the Galasa service.
+
+// Variables set by cobra's command-line parsing.
+type RunsViewCmdValues struct {
+	folderPath         string
+	runName            string
+	downloadFolderPath string
+	port               int
+}
+
+type RunsViewCommand struct {
+	values       *RunsViewCmdValues
+	cobraCommand *cobra.Command
+}
+
+func NewRunsViewCommand(factory spi.Factory, runsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) (spi.GalasaCommand, error) {
+	cmd := new(RunsViewCommand)
+	err := cmd.init(factory, runsCommand, commsFlagSet)
+	return cmd, err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Public methods
+// ------------------------------------------------------------------------------------------------
+func (cmd *RunsViewCommand) Name() string {
+	return COMMAND_NAME_RUNS_VIEW
+}
+
+func (cmd *RunsViewCommand) CobraCommand() *cobra.Command {
+	return cmd.cobraCommand
+}
+
+func (cmd *RunsViewCommand) Values() interface{} {
+	return cmd.values
+}
+
+// ------------------------------------------------------------------------------------------------
+// Private methods
+// ------------------------------------------------------------------------------------------------
+
+func (cmd *RunsViewCommand) init(factory spi.Factory, runsCommand spi.GalasaCommand, commsFlagSet GalasaFlagSet) error {
+	var err error
+	cmd.values = &RunsViewCmdValues{}
+	cmd.cobraCommand, err = cmd.createCobraCommand(factory, runsCommand, commsFlagSet.Values().(*CommsFlagSetValues))
+	return err
+}
+
+func (cmd *RunsViewCommand) createCobraCommand(
+	factory spi.Factory,
+	runsCommand spi.GalasaCommand,
+	commsFlagSetValues *CommsFlagSetValues,
+) (*cobra.Command, error) {
+
+	var err error
+
+	runsViewCobraCmd := &cobra.Command{
+		Use:   "view [folder]",
+		Short: "Browse a downloaded test run in a web browser.",
+		Long: "Starts a web server on localhost which shows a test run that was downloaded using 'runs download'." +
+			" It shows the structure of the run and the results of its methods, a searchable run log," +
+			" a slideshow of the 3270 terminal screens in the order they were seen, and all of the run's artifacts." +
+			" Only the downloaded files are read, so no connection to the Galasa service is needed." +
+			" Say which run to view either by giving the folder it was downloaded to, or by using the '--name' flag." +
+			" The server runs until it is stopped with Ctrl+C.",
+		Args:    cobra.MaximumNArgs(1),
+		Aliases: []string{"runs view"},
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				cmd.values.folderPath = args[0]
+			}
+			return cmd.executeRunsView(factory, commsFlagSetValues)
+		},
+	}
+
+	runsViewCobraCmd.Flags().StringVar(&cmd.values.runName, "name", "",
+		"the name of a test run to view, instead of giving its folder. The run is looked for in the folder named after it inside the --destination folder.")
+	runsViewCobraCmd.Flags().StringVar(&cmd.values.downloadFolderPath, "destination", ".",
+		"the folder the test run was downloaded into using 'runs download --destination'. Only used with --name.")
+	runsViewCobraCmd.Flags().IntVar(&cmd.values.port, "port", 0,
+		"the localhost port to serve the viewer on. Defaults to 0, which uses any free port.")
+
+	runsCommand.CobraCommand().AddCommand(runsViewCobraCmd)
+
+	return runsViewCobraCmd, err
+}
+
+func (cmd *RunsViewCommand) executeRunsView(
+	factory spi.Factory,
+	commsFlagSetValues *CommsFlagSetValues,
+) error {
+
+	var err error
+
+	// Operations on the file system will all be relative to the current folder.
+	fileSystem := factory.GetFileSystem()
+
+	err = utils.CaptureLog(fileSystem, commsFlagSetValues.logFileName)
+	if err == nil {
+		commsFlagSetValues.isCapturingLogs = true
+
+		log.Println("Galasa CLI - View a downloaded test run")
+
+		var console = factory.GetStdOutConsole()
+
+		// Call to process the command in a unit-testable way.
+		err = runs.RunsView(
+			cmd.values.folderPath,
+			cmd.values.runName,
+			cmd.values.downloadFolderPath,
+			cmd.values.port,
+			fileSystem,
+			console,
+		)
+	}
+
+	log.Printf("executeRunsView returning %v", err)
+	return err
+}
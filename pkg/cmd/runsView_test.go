@@ -0,0 +1,111 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsViewCommandInCommandCollection(t *testing.T) {
+
+	factory := utils.NewMockFactory()
+	commands, _ := NewCommandCollection(factory)
+
+	runsViewCommand, err := commands.GetCommand(COMMAND_NAME_RUNS_VIEW)
+	assert.Nil(t, err)
+
+	assert.Equal(t, COMMAND_NAME_RUNS_VIEW, runsViewCommand.Name())
+	assert.NotNil(t, runsViewCommand.Values())
+	assert.IsType(t, &RunsViewCmdValues{}, runsViewCommand.Values())
+	assert.NotNil(t, runsViewCommand.CobraCommand())
+}
+
+func TestRunsViewHelpFlagSetCorrectly(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "view", "--help"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	// Check what the user saw is reasonable.
+	checkOutput("Displays the options for the 'runs view' command.", "", factory, t)
+}
+
+func TestRunsViewNameFlagReturnsOkWithDefaults(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_VIEW, factory, t)
+
+	var args []string = []string{"runs", "view", "--name", "U123"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	checkOutput("", "", factory, t)
+
+	values := cmd.Values().(*RunsViewCmdValues)
+	assert.Equal(t, "U123", values.runName)
+	assert.Equal(t, ".", values.downloadFolderPath)
+	assert.Equal(t, 0, values.port)
+}
+
+func TestRunsViewDestinationAndPortFlagsReturnOk(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, cmd := setupTestCommandCollection(COMMAND_NAME_RUNS_VIEW, factory, t)
+
+	var args []string = []string{"runs", "view", "--name", "U123", "--destination", "/downloads", "--port", "8080"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.Nil(t, err)
+
+	values := cmd.Values().(*RunsViewCmdValues)
+	assert.Equal(t, "/downloads", values.downloadFolderPath)
+	assert.Equal(t, 8080, values.port)
+}
+
+func TestRunsViewTwoFoldersReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+	commandCollection, _ := setupTestCommandCollection(COMMAND_NAME_RUNS_VIEW, factory, t)
+
+	var args []string = []string{"runs", "view", "U123", "U124"}
+
+	// When...
+	err := commandCollection.Execute(args)
+
+	// Then...
+	assert.NotNil(t, err)
+	checkOutput("", "Error: accepts at most 1 arg(s), received 2", factory, t)
+}
+
+func TestRunsViewFolderWhichWasNotDownloadedReturnsError(t *testing.T) {
+	// Given...
+	factory := utils.NewMockFactory()
+
+	var args []string = []string{"runs", "view", "/downloads/U123"}
+
+	// When...
+	err := Execute(factory, args)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1274E")
+}
@@ -8,6 +8,7 @@ package embedded
 import (
 	"embed"
 	"log"
+	"sync"
 
 	"github.com/galasa-dev/cli/pkg/props"
 	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
@@ -18,6 +19,7 @@ const (
 	PROPERTY_NAME_GALASA_BOOT_JAR_VERSION    = "galasa.boot.jar.version"
 	PROPERTY_NAME_GALASA_FRAMEWORK_VERSION   = "galasa.framework.version"
 	PROPERTY_NAME_GALASACTL_REST_API_VERSION = "galasactl.rest.api.version"
+	PROPERTY_NAME_JACOCO_VERSION             = "jacoco.version"
 )
 
 // Embed all the template files into the go executable, so there are no extra files
@@ -31,24 +33,28 @@ var embeddedFileSystem embed.FS
 // An instance of the ReadOnlyFileSystem interface, set once, used many times.
 // It just delegates to teh embed.FS
 var readOnlyFileSystem ReadOnlyFileSystem
+var readOnlyFileSystemOnce sync.Once
 
 type versions struct {
 	galasaFrameworkVersion  string
 	galasaBootJarVersion    string
 	galasactlVersion        string
 	galasactlRestApiVersion string
+	jacocoVersion           string
 }
 
 var (
 	versionsCache *versions = nil
 	PropsFileName           = "templates/version/build.properties"
+
+	// Commands such as 'runs download' talk to the server from several goroutines at once.
+	versionsCacheMutex sync.Mutex
 )
 
 func GetGalasaVersion() (string, error) {
 	var err error
-	fs := GetReadOnlyFileSystem()
-	// Note: The cache is set when we read the versions from the embedded file.
-	versionsCache, err = readVersionsFromEmbeddedFile(fs, versionsCache)
+	var versionsCache *versions
+	versionsCache, err = getVersions()
 	var version string
 	if err == nil {
 		version = versionsCache.galasaFrameworkVersion
@@ -58,9 +64,8 @@ func GetGalasaVersion() (string, error) {
 
 func GetBootJarVersion() (string, error) {
 	var err error
-	fs := GetReadOnlyFileSystem()
-	// Note: The cache is set when we read the versions from the embedded file.
-	versionsCache, err = readVersionsFromEmbeddedFile(fs, versionsCache)
+	var versionsCache *versions
+	versionsCache, err = getVersions()
 	var version string
 	if err == nil {
 		version = versionsCache.galasaBootJarVersion
@@ -68,11 +73,23 @@ func GetBootJarVersion() (string, error) {
 	return version, err
 }
 
+// GetJacocoVersion gets the version of the JaCoCo agent and command-line jars which are embedded,
+// to collect and report on the code coverage of local test runs.
+func GetJacocoVersion() (string, error) {
+	var err error
+	var versionsCache *versions
+	versionsCache, err = getVersions()
+	var version string
+	if err == nil {
+		version = versionsCache.jacocoVersion
+	}
+	return version, err
+}
+
 func GetGalasaCtlVersion() (string, error) {
-	fs := GetReadOnlyFileSystem()
 	var err error
-	// Note: The cache is set when we read the versions from the embedded file.
-	versionsCache, err = readVersionsFromEmbeddedFile(fs, versionsCache)
+	var versionsCache *versions
+	versionsCache, err = getVersions()
 	var version string
 	if err == nil {
 		version = versionsCache.galasactlVersion
@@ -82,9 +99,8 @@ func GetGalasaCtlVersion() (string, error) {
 
 func GetGalasactlRestApiVersion() (string, error) {
 	var err error
-	fs := GetReadOnlyFileSystem()
-	// Note: The cache is set when we read the versions from the embedded file.
-	versionsCache, err = readVersionsFromEmbeddedFile(fs, versionsCache)
+	var versionsCache *versions
+	versionsCache, err = getVersions()
 	var version string
 	if err == nil {
 		version = versionsCache.galasactlRestApiVersion
@@ -96,12 +112,24 @@ func GetGalasactlRestApiVersion() (string, error) {
 }
 
 func GetReadOnlyFileSystem() ReadOnlyFileSystem {
-	if readOnlyFileSystem == nil {
+	readOnlyFileSystemOnce.Do(func() {
 		readOnlyFileSystem = NewReadOnlyFileSystem()
-	}
+	})
 	return readOnlyFileSystem
 }
 
+// getVersions - returns the version data, reading it from the embedded file the first time it is needed.
+func getVersions() (*versions, error) {
+	versionsCacheMutex.Lock()
+	defer versionsCacheMutex.Unlock()
+
+	var err error
+	fs := GetReadOnlyFileSystem()
+	// Note: The cache is set when we read the versions from the embedded file.
+	versionsCache, err = readVersionsFromEmbeddedFile(fs, versionsCache)
+	return versionsCache, err
+}
+
 // readVersionsFromEmbeddedFile - Reads a set of version data from an embedded property file, or returns
 // a set of version data we already know about. So that the version data is only ever read once.
 func readVersionsFromEmbeddedFile(fs ReadOnlyFileSystem, versionDataAlreadyKnown *versions) (*versions, error) {
@@ -122,6 +150,7 @@ func readVersionsFromEmbeddedFile(fs ReadOnlyFileSystem, versionDataAlreadyKnown
 			versionDataAlreadyKnown.galasaFrameworkVersion = properties[PROPERTY_NAME_GALASA_FRAMEWORK_VERSION]
 			versionDataAlreadyKnown.galasactlVersion = properties[PROPERTY_NAME_GALASACTL_VERSION]
 			versionDataAlreadyKnown.galasactlRestApiVersion = properties[PROPERTY_NAME_GALASACTL_REST_API_VERSION]
+			versionDataAlreadyKnown.jacocoVersion = properties[PROPERTY_NAME_JACOCO_VERSION]
 		}
 	}
 	return versionDataAlreadyKnown, err
@@ -17,6 +17,7 @@ func TestCanParseVersionsFromEmbeddedFS(t *testing.T) {
 		"galasa.boot.jar.version=0.1.2\n" +
 		"galasa.framework.version=3.4.5\n" +
 		"galasactl.rest.api.version=0.31.0\n" +
+		"jacoco.version=0.8.12\n" +
 		""
 
 	fs := NewMockReadOnlyFileSystem()
@@ -30,6 +31,7 @@ func TestCanParseVersionsFromEmbeddedFS(t *testing.T) {
 	assert.Equal(t, "3.4.5", versions.galasaFrameworkVersion)
 	assert.Equal(t, "myVersion", versions.galasactlVersion)
 	assert.Equal(t, "0.31.0", versions.galasactlRestApiVersion)
+	assert.Equal(t, "0.8.12", versions.jacocoVersion)
 }
 
 func TestDoesntReReadVersionsFromEmbeddedFSWhenAlreadyKnowAnswers(t *testing.T) {
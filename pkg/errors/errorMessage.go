@@ -436,6 +436,125 @@ var (
 	GALASA_ERROR_DELETE_STREAMS_SERVER_REPORTED_ERROR    = NewMessageType("GAL1245E: Failed to delete stream %s. Unexpected http status code %v received from the server. Error details from the server are: '%s'", 1245, STACK_TRACE_NOT_WANTED)
 	GALASA_ERROR_DELETE_STREAMS_EXPLANATION_NOT_JSON     = NewMessageType("GAL1246E: Failed to delete stream %s. Unexpected http status code %v received from the server. Error details from the server are not in the json format.", 1246, STACK_TRACE_NOT_WANTED)
 
+	// Flaky test analysis errors
+	GALASA_ERROR_FLAKY_AGE_NOT_SPECIFIED      = NewMessageType("GAL1247E: The --age flag must be specified, so that the range of historic test runs to analyse is known. For example '--age 30d'.", 1247, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_INVALID_FLAKY_TOP_COUNT      = NewMessageType("GAL1248E: Invalid '--top' value '%v' provided. The value must be a whole number greater than or equal to 0. 0 means that all flaky tests are listed.", 1248, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_FLAKY_PORTFOLIO_WRITE_FAILED = NewMessageType("GAL1249E: Failed to write a portfolio of flaky tests to file '%s'. Reason: '%s'", 1249, STACK_TRACE_NOT_WANTED)
+
+	// Run log errors
+	GALASA_ERROR_RUN_LOG_RUN_NOT_FOUND     = NewMessageType("GAL1250E: The log of the run named '%s' could not be retrieved because the run was not found by the Galasa service. Try listing runs using 'galasactl runs get' to identify the one you wish to see the log of.", 1250, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_RETRIEVING_RUN_LOG_FAILED = NewMessageType("GAL1251E: Failed to retrieve the log of the run named '%s'. Reason: '%s'", 1251, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_INVALID_RUN_LOG_TAIL      = NewMessageType("GAL1252E: Invalid '--tail' value '%v' provided. The value must be a whole number greater than or equal to 0. 0 means that all lines of the run log are shown.", 1252, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_INVALID_RUN_LOG_GREP      = NewMessageType("GAL1253E: Invalid '--grep' value '%s' provided. The value must be a valid regular expression. Reason: '%s'", 1253, STACK_TRACE_NOT_WANTED)
+
+	// Artifact listing errors
+	GALASA_ERROR_LIST_ARTIFACTS_RUN_NOT_FOUND = NewMessageType("GAL1254E: The artifacts of the run named '%s' could not be listed because the run was not found by the Galasa service. Try listing runs using 'galasactl runs get' to identify the one you wish to list the artifacts of.", 1254, STACK_TRACE_NOT_WANTED)
+
+	// Bulk download errors
+	GALASA_ERROR_INVALID_DOWNLOAD_PARALLEL_COUNT   = NewMessageType("GAL1255E: Invalid '--parallel' value '%v' provided. The value must be a whole number greater than 0.", 1255, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_BULK_DOWNLOAD_FAILED              = NewMessageType("GAL1256E: Failed to download the artifacts of %d out of %d test runs. See the summary above for the reasons.", 1256, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_UNSUPPORTED_ARCHIVE_FORMAT        = NewMessageType("GAL1257E: Unsupported '--archive' file name '%s'. The file name must end with '.tar.gz', '.tgz' or '.zip'.", 1257, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_FAILED_TO_CREATE_ARCHIVE          = NewMessageType("GAL1258E: Failed to create the archive file '%s'. Reason: %s", 1258, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_FAILED_TO_WRITE_DOWNLOAD_MANIFEST = NewMessageType("GAL1259E: Failed to write the download manifest file '%s'. Reason: %s", 1259, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_RESUMING_ARTIFACT_DOWNLOAD_FAILED = NewMessageType("GAL1260E: Failed to resume the download of artifact '%s'. Reason: %s", 1260, STACK_TRACE_WANTED)
+
+	// Bulk delete errors
+	GALASA_ERROR_INVALID_DELETE_PARALLEL_COUNT = NewMessageType("GAL1261E: Invalid '--parallel' value '%v' provided. The value must be a whole number greater than 0.", 1261, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_BULK_DELETE_FAILED            = NewMessageType("GAL1262E: Failed to delete %d out of %d test runs. See the summary above for the reasons.", 1262, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_DELETE_CONFIRMATION_NOT_READ  = NewMessageType("GAL1263E: Could not read the answer to whether %d test runs should be deleted, so nothing was deleted. Use the '--yes' flag to delete the test runs without being asked. Reason: %s", 1263, STACK_TRACE_NOT_WANTED)
+
+	// Bulk cancel and reset errors
+	GALASA_ERROR_NO_RUNS_SELECTED_FOR_STATUS_UPDATE = NewMessageType("GAL1264E: The --name, --group, --requestor or --active flag must be used to identify which test runs to %s."+SEE_COMMAND_REFERENCE, 1264, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_BULK_CANCEL_FAILED                 = NewMessageType("GAL1265E: Failed to cancel %d out of %d test runs. See the summary above for the reasons.", 1265, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_BULK_RESET_FAILED                  = NewMessageType("GAL1266E: Failed to reset %d out of %d test runs. See the summary above for the reasons.", 1266, STACK_TRACE_NOT_WANTED)
+
+	// Rerun errors
+	GALASA_ERROR_RERUN_RUN_NOT_FOUND    = NewMessageType("GAL1267E: The run named '%s' could not be rerun because it was not found by the Galasa service. Try listing runs using 'galasactl runs get' to identify the one you wish to rerun.", 1267, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_RERUN_RUN_NOT_FINISHED = NewMessageType("GAL1268E: The run named '%s' could not be rerun because it has not finished yet. Its status is '%s'.", 1268, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_RERUN_STREAM_UNKNOWN   = NewMessageType("GAL1269E: The test stream used by run '%s' could not be found in its records. Use the '--stream' flag to say which test stream contains the test.", 1269, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_RERUN_OBR_UNKNOWN      = NewMessageType("GAL1270E: The OBR used by run '%s' could not be found in its records. Use the '--obr' flag to say which OBR contains the test.", 1270, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_RERUN_FLAG_NEEDS_LOCAL = NewMessageType("GAL1271E: The '--%s' flag can only be used with the '--local' flag."+SEE_COMMAND_REFERENCE, 1271, STACK_TRACE_NOT_WANTED)
+
+	// Run comparison errors
+	GALASA_ERROR_COMPARE_NEEDS_TWO_RUNS = NewMessageType("GAL1272E: Two test runs are needed for a comparison, but %d were given. Use the '--name' flag twice to say which runs to compare."+SEE_COMMAND_REFERENCE, 1272, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_COMPARE_RUN_NOT_FOUND  = NewMessageType("GAL1273E: The run named '%s' could not be compared because it was not found by the Galasa service. Try listing runs using 'galasactl runs get' to identify the runs you wish to compare.", 1273, STACK_TRACE_NOT_WANTED)
+
+	// Run viewer errors
+	GALASA_ERROR_VIEW_FOLDER_NOT_FOUND     = NewMessageType("GAL1274E: The folder '%s' could not be found. Use 'galasactl runs download' to download the artifacts of a test run, then view the folder they were downloaded to.", 1274, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_VIEW_NEEDS_FOLDER_OR_NAME = NewMessageType("GAL1275E: Say which downloaded test run to view, either by giving the folder it was downloaded to, or by using the '--name' flag, but not both."+SEE_COMMAND_REFERENCE, 1275, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_VIEW_SERVER_FAILED        = NewMessageType("GAL1276E: The test run viewer could not be served on port %d. Reason: %s", 1276, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_VIEW_INVALID_PORT         = NewMessageType("GAL1277E: Invalid '--port' value '%d' provided. The value must be a port number between 0 and 65535. 0 means that any free port is used.", 1277, STACK_TRACE_NOT_WANTED)
+
+	// Terminal animation errors
+	GALASA_ERROR_INVALID_ANIMATION_FORMAT      = NewMessageType("GAL1278E: Unsupported value '%s' for parameter --animation. Supported values are: %s."+SEE_COMMAND_REFERENCE, 1278, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_INVALID_ANIMATION_FRAME_DELAY = NewMessageType("GAL1279E: Invalid '--animation-delay' value '%d' provided. The value must be a number of milliseconds between %d and %d.", 1279, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_ANIMATION_ENCODING_FAILED     = NewMessageType("GAL1280E: Internal Failure. The screens of terminal '%s' could not be encoded into an animation. Reason: %s", 1280, STACK_TRACE_NOT_WANTED)
+
+	// Terminal text errors
+	GALASA_ERROR_INVALID_TERMINAL_TEXT_FORMAT   = NewMessageType("GAL1281E: Unsupported value '%s' for parameter --terminal-text. Supported values are: %s."+SEE_COMMAND_REFERENCE, 1281, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_TERMINALS_SEARCH_RUN_NOT_FOUND = NewMessageType("GAL1282E: The run named '%s' could not be searched because it was not found by the Galasa service. Try listing runs using 'galasactl runs get' to identify the run you wish to search.", 1282, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_TERMINALS_SEARCH_TEXT_BLANK    = NewMessageType("GAL1283E: The text to search for in the 3270 terminal screens is blank. Give the text to search for after the flags, for example: 'galasactl runs terminals search --name U123 \"INVALID PASSWORD\"'.", 1283, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_TERMINALS_SEARCH_READ_FAILED   = NewMessageType("GAL1284E: The 3270 terminal artifact '%s' could not be read. Reason: %s", 1284, STACK_TRACE_NOT_WANTED)
+
+	// Terminal diff errors
+	GALASA_ERROR_TERMINALS_DIFF_NEEDS_TWO_RUNS = NewMessageType("GAL1285E: Two test runs are needed to compare 3270 terminal screens, but %d were given. Use the '--name' flag twice to say which runs to compare."+SEE_COMMAND_REFERENCE, 1285, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_TERMINALS_DIFF_RUN_NOT_FOUND  = NewMessageType("GAL1286E: The 3270 terminal screens of the run named '%s' could not be compared because the run was not found by the Galasa service. Try listing runs using 'galasactl runs get' to identify the runs you wish to compare.", 1286, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_TERMINALS_DIFF_NO_SCREENS     = NewMessageType("GAL1287E: Neither run '%s' nor run '%s' has any 3270 terminal screens to compare.", 1287, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_TERMINALS_DIFF_IMAGE_FAILED   = NewMessageType("GAL1288E: The image showing how screen '%s' differs between the runs could not be written to '%s'. Reason: %s", 1288, STACK_TRACE_NOT_WANTED)
+
+	// Terminal rendering errors
+	GALASA_ERROR_INVALID_TERMINAL_THEME     = NewMessageType("GAL1289E: Unsupported value '%s' for parameter --terminal-theme. Supported values are: %s."+SEE_COMMAND_REFERENCE, 1289, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_INVALID_TERMINAL_FONT_SIZE = NewMessageType("GAL1290E: Unsupported value '%v' for parameter --terminal-font-size. The font size must be between %d and %d points."+SEE_COMMAND_REFERENCE, 1290, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_INVALID_RENDER_CONCURRENCY = NewMessageType("GAL1291E: Invalid '--render-concurrency' value '%v' provided. The value must be a whole number greater than 0.", 1291, STACK_TRACE_NOT_WANTED)
+
+	// Test catalogs of local OBRs
+	GALASA_ERROR_OBR_FLAG_REQUIRED            = NewMessageType("GAL1292E: Invalid flags. --bundle, --package, --test, --tag, and --class flags can only be specified if --obr is provided."+SEE_COMMAND_REFERENCE, 1292, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_OBR_TEST_CATALOG_NOT_FOUND   = NewMessageType("GAL1293E: The test catalog of OBR '%s' was not found in the local Maven repository '%s' or the remote Maven repository '%s'. The test catalog is published next to the OBR, as '%s', when the OBR is built using the Galasa Maven plugin.", 1293, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_OBR_TEST_CATALOG_READ_FAILED = NewMessageType("GAL1294E: The test catalog '%s' of OBR '%s' could not be read. Reason: %s", 1294, STACK_TRACE_NOT_WANTED)
+
+	// JVM configuration of local runs
+	GALASA_ERROR_INVALID_JVM_KEY_VALUE        = NewMessageType("GAL1295E: Invalid value '%s' for the --%s flag. It must be of the form key=value, where the key is not blank.", 1295, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_JVM_SYSTEM_PROPERTY_RESERVED = NewMessageType("GAL1296E: The '%s' system property can not be set using the --jvm-system-property flag, as galasactl sets it for every local test run.", 1296, STACK_TRACE_NOT_WANTED)
+
+	// Profiling of local runs
+	GALASA_ERROR_INVALID_PROFILER       = NewMessageType("GAL1297E: Unsupported value '%s' for the --profile flag. It must be 'jfr', or 'jfr:' followed by the Java Flight Recorder settings to use, such as 'jfr:default' or 'jfr:/path/to/my.jfc'.", 1297, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_PROFILE_NOT_SAVED      = NewMessageType("GAL1298E: The Java Flight Recorder recording of run '%s' could not be saved to '%s'. Reason: %s", 1298, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_PROFILE_NOT_SUMMARISED = NewMessageType("GAL1299E: The Java Flight Recorder recording '%s' could not be summarised. Reason: %s", 1299, STACK_TRACE_NOT_WANTED)
+
+	// Code coverage of local runs
+	GALASA_ERROR_COVERAGE_NOT_SAVED        = NewMessageType("GAL1300E: The code coverage of run '%s' could not be saved to '%s'. Reason: %s", 1300, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_JACOCO_JAR_NOT_INSTALLED  = NewMessageType("GAL1301E: The JaCoCo jar '%s' could not be unpacked into the Galasa home folder. Reason: %s", 1301, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_COVERAGE_OF_RUN_NOT_FOUND = NewMessageType("GAL1302E: No code coverage was found for run '%s'. Expected to find it in '%s'. The code coverage of a local run is only collected if it was submitted using the --coverage flag.", 1302, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_NO_COVERAGE_FOUND         = NewMessageType("GAL1303E: No code coverage was found for any run in the RAS folder '%s'. The code coverage of a local run is only collected if it was submitted using the --coverage flag.", 1303, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_COVERAGE_REPORT_FAILED    = NewMessageType("GAL1304E: The code coverage report could not be created. Reason: %s", 1304, STACK_TRACE_NOT_WANTED)
+
+	// Output of the JVMs of local runs
+	GALASA_ERROR_JVM_OUTPUT_NOT_SAVED                = NewMessageType("GAL1305E: The output of the JVM of run '%s' could not be saved to '%s'. Reason: %s", 1305, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_INVALID_OUTPUT_FILTER               = NewMessageType("GAL1306E: Invalid value '%s' for the --output-filter flag. It must be a valid regular expression. Reason: %s", 1306, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_OUTPUT_FILTER_WITHOUT_STREAM_OUTPUT = NewMessageType("GAL1307E: The --output-filter flag can only be used with the --stream-output flag."+SEE_COMMAND_REFERENCE, 1307, STACK_TRACE_NOT_WANTED)
+
+	// Local runs which hang
+	GALASA_ERROR_INVALID_WATCHDOG_LIMIT = NewMessageType("GAL1308E: Invalid value '%d' for the --%s flag. It must be a number of minutes, or 0 for no limit.", 1308, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_THREAD_DUMP_NOT_SAVED  = NewMessageType("GAL1309E: A thread dump of the JVM of run '%s' could not be saved to '%s'. Reason: %s", 1309, STACK_TRACE_NOT_WANTED)
+
+	// Browsing the results of local runs
+	GALASA_ERROR_LOCAL_RAS_FOLDER_NOT_FOUND = NewMessageType("GAL1310E: The RAS folder '%s' does not exist. Local test runs save their results into the 'ras' folder of the Galasa home folder. Use the --ras flag to browse a different RAS folder.", 1310, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_LOCAL_RUN_NOT_FOUND        = NewMessageType("GAL1311E: No local test run named '%s' was found in the RAS folder '%s'.", 1311, STACK_TRACE_NOT_WANTED)
+
+	// Pruning the results of local runs
+	GALASA_ERROR_INVALID_PRUNE_OLDER_THAN = NewMessageType("GAL1312E: Invalid value '%s' for '%s'. It must be an age made up of a positive integer and a time-unit qualifier, such as '14d'. Supported time-units are %s.", 1312, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_INVALID_PRUNE_KEEP_LAST  = NewMessageType("GAL1313E: Invalid value '%s' for '%s'. It must be the number of the most recent local test runs to keep, which is 1 or more.", 1313, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_INVALID_PRUNE_MAX_SIZE   = NewMessageType("GAL1314E: Invalid value '%s' for '%s'. It must be a positive number followed by a unit of B, KB, MB, GB or TB, such as '5GB'.", 1314, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_LOCAL_RUN_SIZE_NOT_READ  = NewMessageType("GAL1315E: The size of local test run '%s' could not be found. Reason: %s", 1315, STACK_TRACE_NOT_WANTED)
+
+	// Prefetching the OBRs and bundles of local runs
+	GALASA_ERROR_MAVEN_ARTIFACT_NOT_FOUND       = NewMessageType("GAL1316E: %d Maven artifacts were not found in the local Maven repository '%s' or the remote Maven repository '%s': %s", 1316, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_MAVEN_ARTIFACT_DOWNLOAD_FAILED = NewMessageType("GAL1317E: The Maven artifact '%s' could not be downloaded from '%s'. Reason: %s", 1317, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_MAVEN_ARTIFACT_CHECKSUM_BAD    = NewMessageType("GAL1318E: The Maven artifact '%s' downloaded from '%s' is corrupt. Its SHA-1 checksum is '%s', but the checksum published with it is '%s'.", 1318, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_MAVEN_ARTIFACT_NOT_SAVED       = NewMessageType("GAL1319E: The Maven artifact '%s' could not be saved into the local Maven repository as '%s'. Reason: %s", 1319, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_OBR_NOT_READ                   = NewMessageType("GAL1320E: The bundles which OBR '%s' refers to could not be read from '%s'. Reason: %s", 1320, STACK_TRACE_NOT_WANTED)
+	GALASA_ERROR_OFFLINE_ARTIFACTS_MISSING      = NewMessageType("GAL1321E: The tests can't be run with --offline, as %d of the OBRs and bundles they need are missing from the local Maven repository '%s': %s. Use 'galasactl local prefetch' with the same --obr, --galasaVersion and --localMaven flags to download them first.", 1321, STACK_TRACE_NOT_WANTED)
+
 	// When getting multiple monitors...
 	GALASA_ERROR_GET_MONITORS_REQUEST_FAILED           = NewMessageType("GAL1218E: Failed to get monitors. Sending the get request to the Galasa service failed. Cause is %v", 1218, STACK_TRACE_NOT_WANTED)
 	GALASA_ERROR_GET_MONITORS_NO_RESPONSE_CONTENT      = NewMessageType("GAL1219E: Failed to get monitors. Unexpected http status code %v received from the server.", 1219, STACK_TRACE_NOT_WANTED)
@@ -461,7 +580,38 @@ var (
 	GALASA_WARNING_MAVEN_NO_GALASA_OBR_REPO = NewMessageType("GAL2000W: Warning: Maven configuration file settings.xml should contain a reference to a Galasa repository so that the galasa OBR can be resolved. The official release repository is '%s', and 'pre-release' repository is '%s'", 2000, STACK_TRACE_WANTED)
 
 	// Information messages...
-	GALASA_INFO_FOLDER_DOWNLOADED_TO = NewMessageType("GAL2501I: Downloaded %d artifacts to folder '%s'\n", 2501, STACK_TRACE_NOT_WANTED)
-	GALASA_INFO_RUNS_RESET_SUCCESS   = NewMessageType("GAL2503I: The request to reset run '%s' has been accepted by the server.\n", 2503, STACK_TRACE_NOT_WANTED)
-	GALASA_INFO_RUNS_CANCEL_SUCCESS  = NewMessageType("GAL2504I: The request to cancel run '%s' has been accepted by the server.\n", 2504, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_FOLDER_DOWNLOADED_TO         = NewMessageType("GAL2501I: Downloaded %d artifacts to folder '%s'\n", 2501, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_RUNS_RESET_SUCCESS           = NewMessageType("GAL2503I: The request to reset run '%s' has been accepted by the server.\n", 2503, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_RUNS_CANCEL_SUCCESS          = NewMessageType("GAL2504I: The request to cancel run '%s' has been accepted by the server.\n", 2504, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_NO_FLAKY_TESTS_FOUND         = NewMessageType("GAL2505I: No flaky tests were found in %d finished test runs.\n", 2505, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_NO_ARTIFACTS_MATCHED         = NewMessageType("GAL2506I: None of the %d artifacts of run '%s' matched the --include and --exclude filters, so nothing was downloaded.\n", 2506, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_BULK_DOWNLOAD_STARTING       = NewMessageType("GAL2507I: Downloading the artifacts of %d test runs, %d at a time.\n", 2507, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_BULK_DOWNLOAD_PROGRESS       = NewMessageType("GAL2508I: Progress: %d of %d test runs done, %d failed.\n", 2508, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_BULK_DOWNLOAD_NO_RUNS        = NewMessageType("GAL2509I: No test runs matched the query, so there is nothing to download.\n", 2509, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_ARTIFACTS_ALREADY_DOWNLOADED = NewMessageType("GAL2510I: %d artifacts in folder '%s' were already up to date, so were not downloaded again.\n", 2510, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_ARTIFACTS_RESUMED            = NewMessageType("GAL2511I: Resumed the download of %d partly downloaded artifacts in folder '%s'.\n", 2511, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_ARCHIVE_WRITTEN              = NewMessageType("GAL2512I: Archived %d files into '%s'.\n", 2512, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_BULK_DELETE_NO_RUNS          = NewMessageType("GAL2513I: No test runs matched the query, so there is nothing to delete.\n", 2513, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_BULK_DELETE_DRY_RUN          = NewMessageType("GAL2514I: %d test runs would be deleted. Nothing was deleted, because --dry-run was used.\n", 2514, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_BULK_DELETE_NOT_CONFIRMED    = NewMessageType("GAL2515I: Nothing was deleted.\n", 2515, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_BULK_DELETE_STARTING         = NewMessageType("GAL2516I: Deleting %d test runs, %d at a time.\n", 2516, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_BULK_DELETE_PROGRESS         = NewMessageType("GAL2517I: Progress: %d of %d test runs done, %d failed.\n", 2517, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_BULK_STATUS_UPDATE_NO_RUNS   = NewMessageType("GAL2518I: No active test runs matched the query, so there is nothing to %s.\n", 2518, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_BULK_STATUS_UPDATE_DRY_RUN   = NewMessageType("GAL2519I: %d active test runs would be %s. Nothing was changed, because --dry-run was used.\n", 2519, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_RERUN_STARTING               = NewMessageType("GAL2520I: Rerunning test '%s/%s' from run '%s', which was originally requested by '%s', in group '%s'.\n", 2520, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_VIEW_SERVING                 = NewMessageType("GAL2521I: Viewing test run '%s' from folder '%s' at %s\nPress Ctrl+C to stop.\n", 2521, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_TERMINALS_SEARCH_NO_MATCHES  = NewMessageType("GAL2522I: The text '%s' was not found in any of the %d 3270 terminal screens of run '%s'.\n", 2522, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_TERMINALS_DIFF_SAME          = NewMessageType("GAL2523I: All %d 3270 terminal screens of run '%s' are the same as those of run '%s'.\n", 2523, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_RENDER_PROGRESS              = NewMessageType("GAL2524I: Progress: rendered %d of the %d 3270 terminal files downloaded so far.\n", 2524, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_RENDER_DONE                  = NewMessageType("GAL2525I: Rendered %d images of 3270 terminal screens from %d terminal files, %d at a time.\n", 2525, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_PROFILE_SAVED                = NewMessageType("GAL2526I: The Java Flight Recorder recording of run '%s' was saved to '%s'.\n", 2526, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_COVERAGE_SAVED               = NewMessageType("GAL2527I: The code coverage of run '%s' was saved to '%s'.\n", 2527, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_COVERAGE_REPORTED            = NewMessageType("GAL2528I: The merged code coverage of %d runs was reported to %s.\n", 2528, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_RUN_HUNG                     = NewMessageType("GAL2529I: Run '%s' is hung, as %s. Its JVM was terminated and the run was given the result 'Hung'.\n", 2529, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_THREAD_DUMP_SAVED            = NewMessageType("GAL2530I: A thread dump of the JVM of hung run '%s' was saved to '%s'.\n", 2530, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_PRUNE_NO_RUNS                = NewMessageType("GAL2531I: None of the %d local test runs in the RAS folder '%s' matched the prune policies, so there is nothing to prune.\n", 2531, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_PRUNE_DRY_RUN                = NewMessageType("GAL2532I: %d local test runs using %s would be pruned from the RAS folder '%s'. Nothing was deleted, because --dry-run was used.\n", 2532, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_PRUNE_DONE                   = NewMessageType("GAL2533I: Pruned %d local test runs using %s from the RAS folder '%s'.\n", 2533, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_PREFETCH_DONE                = NewMessageType("GAL2534I: All %d OBRs and bundles needed to run the tests locally are in the local Maven repository '%s'. %d were downloaded, and the checksums of %d were verified.\n", 2534, STACK_TRACE_NOT_WANTED)
+	GALASA_INFO_BULK_DELETE_ACTIVE_SKIPPED   = NewMessageType("GAL2535I: %d test runs which matched the query are still queued or running, so will not be deleted.\n", 2535, STACK_TRACE_NOT_WANTED)
 )
@@ -0,0 +1,191 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package files
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/spi"
+)
+
+const (
+	ARCHIVE_FORMAT_TAR_GZ = "tar.gz"
+	ARCHIVE_FORMAT_ZIP    = "zip"
+)
+
+// GetArchiveFormat works out which kind of archive to create from the file name the user asked for.
+func GetArchiveFormat(archivePath string) (string, error) {
+	var err error
+	var format string
+
+	lowerCaseArchivePath := strings.ToLower(archivePath)
+	if strings.HasSuffix(lowerCaseArchivePath, ".tar.gz") || strings.HasSuffix(lowerCaseArchivePath, ".tgz") {
+		format = ARCHIVE_FORMAT_TAR_GZ
+	} else if strings.HasSuffix(lowerCaseArchivePath, ".zip") {
+		format = ARCHIVE_FORMAT_ZIP
+	} else {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_UNSUPPORTED_ARCHIVE_FORMAT, archivePath)
+	}
+	return format, err
+}
+
+// WriteArchive bundles a set of files into a single .tar.gz or .zip file.
+// Each file is stored in the archive using its path relative to the base folder.
+func WriteArchive(
+	fs spi.FileSystem,
+	archivePath string,
+	baseFolderPath string,
+	filePaths []string,
+	modifiedTime time.Time,
+) error {
+	var err error
+	var format string
+
+	format, err = GetArchiveFormat(archivePath)
+	if err == nil {
+		archiveFolderPath := filepath.Dir(archivePath)
+		if archiveFolderPath != "." {
+			err = fs.MkdirAll(archiveFolderPath)
+		}
+	}
+
+	if err == nil {
+		if format == ARCHIVE_FORMAT_ZIP {
+			err = writeZipArchive(fs, archivePath, baseFolderPath, filePaths, modifiedTime)
+		} else {
+			err = writeTarGzArchive(fs, archivePath, baseFolderPath, filePaths, modifiedTime)
+		}
+	}
+
+	if err != nil {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_FAILED_TO_CREATE_ARCHIVE, archivePath, err.Error())
+	}
+	return err
+}
+
+// The archive is written to the file a bit at a time, and each file is copied into it a bit at a time,
+// so large files don't have to fit in memory.
+func writeTarGzArchive(fs spi.FileSystem, archivePath string, baseFolderPath string, filePaths []string, modifiedTime time.Time) error {
+	var err error
+	var archiveFile io.WriteCloser
+
+	archiveFile, err = fs.Create(archivePath)
+	if err == nil {
+		// The tar is compressed on its way to the file system.
+		gzipWriter := gzip.NewWriter(archiveFile)
+		tarWriter := tar.NewWriter(gzipWriter)
+
+		for _, filePath := range filePaths {
+			var entryName string
+			var size int64
+			entryName, err = getArchiveEntryName(baseFolderPath, filePath)
+			if err == nil {
+				size, err = fs.GetFileSize(filePath)
+			}
+			if err == nil {
+				header := &tar.Header{
+					Name:    entryName,
+					Mode:    0644,
+					Size:    size,
+					ModTime: modifiedTime,
+				}
+				err = tarWriter.WriteHeader(header)
+			}
+			if err == nil {
+				err = copyArchiveEntry(fs, filePath, tarWriter)
+			}
+			if err != nil {
+				break
+			}
+		}
+
+		if err == nil {
+			err = tarWriter.Close()
+		}
+		if err == nil {
+			err = gzipWriter.Close()
+		}
+		err = closeArchiveFile(fs, archivePath, archiveFile, err)
+	}
+	return err
+}
+
+func writeZipArchive(fs spi.FileSystem, archivePath string, baseFolderPath string, filePaths []string, modifiedTime time.Time) error {
+	var err error
+	var archiveFile io.WriteCloser
+
+	archiveFile, err = fs.Create(archivePath)
+	if err == nil {
+		zipWriter := zip.NewWriter(archiveFile)
+
+		for _, filePath := range filePaths {
+			var entryName string
+			entryName, err = getArchiveEntryName(baseFolderPath, filePath)
+			if err == nil {
+				header := &zip.FileHeader{
+					Name:     entryName,
+					Method:   zip.Deflate,
+					Modified: modifiedTime,
+				}
+				var entryWriter io.Writer
+				entryWriter, err = zipWriter.CreateHeader(header)
+				if err == nil {
+					err = copyArchiveEntry(fs, filePath, entryWriter)
+				}
+			}
+			if err != nil {
+				break
+			}
+		}
+
+		if err == nil {
+			err = zipWriter.Close()
+		}
+		err = closeArchiveFile(fs, archivePath, archiveFile, err)
+	}
+	return err
+}
+
+// Archives always use '/' between folder names, whatever the local file system uses.
+func getArchiveEntryName(baseFolderPath string, filePath string) (string, error) {
+	entryName, err := filepath.Rel(baseFolderPath, filePath)
+	if err == nil {
+		entryName = filepath.ToSlash(entryName)
+	}
+	return entryName, err
+}
+
+func copyArchiveEntry(fs spi.FileSystem, filePath string, entryWriter io.Writer) error {
+	entryReader, err := fs.Open(filePath)
+	if err == nil {
+		_, err = io.Copy(entryWriter, entryReader)
+		closeErr := entryReader.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// The archive file is always closed. If it couldn't be written completely, what was written is deleted,
+// so a broken archive isn't left behind.
+func closeArchiveFile(fs spi.FileSystem, archivePath string, archiveFile io.WriteCloser, err error) error {
+	closeErr := archiveFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fs.DeleteFile(archivePath)
+	}
+	return err
+}
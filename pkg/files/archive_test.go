@@ -0,0 +1,136 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package files
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetArchiveFormatFromFileName(t *testing.T) {
+	format, err := GetArchiveFormat("out.tar.gz")
+	assert.Nil(t, err)
+	assert.Equal(t, ARCHIVE_FORMAT_TAR_GZ, format)
+
+	format, err = GetArchiveFormat("OUT.TGZ")
+	assert.Nil(t, err)
+	assert.Equal(t, ARCHIVE_FORMAT_TAR_GZ, format)
+
+	format, err = GetArchiveFormat("defects/C1234.zip")
+	assert.Nil(t, err)
+	assert.Equal(t, ARCHIVE_FORMAT_ZIP, format)
+}
+
+func TestGetArchiveFormatWithUnknownSuffixReturnsError(t *testing.T) {
+	_, err := GetArchiveFormat("out.rar")
+
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1257E")
+	assert.ErrorContains(t, err, "'out.rar'")
+}
+
+func TestCanWriteATarGzArchiveOfFilesRelativeToAFolder(t *testing.T) {
+	// Given...
+	fs := NewMockFileSystem()
+	fs.WriteTextFile("/downloads/U27/run.log", "the log")
+	fs.WriteTextFile("/downloads/U27/framework/cps_record.properties", "a=b")
+
+	// When...
+	err := WriteArchive(fs, "/defects/U27.tar.gz", "/downloads",
+		[]string{"/downloads/U27/framework/cps_record.properties", "/downloads/U27/run.log"}, time.Unix(1700000000, 0))
+
+	// Then...
+	assert.Nil(t, err)
+
+	archiveBytes, err := NewGzipFile(fs, "/defects/U27.tar.gz").ReadBytes()
+	assert.Nil(t, err)
+
+	contents := make(map[string]string)
+	tarReader := tar.NewReader(bytes.NewReader(archiveBytes))
+	for {
+		header, tarErr := tarReader.Next()
+		if tarErr != nil {
+			assert.Equal(t, io.EOF, tarErr)
+			break
+		}
+		content, _ := io.ReadAll(tarReader)
+		contents[header.Name] = string(content)
+	}
+
+	assert.Equal(t, map[string]string{
+		"U27/framework/cps_record.properties": "a=b",
+		"U27/run.log":                         "the log",
+	}, contents)
+}
+
+func TestCanWriteAZipArchive(t *testing.T) {
+	// Given...
+	fs := NewMockFileSystem()
+	fs.WriteTextFile("U27/run.log", "the log")
+
+	// When...
+	err := WriteArchive(fs, "U27.zip", ".", []string{"U27/run.log"}, time.Unix(1700000000, 0))
+
+	// Then...
+	assert.Nil(t, err)
+
+	archiveBytes, err := fs.ReadBinaryFile("U27.zip")
+	assert.Nil(t, err)
+
+	zipReader, err := zip.NewReader(bytes.NewReader(archiveBytes), int64(len(archiveBytes)))
+	assert.Nil(t, err)
+	assert.Len(t, zipReader.File, 1)
+	assert.Equal(t, "U27/run.log", zipReader.File[0].Name)
+
+	entryReader, err := zipReader.File[0].Open()
+	assert.Nil(t, err)
+	content, _ := io.ReadAll(entryReader)
+	assert.Equal(t, "the log", string(content))
+}
+
+func TestWriteArchiveOfMissingFileReturnsError(t *testing.T) {
+	// Given...
+	fs := NewMockFileSystem()
+
+	// When...
+	err := WriteArchive(fs, "U27.zip", ".", []string{"U27/missing.log"}, time.Unix(1700000000, 0))
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1258E: Failed to create the archive file 'U27.zip'.")
+
+	isArchiveLeftBehind, _ := fs.Exists("U27.zip")
+	assert.False(t, isArchiveLeftBehind)
+}
+
+func TestWriteArchiveCopiesEachFileWithoutReadingOrWritingWholeFiles(t *testing.T) {
+	// Given...
+	fs := NewOverridableMockFileSystem()
+	fs.WriteTextFile("U27/run.log", "the log")
+	fs.VirtualFunction_ReadBinaryFile = func(filePath string) ([]byte, error) {
+		return nil, errors.New("simulating a file too big to read into memory")
+	}
+	fs.VirtualFunction_WriteBinaryFile = func(targetFilePath string, desiredContents []byte) error {
+		return errors.New("simulating a file too big to write from memory")
+	}
+
+	for _, archivePath := range []string{"U27.tar.gz", "U27.zip"} {
+		// When...
+		err := WriteArchive(fs, archivePath, ".", []string{"U27/run.log"}, time.Unix(1700000000, 0))
+
+		// Then...
+		assert.Nil(t, err)
+		size, _ := fs.GetFileSize(archivePath)
+		assert.Greater(t, size, int64(0))
+	}
+}
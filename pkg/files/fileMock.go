@@ -58,6 +58,10 @@ func (mockFile *MockFile) Close() error {
 // ------------------------------------------------------------------------------------
 
 func (mockFile *MockFile) mockFileWrite(data []byte) (int, error) {
+	// Files can be written while other goroutines use the file system.
+	mockFile.fileSystem.mutexLock.Lock()
+	defer mockFile.fileSystem.mutexLock.Unlock()
+
 	fileNode := mockFile.fileSystem.data[mockFile.path]
 	fileNode.content = append(fileNode.content, data...)
 
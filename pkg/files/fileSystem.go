@@ -68,6 +68,11 @@ func (osFS *OSFileSystem) Create(path string) (io.WriteCloser, error) {
 	return fileWriter, err
 }
 
+func (osFS *OSFileSystem) Open(path string) (io.ReadCloser, error) {
+	fileReader, err := os.Open(path)
+	return fileReader, err
+}
+
 func (osFS *OSFileSystem) GetFilePathSeparator() string {
 	return string(os.PathSeparator)
 }
@@ -190,3 +195,28 @@ func (osFS *OSFileSystem) GetAllFilePaths(rootPath string) ([]string, error) {
 		})
 	return collectedFilePaths, err
 }
+
+func (*OSFileSystem) GetSubFolderNames(folderPath string) ([]string, error) {
+	folderNames := make([]string, 0)
+
+	entries, err := os.ReadDir(folderPath)
+	if err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				folderNames = append(folderNames, entry.Name())
+			}
+		}
+	}
+	return folderNames, err
+}
+
+func (*OSFileSystem) GetFileSize(filePath string) (int64, error) {
+	var size int64
+	metadata, err := os.Stat(filePath)
+	if err != nil {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_FAILED_TO_READ_FILE, filePath, err.Error())
+	} else {
+		size = metadata.Size()
+	}
+	return size, err
+}
@@ -12,6 +12,7 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -59,6 +60,7 @@ type MockFileSystem struct {
 	VirtualFunction_DeleteDir            func(path string)
 	VirtualFunction_DeleteFile           func(path string)
 	VirtualFunction_Create               func(path string) (io.WriteCloser, error)
+	VirtualFunction_Open                 func(path string) (io.ReadCloser, error)
 }
 
 // NewMockFileSystem creates an implementation of the thin file system layer which delegates
@@ -93,6 +95,10 @@ func NewOverridableMockFileSystem() *MockFileSystem {
 		return mockFSCreate(mockFileSystem, path)
 	}
 
+	mockFileSystem.VirtualFunction_Open = func(path string) (io.ReadCloser, error) {
+		return mockFSOpen(mockFileSystem, path)
+	}
+
 	mockFileSystem.VirtualFunction_MkdirAll = func(targetFolderPath string) error {
 		return mockFSMkdirAll(mockFileSystem, targetFolderPath)
 	}
@@ -154,9 +160,18 @@ func (fs *MockFileSystem) SetExecutableExtension(newExtension string) {
 func (fs *MockFileSystem) Create(path string) (io.WriteCloser, error) {
 	// log.Printf("Create entered")
 	// defer log.Printf("Create exited")
+	fs.mutexLock.Lock()
+	defer fs.mutexLock.Unlock()
 	return fs.VirtualFunction_Create(path)
 }
 
+func (fs *MockFileSystem) Open(path string) (io.ReadCloser, error) {
+	fs.mutexLock.Lock()
+	defer fs.mutexLock.Unlock()
+	// Call the virtual function.
+	return fs.VirtualFunction_Open(path)
+}
+
 func (fs *MockFileSystem) GetFilePathSeparator() string {
 	return fs.filePathSeparator
 }
@@ -276,6 +291,21 @@ func mockFSCreate(fs MockFileSystem, path string) (io.WriteCloser, error) {
 	return writer, nil
 }
 
+// The reader has its own copy of the content, so the file can be written while it is being read.
+func mockFSOpen(fs MockFileSystem, path string) (io.ReadCloser, error) {
+	var reader io.ReadCloser
+	var err error
+	node := fs.data[path]
+	if node == nil || node.isDir {
+		err = os.ErrNotExist
+	} else {
+		content := make([]byte, len(node.content))
+		copy(content, node.content)
+		reader = io.NopCloser(bytes.NewReader(content))
+	}
+	return reader, err
+}
+
 func mockFSDeleteDir(fs MockFileSystem, pathToDelete string) {
 
 	// Figure out which entries we are going to delete.
@@ -406,3 +436,42 @@ func (fs *MockFileSystem) GetAllFilePaths(rootPath string) ([]string, error) {
 
 	return collectedFilePaths, err
 }
+
+func (fs *MockFileSystem) GetSubFolderNames(folderPath string) ([]string, error) {
+	fs.mutexLock.Lock()
+	defer fs.mutexLock.Unlock()
+
+	folderNames := make([]string, 0)
+	folderNamesSeen := make(map[string]bool)
+	folderPrefix := strings.TrimSuffix(folderPath, fs.filePathSeparator) + fs.filePathSeparator
+
+	for path, node := range fs.data {
+		if strings.HasPrefix(path, folderPrefix) {
+			// Anything with more of a path under the folder is within a sub-folder.
+			pathParts := strings.SplitN(strings.TrimPrefix(path, folderPrefix), fs.filePathSeparator, 2)
+			isFolder := node.isDir || len(pathParts) > 1
+			if isFolder && pathParts[0] != "" && !folderNamesSeen[pathParts[0]] {
+				folderNamesSeen[pathParts[0]] = true
+				folderNames = append(folderNames, pathParts[0])
+			}
+		}
+	}
+
+	sort.Strings(folderNames)
+	return folderNames, nil
+}
+
+func (fs *MockFileSystem) GetFileSize(filePath string) (int64, error) {
+	fs.mutexLock.Lock()
+	defer fs.mutexLock.Unlock()
+
+	var size int64
+	var err error
+	node := fs.data[filePath]
+	if node == nil {
+		err = os.ErrNotExist
+	} else {
+		size = int64(len(node.content))
+	}
+	return size, err
+}
@@ -178,3 +178,57 @@ func TestCanGetFilePathsFromDeepFolder(t *testing.T) {
 	assert.Equal(t, textFilePath1, collectedPaths[0])
 	assert.Equal(t, textFilePath2, collectedPaths[1])
 }
+
+func TestCanGetTheSizeOfAFile(t *testing.T) {
+	fs := NewOSFileSystem()
+	tempFolderPath, _ := fs.MkTempDir()
+	defer func() {
+		fs.DeleteDir(tempFolderPath)
+	}()
+	textFilePath := tempFolderPath + fs.GetFilePathSeparator() + "textFile.txt"
+	fs.WriteTextFile(textFilePath, "hello\nworld\n")
+
+	size, err := fs.GetFileSize(textFilePath)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(12), size)
+
+	_, err = fs.GetFileSize(tempFolderPath + fs.GetFilePathSeparator() + "missing.txt")
+	assert.NotNil(t, err)
+}
+
+func TestGetSubFolderNamesOnlyListsTheFoldersAtTheTop(t *testing.T) {
+	// Given...
+	fs := NewOSFileSystem()
+	tempFolderPath, _ := fs.MkTempDir()
+	defer func() {
+		fs.DeleteDir(tempFolderPath)
+	}()
+	separator := fs.GetFilePathSeparator()
+	fs.MkdirAll(tempFolderPath + separator + "L2" + separator + "artifacts")
+	fs.MkdirAll(tempFolderPath + separator + "L1")
+	fs.WriteTextFile(tempFolderPath+separator+"notAFolder.txt", "hello")
+
+	// When...
+	folderNames, err := fs.GetSubFolderNames(tempFolderPath)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"L1", "L2"}, folderNames)
+}
+
+func TestMockGetSubFolderNamesOnlyListsTheFoldersAtTheTop(t *testing.T) {
+	// Given...
+	fs := NewMockFileSystem()
+	fs.MkdirAll("/my/ras/L3")
+	fs.WriteTextFile("/my/ras/L2/artifacts/log.txt", "hello")
+	fs.WriteTextFile("/my/ras/L1/structure.json", "{}")
+	fs.WriteTextFile("/my/ras/notAFolder.txt", "hello")
+	fs.WriteTextFile("/my/rasother/L4/structure.json", "{}")
+
+	// When...
+	folderNames, err := fs.GetSubFolderNames("/my/ras")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"L1", "L2", "L3"}, folderNames)
+}
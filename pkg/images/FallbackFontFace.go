@@ -21,8 +21,8 @@ type FallbackFontFace struct {
 	fallbacks []font.Face
 }
 
-// Loads a .ttf or .otf font from the embedded filesystem
-func loadFont(fs embedded.ReadOnlyFileSystem, fontFilePath string) (font.Face, error) {
+// Loads a .ttf or .otf font from the embedded filesystem, at the given size in points
+func loadFont(fs embedded.ReadOnlyFileSystem, fontFilePath string, fontSize float64) (font.Face, error) {
 	var err error
 	var fontBytes []byte
 	var opentypeFont *opentype.Font
@@ -32,14 +32,15 @@ func loadFont(fs embedded.ReadOnlyFileSystem, fontFilePath string) (font.Face, e
 	if err == nil {
 		opentypeFont, err = opentype.Parse(fontBytes)
 		if err == nil {
-			fontFace, err = opentype.NewFace(opentypeFont, nil)
+			// At 72 dots per inch, one point is one pixel.
+			fontFace, err = opentype.NewFace(opentypeFont, &opentype.FaceOptions{Size: fontSize, DPI: 72, Hinting: font.HintingNone})
 		}
 	}
 	return fontFace, err
 }
 
 // Loads a .ttf or .otf font from the embedded filesystem
-func loadFontsFromDirectory(fileSystem embedded.ReadOnlyFileSystem, fontDirectoryPath string) ([]font.Face, error) {
+func loadFontsFromDirectory(fileSystem embedded.ReadOnlyFileSystem, fontDirectoryPath string, fontSize float64) ([]font.Face, error) {
 	var err error
 	var fontFiles []fs.DirEntry
 	fonts := make([]font.Face, 0)
@@ -53,7 +54,7 @@ func loadFontsFromDirectory(fileSystem embedded.ReadOnlyFileSystem, fontDirector
 			// we need to use the file separator from the file system, which may be different.
 			// eg: The embedded file system in golang uses '/' even when on Windows...
 			fontFilePath := fontDirectoryPath + fileSystem.GetFileSeparator() + fontFile.Name()
-			fontFace, err = loadFont(fileSystem, fontFilePath)
+			fontFace, err = loadFont(fileSystem, fontFilePath, fontSize)
 			if err == nil {
 				fonts = append(fonts, fontFace)
 			}
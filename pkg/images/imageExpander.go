@@ -19,6 +19,42 @@ type ImageExpander interface {
 	ExpandImages(rootFolderPath string) error
 	ExpandImage(pathToFile string) error
 	GetExpandedImageFileCount() int
+	AnimateTerminals(rootFolderPath string) error
+	GetAnimationFileCount() int
+	GetTextFileCount() int
+}
+
+// Files which can be created from the 3270 terminal descriptions, as well as a PNG of each screen.
+type TerminalOutputOptions struct {
+	// nil if no animations are wanted.
+	Animation *AnimationOptions
+	// nil if the text of each screen is not wanted.
+	Text *TerminalTextOptions
+	// nil if the screens are drawn the default way.
+	Render *RenderOptions
+}
+
+// NewTerminalOutputOptions checks the extra files the user asked for, and how the screens should be drawn.
+// nil is returned if nothing other than the default images was asked for.
+func NewTerminalOutputOptions(animationFormat string, frameDelayMillis int, textFormat string, renderOptions *RenderOptions) (*TerminalOutputOptions, error) {
+	var err error
+	var options *TerminalOutputOptions
+	var animationOptions *AnimationOptions
+	var textOptions *TerminalTextOptions
+
+	animationOptions, err = NewAnimationOptions(animationFormat, frameDelayMillis)
+	if err == nil {
+		textOptions, err = NewTerminalTextOptions(textFormat)
+	}
+
+	if textOptions != nil && renderOptions != nil {
+		textOptions.IsShowingHiddenFields = renderOptions.IsShowingHiddenFields
+	}
+
+	if err == nil && (animationOptions != nil || textOptions != nil || renderOptions != nil) {
+		options = &TerminalOutputOptions{Animation: animationOptions, Text: textOptions, Render: renderOptions}
+	}
+	return options, err
 }
 
 // ********** A null implementation. Does nothing. ***********
@@ -38,6 +74,18 @@ func (expander *ImageExpanderNullImpl) ExpandImage(filePath string) error {
 	return nil
 }
 
+func (expander *ImageExpanderNullImpl) AnimateTerminals(rootFolderPath string) error {
+	return nil
+}
+
+func (expander *ImageExpanderNullImpl) GetAnimationFileCount() int {
+	return 0
+}
+
+func (expander *ImageExpanderNullImpl) GetTextFileCount() int {
+	return 0
+}
+
 func NewImageExpanderNullImpl() ImageExpander {
 	expander := new(ImageExpanderNullImpl)
 	expander.expandedFileCounter = 0
@@ -50,17 +98,48 @@ type ImageExpanderImpl struct {
 	renderer                    ImageRenderer
 	expandedFileCounter         int
 	forceOverwriteExistingFiles bool
+
+	// nil if no animations are wanted.
+	animationOptions     *AnimationOptions
+	animationFileCounter int
+	// nil if the text of each screen is not wanted.
+	textExporter    TextExporter
+	textFileCounter int
 }
 
 func NewImageExpander(fs spi.FileSystem, renderer ImageRenderer, forceOverwriteExistingFiles bool) ImageExpander {
+	return NewImageExpanderWithOptions(fs, renderer, forceOverwriteExistingFiles, nil)
+}
+
+// NewImageExpanderWithOptions creates an expander which can also play back the screens of each terminal
+// as a single animated image, and write out the text of each screen. Nothing extra is done if the options are nil.
+// If the renderer is nil, no images are drawn, so only the text of each screen can be written out.
+func NewImageExpanderWithOptions(fs spi.FileSystem, renderer ImageRenderer, forceOverwriteExistingFiles bool, options *TerminalOutputOptions) ImageExpander {
 	expander := new(ImageExpanderImpl)
 	expander.fs = fs
 	expander.renderer = renderer
 	expander.expandedFileCounter = 0
 	expander.forceOverwriteExistingFiles = forceOverwriteExistingFiles
+	expander.animationFileCounter = 0
+	expander.textFileCounter = 0
+
+	if options != nil {
+		expander.animationOptions = options.Animation
+		if options.Text != nil {
+			expander.textExporter = NewTextExporter(*options.Text)
+		}
+	}
 	return expander
 }
 
+func (expander *ImageExpanderImpl) GetAnimationFileCount() int {
+	return expander.animationFileCounter
+}
+
+func (expander *ImageExpanderImpl) GetTextFileCount() int {
+	return expander.textFileCounter
+}
+
 func (expander *ImageExpanderImpl) GetExpandedImageFileCount() int {
 	return expander.expandedFileCounter
 }
@@ -92,6 +171,73 @@ func (expander *ImageExpanderImpl) ExpandImages(rootFolderPath string) error {
 		}
 	}
 
+	if err == nil {
+		err = expander.AnimateTerminals(rootFolderPath)
+	}
+
+	return err
+}
+
+// AnimateTerminals writes one animated image for each terminal whose screens are described by
+// the .gz files in a folder. Nothing is done unless animations were asked for.
+func (expander *ImageExpanderImpl) AnimateTerminals(rootFolderPath string) error {
+	var err error
+
+	if expander.animationOptions != nil {
+		log.Printf("Animating the screens of the 3270 terminals in folder: %s\n", rootFolderPath)
+
+		var paths []string
+		paths, err = expander.fs.GetAllFilePaths(rootFolderPath)
+
+		terminalsByFile := make(map[string]Terminal)
+		imageFolderPathsByFile := make(map[string]string)
+		for _, filePath := range paths {
+			if err == nil && strings.HasSuffix(filePath, ".gz") {
+				var imageFolderPath string
+				imageFolderPath, err = expander.calculateTargetImagePaths(filePath)
+				if err == nil && imageFolderPath != "" {
+					var binaryContent []byte
+					binaryContent, err = files.NewGzipFile(expander.fs, filePath).ReadBytes()
+					if err == nil {
+						terminalsByFile[filePath], err = ConvertJsonBytesToTerminal(binaryContent)
+						imageFolderPathsByFile[filePath] = imageFolderPath
+					}
+				}
+			}
+		}
+
+		if err == nil {
+			for _, terminal := range collectAnimatedTerminals(terminalsByFile, imageFolderPathsByFile) {
+				err = expander.animateTerminal(terminal)
+				if err != nil {
+					break
+				}
+			}
+		}
+	}
+
+	return err
+}
+
+func (expander *ImageExpanderImpl) animateTerminal(terminal *animatedTerminal) error {
+	var err error
+
+	fileName := getAnimationFileName(terminal.id, expander.animationOptions)
+	writer := NewImageFileWriter(expander.fs, terminal.imageFolderPath, expander.forceOverwriteExistingFiles)
+
+	var isWritable bool
+	isWritable, err = writer.IsImageFileWritable(fileName)
+	if err == nil && isWritable && len(terminal.images) > 0 {
+		err = expander.fs.MkdirAll(terminal.imageFolderPath)
+		if err == nil {
+			var animationBytes []byte
+			animationBytes, err = renderAnimation(expander.renderer, terminal, expander.animationOptions)
+			if err == nil {
+				err = writer.WriteImageFile(fileName, animationBytes)
+				expander.animationFileCounter = expander.animationFileCounter + writer.GetImageFilesWrittenCount()
+			}
+		}
+	}
 	return err
 }
 
@@ -115,11 +261,19 @@ func (expander *ImageExpanderImpl) expandGzFile(gzFilePath string) error {
 					log.Printf("Could not read the contents of hte gzip file. cause:%v\n", err)
 				} else {
 
-					writer := NewImageFileWriter(expander.fs, targetImageFolderPath, expander.forceOverwriteExistingFiles)
+					if expander.renderer != nil {
+						writer := NewImageFileWriter(expander.fs, targetImageFolderPath, expander.forceOverwriteExistingFiles)
+
+						err = expander.renderer.RenderJsonBytesToImageFiles(binaryContent, writer)
 
-					err = expander.renderer.RenderJsonBytesToImageFiles(binaryContent, writer)
+						expander.expandedFileCounter = expander.expandedFileCounter + writer.GetImageFilesWrittenCount()
+					}
 
-					expander.expandedFileCounter = expander.expandedFileCounter + writer.GetImageFilesWrittenCount()
+					if err == nil && expander.textExporter != nil {
+						textWriter := NewImageFileWriter(expander.fs, targetImageFolderPath, expander.forceOverwriteExistingFiles)
+						err = expander.textExporter.ExportJsonBytesToTextFiles(binaryContent, textWriter)
+						expander.textFileCounter = expander.textFileCounter + textWriter.GetImageFilesWrittenCount()
+					}
 				}
 			}
 		}
@@ -30,9 +30,6 @@ const (
 )
 
 var (
-	charWidth  int
-	charHeight int
-
 	DEFAULT_COLOR = color.RGBA{0, 255, 0, 255}
 	NEUTRAL       = color.RGBA{255, 255, 255, 255}
 	RED           = color.RGBA{255, 0, 0, 255}
@@ -42,6 +39,11 @@ var (
 	TURQUOISE     = color.RGBA{64, 224, 208, 255}
 	YELLOW        = color.RGBA{255, 255, 0, 255}
 
+	// The banners which mark the frames of an animation as screens received from the host, or sent to it.
+	INBOUND_BANNER_COLOR  = TURQUOISE
+	OUTBOUND_BANNER_COLOR = YELLOW
+	BANNER_TEXT_COLOR     = color.RGBA{0, 0, 0, 255}
+
 	colors = map[string]color.RGBA{
 		"d": DEFAULT_COLOR,
 		"r": RED,
@@ -56,17 +58,34 @@ var (
 
 type ImageRenderer interface {
 	RenderJsonBytesToImageFiles(jsonBinary []byte, writer ImageFileWriter) error
+	RenderAnimationFrame(terminalImage TerminalImage) *image.RGBA
+	RenderScreenDiff(terminalImageA TerminalImage, terminalImageB TerminalImage) *image.RGBA
 }
 
 type ImageRendererImpl struct {
-	drawer font.Drawer
-	fs     embedded.ReadOnlyFileSystem
+	drawer  font.Drawer
+	fs      embedded.ReadOnlyFileSystem
+	options RenderOptions
+
+	// The size of each character cell on the screen, in pixels.
+	charWidth  int
+	charHeight int
 }
 
 func NewImageRenderer(fs embedded.ReadOnlyFileSystem) ImageRenderer {
+	return NewImageRendererWithOptions(fs, nil)
+}
+
+// NewImageRendererWithOptions creates a renderer which draws the screens the way the options say.
+// If the options are nil, the screens are drawn the default way.
+func NewImageRendererWithOptions(fs embedded.ReadOnlyFileSystem, options *RenderOptions) ImageRenderer {
 	renderer := new(ImageRendererImpl)
 
 	renderer.fs = fs
+	renderer.options = NewDefaultRenderOptions()
+	if options != nil {
+		renderer.options = *options
+	}
 	renderer.initRendererFonts()
 
 	return renderer
@@ -75,25 +94,25 @@ func NewImageRenderer(fs embedded.ReadOnlyFileSystem) ImageRenderer {
 // Loads all the fonts to be used in the renderer
 func (renderer *ImageRendererImpl) initRendererFonts() {
 	// Get the primary font to use in the renderer
-	primaryFont := loadPrimaryFont(renderer.fs)
+	primaryFont := loadPrimaryFont(renderer.fs, renderer.options.FontSize)
 
 	fallbackFontFace := NewFallbackFontFace(primaryFont)
-	loadFallbackFonts(renderer.fs, fallbackFontFace)
+	loadFallbackFonts(renderer.fs, fallbackFontFace, renderer.options.FontSize)
 
 	renderer.drawer = font.Drawer{
 		Face: fallbackFontFace,
 	}
 
 	// Determine the height and width of characters in the renderer's primary font
-	charHeight = renderer.drawer.Face.Metrics().Ascent.Ceil()
-	charWidth = renderer.drawer.MeasureString(" ").Round()
+	renderer.charHeight = renderer.drawer.Face.Metrics().Ascent.Ceil()
+	renderer.charWidth = renderer.drawer.MeasureString(" ").Round()
 }
 
 func (renderer *ImageRendererImpl) RenderJsonBytesToImageFiles(jsonBinary []byte, writer ImageFileWriter) error {
 	var err error
 	var terminal Terminal
 
-	terminal, err = convertJsonBytesToTerminal(jsonBinary)
+	terminal, err = ConvertJsonBytesToTerminal(jsonBinary)
 	if err == nil {
 		for _, terminalImage := range terminal.Images {
 
@@ -120,43 +139,193 @@ func (renderer *ImageRendererImpl) RenderJsonBytesToImageFiles(jsonBinary []byte
 	return err
 }
 
-// Renders an RGBA image representation of a 3270 terminal and returns the rendered image
+// Renders an RGBA image representation of a 3270 terminal and returns the rendered image.
+// The backgrounds of all the characters are drawn first, so the characters on one row
+// don't get painted over by the backgrounds of the row below.
 func (renderer *ImageRendererImpl) renderTerminalImage(terminalImage TerminalImage) *image.RGBA {
 	targetColumnCount := terminalImage.ImageSize.Columns
 	targetRowCount := terminalImage.ImageSize.Rows + 3
+	theme := renderer.options.Theme
+
+	imagePixelWidth := targetColumnCount * renderer.charWidth
+	imagePixelHeight := targetRowCount * renderer.charHeight
+	img := image.NewRGBA(image.Rect(0, 0, imagePixelWidth, imagePixelHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(theme.Background), image.Pt(0, 0), draw.Src)
+
+	cells := layOutFieldCharacters(terminalImage)
 
-	imagePixelWidth := targetColumnCount * charWidth
-	imagePixelHeight := targetRowCount * charHeight
-	img := createImageBase(imagePixelWidth, imagePixelHeight)
+	for _, cell := range cells {
+		if backgroundColor, isFilled := renderer.getCellBackgroundColor(cell.field); isFilled {
+			draw.Draw(img, renderer.getCellBounds(cell.row, cell.column), image.NewUniform(backgroundColor), image.Pt(0, 0), draw.Src)
+		}
+	}
+
+	for _, cell := range cells {
+		renderer.drawCell(img, cell)
+	}
+
+	if isOnScreen(terminalImage, terminalImage.CursorRow, terminalImage.CursorColumn) {
+		renderer.drawCursor(img, terminalImage.CursorRow, terminalImage.CursorColumn)
+	}
 
-	for _, field := range terminalImage.Fields {
+	statusText := getStatusText(terminalImage, terminalImage.ImageSize.Columns, terminalImage.ImageSize.Rows)
+	renderer.drawString(img, 0, targetRowCount-2, statusText, theme.Status)
+	return img
+}
+
+// One character of a field, at the place it is shown on the screen.
+type fieldCharacter struct {
+	row    int
+	column int
+	char   rune
+	field  *TerminalField
+}
+
+// Works out where each character of each field goes.
+// Field contents can sometimes span multiple rows, so each character is placed individually,
+// moving onto the next row whenever the image column boundary is reached.
+func layOutFieldCharacters(terminalImage TerminalImage) []fieldCharacter {
+	cells := make([]fieldCharacter, 0)
+	targetColumnCount := terminalImage.ImageSize.Columns
+
+	for fieldIndex := range terminalImage.Fields {
+		field := &terminalImage.Fields[fieldIndex]
 		column := field.Column
 		row := field.Row
 
 		for _, contents := range field.Contents {
-			// Field contents can sometimes span multiple rows, so draw each character individually,
-			// adjusting the current row whenever the image column boundary is reached
 			for _, char := range getCharacters(&contents) {
 
 				if column >= targetColumnCount {
 					column = 0
 					row++
 				}
-				textColor := getColor(field.ForegroundColor)
-				renderer.drawString(img, column, row, string(char), textColor)
+				cells = append(cells, fieldCharacter{row: row, column: column, char: char, field: field})
 				column++
 			}
 		}
 	}
-	statusText := getStatusText(terminalImage, terminalImage.ImageSize.Columns, terminalImage.ImageSize.Rows)
-	renderer.drawString(img, 0, targetRowCount-2, statusText, DEFAULT_COLOR)
-	return img
+	return cells
+}
+
+// A field is hidden if it is neither displayed nor intensified, like a password field.
+// Unformatted screens have no field attributes, so everything on them is shown.
+func isHiddenField(field *TerminalField) bool {
+	return !field.Unformatted && !field.FieldDisplay && !field.FieldIntenseDisplay
+}
+
+func isOnScreen(terminalImage TerminalImage, row int, column int) bool {
+	return row >= 0 && row < terminalImage.ImageSize.Rows && column >= 0 && column < terminalImage.ImageSize.Columns
+}
+
+// The pixels a character cell covers, from the top of the tallest character down to its baseline.
+func (renderer *ImageRendererImpl) getCellBounds(row int, column int) image.Rectangle {
+	return image.Rect(column*renderer.charWidth, row*renderer.charHeight, (column+1)*renderer.charWidth, (row+1)*renderer.charHeight)
+}
+
+// Reverse video swaps the colours of a field, so its background is filled with the colour of its characters.
+func (renderer *ImageRendererImpl) getCellBackgroundColor(field *TerminalField) (color.RGBA, bool) {
+	theme := renderer.options.Theme
+	backgroundColor, isFilled := theme.getFieldBackgroundColor(*field)
+	if field.Highlight == HIGHLIGHT_REVERSE {
+		backgroundColor = theme.getFieldColor(*field)
+		isFilled = true
+	}
+	return backgroundColor, isFilled
+}
+
+func (renderer *ImageRendererImpl) drawCell(img *image.RGBA, cell fieldCharacter) {
+	theme := renderer.options.Theme
+	field := cell.field
+
+	if !isHiddenField(field) || renderer.options.IsShowingHiddenFields {
+		textColor := theme.getFieldColor(*field)
+		if field.Highlight == HIGHLIGHT_REVERSE {
+			textColor = theme.Background
+			if backgroundColor, isFound := theme.getFieldBackgroundColor(*field); isFound {
+				textColor = backgroundColor
+			}
+		}
+
+		renderer.drawString(img, cell.column, cell.row, string(cell.char), textColor)
+		if field.FieldIntenseDisplay {
+			// Intensified characters are drawn a second time, one pixel to the right, to make them bold.
+			renderer.drawStringAt(img, cell.column*renderer.charWidth+1, cell.row, string(cell.char), textColor)
+		}
+
+		// A still image can't blink, so blinking characters are underlined with a dotted line instead.
+		switch field.Highlight {
+		case HIGHLIGHT_UNDERSCORE:
+			renderer.drawUnderline(img, cell.row, cell.column, textColor, 1)
+		case HIGHLIGHT_BLINK:
+			renderer.drawUnderline(img, cell.row, cell.column, textColor, 2)
+		}
+	}
+}
+
+// Draws a line along the baseline of a character cell, with a gap every so many pixels if the line is dotted.
+func (renderer *ImageRendererImpl) drawUnderline(img *image.RGBA, row int, column int, lineColor color.RGBA, pixelsPerDot int) {
+	cellBounds := renderer.getCellBounds(row, column)
+	for x := cellBounds.Min.X; x < cellBounds.Max.X; x += pixelsPerDot {
+		img.SetRGBA(x, cellBounds.Max.Y, lineColor)
+	}
+}
+
+// The cursor is a block over a character cell, with the character showing through in the background colour.
+func (renderer *ImageRendererImpl) drawCursor(img *image.RGBA, row int, column int) {
+	theme := renderer.options.Theme
+	cellBounds := renderer.getCellBounds(row, column).Intersect(img.Bounds())
+
+	for y := cellBounds.Min.Y; y < cellBounds.Max.Y; y++ {
+		for x := cellBounds.Min.X; x < cellBounds.Max.X; x++ {
+			if img.RGBAAt(x, y) == theme.Background {
+				img.SetRGBA(x, y, theme.Cursor)
+			} else {
+				img.SetRGBA(x, y, theme.Background)
+			}
+		}
+	}
+}
+
+// Renders a 3270 terminal image as a frame of an animation. A banner is added above the screen,
+// coloured to show whether the screen was received from the host or sent to it,
+// and naming the key the test pressed to send it.
+func (renderer *ImageRendererImpl) RenderAnimationFrame(terminalImage TerminalImage) *image.RGBA {
+	screen := renderer.renderTerminalImage(terminalImage)
+
+	bannerHeight := renderer.charHeight * 2
+	frame := createImageBase(screen.Bounds().Dx(), screen.Bounds().Dy()+bannerHeight)
+
+	bannerColor := INBOUND_BANNER_COLOR
+	bannerText := "<< Inbound from host"
+	if !terminalImage.Inbound {
+		bannerColor = OUTBOUND_BANNER_COLOR
+		bannerText = ">> Outbound to host: " + terminalImage.Aid
+	}
+	bannerText = fmt.Sprintf("%s  (sequence %d)", bannerText, terminalImage.Sequence)
+
+	draw.Draw(frame, image.Rect(0, 0, frame.Bounds().Dx(), bannerHeight), image.NewUniform(bannerColor), image.Pt(0, 0), draw.Src)
+	draw.Draw(frame, screen.Bounds().Add(image.Pt(0, bannerHeight)), screen, image.Pt(0, 0), draw.Src)
+
+	// The banner text sits in the middle of the banner, so half a row down.
+	drawer := renderer.drawer
+	drawer.Src = image.NewUniform(BANNER_TEXT_COLOR)
+	drawer.Dst = frame
+	drawer.Dot = fixed.Point26_6{X: fixed.I(renderer.charWidth), Y: fixed.I(renderer.charHeight + renderer.charHeight/2)}
+	drawer.DrawString(bannerText)
+
+	return frame
 }
 
 // Draws a string of text onto an image at the given column and row (x, y) coordinates
 func (renderer *ImageRendererImpl) drawString(img *image.RGBA, column int, row int, text string, textColor color.RGBA) {
+	renderer.drawStringAt(img, column*renderer.charWidth, row, text, textColor)
+}
+
+// Draws a string of text onto an image, starting x pixels from the left, on the given row
+func (renderer *ImageRendererImpl) drawStringAt(img *image.RGBA, x int, row int, text string, textColor color.RGBA) {
 	drawer := renderer.drawer
-	startPoint := fixed.Point26_6{X: fixed.I(column * charWidth), Y: fixed.I((row + 1) * charHeight)}
+	startPoint := fixed.Point26_6{X: fixed.I(x), Y: fixed.I((row + 1) * renderer.charHeight)}
 
 	drawer.Src = image.NewUniform(textColor)
 	drawer.Dst = img
@@ -166,7 +335,7 @@ func (renderer *ImageRendererImpl) drawString(img *image.RGBA, column int, row i
 }
 
 // Converts a JSON byte array representing one or more 3270 terminals into a Terminal object
-func convertJsonBytesToTerminal(terminalJsonBytes []byte) (Terminal, error) {
+func ConvertJsonBytesToTerminal(terminalJsonBytes []byte) (Terminal, error) {
 	var terminal Terminal
 	var err error
 
@@ -236,23 +405,14 @@ func getStatusText(terminalImage TerminalImage, columns int, rows int) string {
 	return buff.String()
 }
 
-// Returns a color from the colors map that matches the given single-character identifier
-func getColor(colorIdentifier string) color.RGBA {
-	color := DEFAULT_COLOR
-	if matchedColor, ok := colors[colorIdentifier]; ok {
-		color = matchedColor
-	}
-	return color
-}
-
 // Loads the primary font to use in the renderer, defaulting to the built-in Face7x13 monospaced font
 // if a primary font could not be loaded from the embedded filesystem
-func loadPrimaryFont(fs embedded.ReadOnlyFileSystem) font.Face {
+func loadPrimaryFont(fs embedded.ReadOnlyFileSystem, fontSize float64) font.Face {
 	var err error
 	var loadedFonts []font.Face
 	var primaryFont font.Face
 
-	loadedFonts, err = loadFontsFromDirectory(fs, PRIMARY_FONT_DIRECTORY)
+	loadedFonts, err = loadFontsFromDirectory(fs, PRIMARY_FONT_DIRECTORY, fontSize)
 	if err == nil && len(loadedFonts) > 0 {
 		primaryFont = loadedFonts[0]
 	} else {
@@ -264,12 +424,12 @@ func loadPrimaryFont(fs embedded.ReadOnlyFileSystem) font.Face {
 }
 
 // Loads any fallback fonts to use in the renderer when rendering glyphs that are not contained within the primary font
-func loadFallbackFonts(fs embedded.ReadOnlyFileSystem, fallbackFontFace *FallbackFontFace) {
+func loadFallbackFonts(fs embedded.ReadOnlyFileSystem, fallbackFontFace *FallbackFontFace, fontSize float64) {
 	var err error
 	var loadedFonts []font.Face
 
 	// Add any fallback fonts to use in the renderer
-	loadedFonts, err = loadFontsFromDirectory(fs, FALLBACK_FONT_DIRECTORY)
+	loadedFonts, err = loadFontsFromDirectory(fs, FALLBACK_FONT_DIRECTORY, fontSize)
 	if err == nil {
 		for _, font := range loadedFonts {
 			fallbackFontFace.AddFallbackFont(font)
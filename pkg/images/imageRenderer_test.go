@@ -8,6 +8,8 @@ package images
 import (
 	"encoding/json"
 	"fmt"
+	"image"
+	"image/color"
 	"path/filepath"
 	"strconv"
 	"testing"
@@ -640,3 +642,159 @@ func TestRenderTerminalWithMissingFontDefaultsToMonoFont(t *testing.T) {
 	// Then...
 	assertTerminalImageMatchesExpectedSnapshot(t, imageBytes)
 }
+
+// Returns whether every pixel of a character cell is the given colour.
+func isCellAllOneColor(renderer *ImageRendererImpl, img *image.RGBA, row int, column int, cellColor color.RGBA) bool {
+	isAllOneColor := true
+	cellBounds := renderer.getCellBounds(row, column)
+	for y := cellBounds.Min.Y; y < cellBounds.Max.Y; y++ {
+		for x := cellBounds.Min.X; x < cellBounds.Max.X; x++ {
+			if img.RGBAAt(x, y) != cellColor {
+				isAllOneColor = false
+			}
+		}
+	}
+	return isAllOneColor
+}
+
+// A screen with the cursor off the screen, so it doesn't get in the way.
+func createScreenWithField(field TerminalField) TerminalImage {
+	return TerminalImage{
+		ImageSize:    TerminalSize{Rows: 2, Columns: 10},
+		CursorRow:    -1,
+		CursorColumn: -1,
+		Fields:       []TerminalField{field},
+	}
+}
+
+func TestRenderHiddenFieldIsMaskedByDefault(t *testing.T) {
+	// Given...
+	renderer := NewImageRenderer(embedded.GetReadOnlyFileSystem()).(*ImageRendererImpl)
+	field := createTextField(0, 0, "SECRET", "d")
+	field.FieldDisplay = false
+
+	// When...
+	img := renderer.renderTerminalImage(createScreenWithField(field))
+
+	// Then...
+	assert.True(t, isCellAllOneColor(renderer, img, 0, 0, CLASSIC_THEME.Background))
+}
+
+func TestRenderHiddenFieldCanBeShown(t *testing.T) {
+	// Given...
+	options, err := NewRenderOptions(THEME_CLASSIC, DEFAULT_FONT_SIZE, true)
+	assert.Nil(t, err)
+	renderer := NewImageRendererWithOptions(embedded.GetReadOnlyFileSystem(), options).(*ImageRendererImpl)
+	field := createTextField(0, 0, "SECRET", "d")
+	field.FieldDisplay = false
+
+	// When...
+	img := renderer.renderTerminalImage(createScreenWithField(field))
+
+	// Then...
+	assert.False(t, isCellAllOneColor(renderer, img, 0, 0, CLASSIC_THEME.Background))
+}
+
+func TestRenderUnformattedFieldIsNotHidden(t *testing.T) {
+	renderer := NewImageRenderer(embedded.GetReadOnlyFileSystem()).(*ImageRendererImpl)
+	field := createTextField(0, 0, "READY", "")
+	field.FieldDisplay = false
+	field.Unformatted = true
+
+	img := renderer.renderTerminalImage(createScreenWithField(field))
+
+	assert.False(t, isCellAllOneColor(renderer, img, 0, 0, CLASSIC_THEME.Background))
+}
+
+func TestRenderReverseVideoFillsTheCellsWithTheFieldColor(t *testing.T) {
+	// Given...
+	renderer := NewImageRenderer(embedded.GetReadOnlyFileSystem()).(*ImageRendererImpl)
+	field := createTextField(0, 0, "  ", "r")
+	field.Highlight = HIGHLIGHT_REVERSE
+
+	// When...
+	img := renderer.renderTerminalImage(createScreenWithField(field))
+
+	// Then...
+	assert.True(t, isCellAllOneColor(renderer, img, 0, 0, RED))
+	assert.True(t, isCellAllOneColor(renderer, img, 0, 1, RED))
+	assert.True(t, isCellAllOneColor(renderer, img, 0, 2, CLASSIC_THEME.Background))
+}
+
+func TestRenderBackgroundColorFillsTheCells(t *testing.T) {
+	renderer := NewImageRenderer(embedded.GetReadOnlyFileSystem()).(*ImageRendererImpl)
+	field := createTextField(1, 0, " ", "d")
+	field.BackgroundColor = "b"
+
+	img := renderer.renderTerminalImage(createScreenWithField(field))
+
+	assert.True(t, isCellAllOneColor(renderer, img, 1, 0, BLUE))
+}
+
+func TestRenderUnderscoreDrawsALineUnderTheCharacters(t *testing.T) {
+	renderer := NewImageRenderer(embedded.GetReadOnlyFileSystem()).(*ImageRendererImpl)
+	field := createTextField(0, 0, " ", "y")
+	field.Highlight = HIGHLIGHT_UNDERSCORE
+
+	img := renderer.renderTerminalImage(createScreenWithField(field))
+
+	cellBounds := renderer.getCellBounds(0, 0)
+	assert.Equal(t, YELLOW, img.RGBAAt(cellBounds.Min.X, cellBounds.Max.Y))
+	assert.Equal(t, YELLOW, img.RGBAAt(cellBounds.Max.X-1, cellBounds.Max.Y))
+}
+
+func TestRenderIntenseFieldUsesTheThemesIntenseColor(t *testing.T) {
+	theme := CLASSIC_THEME
+
+	normalField := createTextField(0, 0, "A", "d")
+	intenseField := normalField
+	intenseField.FieldDisplay = false
+	intenseField.FieldIntenseDisplay = true
+
+	assert.Equal(t, theme.Normal, theme.getFieldColor(normalField))
+	assert.Equal(t, theme.Intense, theme.getFieldColor(intenseField))
+
+	// A field which asks for a colour gets it, whether it is intensified or not.
+	intenseField.ForegroundColor = "p"
+	assert.Equal(t, PINK, theme.getFieldColor(intenseField))
+}
+
+func TestRenderCursorDrawsABlockOverItsCell(t *testing.T) {
+	// Given...
+	renderer := NewImageRenderer(embedded.GetReadOnlyFileSystem()).(*ImageRendererImpl)
+	terminalImage := createScreenWithField(createTextField(0, 0, "", "d"))
+	terminalImage.CursorRow = 1
+	terminalImage.CursorColumn = 4
+
+	// When...
+	img := renderer.renderTerminalImage(terminalImage)
+
+	// Then...
+	assert.True(t, isCellAllOneColor(renderer, img, 1, 4, CLASSIC_THEME.Cursor))
+	assert.True(t, isCellAllOneColor(renderer, img, 1, 3, CLASSIC_THEME.Background))
+}
+
+func TestRenderWithLightThemeHasAWhiteBackground(t *testing.T) {
+	options, err := NewRenderOptions("Light", DEFAULT_FONT_SIZE, false)
+	assert.Nil(t, err)
+	renderer := NewImageRendererWithOptions(embedded.GetReadOnlyFileSystem(), options).(*ImageRendererImpl)
+
+	img := renderer.renderTerminalImage(createScreenWithField(createTextField(0, 0, "READY", "d")))
+
+	assert.Equal(t, LIGHT_THEME.Background, img.RGBAAt(img.Bounds().Max.X-1, 0))
+}
+
+func TestRenderWithLargerFontSizeMakesALargerImage(t *testing.T) {
+	options, err := NewRenderOptions(THEME_CLASSIC, 24, false)
+	assert.Nil(t, err)
+	defaultRenderer := NewImageRenderer(embedded.GetReadOnlyFileSystem()).(*ImageRendererImpl)
+	largeRenderer := NewImageRendererWithOptions(embedded.GetReadOnlyFileSystem(), options).(*ImageRendererImpl)
+
+	terminalImage := createScreenWithField(createTextField(0, 0, "READY", "d"))
+	defaultImage := defaultRenderer.renderTerminalImage(terminalImage)
+	largeImage := largeRenderer.renderTerminalImage(terminalImage)
+
+	assert.Greater(t, largeRenderer.charWidth, defaultRenderer.charWidth)
+	assert.Greater(t, largeImage.Bounds().Dx(), defaultImage.Bounds().Dx())
+	assert.Greater(t, largeImage.Bounds().Dy(), defaultImage.Bounds().Dy())
+}
@@ -0,0 +1,170 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package images
+
+import (
+	"image/color"
+	"strings"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+)
+
+// How the 3270 terminal screens are drawn: the colours used, the size of the characters,
+// and whether the contents of non-display fields are shown.
+
+const (
+	THEME_CLASSIC       = "classic"
+	THEME_HIGH_CONTRAST = "high-contrast"
+	THEME_LIGHT         = "light"
+
+	// The size of the characters in points, at 72 dots per inch, so one point is one pixel.
+	DEFAULT_FONT_SIZE = 12
+	MIN_FONT_SIZE     = 6
+	MAX_FONT_SIZE     = 72
+
+	// A field which asks for the default colour gets the theme's normal or intense colour.
+	COLOR_DEFAULT = "d"
+
+	// The extended highlighting a field can ask for.
+	HIGHLIGHT_BLINK      = "b"
+	HIGHLIGHT_REVERSE    = "r"
+	HIGHLIGHT_UNDERSCORE = "u"
+)
+
+// RenderTheme is the set of colours a screen is drawn with.
+type RenderTheme struct {
+	Name       string
+	Background color.RGBA
+	// The colour of a field which doesn't ask for a colour of its own.
+	Normal color.RGBA
+	// The colour of an intensified field which doesn't ask for a colour of its own.
+	Intense color.RGBA
+	// The colour of the status line under the screen.
+	Status color.RGBA
+	Cursor color.RGBA
+	// The colours a field can ask for, by their single-character identifier.
+	Colors map[string]color.RGBA
+}
+
+var (
+	// The green-on-black look of the original renderer.
+	CLASSIC_THEME = RenderTheme{
+		Name:       THEME_CLASSIC,
+		Background: color.RGBA{0, 0, 0, 255},
+		Normal:     DEFAULT_COLOR,
+		Intense:    NEUTRAL,
+		Status:     DEFAULT_COLOR,
+		Cursor:     NEUTRAL,
+		Colors:     colors,
+	}
+
+	HIGH_CONTRAST_THEME = RenderTheme{
+		Name:       THEME_HIGH_CONTRAST,
+		Background: color.RGBA{0, 0, 0, 255},
+		Normal:     color.RGBA{255, 255, 255, 255},
+		Intense:    color.RGBA{255, 255, 0, 255},
+		Status:     color.RGBA{255, 255, 255, 255},
+		Cursor:     color.RGBA{255, 255, 0, 255},
+		Colors: map[string]color.RGBA{
+			"r": {255, 96, 96, 255},
+			"g": {0, 255, 0, 255},
+			"b": {112, 176, 255, 255},
+			"p": {255, 112, 255, 255},
+			"t": {0, 255, 255, 255},
+			"y": {255, 255, 0, 255},
+			"n": {255, 255, 255, 255},
+		},
+	}
+
+	// Dark characters on a white background, for printing or pasting into documents.
+	LIGHT_THEME = RenderTheme{
+		Name:       THEME_LIGHT,
+		Background: color.RGBA{255, 255, 255, 255},
+		Normal:     color.RGBA{32, 32, 32, 255},
+		Intense:    color.RGBA{0, 64, 160, 255},
+		Status:     color.RGBA{96, 96, 96, 255},
+		Cursor:     color.RGBA{0, 0, 0, 255},
+		Colors: map[string]color.RGBA{
+			"r": {192, 0, 0, 255},
+			"g": {0, 128, 0, 255},
+			"b": {0, 0, 208, 255},
+			"p": {160, 0, 144, 255},
+			"t": {0, 128, 136, 255},
+			"y": {144, 112, 0, 255},
+			"n": {0, 0, 0, 255},
+		},
+	}
+
+	themes = []RenderTheme{CLASSIC_THEME, HIGH_CONTRAST_THEME, LIGHT_THEME}
+)
+
+// Returns the colour a field asked for, or the theme's normal or intense colour if it didn't ask for one.
+func (theme RenderTheme) getFieldColor(field TerminalField) color.RGBA {
+	fieldColor, isFound := theme.Colors[field.ForegroundColor]
+	if !isFound || field.ForegroundColor == COLOR_DEFAULT {
+		fieldColor = theme.Normal
+		if field.FieldIntenseDisplay {
+			fieldColor = theme.Intense
+		}
+	}
+	return fieldColor
+}
+
+// Returns the background colour a field asked for, and whether it asked for one.
+func (theme RenderTheme) getFieldBackgroundColor(field TerminalField) (color.RGBA, bool) {
+	backgroundColor, isFound := theme.Colors[field.BackgroundColor]
+	return backgroundColor, isFound && field.BackgroundColor != COLOR_DEFAULT
+}
+
+func GetSupportedThemes() []string {
+	themeNames := make([]string, 0, len(themes))
+	for _, theme := range themes {
+		themeNames = append(themeNames, theme.Name)
+	}
+	return themeNames
+}
+
+// RenderOptions say how the screens are drawn.
+type RenderOptions struct {
+	Theme    RenderTheme
+	FontSize float64
+	// Non-display fields, such as passwords, are blank on a real terminal, so are masked unless this is set.
+	IsShowingHiddenFields bool
+}
+
+func NewDefaultRenderOptions() RenderOptions {
+	return RenderOptions{Theme: CLASSIC_THEME, FontSize: DEFAULT_FONT_SIZE}
+}
+
+// NewRenderOptions checks what the user asked for. If the screens are to be drawn the default way,
+// nil is returned.
+func NewRenderOptions(themeName string, fontSize float64, isShowingHiddenFields bool) (*RenderOptions, error) {
+	var err error
+	var options *RenderOptions
+
+	themeName = strings.ToLower(strings.TrimSpace(themeName))
+	if themeName == "" {
+		themeName = THEME_CLASSIC
+	}
+
+	var theme RenderTheme
+	isThemeFound := false
+	for _, supportedTheme := range themes {
+		if supportedTheme.Name == themeName {
+			theme = supportedTheme
+			isThemeFound = true
+		}
+	}
+
+	if !isThemeFound {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_TERMINAL_THEME, themeName, strings.Join(GetSupportedThemes(), ", "))
+	} else if fontSize < MIN_FONT_SIZE || fontSize > MAX_FONT_SIZE {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_TERMINAL_FONT_SIZE, fontSize, MIN_FONT_SIZE, MAX_FONT_SIZE)
+	} else if themeName != THEME_CLASSIC || fontSize != DEFAULT_FONT_SIZE || isShowingHiddenFields {
+		options = &RenderOptions{Theme: theme, FontSize: fontSize, IsShowingHiddenFields: isShowingHiddenFields}
+	}
+	return options, err
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package images
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRenderOptionsWithDefaultsIsNil(t *testing.T) {
+	options, err := NewRenderOptions("", DEFAULT_FONT_SIZE, false)
+
+	assert.Nil(t, err)
+	assert.Nil(t, options)
+}
+
+func TestNewRenderOptionsCanShowHiddenFields(t *testing.T) {
+	options, err := NewRenderOptions(THEME_CLASSIC, DEFAULT_FONT_SIZE, true)
+
+	assert.Nil(t, err)
+	assert.True(t, options.IsShowingHiddenFields)
+	assert.Equal(t, THEME_CLASSIC, options.Theme.Name)
+}
+
+func TestNewRenderOptionsIgnoresTheCaseOfTheTheme(t *testing.T) {
+	options, err := NewRenderOptions("High-Contrast", 16, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, THEME_HIGH_CONTRAST, options.Theme.Name)
+	assert.Equal(t, float64(16), options.FontSize)
+}
+
+func TestNewRenderOptionsWithBadThemeFails(t *testing.T) {
+	_, err := NewRenderOptions("amber", DEFAULT_FONT_SIZE, false)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1289E")
+	assert.Contains(t, err.Error(), "classic, high-contrast, light")
+}
+
+func TestNewRenderOptionsWithFontSizeTooSmallFails(t *testing.T) {
+	_, err := NewRenderOptions(THEME_CLASSIC, 2, false)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1290E: Unsupported value '2' for parameter --terminal-font-size. The font size must be between 6 and 72 points.")
+}
+
+func TestNewTerminalOutputOptionsWithOnlyRenderOptionsIsNotNil(t *testing.T) {
+	renderOptions, _ := NewRenderOptions(THEME_LIGHT, DEFAULT_FONT_SIZE, false)
+
+	options, err := NewTerminalOutputOptions("", DEFAULT_ANIMATION_FRAME_DELAY_MILLIS, "", renderOptions)
+
+	assert.Nil(t, err)
+	assert.Nil(t, options.Animation)
+	assert.Nil(t, options.Text)
+	assert.Equal(t, THEME_LIGHT, options.Render.Theme.Name)
+}
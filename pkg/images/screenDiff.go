@@ -0,0 +1,112 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package images
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// The same screen from two different test runs can be compared, character by character.
+
+var (
+	// Laid over each character cell which differs between the two screens, so the text underneath can still be read.
+	DIFF_HIGHLIGHT_COLOR = color.NRGBA{255, 0, 0, 128}
+	// The strip between the two screens of a diff image.
+	DIFF_SEPARATOR_COLOR = color.RGBA{128, 128, 128, 255}
+)
+
+// ScreenCell is the position of a character on a 3270 screen. Rows and columns count from 0.
+type ScreenCell struct {
+	Row    int
+	Column int
+}
+
+// FindChangedScreenCells lists every character cell which differs between two screens, row by row.
+// If the screens are different sizes, cells which are only on one of the screens count as changed.
+// Unless hidden fields are shown, a change within a hidden field isn't given away.
+func FindChangedScreenCells(terminalImageA TerminalImage, terminalImageB TerminalImage, isShowingHiddenFields bool) []ScreenCell {
+	changedCells := make([]ScreenCell, 0)
+
+	rowsA := GetScreenRows(terminalImageA, isShowingHiddenFields)
+	rowsB := GetScreenRows(terminalImageB, isShowingHiddenFields)
+
+	rowCount := len(rowsA)
+	if len(rowsB) > rowCount {
+		rowCount = len(rowsB)
+	}
+
+	for row := 0; row < rowCount; row++ {
+		runesA := getScreenRowRunes(rowsA, row)
+		runesB := getScreenRowRunes(rowsB, row)
+
+		columnCount := len(runesA)
+		if len(runesB) > columnCount {
+			columnCount = len(runesB)
+		}
+
+		for column := 0; column < columnCount; column++ {
+			if column >= len(runesA) || column >= len(runesB) || runesA[column] != runesB[column] {
+				changedCells = append(changedCells, ScreenCell{Row: row, Column: column})
+			}
+		}
+	}
+	return changedCells
+}
+
+func getScreenRowRunes(rows []string, row int) []rune {
+	var runes []rune
+	if row < len(rows) {
+		runes = []rune(rows[row])
+	}
+	return runes
+}
+
+// Renders two versions of a 3270 screen side by side, the first on the left, with every character
+// which differs between them highlighted on both.
+func (renderer *ImageRendererImpl) RenderScreenDiff(terminalImageA TerminalImage, terminalImageB TerminalImage) *image.RGBA {
+	screenA := renderer.renderTerminalImage(terminalImageA)
+	screenB := renderer.renderTerminalImage(terminalImageB)
+
+	changedCells := FindChangedScreenCells(terminalImageA, terminalImageB, renderer.options.IsShowingHiddenFields)
+	renderer.highlightScreenCells(screenA, changedCells)
+	renderer.highlightScreenCells(screenB, changedCells)
+
+	separatorWidth := renderer.charWidth * 2
+	height := screenA.Bounds().Dy()
+	if screenB.Bounds().Dy() > height {
+		height = screenB.Bounds().Dy()
+	}
+	diffImage := createImageBase(screenA.Bounds().Dx()+separatorWidth+screenB.Bounds().Dx(), height)
+
+	separatorStart := screenA.Bounds().Dx()
+	screenBStart := separatorStart + separatorWidth
+
+	draw.Draw(diffImage, screenA.Bounds(), screenA, image.Pt(0, 0), draw.Src)
+	draw.Draw(diffImage, image.Rect(separatorStart, 0, screenBStart, height), image.NewUniform(DIFF_SEPARATOR_COLOR), image.Pt(0, 0), draw.Src)
+	draw.Draw(diffImage, screenB.Bounds().Add(image.Pt(screenBStart, 0)), screenB, image.Pt(0, 0), draw.Src)
+
+	return diffImage
+}
+
+// Lays the highlight colour over the given cells of a rendered screen.
+// Cells which are off the screen are ignored.
+func (renderer *ImageRendererImpl) highlightScreenCells(img *image.RGBA, cells []ScreenCell) {
+	highlight := image.NewUniform(DIFF_HIGHLIGHT_COLOR)
+	for _, cell := range cells {
+		cellBounds := renderer.getCellBounds(cell.Row, cell.Column).Intersect(img.Bounds())
+		if !cellBounds.Empty() {
+			draw.Draw(img, cellBounds, highlight, image.Pt(0, 0), draw.Over)
+		}
+	}
+}
+
+// RenderScreenDiffToPng renders two versions of a 3270 screen side by side, with the differences
+// highlighted, as a PNG image.
+func RenderScreenDiffToPng(renderer ImageRenderer, terminalImageA TerminalImage, terminalImageB TerminalImage) ([]byte, error) {
+	return encodeImageToPng(renderer.RenderScreenDiff(terminalImageA, terminalImageB))
+}
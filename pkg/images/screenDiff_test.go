@@ -0,0 +1,93 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package images
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/embedded"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindChangedScreenCellsOfTheSameScreenIsEmpty(t *testing.T) {
+	changedCells := FindChangedScreenCells(createSmallTerminalImage(), createSmallTerminalImage(), false)
+
+	assert.Empty(t, changedCells)
+}
+
+func TestFindChangedScreenCellsListsEachDifferentCharacter(t *testing.T) {
+	// Given...
+	terminalImageB := createSmallTerminalImage()
+	terminalImageB.Fields[2].Contents = []FieldContents{{Text: "px"}}
+	terminalImageB.Fields[0].Contents = []FieldContents{{Text: "USERS"}}
+
+	// When...
+	changedCells := FindChangedScreenCells(createSmallTerminalImage(), terminalImageB, true)
+
+	// Then...
+	assert.Equal(t, []ScreenCell{{Row: 0, Column: 4}, {Row: 1, Column: 6}}, changedCells)
+}
+
+func TestFindChangedScreenCellsIgnoresChangesInHiddenFields(t *testing.T) {
+	// Given...
+	terminalImageB := createSmallTerminalImage()
+	terminalImageB.Fields[2].Contents = []FieldContents{{Text: "px"}}
+
+	// When...
+	changedCells := FindChangedScreenCells(createSmallTerminalImage(), terminalImageB, false)
+
+	// Then...
+	assert.Empty(t, changedCells)
+}
+
+func TestFindChangedScreenCellsOfDifferentSizedScreensIncludesCellsOnOnlyOneScreen(t *testing.T) {
+	terminalImageA := TerminalImage{ImageSize: TerminalSize{Rows: 1, Columns: 2}}
+	terminalImageB := TerminalImage{ImageSize: TerminalSize{Rows: 2, Columns: 1}}
+
+	changedCells := FindChangedScreenCells(terminalImageA, terminalImageB, false)
+
+	assert.Equal(t, []ScreenCell{{Row: 0, Column: 1}, {Row: 1, Column: 0}}, changedCells)
+}
+
+func TestRenderScreenDiffHighlightsTheChangedCellsOfBothScreens(t *testing.T) {
+	// Given...
+	renderer := NewImageRenderer(embedded.GetReadOnlyFileSystem()).(*ImageRendererImpl)
+	charWidth := renderer.charWidth
+	charHeight := renderer.charHeight
+	terminalImageA := TerminalImage{ImageSize: TerminalSize{Rows: 2, Columns: 10}}
+	terminalImageB := TerminalImage{
+		ImageSize: TerminalSize{Rows: 2, Columns: 10},
+		Fields:    []TerminalField{{Row: 1, Column: 3, FieldDisplay: true, Contents: []FieldContents{{Text: "X"}}}},
+	}
+
+	// When...
+	diffImage := renderer.RenderScreenDiff(terminalImageA, terminalImageB)
+
+	// Then...
+	screenWidth := 10 * charWidth
+	assert.Equal(t, screenWidth*2+charWidth*2, diffImage.Bounds().Dx())
+	assert.Equal(t, DIFF_SEPARATOR_COLOR, diffImage.RGBAAt(screenWidth, 0))
+
+	// The top-left pixel of the changed cell is blank on screen A, so is only the highlight colour.
+	changedPixelA := diffImage.RGBAAt(3*charWidth, charHeight)
+	assert.Equal(t, uint8(128), changedPixelA.R)
+	assert.Equal(t, uint8(0), changedPixelA.G)
+
+	changedPixelB := diffImage.RGBAAt(screenWidth+charWidth*2+3*charWidth, charHeight)
+	assert.NotZero(t, changedPixelB.R)
+
+	unchangedPixel := diffImage.RGBAAt(0, charHeight)
+	assert.Equal(t, uint8(0), unchangedPixel.R)
+}
+
+func TestRenderScreenDiffToPngEncodesAPng(t *testing.T) {
+	renderer := NewImageRenderer(embedded.GetReadOnlyFileSystem())
+
+	pngBytes, err := RenderScreenDiffToPng(renderer, createSmallTerminalImage(), createSmallTerminalImage())
+
+	assert.Nil(t, err)
+	assert.Equal(t, PNG_SIGNATURE, pngBytes[:len(PNG_SIGNATURE)])
+}
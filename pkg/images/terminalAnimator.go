@@ -0,0 +1,302 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"sort"
+	"strings"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+)
+
+// Each terminal's screens can be played back as a single animated image, as well as the one PNG per screen.
+
+const (
+	ANIMATION_FORMAT_GIF  = "gif"
+	ANIMATION_FORMAT_APNG = "apng"
+
+	DEFAULT_ANIMATION_FRAME_DELAY_MILLIS = 1000
+	MIN_ANIMATION_FRAME_DELAY_MILLIS     = 10
+	// The longest delay an APNG frame can record in milliseconds.
+	MAX_ANIMATION_FRAME_DELAY_MILLIS = 65535
+)
+
+var (
+	PNG_SIGNATURE = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+)
+
+// What kind of animation to create, and how long each screen is shown for.
+type AnimationOptions struct {
+	Format           string
+	FrameDelayMillis int
+}
+
+// NewAnimationOptions checks what the user asked for. If no format is given, no animations
+// are wanted, so nil is returned.
+func NewAnimationOptions(format string, frameDelayMillis int) (*AnimationOptions, error) {
+	var err error
+	var options *AnimationOptions
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "" {
+		if format != ANIMATION_FORMAT_GIF && format != ANIMATION_FORMAT_APNG {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_ANIMATION_FORMAT, format, strings.Join(GetSupportedAnimationFormats(), ", "))
+		} else if frameDelayMillis < MIN_ANIMATION_FRAME_DELAY_MILLIS || frameDelayMillis > MAX_ANIMATION_FRAME_DELAY_MILLIS {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_ANIMATION_FRAME_DELAY,
+				frameDelayMillis, MIN_ANIMATION_FRAME_DELAY_MILLIS, MAX_ANIMATION_FRAME_DELAY_MILLIS)
+		} else {
+			options = &AnimationOptions{Format: format, FrameDelayMillis: frameDelayMillis}
+		}
+	}
+	return options, err
+}
+
+func GetSupportedAnimationFormats() []string {
+	return []string{ANIMATION_FORMAT_GIF, ANIMATION_FORMAT_APNG}
+}
+
+// The animation of a terminal is written next to the PNGs of its screens, as zos3270/images/term1/term1.gif
+func getAnimationFileName(terminalId string, options *AnimationOptions) string {
+	return terminalId + "." + options.Format
+}
+
+// All the screens of one terminal, which can be spread over several .gz files.
+type animatedTerminal struct {
+	id              string
+	imageFolderPath string
+	images          []TerminalImage
+}
+
+// Gathers the screens of each terminal from the terminal descriptions found in a set of files,
+// and puts them into sequence order.
+func collectAnimatedTerminals(terminalsByFile map[string]Terminal, imageFolderPathsByFile map[string]string) []*animatedTerminal {
+	terminalsByKey := make(map[string]*animatedTerminal)
+	keys := make([]string, 0)
+
+	for filePath, terminal := range terminalsByFile {
+		imageFolderPath := imageFolderPathsByFile[filePath]
+		key := imageFolderPath + "|" + terminal.Id
+
+		animated, isKnown := terminalsByKey[key]
+		if !isKnown {
+			animated = &animatedTerminal{id: terminal.Id, imageFolderPath: imageFolderPath, images: make([]TerminalImage, 0)}
+			terminalsByKey[key] = animated
+			keys = append(keys, key)
+		}
+		animated.images = append(animated.images, terminal.Images...)
+	}
+
+	sort.Strings(keys)
+	animatedTerminals := make([]*animatedTerminal, 0, len(keys))
+	for _, key := range keys {
+		animated := terminalsByKey[key]
+		sort.SliceStable(animated.images, func(i, j int) bool {
+			return animated.images[i].Sequence < animated.images[j].Sequence
+		})
+		animatedTerminals = append(animatedTerminals, animated)
+	}
+	return animatedTerminals
+}
+
+// renderAnimation draws each screen of a terminal and encodes them all into a single animated image.
+func renderAnimation(renderer ImageRenderer, terminal *animatedTerminal, options *AnimationOptions) ([]byte, error) {
+	var err error
+	var animationBytes []byte
+
+	frames := make([]*image.RGBA, 0, len(terminal.images))
+	for _, terminalImage := range terminal.images {
+		frames = append(frames, renderer.RenderAnimationFrame(terminalImage))
+	}
+	frames = makeFramesTheSameSize(frames)
+
+	if options.Format == ANIMATION_FORMAT_APNG {
+		animationBytes, err = encodeApngAnimation(frames, options.FrameDelayMillis)
+	} else {
+		animationBytes, err = encodeGifAnimation(frames, options.FrameDelayMillis)
+	}
+
+	if err != nil {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_ANIMATION_ENCODING_FAILED, terminal.id, err.Error())
+	}
+	return animationBytes, err
+}
+
+// A terminal can change size part way through, but every frame of an animation must be the same size,
+// so smaller frames are drawn onto a black background as big as the biggest frame.
+func makeFramesTheSameSize(frames []*image.RGBA) []*image.RGBA {
+	width := 0
+	height := 0
+	for _, frame := range frames {
+		if frame.Bounds().Dx() > width {
+			width = frame.Bounds().Dx()
+		}
+		if frame.Bounds().Dy() > height {
+			height = frame.Bounds().Dy()
+		}
+	}
+
+	resizedFrames := make([]*image.RGBA, 0, len(frames))
+	for _, frame := range frames {
+		if frame.Bounds().Dx() == width && frame.Bounds().Dy() == height {
+			resizedFrames = append(resizedFrames, frame)
+		} else {
+			resizedFrame := createImageBase(width, height)
+			draw.Draw(resizedFrame, frame.Bounds(), frame, frame.Bounds().Min, draw.Src)
+			resizedFrames = append(resizedFrames, resizedFrame)
+		}
+	}
+	return resizedFrames
+}
+
+func encodeGifAnimation(frames []*image.RGBA, frameDelayMillis int) ([]byte, error) {
+	animation := &gif.GIF{
+		Image: make([]*image.Paletted, 0, len(frames)),
+		Delay: make([]int, 0, len(frames)),
+		// Loop forever.
+		LoopCount: 0,
+	}
+
+	// GIF delays are in hundredths of a second.
+	frameDelay := frameDelayMillis / 10
+	for _, frame := range frames {
+		animation.Image = append(animation.Image, convertToPaletted(frame))
+		animation.Delay = append(animation.Delay, frameDelay)
+	}
+
+	buff := new(bytes.Buffer)
+	err := gif.EncodeAll(buff, animation)
+	return buff.Bytes(), err
+}
+
+// GIF frames can only use 256 colours. A screen only uses a few colours, so the palette
+// index of each colour is remembered rather than searched for on every pixel.
+func convertToPaletted(frame *image.RGBA) *image.Paletted {
+	bounds := frame.Bounds()
+	paletted := image.NewPaletted(bounds, palette.Plan9)
+	indexByColor := make(map[color.RGBA]uint8)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			pixelColor := frame.RGBAAt(x, y)
+			index, isKnown := indexByColor[pixelColor]
+			if !isKnown {
+				index = uint8(paletted.Palette.Index(pixelColor))
+				indexByColor[pixelColor] = index
+			}
+			paletted.SetColorIndex(x, y, index)
+		}
+	}
+	return paletted
+}
+
+// encodeApngAnimation builds an animated PNG. Each frame is encoded as a normal PNG, then the chunks
+// holding its pixels are moved into the animation, as described by https://wiki.mozilla.org/APNG_Specification
+func encodeApngAnimation(frames []*image.RGBA, frameDelayMillis int) ([]byte, error) {
+	var err error
+	buff := new(bytes.Buffer)
+	buff.Write(PNG_SIGNATURE)
+
+	// The animation control chunks and frame data chunks share one sequence of numbers.
+	var sequenceNumber uint32 = 0
+
+	for frameIndex, frame := range frames {
+		var chunks []pngChunk
+		chunks, err = encodeToPngChunks(frame)
+		if err != nil {
+			break
+		}
+
+		if frameIndex == 0 {
+			// The header of the first frame describes the whole animation.
+			for _, chunk := range chunks {
+				if chunk.chunkType == "IHDR" {
+					writePngChunk(buff, chunk.chunkType, chunk.data)
+				}
+			}
+			animationControl := make([]byte, 8)
+			binary.BigEndian.PutUint32(animationControl[0:], uint32(len(frames)))
+			// Play forever.
+			binary.BigEndian.PutUint32(animationControl[4:], 0)
+			writePngChunk(buff, "acTL", animationControl)
+		}
+
+		frameControl := make([]byte, 26)
+		binary.BigEndian.PutUint32(frameControl[0:], sequenceNumber)
+		binary.BigEndian.PutUint32(frameControl[4:], uint32(frame.Bounds().Dx()))
+		binary.BigEndian.PutUint32(frameControl[8:], uint32(frame.Bounds().Dy()))
+		// The x and y offsets, and how the frame is disposed of and blended, are all left as 0.
+		binary.BigEndian.PutUint16(frameControl[20:], uint16(frameDelayMillis))
+		binary.BigEndian.PutUint16(frameControl[22:], 1000)
+		writePngChunk(buff, "fcTL", frameControl)
+		sequenceNumber++
+
+		for _, chunk := range chunks {
+			if chunk.chunkType == "IDAT" {
+				if frameIndex == 0 {
+					// The first frame is also the image shown by viewers which can't animate.
+					writePngChunk(buff, "IDAT", chunk.data)
+				} else {
+					frameData := make([]byte, 4, 4+len(chunk.data))
+					binary.BigEndian.PutUint32(frameData, sequenceNumber)
+					writePngChunk(buff, "fdAT", append(frameData, chunk.data...))
+					sequenceNumber++
+				}
+			}
+		}
+	}
+
+	if err == nil {
+		writePngChunk(buff, "IEND", []byte{})
+	}
+	return buff.Bytes(), err
+}
+
+type pngChunk struct {
+	chunkType string
+	data      []byte
+}
+
+func encodeToPngChunks(frame *image.RGBA) ([]pngChunk, error) {
+	chunks := make([]pngChunk, 0)
+
+	buff := new(bytes.Buffer)
+	err := png.Encode(buff, frame)
+	if err == nil {
+		// Each chunk is a 4 byte length, a 4 byte type, the data, then a 4 byte checksum.
+		content := buff.Bytes()[len(PNG_SIGNATURE):]
+		for len(content) >= 12 {
+			length := binary.BigEndian.Uint32(content[0:4])
+			chunks = append(chunks, pngChunk{
+				chunkType: string(content[4:8]),
+				data:      content[8 : 8+length],
+			})
+			content = content[12+length:]
+		}
+	}
+	return chunks, err
+}
+
+func writePngChunk(buff *bytes.Buffer, chunkType string, data []byte) {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header[0:], uint32(len(data)))
+	copy(header[4:], chunkType)
+	buff.Write(header)
+	buff.Write(data)
+
+	checksum := crc32.NewIEEE()
+	checksum.Write(header[4:])
+	checksum.Write(data)
+	binary.Write(buff, binary.BigEndian, checksum.Sum32())
+}
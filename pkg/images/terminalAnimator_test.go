@@ -0,0 +1,219 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/gif"
+	"image/png"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/embedded"
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/stretchr/testify/assert"
+)
+
+// Puts the real example terminal, which has 10 screens, into a mock file system.
+func createMockFileSystemWithExampleTerminal(t *testing.T) spi.FileSystem {
+	gzContents, err := files.NewOSFileSystem().ReadBinaryFile("./testdata/gzipExample/term1-00001.gz")
+	assert.Nil(t, err, "could not load the real gz file data")
+
+	fs := files.NewMockFileSystem()
+	err = fs.WriteBinaryFile("/U423/zos3270/terminals/term1/term1-00001.gz", gzContents)
+	assert.Nil(t, err, "could not write real gz contents into the mock file system")
+	return fs
+}
+
+// Lists the types of the chunks in a PNG file, in order.
+func getPngChunkTypes(t *testing.T, pngBytes []byte) []string {
+	chunkTypes := make([]string, 0)
+	assert.Equal(t, PNG_SIGNATURE, pngBytes[:len(PNG_SIGNATURE)])
+
+	content := pngBytes[len(PNG_SIGNATURE):]
+	for len(content) >= 12 {
+		length := binary.BigEndian.Uint32(content[0:4])
+		chunkTypes = append(chunkTypes, string(content[4:8]))
+		content = content[12+length:]
+	}
+	return chunkTypes
+}
+
+func countChunks(chunkTypes []string, wantedType string) int {
+	count := 0
+	for _, chunkType := range chunkTypes {
+		if chunkType == wantedType {
+			count++
+		}
+	}
+	return count
+}
+
+func TestNewAnimationOptionsWithNoFormatIsNil(t *testing.T) {
+	options, err := NewAnimationOptions("", DEFAULT_ANIMATION_FRAME_DELAY_MILLIS)
+
+	assert.Nil(t, err)
+	assert.Nil(t, options)
+}
+
+func TestNewAnimationOptionsIgnoresCase(t *testing.T) {
+	options, err := NewAnimationOptions("APNG", 500)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &AnimationOptions{Format: ANIMATION_FORMAT_APNG, FrameDelayMillis: 500}, options)
+}
+
+func TestNewAnimationOptionsWithBadFormatFails(t *testing.T) {
+	_, err := NewAnimationOptions("mp4", DEFAULT_ANIMATION_FRAME_DELAY_MILLIS)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1278E")
+	assert.Contains(t, err.Error(), "gif, apng")
+}
+
+func TestNewAnimationOptionsWithBadDelayFails(t *testing.T) {
+	_, err := NewAnimationOptions("gif", 5)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1279E")
+}
+
+func TestExpandImagesWithoutAnimationOptionsMakesNoAnimation(t *testing.T) {
+	// Given...
+	fs := createMockFileSystemWithExampleTerminal(t)
+	expander := NewImageExpander(fs, NewImageRenderer(embedded.GetReadOnlyFileSystem()), false)
+
+	// When...
+	err := expander.ExpandImages("/U423")
+
+	// Then...
+	assert.Nil(t, err)
+	isExists, _ := fs.Exists("/U423/zos3270/images/term1/term1.gif")
+	assert.False(t, isExists)
+	assert.Equal(t, 0, expander.GetAnimationFileCount())
+}
+
+func TestExpandImagesCanMakeAGifAnimationOfEachTerminal(t *testing.T) {
+	// Given...
+	fs := createMockFileSystemWithExampleTerminal(t)
+	options := &AnimationOptions{Format: ANIMATION_FORMAT_GIF, FrameDelayMillis: 500}
+	expander := NewImageExpanderWithOptions(fs, NewImageRenderer(embedded.GetReadOnlyFileSystem()), false, &TerminalOutputOptions{Animation: options})
+
+	// When...
+	err := expander.ExpandImages("/U423")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 10, expander.GetExpandedImageFileCount())
+	assert.Equal(t, 1, expander.GetAnimationFileCount())
+
+	gifBytes, err := fs.ReadBinaryFile("/U423/zos3270/images/term1/term1.gif")
+	assert.Nil(t, err)
+
+	animation, err := gif.DecodeAll(bytes.NewReader(gifBytes))
+	assert.Nil(t, err)
+	assert.Len(t, animation.Image, 10)
+	assert.Equal(t, 50, animation.Delay[0], "GIF delays are in hundredths of a second")
+	assert.Equal(t, 0, animation.LoopCount)
+}
+
+func TestAnimateTerminalsCanMakeAnApngAnimation(t *testing.T) {
+	// Given...
+	fs := createMockFileSystemWithExampleTerminal(t)
+	options := &AnimationOptions{Format: ANIMATION_FORMAT_APNG, FrameDelayMillis: 250}
+	expander := NewImageExpanderWithOptions(fs, NewImageRenderer(embedded.GetReadOnlyFileSystem()), false, &TerminalOutputOptions{Animation: options})
+
+	// When...
+	err := expander.AnimateTerminals("/U423")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 1, expander.GetAnimationFileCount())
+
+	apngBytes, err := fs.ReadBinaryFile("/U423/zos3270/images/term1/term1.apng")
+	assert.Nil(t, err)
+
+	chunkTypes := getPngChunkTypes(t, apngBytes)
+	assert.Equal(t, "IHDR", chunkTypes[0])
+	assert.Equal(t, "acTL", chunkTypes[1])
+	assert.Equal(t, "IEND", chunkTypes[len(chunkTypes)-1])
+	assert.Equal(t, 10, countChunks(chunkTypes, "fcTL"))
+	assert.Equal(t, 1, countChunks(chunkTypes, "IHDR"))
+
+	// Viewers which can't animate show the first frame, so it must still be a valid PNG.
+	firstFrame, err := png.Decode(bytes.NewReader(apngBytes))
+	assert.Nil(t, err)
+	assert.NotNil(t, firstFrame)
+}
+
+func TestAnimateTerminalsDoesNotReplaceAnExistingAnimationUnlessForced(t *testing.T) {
+	// Given...
+	fs := createMockFileSystemWithExampleTerminal(t)
+	fs.WriteBinaryFile("/U423/zos3270/images/term1/term1.gif", []byte("old animation"))
+	options := &AnimationOptions{Format: ANIMATION_FORMAT_GIF, FrameDelayMillis: 500}
+	expander := NewImageExpanderWithOptions(fs, NewImageRenderer(embedded.GetReadOnlyFileSystem()), false, &TerminalOutputOptions{Animation: options})
+
+	// When...
+	err := expander.AnimateTerminals("/U423")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 0, expander.GetAnimationFileCount())
+	content, _ := fs.ReadBinaryFile("/U423/zos3270/images/term1/term1.gif")
+	assert.Equal(t, "old animation", string(content))
+}
+
+func TestCollectAnimatedTerminalsMergesFilesInSequenceOrder(t *testing.T) {
+	// Given...
+	terminalsByFile := map[string]Terminal{
+		"a/terminals/term1/term1-00002.gz": {Id: "term1", Images: []TerminalImage{{Id: "third", Sequence: 3}}},
+		"a/terminals/term1/term1-00001.gz": {Id: "term1", Images: []TerminalImage{{Id: "second", Sequence: 2}, {Id: "first", Sequence: 1}}},
+		"a/terminals/term2/term2-00001.gz": {Id: "term2", Images: []TerminalImage{{Id: "other", Sequence: 1}}},
+	}
+	imageFolderPathsByFile := map[string]string{
+		"a/terminals/term1/term1-00002.gz": "a/images/term1",
+		"a/terminals/term1/term1-00001.gz": "a/images/term1",
+		"a/terminals/term2/term2-00001.gz": "a/images/term2",
+	}
+
+	// When...
+	terminals := collectAnimatedTerminals(terminalsByFile, imageFolderPathsByFile)
+
+	// Then...
+	assert.Len(t, terminals, 2)
+	assert.Equal(t, "term1", terminals[0].id)
+	assert.Equal(t, "a/images/term1", terminals[0].imageFolderPath)
+	assert.Equal(t, "first", terminals[0].images[0].Id)
+	assert.Equal(t, "second", terminals[0].images[1].Id)
+	assert.Equal(t, "third", terminals[0].images[2].Id)
+	assert.Equal(t, "term2", terminals[1].id)
+}
+
+func TestMakeFramesTheSameSizeUsesTheBiggestFrame(t *testing.T) {
+	frames := []*image.RGBA{
+		image.NewRGBA(image.Rect(0, 0, 10, 20)),
+		image.NewRGBA(image.Rect(0, 0, 30, 5)),
+	}
+
+	resizedFrames := makeFramesTheSameSize(frames)
+
+	assert.Equal(t, image.Rect(0, 0, 30, 20), resizedFrames[0].Bounds())
+	assert.Equal(t, image.Rect(0, 0, 30, 20), resizedFrames[1].Bounds())
+}
+
+func TestRenderAnimationFrameAddsABannerColouredByDirection(t *testing.T) {
+	renderer := NewImageRenderer(embedded.GetReadOnlyFileSystem())
+	size := TerminalSize{Rows: 24, Columns: 80}
+
+	inboundFrame := renderer.RenderAnimationFrame(TerminalImage{Id: "in", Inbound: true, ImageSize: size})
+	outboundFrame := renderer.RenderAnimationFrame(TerminalImage{Id: "out", Inbound: false, Aid: "PF3", ImageSize: size})
+
+	assert.Equal(t, INBOUND_BANNER_COLOR, inboundFrame.RGBAAt(0, 0))
+	assert.Equal(t, OUTBOUND_BANNER_COLOR, outboundFrame.RGBAAt(0, 0))
+	assert.Equal(t, inboundFrame.Bounds(), outboundFrame.Bounds())
+}
@@ -0,0 +1,252 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package images
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+)
+
+// Each screen of a terminal can be written out as plain text, as well as a PNG, so that it can be searched.
+
+const (
+	TERMINAL_TEXT_FORMAT_PLAIN     = "plain"
+	TERMINAL_TEXT_FORMAT_ANNOTATED = "annotated"
+
+	// Marks used in the field attribute layer of an annotated screen.
+	FIELD_ATTRIBUTE_PROTECTED   = 'p'
+	FIELD_ATTRIBUTE_UNPROTECTED = 'u'
+	FIELD_ATTRIBUTE_NUMERIC     = 'n'
+	FIELD_ATTRIBUTE_HIDDEN      = 'h'
+	FIELD_ATTRIBUTE_CURSOR      = '_'
+	FIELD_ATTRIBUTE_NONE        = ' '
+
+	FIELD_ATTRIBUTE_LEGEND = "Legend: p protected, u unprotected, n numeric, h hidden, upper case intensified, _ cursor"
+)
+
+// How the screens of a terminal are written out as text.
+type TerminalTextOptions struct {
+	// Whether a layer showing the attributes of each field is added under the screen.
+	IsAnnotated bool
+	// Non-display fields, such as passwords, are left blank, the same as in the images, unless this is set.
+	IsShowingHiddenFields bool
+}
+
+// NewTerminalTextOptions checks what the user asked for. If no format is given, no text is wanted,
+// so nil is returned.
+func NewTerminalTextOptions(format string) (*TerminalTextOptions, error) {
+	var err error
+	var options *TerminalTextOptions
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "" {
+		if format != TERMINAL_TEXT_FORMAT_PLAIN && format != TERMINAL_TEXT_FORMAT_ANNOTATED {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_TERMINAL_TEXT_FORMAT, format, strings.Join(GetSupportedTerminalTextFormats(), ", "))
+		} else {
+			options = &TerminalTextOptions{IsAnnotated: format == TERMINAL_TEXT_FORMAT_ANNOTATED}
+		}
+	}
+	return options, err
+}
+
+func GetSupportedTerminalTextFormats() []string {
+	return []string{TERMINAL_TEXT_FORMAT_PLAIN, TERMINAL_TEXT_FORMAT_ANNOTATED}
+}
+
+// The text of a screen is written next to its PNG, as zos3270/images/term1/term1-00001.txt
+func getTextFileName(terminalId string, terminalImage TerminalImage) string {
+	return fmt.Sprintf("%s-%05d.txt", terminalId, terminalImage.Sequence)
+}
+
+type TextExporter interface {
+	ExportJsonBytesToTextFiles(jsonBinary []byte, writer ImageFileWriter) error
+}
+
+type TextExporterImpl struct {
+	options TerminalTextOptions
+}
+
+func NewTextExporter(options TerminalTextOptions) TextExporter {
+	exporter := new(TextExporterImpl)
+	exporter.options = options
+	return exporter
+}
+
+func (exporter *TextExporterImpl) ExportJsonBytesToTextFiles(jsonBinary []byte, writer ImageFileWriter) error {
+	var err error
+	var terminal Terminal
+
+	terminal, err = ConvertJsonBytesToTerminal(jsonBinary)
+	if err == nil {
+		for _, terminalImage := range terminal.Images {
+			textFileName := getTextFileName(terminal.Id, terminalImage)
+
+			var isWritable bool
+			isWritable, err = writer.IsImageFileWritable(textFileName)
+			if err == nil && isWritable {
+				text := RenderTerminalImageAsText(terminalImage, exporter.options)
+				err = writer.WriteImageFile(textFileName, []byte(text))
+			}
+
+			if err != nil {
+				break
+			}
+		}
+	}
+	return err
+}
+
+// RenderTerminalImageAsText lays a screen out as a fixed-width grid of text, one line per row,
+// under the same status line as the rendered image.
+// If annotated, a second grid is added which marks the attributes of the field each character is in.
+func RenderTerminalImageAsText(terminalImage TerminalImage, options TerminalTextOptions) string {
+	var buff strings.Builder
+
+	buff.WriteString(getStatusText(terminalImage, terminalImage.ImageSize.Columns, terminalImage.ImageSize.Rows))
+	buff.WriteString("\n")
+
+	for _, row := range GetScreenRows(terminalImage, options.IsShowingHiddenFields) {
+		buff.WriteString(row)
+		buff.WriteString("\n")
+	}
+
+	if options.IsAnnotated {
+		buff.WriteString("\nField attributes:\n")
+		for _, row := range getFieldAttributeRows(terminalImage) {
+			buff.WriteString(row)
+			buff.WriteString("\n")
+		}
+		buff.WriteString(FIELD_ATTRIBUTE_LEGEND)
+		buff.WriteString("\n")
+	}
+	return buff.String()
+}
+
+// GetScreenRows returns the characters on each row of a screen. Every row is as wide as the screen,
+// with spaces wherever nothing is shown, so a character's column is its position in the row.
+// Non-display fields, such as passwords, are blank on a real terminal, so are left as spaces unless they are to be shown.
+func GetScreenRows(terminalImage TerminalImage, isShowingHiddenFields bool) []string {
+	grid := newScreenGrid(terminalImage.ImageSize, ' ')
+
+	for _, field := range terminalImage.Fields {
+		column := field.Column
+		row := field.Row
+		isMasked := isHiddenField(&field) && !isShowingHiddenFields
+
+		for _, contents := range field.Contents {
+			// Field contents can span several rows, the same way as when the image is rendered.
+			for _, char := range getCharacters(&contents) {
+				if column >= terminalImage.ImageSize.Columns {
+					column = 0
+					row++
+				}
+				if char < ' ' || isMasked {
+					// Nulls and other control characters show as nothing on a real terminal.
+					char = ' '
+				}
+				grid.set(row, column, char)
+				column++
+			}
+		}
+	}
+	return grid.getRows()
+}
+
+// A field's attributes apply from its first character up to the attribute byte of the next field,
+// which sits just before the next field's first character. The last field wraps around to the first.
+func getFieldAttributeRows(terminalImage TerminalImage) []string {
+	size := terminalImage.ImageSize
+	grid := newScreenGrid(size, FIELD_ATTRIBUTE_NONE)
+	screenLength := size.Rows * size.Columns
+
+	fields := make([]TerminalField, 0, len(terminalImage.Fields))
+	for _, field := range terminalImage.Fields {
+		if !field.Unformatted {
+			fields = append(fields, field)
+		}
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return getScreenPosition(fields[i], size) < getScreenPosition(fields[j], size)
+	})
+
+	if screenLength == 0 {
+		fields = fields[:0]
+	}
+
+	for index, field := range fields {
+		start := getScreenPosition(field, size)
+		nextStart := getScreenPosition(fields[(index+1)%len(fields)], size)
+
+		fieldLength := (nextStart - 1 - start + screenLength) % screenLength
+		if len(fields) == 1 {
+			fieldLength = screenLength - 1
+		}
+
+		mark := getFieldAttributeMark(field)
+		for offset := 0; offset < fieldLength; offset++ {
+			position := (start + offset) % screenLength
+			grid.set(position/size.Columns, position%size.Columns, mark)
+		}
+	}
+
+	grid.set(terminalImage.CursorRow, terminalImage.CursorColumn, FIELD_ATTRIBUTE_CURSOR)
+	return grid.getRows()
+}
+
+func getScreenPosition(field TerminalField, size TerminalSize) int {
+	return field.Row*size.Columns + field.Column
+}
+
+func getFieldAttributeMark(field TerminalField) rune {
+	var mark rune
+	if !field.FieldDisplay && !field.FieldIntenseDisplay {
+		mark = FIELD_ATTRIBUTE_HIDDEN
+	} else {
+		if field.FieldProtected {
+			mark = FIELD_ATTRIBUTE_PROTECTED
+		} else if field.FieldNumeric {
+			mark = FIELD_ATTRIBUTE_NUMERIC
+		} else {
+			mark = FIELD_ATTRIBUTE_UNPROTECTED
+		}
+
+		if field.FieldIntenseDisplay {
+			mark = unicode.ToUpper(mark)
+		}
+	}
+	return mark
+}
+
+// A screen-sized grid of characters, which ignores anything placed outside the screen.
+type screenGrid struct {
+	cells [][]rune
+}
+
+func newScreenGrid(size TerminalSize, blank rune) *screenGrid {
+	grid := &screenGrid{cells: make([][]rune, size.Rows)}
+	for row := range grid.cells {
+		grid.cells[row] = []rune(strings.Repeat(string(blank), size.Columns))
+	}
+	return grid
+}
+
+func (grid *screenGrid) set(row int, column int, char rune) {
+	if row >= 0 && row < len(grid.cells) && column >= 0 && column < len(grid.cells[row]) {
+		grid.cells[row][column] = char
+	}
+}
+
+func (grid *screenGrid) getRows() []string {
+	rows := make([]string, 0, len(grid.cells))
+	for _, cells := range grid.cells {
+		rows = append(rows, string(cells))
+	}
+	return rows
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package images
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/embedded"
+	"github.com/stretchr/testify/assert"
+)
+
+// A small screen with a protected prompt, then an intensified input field,
+// then a hidden input field with the cursor in it.
+func createSmallTerminalImage() TerminalImage {
+	return TerminalImage{
+		Id:           "term1-1",
+		Sequence:     1,
+		Inbound:      true,
+		ImageSize:    TerminalSize{Rows: 2, Columns: 10},
+		CursorRow:    1,
+		CursorColumn: 6,
+		Fields: []TerminalField{
+			{Row: 0, Column: 0, FieldProtected: true, FieldDisplay: true, Contents: []FieldContents{{Text: "USER"}}},
+			{Row: 0, Column: 5, FieldIntenseDisplay: true, Contents: []FieldContents{{Characters: []string{"b", "o", "b", "\u0000"}}}},
+			{Row: 1, Column: 5, Contents: []FieldContents{{Text: "pw"}}},
+		},
+	}
+}
+
+func TestNewTerminalTextOptionsWithNoFormatIsNil(t *testing.T) {
+	options, err := NewTerminalTextOptions("")
+
+	assert.Nil(t, err)
+	assert.Nil(t, options)
+}
+
+func TestNewTerminalTextOptionsIgnoresCase(t *testing.T) {
+	options, err := NewTerminalTextOptions("Annotated")
+
+	assert.Nil(t, err)
+	assert.True(t, options.IsAnnotated)
+}
+
+func TestNewTerminalTextOptionsWithBadFormatFails(t *testing.T) {
+	_, err := NewTerminalTextOptions("html")
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1281E")
+	assert.Contains(t, err.Error(), "plain, annotated")
+}
+
+func TestNewTerminalOutputOptionsWithNothingWantedIsNil(t *testing.T) {
+	options, err := NewTerminalOutputOptions("", DEFAULT_ANIMATION_FRAME_DELAY_MILLIS, "", nil)
+
+	assert.Nil(t, err)
+	assert.Nil(t, options)
+}
+
+func TestNewTerminalOutputOptionsWithOnlyTextWanted(t *testing.T) {
+	options, err := NewTerminalOutputOptions("", DEFAULT_ANIMATION_FRAME_DELAY_MILLIS, "plain", nil)
+
+	assert.Nil(t, err)
+	assert.Nil(t, options.Animation)
+	assert.False(t, options.Text.IsAnnotated)
+}
+
+func TestNewTerminalOutputOptionsShowsHiddenFieldsInTheTextWhenTheImagesShowThem(t *testing.T) {
+	renderOptions, err := NewRenderOptions(THEME_CLASSIC, DEFAULT_FONT_SIZE, true)
+	assert.Nil(t, err)
+
+	options, err := NewTerminalOutputOptions("", DEFAULT_ANIMATION_FRAME_DELAY_MILLIS, "plain", renderOptions)
+
+	assert.Nil(t, err)
+	assert.True(t, options.Text.IsShowingHiddenFields)
+}
+
+func TestGetScreenRowsPadsEachRowToTheScreenWidth(t *testing.T) {
+	rows := GetScreenRows(createSmallTerminalImage(), true)
+
+	assert.Equal(t, []string{
+		"USER bob  ",
+		"     pw   ",
+	}, rows)
+}
+
+func TestGetScreenRowsLeavesHiddenFieldsBlank(t *testing.T) {
+	rows := GetScreenRows(createSmallTerminalImage(), false)
+
+	assert.Equal(t, []string{
+		"USER bob  ",
+		"          ",
+	}, rows)
+}
+
+func TestGetScreenRowsWrapsFieldsOntoTheNextRow(t *testing.T) {
+	terminalImage := TerminalImage{
+		ImageSize: TerminalSize{Rows: 2, Columns: 4},
+		Fields:    []TerminalField{{Row: 0, Column: 2, FieldDisplay: true, Contents: []FieldContents{{Text: "ABCDEFGHIJ"}}}},
+	}
+
+	rows := GetScreenRows(terminalImage, false)
+
+	// Anything which would go past the bottom of the screen is dropped.
+	assert.Equal(t, []string{"  AB", "CDEF"}, rows)
+}
+
+func TestRenderTerminalImageAsPlainText(t *testing.T) {
+	text := RenderTerminalImageAsText(createSmallTerminalImage(), TerminalTextOptions{})
+
+	assert.Equal(t, "term1-1 - 10x2 - Inbound \n"+
+		"USER bob  \n"+
+		"          \n", text)
+}
+
+func TestRenderTerminalImageAsTextShowsHiddenFieldsWhenAsked(t *testing.T) {
+	text := RenderTerminalImageAsText(createSmallTerminalImage(), TerminalTextOptions{IsShowingHiddenFields: true})
+
+	assert.Equal(t, "term1-1 - 10x2 - Inbound \n"+
+		"USER bob  \n"+
+		"     pw   \n", text)
+}
+
+func TestRenderTerminalImageAsAnnotatedTextMarksFieldsAndCursor(t *testing.T) {
+	text := RenderTerminalImageAsText(createSmallTerminalImage(), TerminalTextOptions{IsAnnotated: true})
+
+	assert.Equal(t, "term1-1 - 10x2 - Inbound \n"+
+		"USER bob  \n"+
+		"          \n"+
+		"\n"+
+		"Field attributes:\n"+
+		"pppp UUUUU\n"+
+		"UUUU h_hh \n"+
+		FIELD_ATTRIBUTE_LEGEND+"\n", text)
+}
+
+func TestExpandImagesCanWriteTheTextOfEachScreen(t *testing.T) {
+	// Given...
+	fs := createMockFileSystemWithExampleTerminal(t)
+	options := &TerminalOutputOptions{Text: &TerminalTextOptions{IsAnnotated: false}}
+	expander := NewImageExpanderWithOptions(fs, NewImageRenderer(embedded.GetReadOnlyFileSystem()), false, options)
+
+	// When...
+	err := expander.ExpandImages("/U423")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 10, expander.GetExpandedImageFileCount())
+	assert.Equal(t, 10, expander.GetTextFileCount())
+	assert.Equal(t, 0, expander.GetAnimationFileCount())
+
+	text, err := fs.ReadTextFile("/U423/zos3270/images/term1/term1-00001.txt")
+	assert.Nil(t, err)
+	lines := strings.Split(text, "\n")
+	assert.Equal(t, "term1-1 - 80x24 - Inbound ", lines[0])
+	assert.Contains(t, lines[2], "IBM INTERNAL SYSTEMS MUST BE USED FOR IBM BUSINESS ONLY")
+	assert.Len(t, lines[2], 80)
+}
+
+func TestExpandImagesWithoutTextOptionsWritesNoText(t *testing.T) {
+	// Given...
+	fs := createMockFileSystemWithExampleTerminal(t)
+	expander := NewImageExpander(fs, NewImageRenderer(embedded.GetReadOnlyFileSystem()), false)
+
+	// When...
+	err := expander.ExpandImages("/U423")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 0, expander.GetTextFileCount())
+	isExists, _ := fs.Exists("/U423/zos3270/images/term1/term1-00001.txt")
+	assert.False(t, isExists)
+}
+
+func TestExpandImagesWithoutARendererOnlyWritesTheText(t *testing.T) {
+	// Given...
+	fs := createMockFileSystemWithExampleTerminal(t)
+	options := &TerminalOutputOptions{Text: &TerminalTextOptions{IsAnnotated: false}}
+	expander := NewImageExpanderWithOptions(fs, nil, false, options)
+
+	// When...
+	err := expander.ExpandImages("/U423")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 0, expander.GetExpandedImageFileCount())
+	assert.Equal(t, 10, expander.GetTextFileCount())
+
+	isImageWritten, _ := fs.Exists("/U423/zos3270/images/term1/term1-00001.png")
+	assert.False(t, isImageWritten)
+}
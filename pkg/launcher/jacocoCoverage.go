@@ -0,0 +1,93 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/spi"
+)
+
+// The code coverage of a local test can be collected using the JaCoCo agent, which is unpacked into the
+// Galasa home folder next to the boot jar. The coverage is saved into the RAS folder of the run,
+// as ras/<runId>/coverage/<runId>.exec so the coverage of many runs can be merged and reported on later.
+
+const (
+	// The name of the folder within the RAS folder of a run which holds its code coverage.
+	COVERAGE_FOLDER_NAME = "coverage"
+
+	// The extension JaCoCo uses for the files holding the execution data it collects.
+	COVERAGE_FILE_EXTENSION = "exec"
+)
+
+// jacocoCoverage is the code coverage of a single local test.
+type jacocoCoverage struct {
+	fileSystem   spi.FileSystem
+	agentJarPath string
+
+	temporaryFolderPath string
+	execFilePath        string
+
+	// Set once the JVM has ended and the coverage has been saved into the RAS folder of the run.
+	savedFilePath string
+	err           error
+}
+
+func newJacocoCoverage(fileSystem spi.FileSystem, agentJarPath string) (*jacocoCoverage, error) {
+	var err error
+	coverage := new(jacocoCoverage)
+	coverage.fileSystem = fileSystem
+	coverage.agentJarPath = agentJarPath
+
+	coverage.temporaryFolderPath, err = fileSystem.MkTempDir()
+	if err == nil {
+		coverage.execFilePath = coverage.temporaryFolderPath + fileSystem.GetFilePathSeparator() + "coverage." + COVERAGE_FILE_EXTENSION
+	} else {
+		coverage = nil
+	}
+	return coverage, err
+}
+
+// The JVM option which attaches the JaCoCo agent, which writes out the execution data when the JVM exits.
+func (coverage *jacocoCoverage) getJvmArg() string {
+	return "-javaagent:" + coverage.agentJarPath + "=destfile=" + coverage.execFilePath + ",output=file"
+}
+
+// save moves the execution data into the RAS folder of the run.
+// This must only be called once the JVM has ended.
+func (coverage *jacocoCoverage) save(rasFolderPath string, runId string) {
+	defer coverage.discard()
+
+	savedFilePath, err := saveIntoRunRasFolder(coverage.fileSystem, coverage.execFilePath, rasFolderPath, runId, COVERAGE_FOLDER_NAME, COVERAGE_FILE_EXTENSION)
+
+	if err != nil {
+		coverage.err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_COVERAGE_NOT_SAVED, runId, savedFilePath, err.Error())
+		log.Println(coverage.err.Error())
+	} else {
+		coverage.savedFilePath = savedFilePath
+		log.Printf("Saved the code coverage of run %s to '%s'\n", runId, savedFilePath)
+	}
+}
+
+func (coverage *jacocoCoverage) discard() {
+	coverage.fileSystem.DeleteDir(coverage.temporaryFolderPath)
+}
+
+// getReport says where the execution data was saved, or why it couldn't be.
+// The execution data can't be summarised without the classes it was collected from, which 'local coverage report' is given.
+func (coverage *jacocoCoverage) getReport(runId string) string {
+	var report strings.Builder
+	if coverage.savedFilePath != "" {
+		report.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_COVERAGE_SAVED.Template, runId, coverage.savedFilePath))
+	}
+	if coverage.err != nil {
+		report.WriteString(coverage.err.Error() + "\n")
+	}
+	return report.String()
+}
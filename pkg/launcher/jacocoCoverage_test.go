@@ -0,0 +1,53 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJacocoCoverageJvmArgAttachesTheAgentWritingIntoATemporaryFolder(t *testing.T) {
+	fs := files.NewMockFileSystem()
+	coverage, err := newJacocoCoverage(fs, "/home/.galasa/lib/0.41.0/org.jacoco.agent-0.8.12-runtime.jar")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "-javaagent:/home/.galasa/lib/0.41.0/org.jacoco.agent-0.8.12-runtime.jar=destfile="+coverage.temporaryFolderPath+"/coverage.exec,output=file", coverage.getJvmArg())
+}
+
+func TestJacocoCoverageIsSavedIntoTheRasFolder(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	coverage, _ := newJacocoCoverage(fs, "/agent.jar")
+	fs.WriteBinaryFile(coverage.execFilePath, []byte("execution data"))
+
+	// When...
+	coverage.save("/home/.galasa/ras", "L12")
+
+	// Then...
+	assert.Nil(t, coverage.err)
+	saved, _ := fs.ReadBinaryFile("/home/.galasa/ras/L12/coverage/L12.exec")
+	assert.Equal(t, []byte("execution data"), saved)
+	isTempLeft, _ := fs.DirExists(coverage.temporaryFolderPath)
+	assert.False(t, isTempLeft)
+
+	assert.Equal(t, "GAL2527I: The code coverage of run 'L12' was saved to '/home/.galasa/ras/L12/coverage/L12.exec'.\n", coverage.getReport("L12"))
+}
+
+func TestJacocoCoverageWhichWasNeverWrittenReportsError(t *testing.T) {
+	fs := files.NewMockFileSystem()
+	coverage, _ := newJacocoCoverage(fs, "/agent.jar")
+
+	coverage.save("/ras", "L12")
+
+	assert.NotNil(t, coverage.err)
+	assert.ErrorContains(t, coverage.err, "GAL1300E")
+	report := coverage.getReport("L12")
+	assert.NotContains(t, report, "GAL2527I")
+	assert.Contains(t, report, "GAL1300E")
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/galasa-dev/cli/pkg/embedded"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+)
+
+// The name given to the report, shown at the top of the HTML pages.
+const COVERAGE_REPORT_NAME = "Galasa local test runs"
+
+// These parameters are gathered from the 'local coverage report' command-line.
+type CoverageReportParameters struct {
+	// The RAS folder the local runs were saved into. Defaults to the ras folder in the Galasa home folder.
+	RasFolderPath string
+
+	// The names of the runs whose code coverage is merged, or empty to merge the coverage of every run in the RAS folder.
+	RunNames []string
+
+	// The folders or jars holding the class files the coverage is reported on.
+	ClassFilePaths []string
+
+	// The folders holding the source files of those classes, so the HTML report can show which lines were run.
+	SourceFilePaths []string
+
+	// Where to write the XML report, or blank if it isn't wanted.
+	XmlFilePath string
+
+	// The folder to write the HTML report into, or blank if it isn't wanted.
+	HtmlFolderPath string
+}
+
+// ReportCoverage merges the code coverage collected from local runs, then uses the JaCoCo command-line tool
+// to report on how much of the given classes they ran.
+func ReportCoverage(
+	factory spi.Factory,
+	embeddedFileSystem embedded.ReadOnlyFileSystem,
+	galasaHome spi.GalasaHome,
+	processFactory ProcessFactory,
+	params CoverageReportParameters,
+) error {
+	var err error
+	fileSystem := factory.GetFileSystem()
+
+	javaHome := factory.GetEnvironment().GetEnv("JAVA_HOME")
+	err = utils.ValidateJavaHome(fileSystem, javaHome)
+
+	var execFilePaths []string
+	if err == nil {
+		rasFolderPath := GetLocalRasFolderPath(fileSystem, galasaHome, params.RasFolderPath)
+		execFilePaths, err = findCoverageFiles(fileSystem, rasFolderPath, params.RunNames)
+	}
+
+	var cliJarPath string
+	if err == nil {
+		err = utils.InstallJacocoJars(galasaHome, fileSystem, embeddedFileSystem)
+		if err == nil {
+			cliJarPath, err = utils.GetJacocoCliJarPath(fileSystem, galasaHome)
+		}
+	}
+
+	if err == nil {
+		separator := fileSystem.GetFilePathSeparator()
+		cmd := javaHome + separator + "bin" + separator + "java"
+		args := getCoverageReportArgs(cliJarPath, execFilePaths, params)
+
+		err = runCoverageReportProcess(processFactory, cmd, args)
+	}
+
+	if err == nil {
+		reportLocations := make([]string, 0, 2)
+		if params.XmlFilePath != "" {
+			reportLocations = append(reportLocations, "'"+params.XmlFilePath+"'")
+		}
+		if params.HtmlFolderPath != "" {
+			reportLocations = append(reportLocations, "'"+params.HtmlFolderPath+"'")
+		}
+		message := fmt.Sprintf(galasaErrors.GALASA_INFO_COVERAGE_REPORTED.Template, len(execFilePaths), strings.Join(reportLocations, " and "))
+		err = factory.GetStdOutConsole().WriteString(message)
+	}
+	return err
+}
+
+// Finds the code coverage saved for each run, as ras/<runId>/coverage/<runId>.exec
+func findCoverageFiles(fileSystem spi.FileSystem, rasFolderPath string, runNames []string) ([]string, error) {
+	var err error
+	execFilePaths := make([]string, 0)
+	separator := fileSystem.GetFilePathSeparator()
+
+	if len(runNames) > 0 {
+		for _, runName := range runNames {
+			execFilePath := rasFolderPath + separator + runName + separator + COVERAGE_FOLDER_NAME + separator + runName + "." + COVERAGE_FILE_EXTENSION
+
+			var isExists bool
+			isExists, err = fileSystem.Exists(execFilePath)
+			if err == nil && !isExists {
+				err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_COVERAGE_OF_RUN_NOT_FOUND, runName, execFilePath)
+			}
+			if err != nil {
+				break
+			}
+			execFilePaths = append(execFilePaths, execFilePath)
+		}
+	} else {
+		var isExists bool
+		isExists, err = fileSystem.DirExists(rasFolderPath)
+
+		var filePaths []string
+		if err == nil && isExists {
+			filePaths, err = fileSystem.GetAllFilePaths(rasFolderPath)
+		}
+
+		if err == nil {
+			for _, filePath := range filePaths {
+				if isRunCoverageFile(filePath, rasFolderPath, separator) {
+					execFilePaths = append(execFilePaths, filePath)
+				}
+			}
+			sort.Strings(execFilePaths)
+
+			if len(execFilePaths) < 1 {
+				err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_NO_COVERAGE_FOUND, rasFolderPath)
+			}
+		}
+	}
+	return execFilePaths, err
+}
+
+// Only the coverage the launcher saved for each run is wanted, not any other .exec files which are in the RAS folder.
+func isRunCoverageFile(filePath string, rasFolderPath string, separator string) bool {
+	relativePath := strings.TrimPrefix(strings.TrimPrefix(filePath, rasFolderPath), separator)
+	parts := strings.Split(relativePath, separator)
+	return len(parts) == 3 &&
+		parts[1] == COVERAGE_FOLDER_NAME &&
+		parts[2] == parts[0]+"."+COVERAGE_FILE_EXTENSION
+}
+
+// The JaCoCo 'report' command merges all the execution data it is given before reporting on it.
+// For example:
+//
+//	java -jar org.jacoco.cli-0.8.12-nodeps.jar report L1.exec L2.exec --classfiles target/classes --xml coverage.xml
+func getCoverageReportArgs(cliJarPath string, execFilePaths []string, params CoverageReportParameters) []string {
+	args := []string{"-jar", cliJarPath, "report"}
+	args = append(args, execFilePaths...)
+
+	for _, classFilePath := range params.ClassFilePaths {
+		args = append(args, "--classfiles", classFilePath)
+	}
+	for _, sourceFilePath := range params.SourceFilePaths {
+		args = append(args, "--sourcefiles", sourceFilePath)
+	}
+	if params.XmlFilePath != "" {
+		args = append(args, "--xml", params.XmlFilePath)
+	}
+	if params.HtmlFolderPath != "" {
+		args = append(args, "--html", params.HtmlFolderPath)
+	}
+	args = append(args, "--name", COVERAGE_REPORT_NAME)
+	return args
+}
+
+func runCoverageReportProcess(processFactory ProcessFactory, cmd string, args []string) error {
+	var err error
+	stdOut := bytes.NewBuffer([]byte{})
+	stdErr := bytes.NewBuffer([]byte{})
+
+	log.Printf("Creating the code coverage report using command '%s' '%v'\n", cmd, args)
+	process := processFactory.NewProcess()
+	err = process.Start(cmd, args, nil, stdOut, stdErr)
+	if err == nil {
+		err = process.Wait()
+	}
+	log.Printf("JaCoCo output: %s\n", stdOut.String())
+
+	if err != nil {
+		reason := strings.TrimSpace(stdErr.String())
+		if reason == "" {
+			reason = err.Error()
+		}
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_COVERAGE_REPORT_FAILED, reason)
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/embedded"
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCoverageReportMockFactory() (*utils.MockFactory, spi.GalasaHome) {
+	env := utils.NewMockEnv()
+	env.EnvVars["JAVA_HOME"] = "/java"
+	fs := files.NewMockFileSystem()
+	utils.AddJavaRuntimeToMock(fs, "/java")
+	galasaHome, _ := utils.NewGalasaHome(fs, env, "")
+	factory := &utils.MockFactory{
+		Env:           env,
+		FileSystem:    fs,
+		StdOutConsole: utils.NewMockConsole(),
+	}
+	return factory, galasaHome
+}
+
+func TestReportCoverageMergesTheCoverageOfEveryRunInTheRasFolder(t *testing.T) {
+	// Given...
+	factory, galasaHome := newCoverageReportMockFactory()
+	fs := factory.FileSystem
+	fs.MkdirAll("/User/Home/testuser/.galasa/ras")
+	fs.WriteBinaryFile("/User/Home/testuser/.galasa/ras/L2/coverage/L2.exec", []byte("L2"))
+	fs.WriteBinaryFile("/User/Home/testuser/.galasa/ras/L1/coverage/L1.exec", []byte("L1"))
+	fs.WriteBinaryFile("/User/Home/testuser/.galasa/ras/L1/artifacts/other.exec", []byte("not coverage"))
+	fs.WriteTextFile("/User/Home/testuser/.galasa/ras/L3/structure.json", "{}")
+	mockProcess := NewMockProcess()
+
+	params := CoverageReportParameters{
+		ClassFilePaths:  []string{"bank/target/classes", "payments/target/classes"},
+		SourceFilePaths: []string{"bank/src/main/java"},
+		XmlFilePath:     "coverage.xml",
+		HtmlFolderPath:  "coverage-html",
+	}
+
+	// When...
+	err := ReportCoverage(factory, embedded.NewMockReadOnlyFileSystem(), galasaHome, NewMockProcessFactory(mockProcess), params)
+
+	// Then...
+	assert.Nil(t, err)
+	cliJarPath, _ := utils.GetJacocoCliJarPath(fs, galasaHome)
+	isCliUnpacked, _ := fs.Exists(cliJarPath)
+	assert.True(t, isCliUnpacked)
+
+	assert.Equal(t, "/java/bin/java", mockProcess.cmd)
+	assert.Equal(t, []string{
+		"-jar", cliJarPath, "report",
+		"/User/Home/testuser/.galasa/ras/L1/coverage/L1.exec",
+		"/User/Home/testuser/.galasa/ras/L2/coverage/L2.exec",
+		"--classfiles", "bank/target/classes",
+		"--classfiles", "payments/target/classes",
+		"--sourcefiles", "bank/src/main/java",
+		"--xml", "coverage.xml",
+		"--html", "coverage-html",
+		"--name", "Galasa local test runs",
+	}, mockProcess.args)
+
+	console := factory.StdOutConsole.(*utils.MockConsole)
+	assert.Equal(t, "GAL2528I: The merged code coverage of 2 runs was reported to 'coverage.xml' and 'coverage-html'.\n", console.ReadText())
+}
+
+func TestReportCoverageOfNamedRunsInAnotherRasFolder(t *testing.T) {
+	// Given...
+	factory, galasaHome := newCoverageReportMockFactory()
+	fs := factory.FileSystem
+	fs.WriteBinaryFile("/my/ras/L1/coverage/L1.exec", []byte("L1"))
+	fs.WriteBinaryFile("/my/ras/L2/coverage/L2.exec", []byte("L2"))
+	mockProcess := NewMockProcess()
+
+	params := CoverageReportParameters{
+		RasFolderPath:  "/my/ras",
+		RunNames:       []string{"L2"},
+		ClassFilePaths: []string{"classes"},
+		XmlFilePath:    "coverage.xml",
+	}
+
+	// When...
+	err := ReportCoverage(factory, embedded.NewMockReadOnlyFileSystem(), galasaHome, NewMockProcessFactory(mockProcess), params)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Contains(t, mockProcess.args, "/my/ras/L2/coverage/L2.exec")
+	assert.NotContains(t, mockProcess.args, "/my/ras/L1/coverage/L1.exec")
+	assert.NotContains(t, mockProcess.args, "--html")
+}
+
+func TestReportCoverageOfRunWithNoCoverageReturnsError(t *testing.T) {
+	factory, galasaHome := newCoverageReportMockFactory()
+	mockProcess := NewMockProcess()
+
+	params := CoverageReportParameters{RunNames: []string{"L9"}, ClassFilePaths: []string{"classes"}, XmlFilePath: "coverage.xml"}
+
+	err := ReportCoverage(factory, embedded.NewMockReadOnlyFileSystem(), galasaHome, NewMockProcessFactory(mockProcess), params)
+
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1302E")
+	assert.ErrorContains(t, err, "/User/Home/testuser/.galasa/ras/L9/coverage/L9.exec")
+	assert.Equal(t, "", mockProcess.cmd)
+}
+
+func TestReportCoverageWithNoCoverageInTheRasFolderReturnsError(t *testing.T) {
+	factory, galasaHome := newCoverageReportMockFactory()
+
+	params := CoverageReportParameters{ClassFilePaths: []string{"classes"}, XmlFilePath: "coverage.xml"}
+
+	err := ReportCoverage(factory, embedded.NewMockReadOnlyFileSystem(), galasaHome, NewMockProcessFactory(NewMockProcess()), params)
+
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1303E")
+}
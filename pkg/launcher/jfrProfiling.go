@@ -0,0 +1,344 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/spi"
+)
+
+// A local test can be profiled using the Java Flight Recorder (JFR) built into the JVM. The recording
+// is saved into the RAS folder of the run, as ras/<runId>/profiling/<runId>.jfr and a short summary of it
+// is shown once the test is complete.
+
+const (
+	PROFILER_JFR = "jfr"
+
+	// The settings shipped with the JVM which sample the running methods more often than the 'default' ones.
+	DEFAULT_JFR_SETTINGS = "profile"
+
+	// The name of the folder within the RAS folder of a run which holds its recording.
+	PROFILING_FOLDER_NAME = "profiling"
+
+	// The number of methods listed in the summary of a recording.
+	JFR_HOT_METHOD_COUNT = 5
+
+	// The JFR events the summary is made from.
+	JFR_EVENT_EXECUTION_SAMPLE         = "jdk.ExecutionSample"
+	JFR_EVENT_GARBAGE_COLLECTION       = "jdk.GarbageCollection"
+	JFR_EVENT_OBJECT_ALLOCATION_SAMPLE = "jdk.ObjectAllocationSample"
+)
+
+// ProfilingOptions say how the JVM of each local test is profiled.
+type ProfilingOptions struct {
+	// The JFR settings to record with. Either the name of settings shipped with the JVM, or the path to a .jfc file.
+	JfrSettings string
+}
+
+// NewProfilingOptions checks the value of the --profile flag, which is of the form jfr[:settings]
+// Returns nil if no profiling is wanted.
+func NewProfilingOptions(profileFlagValue string) (*ProfilingOptions, error) {
+	var err error
+	var options *ProfilingOptions
+
+	if profileFlagValue != "" {
+		parts := strings.SplitN(profileFlagValue, ":", 2)
+		if parts[0] != PROFILER_JFR || (len(parts) > 1 && parts[1] == "") {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_PROFILER, profileFlagValue)
+		} else {
+			options = &ProfilingOptions{JfrSettings: DEFAULT_JFR_SETTINGS}
+			if len(parts) > 1 {
+				options.JfrSettings = parts[1]
+			}
+		}
+	}
+	return options, err
+}
+
+// JfrReader is something which can turn a JFR recording into JSON, so the events in it can be summarised.
+// This allows unit tests to supply recordings without needing a JVM.
+type JfrReader interface {
+	ReadEventsAsJson(recordingFilePath string, eventNames []string) ([]byte, error)
+}
+
+// Uses the 'jfr' tool which comes with the JVM.
+type realJfrReader struct {
+	jfrToolPath string
+}
+
+func newRealJfrReader(javaHome string, separator string) JfrReader {
+	reader := new(realJfrReader)
+	reader.jfrToolPath = javaHome + separator + "bin" + separator + "jfr"
+	return reader
+}
+
+func (reader *realJfrReader) ReadEventsAsJson(recordingFilePath string, eventNames []string) ([]byte, error) {
+	command := exec.Command(reader.jfrToolPath, "print", "--json", "--events", strings.Join(eventNames, ","), recordingFilePath)
+	return command.Output()
+}
+
+// jfrRecording is the recording of a single local test.
+type jfrRecording struct {
+	fileSystem spi.FileSystem
+	reader     JfrReader
+	settings   string
+
+	temporaryFolderPath string
+	recordingFilePath   string
+
+	// Set once the JVM has ended and the recording has been saved into the RAS folder of the run.
+	savedFilePath string
+	summary       *JfrSummary
+	err           error
+}
+
+func newJfrRecording(fileSystem spi.FileSystem, reader JfrReader, options *ProfilingOptions) (*jfrRecording, error) {
+	var err error
+	recording := new(jfrRecording)
+	recording.fileSystem = fileSystem
+	recording.reader = reader
+	recording.settings = options.JfrSettings
+
+	recording.temporaryFolderPath, err = fileSystem.MkTempDir()
+	if err == nil {
+		recording.recordingFilePath = recording.temporaryFolderPath + fileSystem.GetFilePathSeparator() + "recording.jfr"
+	} else {
+		recording = nil
+	}
+	return recording, err
+}
+
+// The JVM option which starts the recording as soon as the JVM starts, and writes it out when the JVM exits.
+func (recording *jfrRecording) getJvmArg() string {
+	return "-XX:StartFlightRecording=settings=" + recording.settings + ",dumponexit=true,filename=" + recording.recordingFilePath
+}
+
+// save moves the recording into the RAS folder of the run, then summarises it.
+// This must only be called once the JVM has ended.
+func (recording *jfrRecording) save(rasFolderPath string, runId string) {
+	defer recording.discard()
+
+	savedFilePath, err := saveIntoRunRasFolder(recording.fileSystem, recording.recordingFilePath, rasFolderPath, runId, PROFILING_FOLDER_NAME, "jfr")
+
+	if err != nil {
+		recording.err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_PROFILE_NOT_SAVED, runId, savedFilePath, err.Error())
+	} else {
+		recording.savedFilePath = savedFilePath
+		log.Printf("Saved the JFR recording of run %s to '%s'\n", runId, savedFilePath)
+
+		var eventsJson []byte
+		eventsJson, err = recording.reader.ReadEventsAsJson(savedFilePath, []string{
+			JFR_EVENT_EXECUTION_SAMPLE, JFR_EVENT_GARBAGE_COLLECTION, JFR_EVENT_OBJECT_ALLOCATION_SAMPLE})
+		if err == nil {
+			recording.summary, err = summariseJfrEvents(eventsJson)
+		}
+		if err != nil {
+			recording.err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_PROFILE_NOT_SUMMARISED, savedFilePath, err.Error())
+		}
+	}
+
+	if recording.err != nil {
+		log.Println(recording.err.Error())
+	}
+}
+
+func (recording *jfrRecording) discard() {
+	recording.fileSystem.DeleteDir(recording.temporaryFolderPath)
+}
+
+// getReport says where the recording was saved, and what it shows, or why it couldn't be.
+func (recording *jfrRecording) getReport(runId string) string {
+	var report strings.Builder
+	if recording.savedFilePath != "" {
+		report.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_PROFILE_SAVED.Template, runId, recording.savedFilePath))
+	}
+	if recording.summary != nil {
+		report.WriteString(recording.summary.String())
+	}
+	if recording.err != nil {
+		report.WriteString(recording.err.Error() + "\n")
+	}
+	return report.String()
+}
+
+// JfrSummary is what a recording shows about where the time went, and how hard the garbage collector worked.
+type JfrSummary struct {
+	// The methods running most often when the running threads were sampled, busiest first.
+	HotMethods       []JfrHotMethod
+	ExecutionSamples int
+
+	GcCount        int
+	GcPauseTotal   time.Duration
+	GcPauseLongest time.Duration
+
+	// Estimated from the samples of allocated objects, over the time span of the recorded events.
+	AllocatedBytes               int64
+	AllocationRateBytesPerSecond float64
+}
+
+type JfrHotMethod struct {
+	Method  string
+	Samples int
+}
+
+func (summary *JfrSummary) String() string {
+	var buff strings.Builder
+
+	buff.WriteString(fmt.Sprintf("  Hot methods (from %d samples):\n", summary.ExecutionSamples))
+	for _, hotMethod := range summary.HotMethods {
+		percentage := float64(hotMethod.Samples) * 100 / float64(summary.ExecutionSamples)
+		buff.WriteString(fmt.Sprintf("    %5.1f%%  %s\n", percentage, hotMethod.Method))
+	}
+	if len(summary.HotMethods) == 0 {
+		buff.WriteString("    none\n")
+	}
+
+	buff.WriteString(fmt.Sprintf("  GC pauses: %d collections, %v paused in total, longest pause %v\n",
+		summary.GcCount, summary.GcPauseTotal, summary.GcPauseLongest))
+
+	buff.WriteString(fmt.Sprintf("  Allocation rate: %.1f MB/s (%.1f MB sampled)\n",
+		summary.AllocationRateBytesPerSecond/(1024*1024), float64(summary.AllocatedBytes)/(1024*1024)))
+
+	return buff.String()
+}
+
+// The JSON written by 'jfr print --json'. Only the fields used by the summary are read.
+type jfrJson struct {
+	Recording struct {
+		Events []jfrJsonEvent `json:"events"`
+	} `json:"recording"`
+}
+
+type jfrJsonEvent struct {
+	Type   string `json:"type"`
+	Values struct {
+		StartTime    string          `json:"startTime"`
+		SumOfPauses  string          `json:"sumOfPauses"`
+		LongestPause string          `json:"longestPause"`
+		Weight       json.RawMessage `json:"weight"`
+		StackTrace   *struct {
+			Frames []struct {
+				Method struct {
+					Type struct {
+						Name string `json:"name"`
+					} `json:"type"`
+					Name string `json:"name"`
+				} `json:"method"`
+			} `json:"frames"`
+		} `json:"stackTrace"`
+	} `json:"values"`
+}
+
+func summariseJfrEvents(eventsJson []byte) (*JfrSummary, error) {
+	var err error
+	var recording jfrJson
+	var summary *JfrSummary
+
+	err = json.Unmarshal(eventsJson, &recording)
+	if err == nil {
+		summary = new(JfrSummary)
+		methodSamples := make(map[string]int)
+		var firstEventTime, lastEventTime time.Time
+
+		for _, event := range recording.Recording.Events {
+			eventTime, timeErr := time.Parse(time.RFC3339Nano, event.Values.StartTime)
+			if timeErr == nil {
+				if firstEventTime.IsZero() || eventTime.Before(firstEventTime) {
+					firstEventTime = eventTime
+				}
+				if eventTime.After(lastEventTime) {
+					lastEventTime = eventTime
+				}
+			}
+
+			switch event.Type {
+			case JFR_EVENT_EXECUTION_SAMPLE:
+				// The method at the top of the stack is the one which was running.
+				if event.Values.StackTrace != nil && len(event.Values.StackTrace.Frames) > 0 {
+					method := event.Values.StackTrace.Frames[0].Method
+					methodSamples[method.Type.Name+"."+method.Name]++
+					summary.ExecutionSamples++
+				}
+			case JFR_EVENT_GARBAGE_COLLECTION:
+				summary.GcCount++
+				summary.GcPauseTotal += parseJfrDuration(event.Values.SumOfPauses)
+				longestPause := parseJfrDuration(event.Values.LongestPause)
+				if longestPause > summary.GcPauseLongest {
+					summary.GcPauseLongest = longestPause
+				}
+			case JFR_EVENT_OBJECT_ALLOCATION_SAMPLE:
+				summary.AllocatedBytes += parseJfrNumber(event.Values.Weight)
+			}
+		}
+
+		summary.HotMethods = getHotMethods(methodSamples)
+
+		recordedSeconds := lastEventTime.Sub(firstEventTime).Seconds()
+		if recordedSeconds > 0 {
+			summary.AllocationRateBytesPerSecond = float64(summary.AllocatedBytes) / recordedSeconds
+		}
+	}
+	return summary, err
+}
+
+// The busiest methods first. Methods with the same number of samples are sorted by name, so the order is always the same.
+func getHotMethods(methodSamples map[string]int) []JfrHotMethod {
+	hotMethods := make([]JfrHotMethod, 0, len(methodSamples))
+	for method, samples := range methodSamples {
+		hotMethods = append(hotMethods, JfrHotMethod{Method: method, Samples: samples})
+	}
+	sort.Slice(hotMethods, func(i, j int) bool {
+		if hotMethods[i].Samples != hotMethods[j].Samples {
+			return hotMethods[i].Samples > hotMethods[j].Samples
+		}
+		return hotMethods[i].Method < hotMethods[j].Method
+	})
+	if len(hotMethods) > JFR_HOT_METHOD_COUNT {
+		hotMethods = hotMethods[:JFR_HOT_METHOD_COUNT]
+	}
+	return hotMethods
+}
+
+// Durations are written as ISO-8601 durations, such as PT0.0123S or PT1M2.5S
+// Anything which can't be understood counts as no time at all.
+func parseJfrDuration(isoDuration string) time.Duration {
+	var duration time.Duration
+	remaining := strings.TrimPrefix(isoDuration, "PT")
+	if remaining != isoDuration {
+		units := map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+		for remaining != "" {
+			unitIndex := strings.IndexAny(remaining, "HMS")
+			if unitIndex < 1 {
+				break
+			}
+			amount, err := strconv.ParseFloat(remaining[:unitIndex], 64)
+			if err != nil {
+				break
+			}
+			duration += time.Duration(amount * float64(units[remaining[unitIndex]]))
+			remaining = remaining[unitIndex+1:]
+		}
+	}
+	return duration
+}
+
+// Amounts of data are written as plain numbers, but may be quoted.
+func parseJfrNumber(rawValue json.RawMessage) int64 {
+	value, err := strconv.ParseInt(strings.Trim(string(rawValue), `"`), 10, 64)
+	if err != nil {
+		value = 0
+	}
+	return value
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/stretchr/testify/assert"
+)
+
+// Events like those written by 'jfr print --json', cut down to the fields which are summarised.
+const JFR_EVENTS_JSON = `{
+  "recording": {
+    "events": [
+      {
+        "type": "jdk.ExecutionSample",
+        "values": {
+          "startTime": "2024-10-01T10:00:00.000000000+01:00",
+          "stackTrace": {"truncated": false, "frames": [
+            {"method": {"type": {"name": "java.util.HashMap"}, "name": "get"}, "lineNumber": 10, "type": "JIT compiled"},
+            {"method": {"type": {"name": "dev.galasa.example.TestBank"}, "name": "testAccount"}, "lineNumber": 20, "type": "Interpreted"}
+          ]}
+        }
+      },
+      {
+        "type": "jdk.ExecutionSample",
+        "values": {
+          "startTime": "2024-10-01T10:00:01.000000000+01:00",
+          "stackTrace": {"truncated": false, "frames": [
+            {"method": {"type": {"name": "java.util.HashMap"}, "name": "get"}, "lineNumber": 10, "type": "JIT compiled"}
+          ]}
+        }
+      },
+      {
+        "type": "jdk.ExecutionSample",
+        "values": {
+          "startTime": "2024-10-01T10:00:01.500000000+01:00",
+          "stackTrace": {"truncated": false, "frames": [
+            {"method": {"type": {"name": "java.lang.String"}, "name": "indexOf"}, "lineNumber": 5, "type": "JIT compiled"}
+          ]}
+        }
+      },
+      {
+        "type": "jdk.GarbageCollection",
+        "values": {
+          "startTime": "2024-10-01T10:00:01.000000000+01:00",
+          "gcId": 1,
+          "name": "G1New",
+          "sumOfPauses": "PT0.0025S",
+          "longestPause": "PT0.002S"
+        }
+      },
+      {
+        "type": "jdk.GarbageCollection",
+        "values": {
+          "startTime": "2024-10-01T10:00:01.200000000+01:00",
+          "gcId": 2,
+          "name": "G1Old",
+          "sumOfPauses": "PT0.0125S",
+          "longestPause": "PT0.01S"
+        }
+      },
+      {
+        "type": "jdk.ObjectAllocationSample",
+        "values": {
+          "startTime": "2024-10-01T10:00:02.000000000+01:00",
+          "weight": 4194304
+        }
+      }
+    ]
+  }
+}`
+
+type mockJfrReader struct {
+	eventsJson []byte
+	err        error
+}
+
+func (reader *mockJfrReader) ReadEventsAsJson(recordingFilePath string, eventNames []string) ([]byte, error) {
+	return reader.eventsJson, reader.err
+}
+
+func TestNewProfilingOptionsWithNoValueIsNil(t *testing.T) {
+	options, err := NewProfilingOptions("")
+
+	assert.Nil(t, err)
+	assert.Nil(t, options)
+}
+
+func TestNewProfilingOptionsDefaultsTheSettings(t *testing.T) {
+	options, err := NewProfilingOptions("jfr")
+
+	assert.Nil(t, err)
+	assert.Equal(t, &ProfilingOptions{JfrSettings: "profile"}, options)
+}
+
+func TestNewProfilingOptionsKeepsSettingsWhichArePaths(t *testing.T) {
+	options, err := NewProfilingOptions("jfr:C:/my/settings.jfc")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "C:/my/settings.jfc", options.JfrSettings)
+}
+
+func TestNewProfilingOptionsWithUnknownProfilerReturnsError(t *testing.T) {
+	for _, badValue := range []string{"async", "jfr:", "JFR"} {
+		_, err := NewProfilingOptions(badValue)
+
+		assert.NotNil(t, err, badValue)
+		assert.ErrorContains(t, err, "GAL1297E")
+	}
+}
+
+func TestJfrRecordingJvmArgWritesIntoATemporaryFolder(t *testing.T) {
+	fs := files.NewMockFileSystem()
+	recording, err := newJfrRecording(fs, &mockJfrReader{}, &ProfilingOptions{JfrSettings: "default"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "-XX:StartFlightRecording=settings=default,dumponexit=true,filename="+recording.temporaryFolderPath+"/recording.jfr", recording.getJvmArg())
+}
+
+func TestJfrRecordingIsSavedIntoTheRasFolderAndSummarised(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	recording, _ := newJfrRecording(fs, &mockJfrReader{eventsJson: []byte(JFR_EVENTS_JSON)}, &ProfilingOptions{JfrSettings: "profile"})
+	fs.WriteBinaryFile(recording.recordingFilePath, []byte("recorded"))
+
+	// When...
+	recording.save("/home/.galasa/ras", "L12")
+
+	// Then...
+	assert.Nil(t, recording.err)
+	saved, _ := fs.ReadBinaryFile("/home/.galasa/ras/L12/profiling/L12.jfr")
+	assert.Equal(t, []byte("recorded"), saved)
+	isTempLeft, _ := fs.DirExists(recording.temporaryFolderPath)
+	assert.False(t, isTempLeft)
+
+	assert.Equal(t, "GAL2526I: The Java Flight Recorder recording of run 'L12' was saved to '/home/.galasa/ras/L12/profiling/L12.jfr'.\n"+
+		"  Hot methods (from 3 samples):\n"+
+		"     66.7%  java.util.HashMap.get\n"+
+		"     33.3%  java.lang.String.indexOf\n"+
+		"  GC pauses: 2 collections, 15ms paused in total, longest pause 10ms\n"+
+		"  Allocation rate: 2.0 MB/s (4.0 MB sampled)\n",
+		recording.getReport("L12"))
+}
+
+func TestJfrRecordingWhichCantBeSummarisedIsStillSaved(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	recording, _ := newJfrRecording(fs, &mockJfrReader{err: errors.New("jfr tool not found")}, &ProfilingOptions{JfrSettings: "profile"})
+	fs.WriteBinaryFile(recording.recordingFilePath, []byte("recorded"))
+
+	// When...
+	recording.save("/ras", "L12")
+
+	// Then...
+	isSaved, _ := fs.Exists("/ras/L12/profiling/L12.jfr")
+	assert.True(t, isSaved)
+	report := recording.getReport("L12")
+	assert.Contains(t, report, "GAL2526I")
+	assert.Contains(t, report, "GAL1299E")
+	assert.Contains(t, report, "jfr tool not found")
+}
+
+func TestJfrRecordingWhichWasNeverWrittenReportsError(t *testing.T) {
+	fs := files.NewMockFileSystem()
+	recording, _ := newJfrRecording(fs, &mockJfrReader{}, &ProfilingOptions{JfrSettings: "profile"})
+
+	recording.save("/ras", "L12")
+
+	assert.NotNil(t, recording.err)
+	assert.ErrorContains(t, recording.err, "GAL1298E")
+	assert.NotContains(t, recording.getReport("L12"), "GAL2526I")
+}
+
+func TestSummariseJfrEventsWithBadJsonReturnsError(t *testing.T) {
+	_, err := summariseJfrEvents([]byte("not json"))
+
+	assert.NotNil(t, err)
+}
+
+func TestParseJfrDuration(t *testing.T) {
+	assert.Equal(t, 12300*time.Microsecond, parseJfrDuration("PT0.0123S"))
+	assert.Equal(t, time.Minute+2500*time.Millisecond, parseJfrDuration("PT1M2.5S"))
+	assert.Equal(t, 2*time.Hour, parseJfrDuration("PT2H"))
+	assert.Equal(t, time.Duration(0), parseJfrDuration("12 ms"))
+}
+
+func TestHotMethodsAreLimitedToTheBusiest(t *testing.T) {
+	methodSamples := map[string]int{"a": 1, "b": 7, "c": 3, "d": 3, "e": 2, "f": 9}
+
+	hotMethods := getHotMethods(methodSamples)
+
+	assert.Equal(t, []JfrHotMethod{{"f", 9}, {"b", 7}, {"c", 3}, {"d", 3}, {"e", 2}}, hotMethods)
+}
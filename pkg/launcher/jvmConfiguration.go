@@ -0,0 +1,127 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"sort"
+	"strings"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/props"
+)
+
+// System properties which galasactl sets for every local test run, so can't be set by the user.
+var RESERVED_JVM_SYSTEM_PROPERTIES = []string{"GALASA_HOME", "GALASA_JWT"}
+
+// JvmConfiguration is how the JVM of a local test run is configured, on top of what galasactl always needs.
+// It is recorded in the report of each local run, so the run can be repeated in the same JVM set-up.
+//
+// Where options clash, the last one given to the JVM wins, so the precedence is:
+// - the options in the galasactl.jvm.local.launch.options bootstrap property, which come first
+// - then the --jvm-arg flags, in the order they are given
+// - then the --jvm-system-property flags, which win over any -D options set in either of the above.
+type JvmConfiguration struct {
+	// The JVM options, from the bootstrap properties followed by those from the command-line.
+	Args []string `yaml:"args,omitempty" json:"args,omitempty"`
+
+	// The system properties from the command-line, each of which is passed to the JVM as -Dkey=value
+	SystemProperties map[string]string `yaml:"systemProperties,omitempty" json:"systemProperties,omitempty"`
+
+	// Environment variables from the command-line, which are added to those the JVM inherits from galasactl.
+	Environment map[string]string `yaml:"environment,omitempty" json:"environment,omitempty"`
+}
+
+// NewJvmConfiguration merges the JVM options in the bootstrap properties with those from the command-line.
+// The system properties and environment variables are each of the form key=value
+func NewJvmConfiguration(
+	bootstrapProperties props.JavaProperties,
+	jvmArgs []string,
+	jvmSystemProperties []string,
+	jvmEnvironment []string,
+) (*JvmConfiguration, error) {
+	var err error
+
+	jvmConfiguration := newBootstrapJvmConfiguration(bootstrapProperties)
+	jvmConfiguration.Args = append(jvmConfiguration.Args, jvmArgs...)
+
+	jvmConfiguration.SystemProperties, err = parseKeyValuePairs(jvmSystemProperties, "jvm-system-property")
+	if err == nil {
+		for _, reservedKey := range RESERVED_JVM_SYSTEM_PROPERTIES {
+			if _, isSet := jvmConfiguration.SystemProperties[reservedKey]; isSet {
+				err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_JVM_SYSTEM_PROPERTY_RESERVED, reservedKey)
+				break
+			}
+		}
+	}
+
+	if err == nil {
+		jvmConfiguration.Environment, err = parseKeyValuePairs(jvmEnvironment, "jvm-env")
+	}
+
+	if err != nil {
+		jvmConfiguration = nil
+	}
+	return jvmConfiguration, err
+}
+
+// The JVM is only configured by the bootstrap properties when nothing is said on the command-line.
+func newBootstrapJvmConfiguration(bootstrapProperties props.JavaProperties) *JvmConfiguration {
+	jvmConfiguration := new(JvmConfiguration)
+	jvmConfiguration.Args = appendArgsBootstrapJvmLaunchOptions(make([]string, 0), bootstrapProperties)
+	jvmConfiguration.SystemProperties = make(map[string]string)
+	jvmConfiguration.Environment = make(map[string]string)
+	return jvmConfiguration
+}
+
+// withExtraArgs returns a copy of the configuration with some more JVM options after the others.
+func (jvmConfiguration *JvmConfiguration) withExtraArgs(extraArgs ...string) *JvmConfiguration {
+	extendedConfiguration := *jvmConfiguration
+	extendedConfiguration.Args = make([]string, 0, len(jvmConfiguration.Args)+len(extraArgs))
+	extendedConfiguration.Args = append(extendedConfiguration.Args, jvmConfiguration.Args...)
+	extendedConfiguration.Args = append(extendedConfiguration.Args, extraArgs...)
+	return &extendedConfiguration
+}
+
+// The values are split at the first '=', so the value itself may contain '=' characters.
+func parseKeyValuePairs(keyValuePairs []string, flagName string) (map[string]string, error) {
+	var err error
+	parsed := make(map[string]string)
+
+	for _, keyValuePair := range keyValuePairs {
+		parts := strings.SplitN(keyValuePair, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) < 2 || key == "" {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_JVM_KEY_VALUE, keyValuePair, flagName)
+			break
+		}
+		parsed[key] = parts[1]
+	}
+	return parsed, err
+}
+
+// Gets the system properties as JVM options, sorted by key so the command-line is always the same.
+func (jvmConfiguration *JvmConfiguration) getSystemPropertyArgs() []string {
+	return toSortedKeyValuePairs(jvmConfiguration.SystemProperties, "-D")
+}
+
+// Gets the environment variables in the key=value form a process expects, sorted by key.
+func (jvmConfiguration *JvmConfiguration) getEnvironmentVariables() []string {
+	return toSortedKeyValuePairs(jvmConfiguration.Environment, "")
+}
+
+func toSortedKeyValuePairs(values map[string]string, prefix string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	keyValuePairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		keyValuePairs = append(keyValuePairs, prefix+key+"="+values[key])
+	}
+	return keyValuePairs
+}
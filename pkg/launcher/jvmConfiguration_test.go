@@ -0,0 +1,64 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/props"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewJvmConfigurationPutsCommandLineArgsAfterBootstrapOptions(t *testing.T) {
+	bootstrapProps := props.JavaProperties{}
+	bootstrapProps[api.BOOTSTRAP_PROPERTY_NAME_LOCAL_JVM_LAUNCH_OPTIONS] = "-Xmx40m -Xms20m"
+
+	jvmConfiguration, err := NewJvmConfiguration(bootstrapProps, []string{"-Xmx1g", "-javaagent:/my/agent.jar=opt1,opt2"}, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"-Xmx40m", "-Xms20m", "-Xmx1g", "-javaagent:/my/agent.jar=opt1,opt2"}, jvmConfiguration.Args)
+	assert.Empty(t, jvmConfiguration.SystemProperties)
+	assert.Empty(t, jvmConfiguration.Environment)
+}
+
+func TestNewJvmConfigurationParsesKeyValuePairs(t *testing.T) {
+	jvmConfiguration, err := NewJvmConfiguration(props.JavaProperties{},
+		nil,
+		[]string{"my.prop=a=b", "my.empty.prop="},
+		[]string{"MY_VAR=myValue", "OTHER_VAR=x"},
+	)
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"my.prop": "a=b", "my.empty.prop": ""}, jvmConfiguration.SystemProperties)
+	assert.Equal(t, []string{"-Dmy.empty.prop=", "-Dmy.prop=a=b"}, jvmConfiguration.getSystemPropertyArgs())
+	assert.Equal(t, []string{"MY_VAR=myValue", "OTHER_VAR=x"}, jvmConfiguration.getEnvironmentVariables())
+}
+
+func TestNewJvmConfigurationWithNoEqualsReturnsError(t *testing.T) {
+	jvmConfiguration, err := NewJvmConfiguration(props.JavaProperties{}, nil, nil, []string{"MY_VAR"})
+
+	assert.Nil(t, jvmConfiguration)
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1295E")
+	assert.ErrorContains(t, err, "--jvm-env")
+}
+
+func TestNewJvmConfigurationWithBlankKeyReturnsError(t *testing.T) {
+	_, err := NewJvmConfiguration(props.JavaProperties{}, nil, []string{" =value"}, nil)
+
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1295E")
+	assert.ErrorContains(t, err, "--jvm-system-property")
+}
+
+func TestNewJvmConfigurationWithReservedSystemPropertyReturnsError(t *testing.T) {
+	_, err := NewJvmConfiguration(props.JavaProperties{}, nil, []string{"GALASA_HOME=/somewhere/else"}, nil)
+
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1296E")
+	assert.ErrorContains(t, err, "GALASA_HOME")
+}
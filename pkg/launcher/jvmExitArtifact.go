@@ -0,0 +1,53 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"github.com/galasa-dev/cli/pkg/spi"
+)
+
+// jvmExitArtifact is a file the JVM of a local test writes out as it exits, such as a recording of how the test ran.
+// The JVM writes it into a temporary folder, as the RAS folder of the run isn't known until the test has started.
+type jvmExitArtifact interface {
+	// The JVM option which makes the JVM write the file.
+	getJvmArg() string
+
+	// Moves the file into the RAS folder of the run. This must only be called once the JVM has ended.
+	save(rasFolderPath string, runId string)
+
+	// Throws the file away, when the test ended before it had a RAS folder to save it into.
+	discard()
+
+	// Says where the file was saved, and what it shows, or why it couldn't be.
+	getReport(runId string) string
+}
+
+// Copies a file written by the JVM into a folder of its own within the RAS folder of the run,
+// as ras/<runId>/<folderName>/<runId>.<extension>
+// Returns the path the file was saved to.
+func saveIntoRunRasFolder(
+	fileSystem spi.FileSystem,
+	filePath string,
+	rasFolderPath string,
+	runId string,
+	folderName string,
+	extension string,
+) (string, error) {
+	var err error
+	var contents []byte
+
+	folderPath := rasFolderPath + "/" + runId + "/" + folderName
+	savedFilePath := folderPath + "/" + runId + "." + extension
+
+	contents, err = fileSystem.ReadBinaryFile(filePath)
+	if err == nil {
+		err = fileSystem.MkdirAll(folderPath)
+	}
+	if err == nil {
+		err = fileSystem.WriteBinaryFile(savedFilePath, contents)
+	}
+	return savedFilePath, err
+}
@@ -11,6 +11,7 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/galasa-dev/cli/pkg/api"
 	"github.com/galasa-dev/cli/pkg/embedded"
@@ -72,8 +73,30 @@ type JvmLauncher struct {
 	// A map of bootstrap properties
 	bootstrapProps props.JavaProperties
 
+	// The JVM options, system properties and environment variables each test JVM is launched with.
+	jvmConfiguration *JvmConfiguration
+
+	// How each test JVM is profiled, or nil if it isn't.
+	profilingOptions *ProfilingOptions
+
+	// Summarises the recordings of tests which are profiled.
+	jfrReader JfrReader
+
+	// The JaCoCo agent jar in the Galasa home folder, or blank if the code coverage of tests isn't collected.
+	jacocoAgentJarPath string
+
+	// Echoes the output of every test JVM to the console, or nil if the output isn't streamed.
+	outputStreamer *jvmOutputStreamer
+
+	// Gets thread dumps of test JVMs which hang.
+	threadDumper ThreadDumper
+
 	// So we can get common objects easily.
 	factory spi.Factory
+
+	// The OBRs known to be in the local Maven repository along with everything they need, or nil if none have been checked yet.
+	// This is only used when running --offline.
+	offlineCheckedObrs map[utils.MavenCoordinates]bool
 }
 
 // These parameters are gathered from the command-line and passed into the laucher.
@@ -107,6 +130,36 @@ type RunsSubmitLocalCmdParameters struct {
 
 	// A string containing the url of the gherkin test file to be exceuted
 	GherkinURL string
+
+	// Extra options for the JVM, such as -Xmx1g, added after those in the bootstrap properties.
+	JvmArgs []string
+
+	// Extra system properties for the JVM, each of the form key=value
+	JvmSystemProperties []string
+
+	// Extra environment variables for the JVM, each of the form key=value
+	JvmEnvironment []string
+
+	// How to profile each test, of the form jfr[:settings], or blank if tests aren't profiled.
+	Profile string
+
+	// Should the code coverage of each test be collected using the JaCoCo agent ?
+	IsCoverageEnabled bool
+
+	// Should the output of each test JVM be echoed to the console as it arrives ?
+	IsStreamingOutput bool
+
+	// A regular expression which the lines of streamed output must match to be shown, or blank to show every line.
+	OutputFilter string
+
+	// How many minutes a test JVM can write no output for before it is treated as hung, or 0 for no limit.
+	InactivityTimeoutMinutes int
+
+	// How many minutes a test JVM can run for before it is treated as hung, or 0 for no limit.
+	MaxDurationMinutes int
+
+	// Should the OBRs and bundles all come from the local Maven repository, without going to the remote one ?
+	IsOffline bool
 }
 
 const (
@@ -143,6 +196,39 @@ func NewJVMLauncher(
 
 	err = utils.ValidateJavaHome(fileSystem, javaHome)
 
+	var jvmConfiguration *JvmConfiguration
+	if err == nil {
+		jvmConfiguration, err = NewJvmConfiguration(
+			bootstrapProps,
+			runsSubmitLocalCmdParams.JvmArgs,
+			runsSubmitLocalCmdParams.JvmSystemProperties,
+			runsSubmitLocalCmdParams.JvmEnvironment,
+		)
+	}
+
+	var profilingOptions *ProfilingOptions
+	if err == nil {
+		profilingOptions, err = NewProfilingOptions(runsSubmitLocalCmdParams.Profile)
+	}
+
+	if err == nil {
+		err = validateWatchdogLimit(runsSubmitLocalCmdParams.InactivityTimeoutMinutes, "inactivity-timeout")
+		if err == nil {
+			err = validateWatchdogLimit(runsSubmitLocalCmdParams.MaxDurationMinutes, "max-duration")
+		}
+	}
+
+	var outputStreamer *jvmOutputStreamer
+	if err == nil {
+		if runsSubmitLocalCmdParams.IsStreamingOutput {
+			// The colours can be turned off in the usual way, by setting NO_COLOR.
+			isColoured := env.GetEnv("NO_COLOR") == ""
+			outputStreamer, err = newJvmOutputStreamer(factory.GetStdOutConsole(), runsSubmitLocalCmdParams.OutputFilter, isColoured)
+		} else if runsSubmitLocalCmdParams.OutputFilter != "" {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_OUTPUT_FILTER_WITHOUT_STREAM_OUTPUT)
+		}
+	}
+
 	if err == nil {
 		launcher = new(JvmLauncher)
 		launcher.factory = factory
@@ -156,6 +242,11 @@ func NewJVMLauncher(
 		launcher.timeService = factory.GetTimeService()
 		launcher.timedSleeper = timedSleeper
 		launcher.bootstrapProps = bootstrapProps
+		launcher.jvmConfiguration = jvmConfiguration
+		launcher.profilingOptions = profilingOptions
+		launcher.jfrReader = newRealJfrReader(javaHome, fileSystem.GetFilePathSeparator())
+		launcher.outputStreamer = outputStreamer
+		launcher.threadDumper = newRealThreadDumper(javaHome, fileSystem.GetFilePathSeparator())
 
 		// Make sure the home folder has the boot jar unpacked and ready to invoke.
 		err = utils.InitialiseGalasaHomeFolder(
@@ -165,9 +256,60 @@ func NewJVMLauncher(
 		)
 	}
 
+	if err == nil && runsSubmitLocalCmdParams.IsOffline {
+		// Anything missing from the local Maven repository is reported before any tests are launched.
+		var obrs []utils.MavenCoordinates
+		obrs, err = utils.ValidateObrs(runsSubmitLocalCmdParams.Obrs)
+		if err == nil {
+			err = launcher.checkObrsAreLocal(obrs)
+		}
+	}
+
+	if err == nil && runsSubmitLocalCmdParams.IsCoverageEnabled {
+		// The JaCoCo agent is only unpacked when it is needed.
+		err = utils.InstallJacocoJars(launcher.galasaHome, launcher.fileSystem, launcher.embeddedFileSystem)
+		if err == nil {
+			launcher.jacocoAgentJarPath, err = utils.GetJacocoAgentJarPath(launcher.fileSystem, launcher.galasaHome)
+		}
+	}
+
 	return launcher, err
 }
 
+// The OBR of a portfolio is only known as each of its tests is launched, so is checked then.
+func (launcher *JvmLauncher) checkObrsAreLocal(obrs []utils.MavenCoordinates) error {
+	var err error
+
+	isCheckNeeded := launcher.offlineCheckedObrs == nil
+	for _, obr := range obrs {
+		if !launcher.offlineCheckedObrs[obr] {
+			isCheckNeeded = true
+		}
+	}
+
+	if isCheckNeeded {
+		err = CheckLocalRunDependenciesAreLocal(
+			launcher.fileSystem, obrs, launcher.cmdParams.TargetGalasaVersion, launcher.cmdParams.LocalMaven)
+		if err == nil {
+			if launcher.offlineCheckedObrs == nil {
+				launcher.offlineCheckedObrs = make(map[utils.MavenCoordinates]bool)
+			}
+			for _, obr := range obrs {
+				launcher.offlineCheckedObrs[obr] = true
+			}
+		}
+	}
+	return err
+}
+
+func validateWatchdogLimit(limitMinutes int, flagName string) error {
+	var err error
+	if limitMinutes < 0 {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_WATCHDOG_LIMIT, limitMinutes, flagName)
+	}
+	return err
+}
+
 //-----------------------------------------------------------------------------
 // Implementation of the Launcher interface
 //-----------------------------------------------------------------------------
@@ -218,6 +360,9 @@ func (launcher *JvmLauncher) SubmitTestRun(
 			// There are no obrs ! We have no idea how to find the test!
 			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_NO_OBR_SPECIFIED_ON_INPUTS, className)
 		}
+		if err == nil && launcher.cmdParams.IsOffline {
+			err = launcher.checkObrsAreLocal(obrs)
+		}
 		if err == nil {
 
 			var (
@@ -263,49 +408,93 @@ func (launcher *JvmLauncher) SubmitTestRun(
 						jwt, err = authenticator.GetBearerToken()
 					}
 
+					// Each test being profiled or having its code coverage collected has files of its own,
+					// which its JVM writes out as it exits.
+					jvmConfiguration := launcher.jvmConfiguration
+					var jvmExitArtifacts []jvmExitArtifact
+					if err == nil {
+						jvmExitArtifacts, err = launcher.newJvmExitArtifacts()
+						for _, artifact := range jvmExitArtifacts {
+							jvmConfiguration = jvmConfiguration.withExtraArgs(artifact.getJvmArg())
+						}
+					}
+
+					// The output of every test JVM is saved into the RAS folder of its run.
+					var outputCapture *jvmOutputCapture
+					if err == nil {
+						outputCapture, err = newJvmOutputCapture(launcher.fileSystem)
+						if err != nil {
+							for _, artifact := range jvmExitArtifacts {
+								artifact.discard()
+							}
+						}
+					}
+
 					if err == nil {
 
 						var (
-							cmd  string
-							args []string
-						)
-						cmd, args, err = getCommandSyntax(
-							launcher.bootstrapProps,
-							launcher.galasaHome,
-							launcher.fileSystem, launcher.javaHome, obrs,
-							*testClassToLaunch, launcher.cmdParams.RemoteMaven, launcher.cmdParams.LocalMaven,
-							launcher.cmdParams.TargetGalasaVersion, overridesFilePath,
-							gherkinURL,
-							isTraceEnabled,
-							launcher.cmdParams.IsDebugEnabled,
-							launcher.cmdParams.DebugPort,
-							launcher.cmdParams.DebugMode,
-							jwt,
+							cmd         string
+							args        []string
+							remoteMaven string
 						)
+						remoteMaven, err = launcher.getRemoteMavenForJvm()
 						if err == nil {
+							cmd, args, err = getCommandSyntax(
+								launcher.bootstrapProps,
+								launcher.galasaHome,
+								launcher.fileSystem, launcher.javaHome, obrs,
+								*testClassToLaunch, remoteMaven, launcher.cmdParams.LocalMaven,
+								launcher.cmdParams.TargetGalasaVersion, overridesFilePath,
+								gherkinURL,
+								isTraceEnabled,
+								launcher.cmdParams.IsDebugEnabled,
+								launcher.cmdParams.DebugPort,
+								launcher.cmdParams.DebugMode,
+								jwt,
+								jvmConfiguration,
+							)
+						}
+						if err != nil {
+							// Nothing is launched, so nothing will be written.
+							outputCapture.discard()
+							for _, artifact := range jvmExitArtifacts {
+								artifact.discard()
+							}
+						} else {
 							log.Printf("Launching command '%s' '%v'\n", cmd, args)
 							localTest := NewLocalTest(launcher.timedSleeper, launcher.fileSystem, launcher.processFactory)
-							err = localTest.launch(cmd, args)
+							localTest.jvmExitArtifacts = jvmExitArtifacts
+							localTest.captureOutput(outputCapture, launcher.outputStreamer)
+							if launcher.isWatchingForHungTests() {
+								localTest.watch(newJvmWatchdog(
+									launcher.timeService,
+									time.Duration(launcher.cmdParams.InactivityTimeoutMinutes)*time.Minute,
+									time.Duration(launcher.cmdParams.MaxDurationMinutes)*time.Minute,
+									launcher.threadDumper,
+								))
+							}
+							err = localTest.launch(cmd, args, jvmConfiguration.getEnvironmentVariables())
 
 							if err == nil {
 								// The JVM process started. Store away its' details
 								launcher.localTests = append(launcher.localTests, localTest)
 
-								localTest.testRun = new(galasaapi.TestRun)
+								testRun := new(galasaapi.TestRun)
 								if testClassToLaunch.OSGiBundleName != "" {
-									localTest.testRun.SetBundleName(testClassToLaunch.OSGiBundleName)
+									testRun.SetBundleName(testClassToLaunch.OSGiBundleName)
 								}
-								localTest.testRun.SetStream(stream)
-								localTest.testRun.SetGroup(groupName)
-								localTest.testRun.SetRequestor(requestor)
-								localTest.testRun.SetTrace(isTraceEnabled)
-								localTest.testRun.SetType(requestType)
-								localTest.testRun.SetName(localTest.runId)
+								testRun.SetStream(stream)
+								testRun.SetGroup(groupName)
+								testRun.SetRequestor(requestor)
+								testRun.SetTrace(isTraceEnabled)
+								testRun.SetType(requestType)
+								testRun.SetName(localTest.getRunId())
 
-								localTest.testRun.SetSubmissionId("")
+								testRun.SetSubmissionId("")
+								localTest.setTestRun(testRun)
 
 								// The test run we started can be returned to the submitter.
-								testRuns.Runs = append(testRuns.Runs, *localTest.testRun)
+								testRuns.Runs = append(testRuns.Runs, *testRun)
 							}
 						}
 					}
@@ -317,6 +506,41 @@ func (launcher *JvmLauncher) SubmitTestRun(
 	return testRuns, err
 }
 
+// Creates the files which the JVM of a test writes out as it exits, for the profiling and code coverage wanted.
+func (launcher *JvmLauncher) newJvmExitArtifacts() ([]jvmExitArtifact, error) {
+	var err error
+	jvmExitArtifacts := make([]jvmExitArtifact, 0)
+
+	if launcher.profilingOptions != nil {
+		var recording *jfrRecording
+		recording, err = newJfrRecording(launcher.fileSystem, launcher.jfrReader, launcher.profilingOptions)
+		if err == nil {
+			jvmExitArtifacts = append(jvmExitArtifacts, recording)
+		}
+	}
+
+	if err == nil && launcher.jacocoAgentJarPath != "" {
+		var coverage *jacocoCoverage
+		coverage, err = newJacocoCoverage(launcher.fileSystem, launcher.jacocoAgentJarPath)
+		if err == nil {
+			jvmExitArtifacts = append(jvmExitArtifacts, coverage)
+		}
+	}
+
+	if err != nil {
+		for _, artifact := range jvmExitArtifacts {
+			artifact.discard()
+		}
+		jvmExitArtifacts = nil
+	}
+	return jvmExitArtifacts, err
+}
+
+// Test JVMs are only watched for hanging if there is a limit on how long they can be inactive or run for.
+func (launcher *JvmLauncher) isWatchingForHungTests() bool {
+	return launcher.cmdParams.InactivityTimeoutMinutes > 0 || launcher.cmdParams.MaxDurationMinutes > 0
+}
+
 // isCPSRemote - decide whether the config store used by tests is remote or not.
 // If it is remote, we are going to have to get a valid JWT to use.
 func (launcher *JvmLauncher) isCPSRemote() bool {
@@ -358,7 +582,8 @@ func buildListOfAllObrs(obrsFromCommandLine []string, obrFromPortfolio string) (
 		if obrFromPortfolio != "" {
 			var obrMavenCoordinates utils.MavenCoordinates
 			obrMavenCoordinates, err = utils.ValidateObr(obrFromPortfolio)
-			if err == nil {
+			// Tests selected from the test catalog of an OBR given on the command-line are recorded with that same OBR.
+			if err == nil && !isObrInList(obrs, obrMavenCoordinates) {
 				obrs = append(obrs, obrMavenCoordinates)
 			}
 		}
@@ -366,6 +591,17 @@ func buildListOfAllObrs(obrsFromCommandLine []string, obrFromPortfolio string) (
 	return obrs, err
 }
 
+func isObrInList(obrs []utils.MavenCoordinates, obrToFind utils.MavenCoordinates) bool {
+	isFound := false
+	for _, obr := range obrs {
+		if obr == obrToFind {
+			isFound = true
+			break
+		}
+	}
+	return isFound
+}
+
 func deleteTempFiles(fileSystem spi.FileSystem, temporaryFolderPath string) {
 	fileSystem.DeleteDir(temporaryFolderPath)
 }
@@ -476,19 +712,18 @@ func (launcher *JvmLauncher) GetRunsByGroup(groupName string) (*galasaapi.TestRu
 
 	for _, localTest := range launcher.localTests {
 
-		testName := localTest.testRun.GetName()
+		testName := localTest.getTestRun().GetName()
 
 		if localTest.isCompleted() {
 			log.Printf("GetRunsByGroup: localTest %s is complete.\n", testName)
+			launcher.reportCompletedTest(localTest)
 		} else {
 			log.Printf("GetRunsByGroup: localTest %s is not yet complete.\n", testName)
 			isAllComplete = false
 		}
 
-		var testRun *galasaapi.TestRun
-		if localTest.testRun != nil {
-			testRun = localTest.testRun
-		} else {
+		testRun := localTest.getTestRun()
+		if testRun == nil {
 			testRun = createSimulatedTestRun(testName)
 		}
 
@@ -502,6 +737,28 @@ func (launcher *JvmLauncher) GetRunsByGroup(groupName string) (*galasaapi.TestRu
 	return &testRuns, nil
 }
 
+// Tells the user whether a completed test hung, and what the files written by its JVM show,
+// the first time the test is seen to be complete.
+func (launcher *JvmLauncher) reportCompletedTest(localTest *LocalTest) {
+	if !localTest.isCompletionReported {
+		localTest.isCompletionReported = true
+		console := launcher.factory.GetStdOutConsole()
+
+		runId := localTest.getRunId()
+		hungReason := localTest.getHungReason()
+		if hungReason != "" {
+			console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_RUN_HUNG.Template, runId, hungReason))
+			if localTest.threadDumpFilePath != "" {
+				console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_THREAD_DUMP_SAVED.Template, runId, localTest.threadDumpFilePath))
+			}
+		}
+
+		for _, artifact := range localTest.jvmExitArtifacts {
+			console.WriteString(artifact.getReport(runId))
+		}
+	}
+}
+
 // GetRunsById gets the Run information for the run with a specific run identifier
 func (launcher *JvmLauncher) GetRunsById(runId string) (*galasaapi.Run, error) {
 	log.Printf("JvmLauncher: GetRunsById entered. runId=%s", runId)
@@ -511,7 +768,7 @@ func (launcher *JvmLauncher) GetRunsById(runId string) (*galasaapi.Run, error) {
 
 	for _, localTest := range launcher.localTests {
 
-		testRunId := localTest.runId
+		testRunId := localTest.getRunId()
 
 		if testRunId == runId {
 			log.Printf("JvmLauncher: GetRunsById - testRunId '%s' matches with what we're looking for: runId '%s'", testRunId, runId)
@@ -543,13 +800,14 @@ func createRunFromLocalTest(localTest *LocalTest) (*galasaapi.Run, error) {
 	var run = galasaapi.NewRun()
 	var err error
 
-	run.SetRunId(localTest.runId)
+	runId, rasFolderPathUrl := localTest.getRunIdAndRasFolderPathUrl()
+	run.SetRunId(runId)
 
-	if localTest.rasFolderPathUrl == "" {
+	if rasFolderPathUrl == "" {
 		err = fmt.Errorf("createRunFromLocalTest - Don't have enough information to find the structure.json in the RAS folder")
 		log.Printf("%v", err.Error())
 	} else {
-		jsonFilePath := strings.TrimPrefix(localTest.rasFolderPathUrl, "file:///") + "/" + localTest.runId + "/structure.json"
+		jsonFilePath := strings.TrimPrefix(rasFolderPathUrl, "file:///") + "/" + runId + "/structure.json"
 		log.Printf("createRunFromLocalTest - Reading latest test status from '%s'\n", jsonFilePath)
 
 		err = setTestStructureFromRasFile(run, jsonFilePath, localTest.fileSystem)
@@ -601,16 +859,49 @@ func setTestStructureFromRasFile(run *galasaapi.Run, jsonFilePath string, fileSy
 	return err
 }
 
-// GetStreams gets a list of streams available on this launcher
+// GetStreams gets a list of streams available on this launcher.
+// Local runs have no streams, so each of the --obr values is used as one.
 func (launcher *JvmLauncher) GetStreams() ([]string, error) {
 	log.Printf("JvmLauncher: GetStreams entered.")
-	return nil, nil
+	return launcher.getTestCatalogProvider().GetStreams()
 }
 
 // GetTestCatalog gets the test catalog for a given stream.
+// The test catalogs published next to the OBRs are used. If the stream is blank, the
+// test catalogs of all the OBRs are merged together.
 func (launcher *JvmLauncher) GetTestCatalog(stream string) (TestCatalog, error) {
 	log.Printf("JvmLauncher: GetTestCatalog entered. stream=%s", stream)
-	return nil, nil
+	return launcher.getTestCatalogProvider().GetTestCatalog(stream)
+}
+
+// GetJvmConfiguration gets how the JVM of each test is configured, so it can be recorded with the run.
+func (launcher *JvmLauncher) GetJvmConfiguration() *JvmConfiguration {
+	return launcher.jvmConfiguration
+}
+
+// getRemoteMavenForJvm returns the remote Maven repository the JVM of each test loads bundles from.
+// When running offline, the JVM is given the local Maven repository in its place, so it can't go anywhere else.
+func (launcher *JvmLauncher) getRemoteMavenForJvm() (string, error) {
+	var err error
+	remoteMaven := launcher.cmdParams.RemoteMaven
+	if launcher.cmdParams.IsOffline {
+		remoteMaven, err = defaultLocalMavenIfNotSet(launcher.cmdParams.LocalMaven, launcher.fileSystem)
+	}
+	return remoteMaven, err
+}
+
+func (launcher *JvmLauncher) getTestCatalogProvider() TestCatalogProvider {
+	// When running offline, the test catalogs must also be in the local Maven repository.
+	remoteMaven := launcher.cmdParams.RemoteMaven
+	if launcher.cmdParams.IsOffline {
+		remoteMaven = ""
+	}
+	return NewObrTestCatalogProvider(
+		launcher.fileSystem,
+		launcher.cmdParams.Obrs,
+		launcher.cmdParams.LocalMaven,
+		remoteMaven,
+	)
 }
 
 // -----------------------------------------------------------------------------
@@ -655,6 +946,7 @@ func getCommandSyntax(
 	debugPort uint32,
 	debugMode string,
 	jwt string,
+	jvmConfiguration *JvmConfiguration,
 ) (string, []string, error) {
 
 	var cmd string = ""
@@ -682,12 +974,18 @@ func getCommandSyntax(
 
 		args = appendArgsDebugOptions(args, isDebugEnabled, debugMode, debugPort)
 
-		args = appendArgsBootstrapJvmLaunchOptions(args, bootstrapProperties)
+		if jvmConfiguration == nil {
+			jvmConfiguration = newBootstrapJvmConfiguration(bootstrapProperties)
+		}
+		args = append(args, jvmConfiguration.Args...)
 
 		// Note: Any -D properties are options for the JVM, so must appear before the -jar parameter.
 		// Parameters after the -jar parameter get passed into the 'main' of the launched java program.
 		args = append(args, "-Dfile.encoding=UTF-8")
 
+		// Later system properties win, so these can override any set by the JVM options above.
+		args = append(args, jvmConfiguration.getSystemPropertyArgs()...)
+
 		nativeGalasaHomeFolderPath := galasaHome.GetNativeFolderPath()
 		args = append(args, `-DGALASA_HOME="`+nativeGalasaHomeFolderPath+`"`)
 
@@ -732,7 +1030,7 @@ func getCommandSyntax(
 
 		// --obr mvn:dev.galasa/dev.galasa.uber.obr/${OBR_VERSION}/obr
 		args = append(args, "--obr")
-		galasaUberObrPath := "mvn:dev.galasa/dev.galasa.uber.obr/" + galasaVersionToRun + "/obr"
+		galasaUberObrPath := GetGalasaUberObr(galasaVersionToRun)
 		args = append(args, galasaUberObrPath)
 
 		if gherkinUrl != "" {
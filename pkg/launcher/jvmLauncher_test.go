@@ -6,10 +6,13 @@
 package launcher
 
 import (
+	"errors"
 	"log"
+	"os"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/galasa-dev/cli/pkg/api"
 	"github.com/galasa-dev/cli/pkg/files"
@@ -576,6 +579,7 @@ func TestCommandIncludesTraceWhenTraceIsEnabled(t *testing.T) {
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, cmd)
@@ -612,6 +616,7 @@ func TestCommandDoesNotIncludeTraceWhenTraceIsDisabled(t *testing.T) {
 		"", // No Gherkin URL supplied
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode, BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, cmd)
@@ -650,6 +655,7 @@ func TestCommandSyntaxContainsJavaHomeUnixSlashes(t *testing.T) {
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, cmd)
@@ -690,6 +696,7 @@ func TestCommandSyntaxContainsJavaHomeWindowsSlashes(t *testing.T) {
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, cmd)
@@ -765,6 +772,7 @@ func TestCommandIncludesGALASA_HOMESystemProperty(t *testing.T) {
 		debugPort,
 		debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, cmd)
@@ -807,6 +815,7 @@ func TestCommandAllDashDSystemPropertiesPassedAppearBeforeTheDashJar(t *testing.
 		debugPort,
 		debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, cmd)
@@ -884,6 +893,7 @@ func TestCommandIncludesFlagsFromBootstrapProperties(t *testing.T) {
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, cmd)
@@ -923,6 +933,7 @@ func TestCommandIncludesTwoFlagsFromBootstrapProperties(t *testing.T) {
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, cmd)
@@ -962,6 +973,7 @@ func TestCommandIncludesDefaultDebugPortAndMode(t *testing.T) {
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, command)
@@ -1002,6 +1014,7 @@ func TestCommandDrawsValidDebugPortFromBootstrap(t *testing.T) {
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, command)
@@ -1042,6 +1055,7 @@ func TestCommandDrawsInvalidDebugPortFromBootstrap(t *testing.T) {
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, err)
@@ -1082,6 +1096,7 @@ func TestCommandDrawsValidDebugModeFromBootstrap(t *testing.T) {
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, command)
@@ -1128,6 +1143,7 @@ func TestCommandDrawsInvalidDebugModeFromBootstrap(t *testing.T) {
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, err)
@@ -1166,6 +1182,7 @@ func TestCommandDrawsValidDebugModeListenFromCommandLine(t *testing.T) {
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, command)
@@ -1210,6 +1227,7 @@ func TestCommandDrawsValidDebugModeAttachFromCommandLine(t *testing.T) {
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, command)
@@ -1254,6 +1272,7 @@ func TestCommandDrawsInvalidDebugModeFromCommandLine(t *testing.T) {
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	assert.NotNil(t, err)
@@ -1294,6 +1313,7 @@ func TestLocalMavenNotSetDefaults(t *testing.T) {
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	// Then...
@@ -1335,6 +1355,7 @@ func TestLocalMavenSet(t *testing.T) {
 		isTraceEnabled,
 		isDebugEnabled, debugPort, debugMode,
 		BLANK_JWT,
+		nil, // No JVM configuration from the command-line
 	)
 
 	// Then...
@@ -1706,3 +1727,623 @@ func TestGetRunsByIdReturnsOk(t *testing.T) {
 	assert.Equal(t, "Passed", run.TestStructure.GetResult())
 	assert.Equal(t, "simpleSampleTest", run.GetTestStructure().Methods[0].GetMethodName())
 }
+
+func TestCommandPutsJvmConfigurationFromTheCommandLineAfterTheBootstrapOptions(t *testing.T) {
+	bootstrapProps,
+		_, galasaHome, fs,
+		javaHome,
+		testObrs,
+		testLocation,
+		remoteMaven,
+		localMaven,
+		galasaVersionToRun,
+		overridesFilePath,
+		_ := getDefaultCommandSyntaxTestParameters()
+
+	bootstrapProps[api.BOOTSTRAP_PROPERTY_NAME_LOCAL_JVM_LAUNCH_OPTIONS] = "-Xmx40m -Dmy.prop=fromBootstrap"
+	jvmConfiguration, _ := NewJvmConfiguration(bootstrapProps, []string{"-Xmx1g"}, []string{"my.prop=fromCommandLine"}, nil)
+
+	_, args, err := getCommandSyntax(
+		bootstrapProps, galasaHome, fs, javaHome,
+		testObrs,
+		testLocation,
+		remoteMaven,
+		localMaven,
+		galasaVersionToRun,
+		overridesFilePath,
+		"", // No Gherkin URL supplied
+		false, false, 0, "",
+		BLANK_JWT,
+		jvmConfiguration,
+	)
+
+	assert.Nil(t, err)
+	// The last of any clashing options wins, so the command-line takes precedence.
+	assert.Equal(t, []string{"-Xmx40m", "-Dmy.prop=fromBootstrap", "-Xmx1g", "-Dfile.encoding=UTF-8", "-Dmy.prop=fromCommandLine"}, args[:5])
+	assert.Contains(t, args[5], "-DGALASA_HOME=")
+}
+
+func TestLaunchedTestJvmIsGivenTheEnvironmentFromTheCommandLine(t *testing.T) {
+	// Given...
+	env := utils.NewMockEnv()
+	env.EnvVars["JAVA_HOME"] = "/java"
+	fs := files.NewMockFileSystem()
+	utils.AddJavaRuntimeToMock(fs, "/java")
+	galasaHome, _ := utils.NewGalasaHome(fs, env, "")
+	mockProcess := NewMockProcess()
+	mockFactory := &utils.MockFactory{
+		Env:         env,
+		FileSystem:  fs,
+		TimeService: utils.NewMockTimeService(),
+	}
+
+	jvmLaunchParams := getBasicJvmLaunchParams()
+	jvmLaunchParams.JvmEnvironment = []string{"MY_VAR=myValue"}
+	jvmLaunchParams.JvmSystemProperties = []string{"my.prop=myValue"}
+
+	launcher, err := NewJVMLauncher(
+		mockFactory,
+		getBasicBootstrapProperties(), embedded.GetReadOnlyFileSystem(),
+		jvmLaunchParams, NewMockProcessFactory(mockProcess), galasaHome, utils.NewRealTimedSleeper(),
+	)
+	assert.Nil(t, err)
+
+	// When...
+	_, err = launcher.SubmitTestRun(
+		"myGroup",
+		"galasa.dev.example.banking.account/galasa.dev.example.banking.account.TestAccount",
+		"myRequestType-UnitTest",
+		"myRequestor",
+		"unitTestStream",
+		"mvn:myGroup/myArtifact/myClassifier/obr",
+		false,
+		"", // No Gherkin URL supplied
+		"", // No Gherkin Feature supplied
+		make(map[string]interface{}),
+	)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"MY_VAR=myValue"}, mockProcess.env)
+	assert.Contains(t, mockProcess.args, "-Dmy.prop=myValue")
+	assert.Equal(t, map[string]string{"MY_VAR": "myValue"}, launcher.GetJvmConfiguration().Environment)
+}
+
+func TestCreateJvmLauncherWithBadJvmSystemPropertyFails(t *testing.T) {
+	bootstrapProps, env, fs, embeddedReadOnlyFS,
+		jvmLaunchParams, timeService, timedSleeper, mockProcessFactory, galasaHome := NewMockLauncherParams()
+	jvmLaunchParams.JvmSystemProperties = []string{"notAKeyValuePair"}
+
+	mockFactory := &utils.MockFactory{
+		Env:         env,
+		FileSystem:  fs,
+		TimeService: timeService,
+	}
+
+	launcher, err := NewJVMLauncher(
+		mockFactory,
+		bootstrapProps, embeddedReadOnlyFS,
+		jvmLaunchParams, mockProcessFactory, galasaHome, timedSleeper,
+	)
+
+	assert.Nil(t, launcher)
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1295E")
+}
+
+func TestProfiledTestJvmStartsAFlightRecording(t *testing.T) {
+	// Given...
+	env := utils.NewMockEnv()
+	env.EnvVars["JAVA_HOME"] = "/java"
+	fs := files.NewMockFileSystem()
+	utils.AddJavaRuntimeToMock(fs, "/java")
+	galasaHome, _ := utils.NewGalasaHome(fs, env, "")
+	mockProcess := NewMockProcess()
+	mockFactory := &utils.MockFactory{
+		Env:         env,
+		FileSystem:  fs,
+		TimeService: utils.NewMockTimeService(),
+	}
+
+	jvmLaunchParams := getBasicJvmLaunchParams()
+	jvmLaunchParams.Profile = "jfr:default"
+
+	launcher, err := NewJVMLauncher(
+		mockFactory,
+		getBasicBootstrapProperties(), embedded.GetReadOnlyFileSystem(),
+		jvmLaunchParams, NewMockProcessFactory(mockProcess), galasaHome, utils.NewRealTimedSleeper(),
+	)
+	assert.Nil(t, err)
+
+	// When...
+	_, err = launcher.SubmitTestRun(
+		"myGroup",
+		"galasa.dev.example.banking.account/galasa.dev.example.banking.account.TestAccount",
+		"myRequestType-UnitTest",
+		"myRequestor",
+		"unitTestStream",
+		"mvn:myGroup/myArtifact/myClassifier/obr",
+		false,
+		"", // No Gherkin URL supplied
+		"", // No Gherkin Feature supplied
+		make(map[string]interface{}),
+	)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Len(t, launcher.localTests[0].jvmExitArtifacts, 1)
+	recording := launcher.localTests[0].jvmExitArtifacts[0].(*jfrRecording)
+	assert.Contains(t, mockProcess.args, recording.getJvmArg())
+	assert.Contains(t, recording.getJvmArg(), "settings=default")
+	// The recording is only for this test, so isn't recorded as part of the JVM configuration of every run.
+	assert.NotContains(t, launcher.GetJvmConfiguration().Args, recording.getJvmArg())
+}
+
+func TestRecordingIsReportedOnceWhenTheTestIsComplete(t *testing.T) {
+	// Given...
+	console := utils.NewMockConsole()
+	launcher := new(JvmLauncher)
+	launcher.factory = &utils.MockFactory{StdOutConsole: console}
+
+	localTest := NewLocalTest(utils.NewRealTimedSleeper(), files.NewMockFileSystem(), nil)
+	localTest.runId = "L12"
+	localTest.jvmExitArtifacts = []jvmExitArtifact{
+		&jfrRecording{savedFilePath: "/ras/L12/profiling/L12.jfr", summary: &JfrSummary{}},
+		&jacocoCoverage{savedFilePath: "/ras/L12/coverage/L12.exec"},
+	}
+
+	// When...
+	launcher.reportCompletedTest(localTest)
+	launcher.reportCompletedTest(localTest)
+
+	// Then...
+	text := console.ReadText()
+	assert.Equal(t, 1, strings.Count(text, "GAL2526I: The Java Flight Recorder recording of run 'L12' was saved to '/ras/L12/profiling/L12.jfr'."))
+	assert.Equal(t, 1, strings.Count(text, "GAL2527I: The code coverage of run 'L12' was saved to '/ras/L12/coverage/L12.exec'."))
+}
+
+func TestCoveredTestJvmAttachesTheJacocoAgentUnpackedIntoGalasaHome(t *testing.T) {
+	// Given...
+	env := utils.NewMockEnv()
+	env.EnvVars["JAVA_HOME"] = "/java"
+	fs := files.NewMockFileSystem()
+	utils.AddJavaRuntimeToMock(fs, "/java")
+	galasaHome, _ := utils.NewGalasaHome(fs, env, "")
+	mockProcess := NewMockProcess()
+	mockFactory := &utils.MockFactory{
+		Env:         env,
+		FileSystem:  fs,
+		TimeService: utils.NewMockTimeService(),
+	}
+
+	jvmLaunchParams := getBasicJvmLaunchParams()
+	jvmLaunchParams.Profile = "jfr"
+	jvmLaunchParams.IsCoverageEnabled = true
+
+	launcher, err := NewJVMLauncher(
+		mockFactory,
+		getBasicBootstrapProperties(), embedded.NewMockReadOnlyFileSystem(),
+		jvmLaunchParams, NewMockProcessFactory(mockProcess), galasaHome, utils.NewRealTimedSleeper(),
+	)
+	assert.Nil(t, err)
+
+	agentJarPath, _ := utils.GetJacocoAgentJarPath(fs, galasaHome)
+	isAgentUnpacked, _ := fs.Exists(agentJarPath)
+	assert.True(t, isAgentUnpacked)
+
+	// When...
+	_, err = launcher.SubmitTestRun(
+		"myGroup",
+		"galasa.dev.example.banking.account/galasa.dev.example.banking.account.TestAccount",
+		"myRequestType-UnitTest",
+		"myRequestor",
+		"unitTestStream",
+		"mvn:myGroup/myArtifact/myClassifier/obr",
+		false,
+		"", // No Gherkin URL supplied
+		"", // No Gherkin Feature supplied
+		make(map[string]interface{}),
+	)
+
+	// Then...
+	assert.Nil(t, err)
+	jvmExitArtifacts := launcher.localTests[0].jvmExitArtifacts
+	assert.Len(t, jvmExitArtifacts, 2)
+	coverage := jvmExitArtifacts[1].(*jacocoCoverage)
+	assert.Contains(t, mockProcess.args, jvmExitArtifacts[0].getJvmArg())
+	assert.Contains(t, mockProcess.args, "-javaagent:"+agentJarPath+"=destfile="+coverage.execFilePath+",output=file")
+}
+
+func TestTestJvmWithNoCoverageDoesntUnpackTheJacocoAgent(t *testing.T) {
+	bootstrapProps, env, fs, embeddedReadOnlyFS,
+		jvmLaunchParams, timeService, timedSleeper, mockProcessFactory, galasaHome := NewMockLauncherParams()
+
+	mockFactory := &utils.MockFactory{
+		Env:         env,
+		FileSystem:  fs,
+		TimeService: timeService,
+	}
+
+	_, err := NewJVMLauncher(
+		mockFactory,
+		bootstrapProps, embeddedReadOnlyFS,
+		jvmLaunchParams, mockProcessFactory, galasaHome, timedSleeper,
+	)
+
+	assert.Nil(t, err)
+	agentJarPath, _ := utils.GetJacocoAgentJarPath(fs, galasaHome)
+	isAgentUnpacked, _ := fs.Exists(agentJarPath)
+	assert.False(t, isAgentUnpacked)
+}
+
+func TestCreateJvmLauncherWithUnknownProfilerFails(t *testing.T) {
+	bootstrapProps, env, fs, embeddedReadOnlyFS,
+		jvmLaunchParams, timeService, timedSleeper, mockProcessFactory, galasaHome := NewMockLauncherParams()
+	jvmLaunchParams.Profile = "yourkit"
+
+	mockFactory := &utils.MockFactory{
+		Env:         env,
+		FileSystem:  fs,
+		TimeService: timeService,
+	}
+
+	launcher, err := NewJVMLauncher(
+		mockFactory,
+		bootstrapProps, embeddedReadOnlyFS,
+		jvmLaunchParams, mockProcessFactory, galasaHome, timedSleeper,
+	)
+
+	assert.Nil(t, launcher)
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1297E")
+}
+
+func TestCreateJvmLauncherWithOutputFilterButNoStreamingFails(t *testing.T) {
+	bootstrapProps, env, fs, embeddedReadOnlyFS,
+		jvmLaunchParams, timeService, timedSleeper, mockProcessFactory, galasaHome := NewMockLauncherParams()
+	jvmLaunchParams.OutputFilter = `\*\*\*`
+
+	mockFactory := &utils.MockFactory{
+		Env:         env,
+		FileSystem:  fs,
+		TimeService: timeService,
+	}
+
+	launcher, err := NewJVMLauncher(
+		mockFactory,
+		bootstrapProps, embeddedReadOnlyFS,
+		jvmLaunchParams, mockProcessFactory, galasaHome, timedSleeper,
+	)
+
+	assert.Nil(t, launcher)
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1307E")
+}
+
+func TestCreateJvmLauncherWithInvalidOutputFilterFails(t *testing.T) {
+	bootstrapProps, env, fs, embeddedReadOnlyFS,
+		jvmLaunchParams, timeService, timedSleeper, mockProcessFactory, galasaHome := NewMockLauncherParams()
+	jvmLaunchParams.IsStreamingOutput = true
+	jvmLaunchParams.OutputFilter = "[unclosed"
+
+	mockFactory := &utils.MockFactory{
+		Env:         env,
+		FileSystem:  fs,
+		TimeService: timeService,
+	}
+
+	launcher, err := NewJVMLauncher(
+		mockFactory,
+		bootstrapProps, embeddedReadOnlyFS,
+		jvmLaunchParams, mockProcessFactory, galasaHome, timedSleeper,
+	)
+
+	assert.Nil(t, launcher)
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1306E")
+}
+
+func TestLaunchedTestJvmOutputIsStreamedToTheConsole(t *testing.T) {
+	// Given...
+	bootstrapProps, env, fs, embeddedReadOnlyFS,
+		jvmLaunchParams, timeService, timedSleeper, mockProcessFactory, galasaHome := NewMockLauncherParams()
+	jvmLaunchParams.IsStreamingOutput = true
+	env.EnvVars["NO_COLOR"] = "1"
+	console := utils.NewMockConsole()
+
+	mockFactory := &utils.MockFactory{
+		Env:           env,
+		FileSystem:    fs,
+		TimeService:   timeService,
+		StdOutConsole: console,
+	}
+
+	launcher, err := NewJVMLauncher(
+		mockFactory,
+		bootstrapProps, embeddedReadOnlyFS,
+		jvmLaunchParams, mockProcessFactory, galasaHome, timedSleeper,
+	)
+	assert.Nil(t, err)
+
+	// When...
+	_, err = launcher.SubmitTestRun(
+		"myGroup",
+		"galasa.dev.example.banking.account/galasa.dev.example.banking.account.TestAccount",
+		"myRequestType-UnitTest",
+		"myRequestor",
+		"unitTestStream",
+		"mvn:myGroup/myArtifact/myClassifier/obr",
+		false,
+		"", // No Gherkin URL supplied
+		"", // No Gherkin Feature supplied
+		make(map[string]interface{}),
+	)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.NotNil(t, launcher.localTests[0].outputCapture)
+
+	// The output is streamed by the go routine waiting for the JVM, so wait for the JVM to end before looking at it.
+	isExited := launcher.localTests[0].waitForJvmExit(JVM_EXIT_TIMEOUT)
+	assert.True(t, isExited)
+	text := console.ReadText()
+	assert.Contains(t, text, "[L12345] Allocated Run Name L12345")
+	assert.Contains(t, text, "[L12345] d.g.f.Framework - Framework shutdown")
+}
+
+func TestCreateJvmLauncherWithNegativeInactivityTimeoutFails(t *testing.T) {
+	bootstrapProps, env, fs, embeddedReadOnlyFS,
+		jvmLaunchParams, timeService, timedSleeper, mockProcessFactory, galasaHome := NewMockLauncherParams()
+	jvmLaunchParams.InactivityTimeoutMinutes = -1
+
+	mockFactory := &utils.MockFactory{
+		Env:         env,
+		FileSystem:  fs,
+		TimeService: timeService,
+	}
+
+	launcher, err := NewJVMLauncher(
+		mockFactory,
+		bootstrapProps, embeddedReadOnlyFS,
+		jvmLaunchParams, mockProcessFactory, galasaHome, timedSleeper,
+	)
+
+	assert.Nil(t, launcher)
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1308E")
+	assert.ErrorContains(t, err, "--inactivity-timeout")
+}
+
+// Launches a test whose JVM hangs, with a watchdog which gives up on it after 10 minutes of no output.
+func launchHungTest(t *testing.T, mockProcess *mockProcess, threadDumper ThreadDumper) (*JvmLauncher, *utils.MockTimeService, spi.FileSystem, *utils.MockConsole) {
+	env := utils.NewMockEnv()
+	env.EnvVars["JAVA_HOME"] = "/java"
+	fs := files.NewMockFileSystem()
+	utils.AddJavaRuntimeToMock(fs, "/java")
+	galasaHome, _ := utils.NewGalasaHome(fs, env, "")
+	timeService := utils.NewMockTimeService()
+	console := utils.NewMockConsole()
+	mockFactory := &utils.MockFactory{
+		Env:           env,
+		FileSystem:    fs,
+		TimeService:   timeService,
+		StdOutConsole: console,
+	}
+
+	jvmLaunchParams := getBasicJvmLaunchParams()
+	jvmLaunchParams.InactivityTimeoutMinutes = 10
+
+	launcher, err := NewJVMLauncher(
+		mockFactory,
+		getBasicBootstrapProperties(), embedded.NewMockReadOnlyFileSystem(),
+		jvmLaunchParams, NewMockProcessFactory(mockProcess), galasaHome, utils.NewRealTimedSleeper(),
+	)
+	assert.Nil(t, err)
+	launcher.threadDumper = threadDumper
+
+	_, err = launcher.SubmitTestRun(
+		"myGroup",
+		"galasa.dev.example.banking.account/galasa.dev.example.banking.account.TestAccount",
+		"myRequestType-UnitTest",
+		"myRequestor",
+		"unitTestStream",
+		"mvn:myGroup/myArtifact/myClassifier/obr",
+		false,
+		"", // No Gherkin URL supplied
+		"", // No Gherkin Feature supplied
+		make(map[string]interface{}),
+	)
+	assert.Nil(t, err)
+	return launcher, timeService, fs, console
+}
+
+func TestHungTestIsInterruptedAndMarkedAsHung(t *testing.T) {
+	// Given...
+	mockProcess := NewMockProcess()
+	mockProcess.isHanging = true
+	threadDumper := &mockThreadDumper{threadDump: []byte("\"main\" waiting on a lock")}
+	launcher, timeService, fs, console := launchHungTest(t, mockProcess, threadDumper)
+
+	testRuns, _ := launcher.GetRunsByGroup("myGroup")
+	assert.False(t, *testRuns.Complete)
+
+	// When...
+	timeService.AdvanceClock(10 * time.Minute)
+	testRuns, err := launcher.GetRunsByGroup("myGroup")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.True(t, *testRuns.Complete)
+	assert.Equal(t, "finished", testRuns.Runs[0].GetStatus())
+	assert.Equal(t, "Hung", testRuns.Runs[0].GetResult())
+
+	assert.Equal(t, []os.Signal{os.Interrupt}, mockProcess.signals)
+	assert.False(t, mockProcess.isKilled)
+
+	assert.Equal(t, []int{12345}, threadDumper.pids)
+	threadDump, _ := fs.ReadTextFile("/temp/ras/L12345/thread-dump.txt")
+	assert.Equal(t, "\"main\" waiting on a lock", threadDump)
+
+	text := console.ReadText()
+	assert.Contains(t, text, "GAL2529I: Run 'L12345' is hung, as its JVM has written no output for 10m0s. Its JVM was terminated and the run was given the result 'Hung'.")
+	assert.Contains(t, text, "GAL2530I: A thread dump of the JVM of hung run 'L12345' was saved to '/temp/ras/L12345/thread-dump.txt'.")
+}
+
+func TestHungTestWhichIgnoresInterruptsIsKilled(t *testing.T) {
+	// Given...
+	mockProcess := NewMockProcess()
+	mockProcess.isHanging = true
+	mockProcess.isIgnoringInterrupts = true
+	launcher, timeService, _, _ := launchHungTest(t, mockProcess, &mockThreadDumper{})
+	launcher.localTests[0].watchdog.terminationGracePeriod = time.Millisecond
+
+	// When...
+	timeService.AdvanceClock(10 * time.Minute)
+	testRuns, _ := launcher.GetRunsByGroup("myGroup")
+
+	// Then...
+	assert.True(t, *testRuns.Complete)
+	assert.Equal(t, "Hung", testRuns.Runs[0].GetResult())
+	assert.True(t, mockProcess.isKilled)
+}
+
+func TestHungTestWithNoJcmdIsAskedToWriteAThreadDumpIntoItsOutput(t *testing.T) {
+	// Given...
+	mockProcess := NewMockProcess()
+	mockProcess.isHanging = true
+	launcher, timeService, fs, console := launchHungTest(t, mockProcess, &mockThreadDumper{err: errors.New("jcmd not found")})
+
+	// When...
+	timeService.AdvanceClock(10 * time.Minute)
+	launcher.GetRunsByGroup("myGroup")
+
+	// Then...
+	isThreadDumpSaved, _ := fs.Exists("/temp/ras/L12345/thread-dump.txt")
+	assert.False(t, isThreadDumpSaved)
+	assert.NotContains(t, console.ReadText(), "GAL2530I")
+	if threadDumpSignal != nil {
+		assert.Equal(t, []os.Signal{threadDumpSignal, os.Interrupt}, mockProcess.signals)
+	}
+}
+
+func TestCreateOfflineJvmLauncherWithObrMissingFromTheLocalMavenRepoFails(t *testing.T) {
+	bootstrapProps, env, fs, embeddedReadOnlyFS,
+		jvmLaunchParams, timeService, timedSleeper, mockProcessFactory, galasaHome := NewMockLauncherParams()
+	jvmLaunchParams.IsOffline = true
+	jvmLaunchParams.LocalMaven = "file:///localrepo"
+	jvmLaunchParams.TargetGalasaVersion = "0.38.0"
+	jvmLaunchParams.Obrs = []string{BANKING_OBR}
+	fs.WriteTextFile("/localrepo"+PREFETCH_UBER_OBR_PATH, `<repository name='Galasa'/>`)
+
+	mockFactory := &utils.MockFactory{
+		Env:         env,
+		FileSystem:  fs,
+		TimeService: timeService,
+	}
+
+	_, err := NewJVMLauncher(
+		mockFactory,
+		bootstrapProps, embeddedReadOnlyFS,
+		jvmLaunchParams, mockProcessFactory, galasaHome, timedSleeper,
+	)
+
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1321E")
+	assert.ErrorContains(t, err, "'file:///localrepo': "+BANKING_OBR+".")
+}
+
+func TestOfflineTestFromAPortfolioObrMissingFromTheLocalMavenRepoIsNotLaunched(t *testing.T) {
+	// Given...
+	bootstrapProps, env, fs, embeddedReadOnlyFS,
+		jvmLaunchParams, timeService, timedSleeper, mockProcessFactory, galasaHome := NewMockLauncherParams()
+	jvmLaunchParams.IsOffline = true
+	jvmLaunchParams.LocalMaven = "file:///localrepo"
+	jvmLaunchParams.TargetGalasaVersion = "0.38.0"
+	fs.WriteTextFile("/localrepo"+PREFETCH_UBER_OBR_PATH, `<repository name='Galasa'/>`)
+
+	mockFactory := &utils.MockFactory{
+		Env:         env,
+		FileSystem:  fs,
+		TimeService: timeService,
+	}
+
+	launcher, err := NewJVMLauncher(
+		mockFactory,
+		bootstrapProps, embeddedReadOnlyFS,
+		jvmLaunchParams, mockProcessFactory, galasaHome, timedSleeper,
+	)
+	assert.Nil(t, err)
+
+	// When...
+	_, err = launcher.SubmitTestRun(
+		"myGroup",
+		"galasa.dev.example.banking.account/galasa.dev.example.banking.account.TestAccount",
+		"myRequestType-UnitTest",
+		"myRequestor",
+		"unitTestStream",
+		BANKING_OBR,
+		false,
+		"", // No Gherkin URL supplied
+		"", // No Gherkin Feature supplied
+		make(map[string]interface{}),
+	)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1321E")
+	assert.Empty(t, launcher.localTests)
+}
+
+func TestOfflineTestJvmIsGivenTheLocalMavenRepoInPlaceOfTheRemoteOne(t *testing.T) {
+	// Given...
+	env := utils.NewMockEnv()
+	env.EnvVars["JAVA_HOME"] = "/java"
+	fs := files.NewMockFileSystem()
+	utils.AddJavaRuntimeToMock(fs, "/java")
+	galasaHome, _ := utils.NewGalasaHome(fs, env, "")
+	mockProcess := NewMockProcess()
+	mockFactory := &utils.MockFactory{
+		Env:         env,
+		FileSystem:  fs,
+		TimeService: utils.NewMockTimeService(),
+	}
+
+	fs.WriteTextFile("/localrepo"+PREFETCH_UBER_OBR_PATH, PREFETCH_UBER_OBR)
+	fs.WriteTextFile("/localrepo"+PREFETCH_BANKING_OBR_PATH, PREFETCH_BANKING_OBR)
+	fs.WriteTextFile("/localrepo"+PREFETCH_FRAMEWORK_PATH, "framework bundle")
+	fs.WriteTextFile("/localrepo"+PREFETCH_CORE_MANAGER_PATH, "core manager bundle")
+	fs.WriteTextFile("/localrepo"+PREFETCH_ACCOUNT_PATH, "account bundle")
+
+	jvmLaunchParams := getBasicJvmLaunchParams()
+	jvmLaunchParams.IsOffline = true
+	jvmLaunchParams.LocalMaven = "file:///localrepo"
+	jvmLaunchParams.RemoteMaven = "https://my.remote.maven"
+	jvmLaunchParams.TargetGalasaVersion = "0.38.0"
+	jvmLaunchParams.Obrs = []string{BANKING_OBR}
+
+	launcher, err := NewJVMLauncher(
+		mockFactory,
+		getBasicBootstrapProperties(), embedded.GetReadOnlyFileSystem(),
+		jvmLaunchParams, NewMockProcessFactory(mockProcess), galasaHome, utils.NewRealTimedSleeper(),
+	)
+	assert.Nil(t, err)
+
+	// When...
+	_, err = launcher.SubmitTestRun(
+		"myGroup",
+		"dev.galasa.example.banking.account/dev.galasa.example.banking.account.TestAccount",
+		"myRequestType-UnitTest",
+		"myRequestor",
+		"unitTestStream",
+		"",
+		false,
+		"", // No Gherkin URL supplied
+		"", // No Gherkin Feature supplied
+		make(map[string]interface{}),
+	)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Contains(t, strings.Join(mockProcess.args, " "), "--localmaven file:///localrepo --remotemaven file:///localrepo")
+	assert.NotContains(t, mockProcess.args, "https://my.remote.maven")
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"io"
+	"log"
+	"sync"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/spi"
+)
+
+// The name of the file within the RAS folder of a run which holds everything its JVM wrote to stdout and stderr.
+const JVM_OUTPUT_FILE_NAME = "jvm-output.txt"
+
+// jvmOutputCapture saves everything the JVM of a local test writes to its stdout and stderr.
+// The output is written into a temporary file as it arrives, rather than being kept in memory,
+// and is moved into the RAS folder of the run once the JVM has ended.
+type jvmOutputCapture struct {
+	fileSystem spi.FileSystem
+
+	temporaryFolderPath string
+	filePath            string
+
+	// stdout and stderr are written from different goroutines.
+	mutex sync.Mutex
+	file  io.WriteCloser
+
+	// Set if the output could not be written, so the failure is only logged once.
+	writeErr error
+}
+
+func newJvmOutputCapture(fileSystem spi.FileSystem) (*jvmOutputCapture, error) {
+	var err error
+	capture := new(jvmOutputCapture)
+	capture.fileSystem = fileSystem
+
+	capture.temporaryFolderPath, err = fileSystem.MkTempDir()
+	if err == nil {
+		capture.filePath = capture.temporaryFolderPath + fileSystem.GetFilePathSeparator() + JVM_OUTPUT_FILE_NAME
+		capture.file, err = fileSystem.Create(capture.filePath)
+		if err != nil {
+			fileSystem.DeleteDir(capture.temporaryFolderPath)
+		}
+	}
+
+	if err != nil {
+		capture = nil
+	}
+	return capture, err
+}
+
+// Part of the io.Writer interface. A failure to save the output never stops the JVM from running,
+// so this always claims to have written everything.
+func (capture *jvmOutputCapture) Write(bytesToWrite []byte) (int, error) {
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+
+	if capture.file != nil && capture.writeErr == nil {
+		_, capture.writeErr = capture.file.Write(bytesToWrite)
+		if capture.writeErr != nil {
+			log.Printf("Failed to save the JVM output into '%s'. Reason: %s\n", capture.filePath, capture.writeErr.Error())
+		}
+	}
+	return len(bytesToWrite), nil
+}
+
+// save moves the output into the RAS folder of the run, as ras/<runId>/jvm-output.txt
+// This must only be called once the JVM has ended.
+func (capture *jvmOutputCapture) save(rasFolderPath string, runId string) {
+	defer capture.discard()
+
+	runFolderPath := rasFolderPath + "/" + runId
+	savedFilePath := runFolderPath + "/" + JVM_OUTPUT_FILE_NAME
+
+	err := capture.close()
+
+	var contents []byte
+	if err == nil {
+		contents, err = capture.fileSystem.ReadBinaryFile(capture.filePath)
+	}
+	if err == nil {
+		err = capture.fileSystem.MkdirAll(runFolderPath)
+	}
+	if err == nil {
+		err = capture.fileSystem.WriteBinaryFile(savedFilePath, contents)
+	}
+
+	if err != nil {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_JVM_OUTPUT_NOT_SAVED, runId, savedFilePath, err.Error())
+		log.Println(err.Error())
+	} else {
+		log.Printf("Saved the output of the JVM of run %s to '%s'\n", runId, savedFilePath)
+	}
+}
+
+// Throws the output away, when the test ended before it had a RAS folder to save it into.
+func (capture *jvmOutputCapture) discard() {
+	capture.close()
+	capture.fileSystem.DeleteDir(capture.temporaryFolderPath)
+}
+
+func (capture *jvmOutputCapture) close() error {
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+
+	var err error
+	if capture.file != nil {
+		err = capture.file.Close()
+		capture.file = nil
+	}
+	if err == nil {
+		err = capture.writeErr
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJvmOutputCaptureIsSavedIntoTheRasFolder(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	capture, err := newJvmOutputCapture(fs)
+	assert.Nil(t, err)
+
+	capture.Write([]byte("line one\n"))
+	capture.Write([]byte("line two\n"))
+
+	// When...
+	capture.save("/home/.galasa/ras", "L12")
+
+	// Then...
+	saved, err := fs.ReadBinaryFile("/home/.galasa/ras/L12/jvm-output.txt")
+	assert.Nil(t, err)
+	assert.Equal(t, "line one\nline two\n", string(saved))
+	isTempLeft, _ := fs.DirExists(capture.temporaryFolderPath)
+	assert.False(t, isTempLeft)
+}
+
+func TestJvmOutputCaptureIgnoresWritesOnceClosed(t *testing.T) {
+	fs := files.NewMockFileSystem()
+	capture, _ := newJvmOutputCapture(fs)
+	capture.discard()
+
+	n, err := capture.Write([]byte("too late"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 8, n)
+}
+
+func TestJvmOutputCaptureDiscardedLeavesNothingBehind(t *testing.T) {
+	fs := files.NewMockFileSystem()
+	capture, _ := newJvmOutputCapture(fs)
+	capture.Write([]byte("some output\n"))
+
+	capture.discard()
+
+	isTempLeft, _ := fs.DirExists(capture.temporaryFolderPath)
+	assert.False(t, isTempLeft)
+	isSaved, _ := fs.Exists("/home/.galasa/ras/L12/jvm-output.txt")
+	assert.False(t, isSaved)
+}
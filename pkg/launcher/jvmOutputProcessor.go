@@ -6,10 +6,11 @@
 package launcher
 
 import (
-	"bytes"
+	"io"
 	"log"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/galasa-dev/cli/pkg/utils"
 )
@@ -25,8 +26,13 @@ import (
 // Items we detect are stored in the structure below as we find them.
 type JVMOutputProcessor struct {
 
-	// The data which the processor has been passed so far.
-	bytesCollected *bytes.Buffer
+	// Where the output is copied to as it arrives, such as the file it is saved into.
+	// The output isn't kept in memory, as a long-running test can write a lot of it.
+	copies []io.Writer
+
+	// The JVM writes from one goroutine, while others read what has been detected,
+	// so the detected runid and RAS folder are only used while this is locked.
+	detectedMutex sync.Mutex
 
 	// The runid which has been detected.
 	detectedRunId string
@@ -45,12 +51,18 @@ type JVMOutputProcessor struct {
 func NewJVMOutputProcessor() *JVMOutputProcessor {
 	processor := new(JVMOutputProcessor)
 	processor.detectedRunId = ""
-	processor.bytesCollected = bytes.NewBuffer([]byte{})
+	processor.copies = make([]io.Writer, 0)
 	processor.publishResultChannel = make(chan string, 10)
 	processor.detectedRasFolderPathUrl = ""
 	return processor
 }
 
+// copyTo makes the processor copy all the output it is passed to another writer.
+// This must be called before the JVM starts writing.
+func (processor *JVMOutputProcessor) copyTo(writer io.Writer) {
+	processor.copies = append(processor.copies, writer)
+}
+
 // Some regex expressions we need to use to extract fields from trace strings.
 var (
 	runIdRegex         *regexp.Regexp = regexp.MustCompile(`Allocated Run Name (?P<runid>\S*) to this run`)
@@ -67,53 +79,73 @@ const (
 // we are intercepting and monitoring.
 func (processor *JVMOutputProcessor) Write(bytesToWrite []byte) (int, error) {
 
-	// Store away the trace information anyway for posterity.
-	bytesWrittenCount, err := processor.bytesCollected.Write(bytesToWrite)
-
-	if err == nil {
-		// See if we can gather the runId from the trace output.
-		// We would expect it to appear in a string like this:
-		// "d.g.f.FrameworkInitialisation - Allocated Run Name U525 to this run"
-		stringToSearch := string(bytesToWrite)
-		jvmStringNoTrailingNewline := strings.TrimSpace(stringToSearch)
-
-		// Golang doesn't like printing 0x0d characters, it would rather they are 0x0a characters instead.
-		// So for the purposes of echoing a log record to the terminal, do the conversion so it
-		// comes out correctly.
-		stringToLog := utils.StringWithNewLinesInsteadOfCRLFs(jvmStringNoTrailingNewline)
-
-		if processor.detectedRunId != "" {
-			log.Printf("JVM output: (runid:%s) : %s\n", processor.detectedRunId, stringToLog)
-		} else {
-			log.Printf("JVM output: %s\n", stringToLog)
-		}
-
-		isAlertable := false
-
-		runId := detectRunId(stringToSearch)
-		if runId != "" {
-			processor.detectedRunId = runId
-			isAlertable = true
-		}
-
-		rasFolderPathUrl := detectRasFolderPath(stringToSearch)
-		if rasFolderPathUrl != "" {
-			processor.detectedRasFolderPathUrl = rasFolderPathUrl
-			isAlertable = true
-		}
-
-		isShutdownDetected := detectShutdown(stringToSearch)
-		if isShutdownDetected {
-			isAlertable = true
-		}
-
-		if isAlertable {
-			// Now alert anyone who may be listening on the go channel.
-			processor.publishResultChannel <- "ALERT"
-		}
+	// See if we can gather the runId from the trace output.
+	// We would expect it to appear in a string like this:
+	// "d.g.f.FrameworkInitialisation - Allocated Run Name U525 to this run"
+	stringToSearch := string(bytesToWrite)
+	jvmStringNoTrailingNewline := strings.TrimSpace(stringToSearch)
+
+	// Golang doesn't like printing 0x0d characters, it would rather they are 0x0a characters instead.
+	// So for the purposes of echoing a log record to the terminal, do the conversion so it
+	// comes out correctly.
+	stringToLog := utils.StringWithNewLinesInsteadOfCRLFs(jvmStringNoTrailingNewline)
+
+	detectedRunId := processor.getDetectedRunId()
+	if detectedRunId != "" {
+		log.Printf("JVM output: (runid:%s) : %s\n", detectedRunId, stringToLog)
+	} else {
+		log.Printf("JVM output: %s\n", stringToLog)
+	}
+
+	isAlertable := false
+
+	runId := detectRunId(stringToSearch)
+	if runId != "" {
+		processor.detectedMutex.Lock()
+		processor.detectedRunId = runId
+		processor.detectedMutex.Unlock()
+		isAlertable = true
+	}
+
+	rasFolderPathUrl := detectRasFolderPath(stringToSearch)
+	if rasFolderPathUrl != "" {
+		processor.detectedMutex.Lock()
+		processor.detectedRasFolderPathUrl = rasFolderPathUrl
+		processor.detectedMutex.Unlock()
+		isAlertable = true
 	}
 
-	return bytesWrittenCount, err
+	isShutdownDetected := detectShutdown(stringToSearch)
+	if isShutdownDetected {
+		isAlertable = true
+	}
+
+	// The copies are written after the runId has been detected, so they can tell which run the output is from.
+	for _, copy := range processor.copies {
+		copy.Write(bytesToWrite)
+	}
+
+	if isAlertable {
+		// Now alert anyone who may be listening on the go channel.
+		processor.publishResultChannel <- "ALERT"
+	}
+
+	// The output is never refused.
+	return len(bytesToWrite), nil
+}
+
+// getDetectedRunId returns the runid the JVM has been allocated, or blank if it isn't known yet.
+func (processor *JVMOutputProcessor) getDetectedRunId() string {
+	processor.detectedMutex.Lock()
+	defer processor.detectedMutex.Unlock()
+	return processor.detectedRunId
+}
+
+// getDetectedRasFolderPathUrl returns the location of the RAS folder, or blank if it isn't known yet.
+func (processor *JVMOutputProcessor) getDetectedRasFolderPathUrl() string {
+	processor.detectedMutex.Lock()
+	defer processor.detectedMutex.Unlock()
+	return processor.detectedRasFolderPathUrl
 }
 
 // We expect each test to trace the following:
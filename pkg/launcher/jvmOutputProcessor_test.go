@@ -6,6 +6,7 @@
 package launcher
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,7 +25,7 @@ func TestJvmOutputProcessorCanDetectARunId(t *testing.T) {
 	processorToTest := NewJVMOutputProcessor()
 	processorToTest.Write([]byte("14/02/2023 12:19:11.990 INFO  d.g.f.FrameworkInitialisation - Allocated Run Name U525 to this run"))
 
-	assert.Equal(t, "U525", processorToTest.detectedRunId, "Runid was not detected by the JVMOutputProcessor")
+	assert.Equal(t, "U525", processorToTest.getDetectedRunId(), "Runid was not detected by the JVMOutputProcessor")
 }
 
 func TestJvmOutputProcessorSignalsWhenRunIdFound(t *testing.T) {
@@ -35,6 +36,20 @@ func TestJvmOutputProcessorSignalsWhenRunIdFound(t *testing.T) {
 	assert.Equal(t, "ALERT", msg, "unexpected message received from the output detector.")
 }
 
+func TestJvmOutputProcessorRunIdCanBeReadWhileTheJvmWrites(t *testing.T) {
+	processorToTest := NewJVMOutputProcessor()
+
+	// When...
+	// The output streamers read the runId while the JVM is still writing.
+	go processorToTest.Write([]byte("14/02/2023 12:19:11.990 INFO  d.g.f.FrameworkInitialisation - Allocated Run Name U525 to this run"))
+	runId := processorToTest.getDetectedRunId()
+	assert.Contains(t, []string{"", "U525"}, runId)
+
+	// Then...
+	<-processorToTest.publishResultChannel
+	assert.Equal(t, "U525", processorToTest.getDetectedRunId())
+}
+
 func TestJvmOutputProcessorCollectsRasFolderPathUrl(t *testing.T) {
 	processorToTest := NewJVMOutputProcessor()
 	expectedLocation := "file:///Users/mcobbett/.galasa/ras"
@@ -50,8 +65,8 @@ func TestJvmOutputProcessorCollectsRasFolderPathUrl(t *testing.T) {
 	assert.Equal(t, "ALERT", msg, "unexpected message received from the output detector.")
 
 	// And the RAS location should be known.
-	assert.NotEmpty(t, processorToTest.detectedRasFolderPathUrl, "RAS folder path was not detected in simulated JVM trace output")
-	assert.Equal(t, expectedLocation, processorToTest.detectedRasFolderPathUrl, "Wrong RAS folder path parsed from trace outpout")
+	assert.NotEmpty(t, processorToTest.getDetectedRasFolderPathUrl(), "RAS folder path was not detected in simulated JVM trace output")
+	assert.Equal(t, expectedLocation, processorToTest.getDetectedRasFolderPathUrl(), "Wrong RAS folder path parsed from trace outpout")
 }
 
 func TestJvmOutputProcessorCanDetectAFrameworkShutdown(t *testing.T) {
@@ -62,3 +77,13 @@ func TestJvmOutputProcessorCanDetectAFrameworkShutdown(t *testing.T) {
 	msg := <-processorToTest.publishResultChannel
 	assert.Equal(t, "ALERT", msg, "unexpected message received from the output detector.")
 }
+
+func TestJvmOutputProcessorCopiesWhatIsWritten(t *testing.T) {
+	processorToTest := NewJVMOutputProcessor()
+	copy := bytes.NewBuffer([]byte{})
+	processorToTest.copyTo(copy)
+
+	processorToTest.Write([]byte("A short string"))
+
+	assert.Equal(t, "A short string", copy.String())
+}
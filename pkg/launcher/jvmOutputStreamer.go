@@ -0,0 +1,164 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/spi"
+)
+
+// The output of the JVMs of local tests can be echoed to the console as it arrives, with each line
+// prefixed by the run it came from. When several tests run at once, each run is given a colour of its own,
+// so its lines can be picked out.
+
+const (
+	ANSI_RESET = "\033[0m"
+
+	// The prefix given to lines written before the JVM said which run it is.
+	UNKNOWN_RUN_ID_PREFIX = "?"
+)
+
+// The colours given to each run in turn: cyan, magenta, yellow, green, blue, red.
+var JVM_OUTPUT_RUN_COLOURS = []string{"\033[36m", "\033[35m", "\033[33m", "\033[32m", "\033[34m", "\033[31m"}
+
+// jvmOutputStreamer writes lines of JVM output to the console. It is shared by all the tests being launched.
+type jvmOutputStreamer struct {
+	console spi.Console
+
+	// Only lines which match the filter are shown, or every line if it is nil.
+	filter *regexp.Regexp
+
+	isColoured bool
+
+	// Lines from different JVMs arrive on different goroutines.
+	mutex      sync.Mutex
+	runColours map[string]string
+}
+
+// newJvmOutputStreamer checks the regular expression the lines must match, if there is one.
+func newJvmOutputStreamer(console spi.Console, outputFilter string, isColoured bool) (*jvmOutputStreamer, error) {
+	var err error
+	streamer := new(jvmOutputStreamer)
+	streamer.console = console
+	streamer.isColoured = isColoured
+	streamer.runColours = make(map[string]string)
+
+	if outputFilter != "" {
+		streamer.filter, err = regexp.Compile(outputFilter)
+		if err != nil {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_OUTPUT_FILTER, outputFilter, err.Error())
+			streamer = nil
+		}
+	}
+	return streamer, err
+}
+
+// Writes a single line, without its line ending, prefixed by the run it came from.
+func (streamer *jvmOutputStreamer) writeLine(runId string, line string) {
+	if streamer.filter == nil || streamer.filter.MatchString(line) {
+		streamer.mutex.Lock()
+		defer streamer.mutex.Unlock()
+
+		prefix := "[" + runId + "]"
+		if streamer.isColoured {
+			prefix = streamer.getRunColour(runId) + prefix + ANSI_RESET
+		}
+		streamer.console.WriteString(prefix + " " + line + "\n")
+	}
+}
+
+// Each run keeps the colour it was first given.
+func (streamer *jvmOutputStreamer) getRunColour(runId string) string {
+	colour, isKnown := streamer.runColours[runId]
+	if !isKnown {
+		colour = JVM_OUTPUT_RUN_COLOURS[len(streamer.runColours)%len(JVM_OUTPUT_RUN_COLOURS)]
+		streamer.runColours[runId] = colour
+	}
+	return colour
+}
+
+// jvmOutputLineWriter splits the stdout or stderr of a single JVM into lines, which it passes to the streamer.
+// Lines written before the JVM says which run it is are held back until the run is known,
+// so every line can be prefixed by its run.
+type jvmOutputLineWriter struct {
+	streamer *jvmOutputStreamer
+
+	// Gets the ID of the run, or "" if it isn't known yet.
+	getRunId func() string
+
+	// Only one goroutine writes to each line writer, but it is flushed from another once the JVM has ended.
+	mutex        sync.Mutex
+	partialLine  []byte
+	pendingLines []string
+}
+
+func newJvmOutputLineWriter(streamer *jvmOutputStreamer, getRunId func() string) *jvmOutputLineWriter {
+	writer := new(jvmOutputLineWriter)
+	writer.streamer = streamer
+	writer.getRunId = getRunId
+	return writer
+}
+
+// Part of the io.Writer interface.
+func (writer *jvmOutputLineWriter) Write(bytesToWrite []byte) (int, error) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	writer.partialLine = append(writer.partialLine, bytesToWrite...)
+	for {
+		lineEndIndex := bytes.IndexByte(writer.partialLine, '\n')
+		if lineEndIndex < 0 {
+			break
+		}
+		writer.writeLine(string(writer.partialLine[:lineEndIndex]))
+		writer.partialLine = writer.partialLine[lineEndIndex+1:]
+	}
+	return len(bytesToWrite), nil
+}
+
+// flush writes out anything still held back, once the JVM has ended.
+func (writer *jvmOutputLineWriter) flush() {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	if len(writer.partialLine) > 0 {
+		writer.writeLine(string(writer.partialLine))
+		writer.partialLine = nil
+	}
+
+	if len(writer.pendingLines) > 0 {
+		runId := writer.getRunId()
+		if runId == "" {
+			runId = UNKNOWN_RUN_ID_PREFIX
+		}
+		writer.writePendingLines(runId)
+	}
+}
+
+func (writer *jvmOutputLineWriter) writeLine(line string) {
+	// Lines written on Windows end with CRLF.
+	line = strings.TrimSuffix(line, "\r")
+
+	runId := writer.getRunId()
+	if runId == "" {
+		writer.pendingLines = append(writer.pendingLines, line)
+	} else {
+		writer.writePendingLines(runId)
+		writer.streamer.writeLine(runId, line)
+	}
+}
+
+func (writer *jvmOutputLineWriter) writePendingLines(runId string) {
+	for _, pendingLine := range writer.pendingLines {
+		writer.streamer.writeLine(runId, pendingLine)
+	}
+	writer.pendingLines = nil
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJvmOutputStreamerPrefixesLinesWithTheirRun(t *testing.T) {
+	console := utils.NewMockConsole()
+	streamer, _ := newJvmOutputStreamer(console, "", false)
+	writer := newJvmOutputLineWriter(streamer, func() string { return "L12" })
+
+	writer.Write([]byte("line one\nline "))
+	writer.Write([]byte("two\r\n"))
+
+	assert.Equal(t, "[L12] line one\n[L12] line two\n", console.ReadText())
+}
+
+func TestJvmOutputStreamerGivesEachRunAColourOfItsOwn(t *testing.T) {
+	console := utils.NewMockConsole()
+	streamer, _ := newJvmOutputStreamer(console, "", true)
+
+	streamer.writeLine("L1", "first")
+	streamer.writeLine("L2", "second")
+	streamer.writeLine("L1", "third")
+
+	assert.Equal(t,
+		"\033[36m[L1]\033[0m first\n"+
+			"\033[35m[L2]\033[0m second\n"+
+			"\033[36m[L1]\033[0m third\n",
+		console.ReadText())
+}
+
+func TestJvmOutputStreamerOnlyShowsLinesMatchingTheFilter(t *testing.T) {
+	console := utils.NewMockConsole()
+	streamer, err := newJvmOutputStreamer(console, `\*\*\*`, false)
+	assert.Nil(t, err)
+	writer := newJvmOutputLineWriter(streamer, func() string { return "L12" })
+
+	writer.Write([]byte("INFO  some detail\n*** Start of test method testA\nINFO  more detail\n*** Passed test method testA\n"))
+
+	assert.Equal(t, "[L12] *** Start of test method testA\n[L12] *** Passed test method testA\n", console.ReadText())
+}
+
+func TestJvmOutputStreamerWithInvalidFilterFails(t *testing.T) {
+	streamer, err := newJvmOutputStreamer(utils.NewMockConsole(), "[unclosed", false)
+
+	assert.Nil(t, streamer)
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1306E")
+	assert.ErrorContains(t, err, "[unclosed")
+}
+
+func TestJvmOutputLineWriterHoldsLinesBackUntilTheRunIsKnown(t *testing.T) {
+	console := utils.NewMockConsole()
+	streamer, _ := newJvmOutputStreamer(console, "", false)
+	runId := ""
+	writer := newJvmOutputLineWriter(streamer, func() string { return runId })
+
+	writer.Write([]byte("starting up\n"))
+	assert.Equal(t, "", console.ReadText())
+
+	runId = "L12"
+	writer.Write([]byte("Allocated Run Name L12 to this run\n"))
+
+	assert.Equal(t, "[L12] starting up\n[L12] Allocated Run Name L12 to this run\n", console.ReadText())
+}
+
+func TestJvmOutputLineWriterFlushWritesWhatIsHeldBack(t *testing.T) {
+	console := utils.NewMockConsole()
+	streamer, _ := newJvmOutputStreamer(console, "", false)
+	writer := newJvmOutputLineWriter(streamer, func() string { return "" })
+
+	writer.Write([]byte("Error: Could not find or load main class\nno newline"))
+	writer.flush()
+
+	assert.Equal(t, "[?] Error: Could not find or load main class\n[?] no newline\n", console.ReadText())
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/galasa-dev/cli/pkg/spi"
+)
+
+// A local test whose JVM deadlocks would otherwise be waited for forever. A watchdog notices when the JVM
+// has written nothing for too long, or has run for too long, so the JVM can be terminated and the run
+// given the result 'Hung', with a thread dump saved into its RAS folder to show where it was stuck.
+
+const (
+	// The result given to a run whose JVM was terminated by the watchdog.
+	RESULT_HUNG = "Hung"
+
+	// The name of the file within the RAS folder of a hung run which holds the thread dump of its JVM.
+	THREAD_DUMP_FILE_NAME = "thread-dump.txt"
+
+	// How long a hung JVM is given to end once it has been interrupted, before it is killed.
+	JVM_TERMINATION_GRACE_PERIOD = 30 * time.Second
+
+	// How long 'jcmd' is given to get a thread dump. A JVM which is badly hung may never answer it,
+	// in which case the JVM is asked to write the thread dump into its own output instead.
+	THREAD_DUMP_TIMEOUT = 30 * time.Second
+)
+
+// ThreadDumper is something which can get a thread dump of a running JVM.
+// This allows unit tests to supply thread dumps without needing a JVM.
+type ThreadDumper interface {
+	DumpThreads(pid int) ([]byte, error)
+}
+
+// Uses the 'jcmd' tool which comes with the JVM.
+type realThreadDumper struct {
+	jcmdToolPath string
+	timeout      time.Duration
+}
+
+func newRealThreadDumper(javaHome string, separator string) ThreadDumper {
+	dumper := new(realThreadDumper)
+	dumper.jcmdToolPath = javaHome + separator + "bin" + separator + "jcmd"
+	dumper.timeout = THREAD_DUMP_TIMEOUT
+	return dumper
+}
+
+// DumpThreads returns an error if 'jcmd' doesn't finish within the timeout, as it is killed.
+func (dumper *realThreadDumper) DumpThreads(pid int) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dumper.timeout)
+	defer cancel()
+
+	command := exec.CommandContext(ctx, dumper.jcmdToolPath, strconv.Itoa(pid), "Thread.print", "-l")
+	threadDump, err := command.Output()
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return threadDump, err
+}
+
+// jvmWatchdog watches the JVM of a single local test. It is written to with everything the JVM outputs,
+// so it knows when the JVM was last active.
+type jvmWatchdog struct {
+	timeService spi.TimeService
+
+	// The limits, each of which is 0 if there is no limit.
+	inactivityTimeout time.Duration
+	maxDuration       time.Duration
+
+	terminationGracePeriod time.Duration
+	threadDumper           ThreadDumper
+
+	// stdout and stderr are written from different goroutines.
+	mutex            sync.Mutex
+	startTime        time.Time
+	lastActivityTime time.Time
+}
+
+// newJvmWatchdog creates a watchdog for a JVM which is about to be launched.
+func newJvmWatchdog(
+	timeService spi.TimeService,
+	inactivityTimeout time.Duration,
+	maxDuration time.Duration,
+	threadDumper ThreadDumper,
+) *jvmWatchdog {
+	watchdog := new(jvmWatchdog)
+	watchdog.timeService = timeService
+	watchdog.inactivityTimeout = inactivityTimeout
+	watchdog.maxDuration = maxDuration
+	watchdog.terminationGracePeriod = JVM_TERMINATION_GRACE_PERIOD
+	watchdog.threadDumper = threadDumper
+	watchdog.startTime = timeService.Now()
+	watchdog.lastActivityTime = watchdog.startTime
+	return watchdog
+}
+
+// Part of the io.Writer interface. Any output means the JVM is still active.
+func (watchdog *jvmWatchdog) Write(bytesToWrite []byte) (int, error) {
+	watchdog.mutex.Lock()
+	defer watchdog.mutex.Unlock()
+
+	watchdog.lastActivityTime = watchdog.timeService.Now()
+	return len(bytesToWrite), nil
+}
+
+// getExpiredLimit says why the JVM is hung, or returns blank if neither limit has expired yet.
+func (watchdog *jvmWatchdog) getExpiredLimit() string {
+	watchdog.mutex.Lock()
+	defer watchdog.mutex.Unlock()
+
+	expiredLimit := ""
+	now := watchdog.timeService.Now()
+	if watchdog.maxDuration > 0 && now.Sub(watchdog.startTime) >= watchdog.maxDuration {
+		expiredLimit = "it has run for longer than " + watchdog.maxDuration.String()
+	} else if watchdog.inactivityTimeout > 0 && now.Sub(watchdog.lastActivityTime) >= watchdog.inactivityTimeout {
+		expiredLimit = "its JVM has written no output for " + watchdog.inactivityTimeout.String()
+	}
+	return expiredLimit
+}
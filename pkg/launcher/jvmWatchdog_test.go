@@ -0,0 +1,97 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockThreadDumper struct {
+	threadDump []byte
+	err        error
+	pids       []int
+}
+
+func (dumper *mockThreadDumper) DumpThreads(pid int) ([]byte, error) {
+	dumper.pids = append(dumper.pids, pid)
+	return dumper.threadDump, dumper.err
+}
+
+func TestJvmWatchdogExpiresWhenTheJvmWritesNothingForTooLong(t *testing.T) {
+	timeService := utils.NewMockTimeService()
+	watchdog := newJvmWatchdog(timeService, 10*time.Minute, 0, &mockThreadDumper{})
+
+	timeService.AdvanceClock(9 * time.Minute)
+	assert.Equal(t, "", watchdog.getExpiredLimit())
+
+	timeService.AdvanceClock(time.Minute)
+	assert.Equal(t, "its JVM has written no output for 10m0s", watchdog.getExpiredLimit())
+}
+
+func TestJvmWatchdogIsKeptAwakeByOutput(t *testing.T) {
+	timeService := utils.NewMockTimeService()
+	watchdog := newJvmWatchdog(timeService, 10*time.Minute, 0, &mockThreadDumper{})
+
+	timeService.AdvanceClock(9 * time.Minute)
+	n, err := watchdog.Write([]byte("still going"))
+	timeService.AdvanceClock(9 * time.Minute)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 11, n)
+	assert.Equal(t, "", watchdog.getExpiredLimit())
+}
+
+func TestJvmWatchdogExpiresWhenTheJvmRunsForTooLongEvenIfActive(t *testing.T) {
+	timeService := utils.NewMockTimeService()
+	watchdog := newJvmWatchdog(timeService, 10*time.Minute, time.Hour, &mockThreadDumper{})
+
+	for i := 0; i < 12; i++ {
+		timeService.AdvanceClock(5 * time.Minute)
+		watchdog.Write([]byte("still going"))
+	}
+
+	assert.Equal(t, "it has run for longer than 1h0m0s", watchdog.getExpiredLimit())
+}
+
+func TestJvmWatchdogWithNoLimitsNeverExpires(t *testing.T) {
+	timeService := utils.NewMockTimeService()
+	watchdog := newJvmWatchdog(timeService, 0, 0, &mockThreadDumper{})
+
+	timeService.AdvanceClock(1000 * time.Hour)
+
+	assert.Equal(t, "", watchdog.getExpiredLimit())
+}
+
+func TestRealThreadDumperGivesUpWhenJcmdDoesNotAnswer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("A shell script is used in place of jcmd.")
+	}
+
+	// Given...
+	javaHome := t.TempDir()
+	err := os.Mkdir(filepath.Join(javaHome, "bin"), 0755)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(javaHome, "bin", "jcmd"), []byte("#!/bin/sh\nexec sleep 60\n"), 0755)
+	assert.Nil(t, err)
+
+	dumper := newRealThreadDumper(javaHome, "/").(*realThreadDumper)
+	dumper.timeout = 100 * time.Millisecond
+
+	// When...
+	startTime := time.Now()
+	_, err = dumper.DumpThreads(12345)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Less(t, time.Since(startTime), 30*time.Second)
+}
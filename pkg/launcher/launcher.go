@@ -38,6 +38,16 @@ type Launcher interface {
 	// Gets a run based on the submission ID of that run.
 	GetRunsBySubmissionId(submissionId string, groupId string) (*galasaapi.Run, error)
 
+	// Gets how the JVM of each test is configured, or nil if the tests are not launched in a local JVM.
+	GetJvmConfiguration() *JvmConfiguration
+
+	TestCatalogProvider
+}
+
+// ----------------------------------------------------------------------------------
+// TestCatalogProvider something which knows which tests are available to be selected.
+type TestCatalogProvider interface {
+
 	// GetStreams gets a list of streams available on this launcher
 	GetStreams() ([]string, error)
 
@@ -132,6 +132,11 @@ func (launcher *MockLauncher) GetRunsBySubmissionId(submissionId string, groupId
 	return &galasaapi.Run{}, nil
 }
 
+// GetJvmConfiguration gets how the JVM of each test is configured.
+func (launcher *MockLauncher) GetJvmConfiguration() *JvmConfiguration {
+	return nil
+}
+
 // GetStreams gets a list of streams available on this launcher
 func (launcher *MockLauncher) GetStreams() ([]string, error) {
 	return make([]string, 0), nil
@@ -0,0 +1,74 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"log"
+	"sort"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/spi"
+)
+
+// Each local test run saves its results into a folder of its own within the RAS folder, named after the run.
+// The folder holds the status of the run as ras/<runId>/structure.json, next to its run log and artifacts.
+
+const (
+	LOCAL_RUN_STRUCTURE_FILE_NAME = "structure.json"
+	LOCAL_RUN_LOG_FILE_NAME       = "run.log"
+
+	// The status of a local run once it is complete, and no longer writing into its folder.
+	LOCAL_RUN_STATUS_FINISHED = "finished"
+)
+
+// GetLocalRasFolderPath returns the RAS folder the user gave, or the 'ras' folder in the Galasa home folder,
+// which local test runs save their results into by default.
+func GetLocalRasFolderPath(fileSystem spi.FileSystem, galasaHome spi.GalasaHome, rasFolderPath string) string {
+	if rasFolderPath == "" {
+		rasFolderPath = galasaHome.GetNativeFolderPath() + fileSystem.GetFilePathSeparator() + "ras"
+	}
+	return rasFolderPath
+}
+
+// GetLocalRuns reads the status of every run in a local RAS folder from its structure.json file,
+// ordered by the name of the run.
+// A run whose structure.json can't be read, such as one which has only just started, is left out.
+// Only the folders at the top of the RAS folder are looked at, so the artifacts of each run aren't listed.
+func GetLocalRuns(fileSystem spi.FileSystem, rasFolderPath string) ([]galasaapi.Run, error) {
+	var err error
+	runs := make([]galasaapi.Run, 0)
+	separator := fileSystem.GetFilePathSeparator()
+
+	var isExists bool
+	isExists, err = fileSystem.DirExists(rasFolderPath)
+	if err == nil && !isExists {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_LOCAL_RAS_FOLDER_NOT_FOUND, rasFolderPath)
+	}
+
+	var runIds []string
+	if err == nil {
+		runIds, err = fileSystem.GetSubFolderNames(rasFolderPath)
+	}
+
+	if err == nil {
+		sort.Strings(runIds)
+
+		for _, runId := range runIds {
+			run := galasaapi.NewRun()
+			run.SetRunId(runId)
+			jsonFilePath := rasFolderPath + separator + runId + separator + LOCAL_RUN_STRUCTURE_FILE_NAME
+			if setTestStructureFromRasFile(run, jsonFilePath, fileSystem) == nil {
+				runs = append(runs, *run)
+			} else {
+				log.Printf("The status of local run '%s' could not be read, so it is left out.\n", runId)
+			}
+		}
+	}
+
+	log.Printf("Found %d local runs in the RAS folder '%s'\n", len(runs), rasFolderPath)
+	return runs, err
+}
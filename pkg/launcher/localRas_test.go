@@ -0,0 +1,78 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLocalRunsReadsTheStatusOfEveryRunInTheRasFolder(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	fs.MkdirAll("/my/ras")
+	fs.WriteTextFile("/my/ras/L2/structure.json", `{ "runName": "L2", "result": "Failed" }`)
+	fs.WriteTextFile("/my/ras/L1/structure.json", `{ "runName": "L1", "result": "Passed" }`)
+	fs.WriteTextFile("/my/ras/L1/artifacts/structure.json", `{ "runName": "not a run" }`)
+
+	// When...
+	runs, err := GetLocalRuns(fs, "/my/ras")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Len(t, runs, 2)
+	assert.Equal(t, "L1", runs[0].GetRunId())
+	assert.Equal(t, "Passed", runs[0].TestStructure.GetResult())
+	assert.Equal(t, "L2", runs[1].GetRunId())
+	assert.Equal(t, "Failed", runs[1].TestStructure.GetResult())
+}
+
+func TestGetLocalRunsLeavesOutRunsWhoseStatusCantBeRead(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	fs.MkdirAll("/my/ras")
+	fs.WriteTextFile("/my/ras/L1/structure.json", `{ "runName": "L1" }`)
+	fs.WriteTextFile("/my/ras/L2/structure.json", "")
+	fs.WriteTextFile("/my/ras/L3/run.log", "A run which has only just started")
+
+	// When...
+	runs, err := GetLocalRuns(fs, "/my/ras")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Len(t, runs, 1)
+	assert.Equal(t, "L1", runs[0].GetRunId())
+}
+
+func TestGetLocalRunsWithMissingRasFolderReturnsError(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+
+	// When...
+	_, err := GetLocalRuns(fs, "/my/ras")
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1310E")
+	assert.Contains(t, err.Error(), "'/my/ras'")
+}
+
+func TestGetLocalRasFolderPathDefaultsToTheGalasaHomeFolder(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	galasaHome, _ := utils.NewGalasaHome(fs, utils.NewMockEnv(), "")
+
+	// When...
+	defaultPath := GetLocalRasFolderPath(fs, galasaHome, "")
+	givenPath := GetLocalRasFolderPath(fs, galasaHome, "/my/ras")
+
+	// Then...
+	assert.Equal(t, "/User/Home/testuser/.galasa/ras", defaultPath)
+	assert.Equal(t, "/my/ras", givenPath)
+}
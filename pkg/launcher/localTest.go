@@ -6,10 +6,12 @@
 package launcher
 
 import (
-	"bytes"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
 	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
@@ -17,17 +19,27 @@ import (
 	"github.com/galasa-dev/cli/pkg/spi"
 )
 
+// How long to wait for the JVM of a test which has finished to exit, before checking on the other tests again.
+const JVM_EXIT_TIMEOUT = 10 * time.Second
+
 // A local test which gets run.
 type LocalTest struct {
 	process Process
 	stdout  *JVMOutputProcessor
-	stderr  *bytes.Buffer
+
+	// Everything the JVM writes to stderr is copied to each of these.
+	stderrCopies []io.Writer
 
 	// A go channel. Anything waiting for the test to complete will wait on
 	// this channel. When the test completes, a string message is placed
 	// on this channel to wake up any waiting threads.
 	reportingChannel chan string
 
+	// The go routine waiting for the JVM to complete updates the status of the test while the
+	// launching thread polls it, so the runId, rasFolderPathUrl, testRun and hungReason
+	// are only used while this is locked. A testRun is never changed once it has been set.
+	statusMutex sync.Mutex
+
 	// What runId is this test using ?
 	// We don't initially know it. This info is extracted from the JVM trace.
 	runId string
@@ -51,6 +63,29 @@ type LocalTest struct {
 
 	// Something which can create new processes in the operating system
 	processFactory ProcessFactory
+
+	// Files the JVM writes out as it exits, such as the recording of the test when it is profiled.
+	// Empty unless the test is being profiled or its code coverage is being collected.
+	jvmExitArtifacts []jvmExitArtifact
+
+	// Set once the user has been told how the test completed, and what the files written by the JVM show.
+	isCompletionReported bool
+
+	// Saves the stdout and stderr of the JVM, or nil if they aren't saved.
+	outputCapture *jvmOutputCapture
+
+	// Echo the stdout and stderr of the JVM to the console a line at a time, if the output is being streamed.
+	outputLineWriters []*jvmOutputLineWriter
+
+	// Notices if the JVM hangs, or nil if it isn't being watched.
+	watchdog *jvmWatchdog
+
+	// Why the JVM was found to be hung, or blank if it isn't.
+	hungReason string
+
+	// Where the thread dump of a hung JVM was saved, or blank if it wasn't.
+	// Only used by the launching thread.
+	threadDumpFilePath string
 }
 
 // A structure which tells us all we know about a JVM process we launched.
@@ -63,7 +98,6 @@ func NewLocalTest(
 	localTest := new(LocalTest)
 
 	localTest.stdout = NewJVMOutputProcessor()
-	localTest.stderr = bytes.NewBuffer([]byte{})
 	localTest.runId = ""
 	localTest.testRun = nil
 	localTest.mainPollLoopSleeper = mainPollLoopSleeper
@@ -75,26 +109,72 @@ func NewLocalTest(
 	return localTest
 }
 
-// Launch a test within a JVM.
+// captureOutput saves the stdout and stderr of the JVM, so they can be moved into the RAS folder of the run
+// once the JVM has ended, and echoes them to the console if there is a streamer.
+// This must be called before the test is launched.
+func (localTest *LocalTest) captureOutput(outputCapture *jvmOutputCapture, outputStreamer *jvmOutputStreamer) {
+	if outputCapture != nil {
+		localTest.outputCapture = outputCapture
+		localTest.copyOutputTo(outputCapture, outputCapture)
+	}
+
+	if outputStreamer != nil {
+		getRunId := localTest.stdout.getDetectedRunId
+		stdoutLineWriter := newJvmOutputLineWriter(outputStreamer, getRunId)
+		stderrLineWriter := newJvmOutputLineWriter(outputStreamer, getRunId)
+		localTest.outputLineWriters = []*jvmOutputLineWriter{stdoutLineWriter, stderrLineWriter}
+		localTest.copyOutputTo(stdoutLineWriter, stderrLineWriter)
+	}
+}
+
+// watch has the watchdog told about everything the JVM writes, so it can tell when the JVM is hung.
+// This must be called before the test is launched.
+func (localTest *LocalTest) watch(watchdog *jvmWatchdog) {
+	localTest.watchdog = watchdog
+	localTest.copyOutputTo(watchdog, watchdog)
+}
+
+func (localTest *LocalTest) copyOutputTo(stdoutCopy io.Writer, stderrCopy io.Writer) {
+	localTest.stdout.copyTo(stdoutCopy)
+	localTest.stderrCopies = append(localTest.stderrCopies, stderrCopy)
+}
+
+// Launch a test within a JVM, with some extra environment variables of the form key=value
 // Hang around waiting for the JVM to trace the runID and ras location.
-func (localTest *LocalTest) launch(cmd string, args []string) error {
+func (localTest *LocalTest) launch(cmd string, args []string, env []string) error {
 
 	// Create a new process, so we can track it and all we know about it.
 	localTest.process = localTest.processFactory.NewProcess()
 
 	// Start the process so it invokes the command.
-	err := localTest.process.Start(cmd, args, localTest.stdout, localTest.stderr)
+	stderr := io.MultiWriter(localTest.stderrCopies...)
+	err := localTest.process.Start(cmd, args, env, localTest.stdout, stderr)
 	if err != nil {
 		log.Printf("Failed to start the JVM. %s\n", err.Error())
 		log.Printf("Failing command is %s %v\n", cmd, args)
+		for _, artifact := range localTest.jvmExitArtifacts {
+			artifact.discard()
+		}
+		if localTest.outputCapture != nil {
+			localTest.outputCapture.discard()
+		}
 	} else {
 
 		log.Printf("JVM test started. Spawning a go routine to wait for it to complete.\n")
 		go localTest.waitForCompletion()
 
-		localTest.runId, err = localTest.waitForRunIdAllocation(localTest.stdout)
+		var runId string
+		runId, err = localTest.waitForRunIdAllocation(localTest.stdout)
+		localTest.statusMutex.Lock()
+		localTest.runId = runId
+		localTest.statusMutex.Unlock()
+
 		if err == nil {
-			localTest.rasFolderPathUrl, err = localTest.waitForRasFolderPathUrl(localTest.stdout, localTest.runId)
+			var rasFolderPathUrl string
+			rasFolderPathUrl, err = localTest.waitForRasFolderPathUrl(localTest.stdout, runId)
+			localTest.statusMutex.Lock()
+			localTest.rasFolderPathUrl = rasFolderPathUrl
+			localTest.statusMutex.Unlock()
 
 			if err == nil {
 				log.Printf("JVM test started and in progress. We know how to monitor it now.\n")
@@ -130,7 +210,7 @@ func (localTest *LocalTest) waitForRasFolderPathUrl(outputProcessor *JVMOutputPr
 		}
 	}
 
-	rasFolderPathUrl = outputProcessor.detectedRasFolderPathUrl
+	rasFolderPathUrl = outputProcessor.getDetectedRasFolderPathUrl()
 
 	if rasFolderPathUrl == "" {
 		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_RAS_FOLDER_NOT_DETECTED, runId)
@@ -170,7 +250,7 @@ func (localTest *LocalTest) waitForRunIdAllocation(outputProcessor *JVMOutputPro
 
 	// If the timer went, and we hadn't noted that the localTest was completed yet, there
 	// is a timing window where we don't collect the detected runId.
-	runId = outputProcessor.detectedRunId
+	runId = outputProcessor.getDetectedRunId()
 
 	if runId == "" {
 		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_RUN_ID_NOT_DETECTED)
@@ -194,6 +274,18 @@ func (localTest *LocalTest) waitForCompletion() error {
 		log.Printf("JVM has completed. Detected by waiting go routine.\n")
 	}
 
+	// Anything the JVM wrote without ending the line is echoed now.
+	for _, outputLineWriter := range localTest.outputLineWriters {
+		outputLineWriter.flush()
+	}
+
+	if localTest.outputCapture != nil {
+		localTest.saveJvmOutput()
+	}
+
+	// The JVM only writes out files such as its recording as it exits.
+	localTest.saveJvmExitArtifacts()
+
 	// Read any final status from the file created by the JVM
 	localTest.updateTestStatusFromRasFile()
 
@@ -201,43 +293,126 @@ func (localTest *LocalTest) waitForCompletion() error {
 	localTest.reportingChannel <- "DONE"
 	close(localTest.reportingChannel)
 
-	msg := fmt.Sprintf("Test run %s completed.", localTest.runId)
+	msg := fmt.Sprintf("Test run %s completed.", localTest.getRunId())
 	localTest.mainPollLoopSleeper.Interrupt(msg)
 
 	return err
 }
 
+// Moves the saved output of the JVM into the RAS folder of the test, if the test got far enough to have one.
+func (localTest *LocalTest) saveJvmOutput() {
+	runId, rasFolderPath := localTest.getDetectedRunRasFolder()
+	if rasFolderPath == "" {
+		log.Printf("The JVM ended before it had a RAS folder, so its output can't be saved there.\n")
+		localTest.outputCapture.discard()
+	} else {
+		localTest.outputCapture.save(rasFolderPath, runId)
+	}
+}
+
+// Moves the files written by the JVM as it exited into the RAS folder of the test, if the test got far enough to have one.
+func (localTest *LocalTest) saveJvmExitArtifacts() {
+	runId, rasFolderPath := localTest.getDetectedRunRasFolder()
+	for _, artifact := range localTest.jvmExitArtifacts {
+		if rasFolderPath == "" {
+			log.Printf("The JVM ended before it had a RAS folder, so the files it wrote as it exited can't be saved.\n")
+			artifact.discard()
+		} else {
+			artifact.save(rasFolderPath, runId)
+		}
+	}
+}
+
+// Gets the runId and the path of the RAS folder from the JVM output, rather than waiting for them to be noted
+// by the thread which launched the JVM, as the JVM may have ended before then.
+// The RAS folder path is blank if either isn't known.
+func (localTest *LocalTest) getDetectedRunRasFolder() (string, string) {
+	runId := localTest.stdout.getDetectedRunId()
+	rasFolderPathUrl := localTest.stdout.getDetectedRasFolderPathUrl()
+	rasFolderPath := ""
+	if runId != "" && rasFolderPathUrl != "" {
+		rasFolderPath = fileUrlToFilePath(rasFolderPathUrl)
+	}
+	return runId, rasFolderPath
+}
+
 // If we can find it, read the status report for the test from the
 // ras folder.
 func (localTest *LocalTest) updateTestStatusFromRasFile() error {
 
 	var err error
 
-	if localTest.runId == "" || localTest.rasFolderPathUrl == "" {
+	runId, rasFolderPathUrl := localTest.getRunIdAndRasFolderPathUrl()
+
+	if runId == "" || rasFolderPathUrl == "" {
 		log.Printf("Don't have enough information to find the structure.json in the RAS folder yet. Test JVM is starting up.\n")
 	} else {
 
-		jsonFilePath := strings.TrimPrefix(localTest.rasFolderPathUrl, "file:///") + "/" + localTest.runId + "/structure.json"
+		jsonFilePath := strings.TrimPrefix(rasFolderPathUrl, "file:///") + "/" + runId + "/structure.json"
 		log.Printf("Reading latest test status from '%s'\n", jsonFilePath)
 
 		var testRun *galasaapi.TestRun
 		testRun, err = readTestRunFromJsonFile(localTest.fileSystem, jsonFilePath)
 
 		if err == nil {
-			localTest.testRun = testRun
+			localTest.setTestRun(testRun)
 		}
 	}
 	return err
 }
 
+// getRunId returns the ID of the test run, or blank if the JVM hasn't said what it is yet.
+func (localTest *LocalTest) getRunId() string {
+	localTest.statusMutex.Lock()
+	defer localTest.statusMutex.Unlock()
+	return localTest.runId
+}
+
+// getRunIdAndRasFolderPathUrl returns the ID of the test run and the URL of the RAS folder it writes into,
+// or blanks if the JVM hasn't said what they are yet.
+func (localTest *LocalTest) getRunIdAndRasFolderPathUrl() (string, string) {
+	localTest.statusMutex.Lock()
+	defer localTest.statusMutex.Unlock()
+	return localTest.runId, localTest.rasFolderPathUrl
+}
+
+// getTestRun returns the latest status of the test, or nil if there isn't one yet.
+func (localTest *LocalTest) getTestRun() *galasaapi.TestRun {
+	localTest.statusMutex.Lock()
+	defer localTest.statusMutex.Unlock()
+	return localTest.testRun
+}
+
+// setTestRun records the latest status of the test.
+// The structure.json of a hung test won't say it has finished, so a hung test stays marked as hung.
+func (localTest *LocalTest) setTestRun(testRun *galasaapi.TestRun) {
+	localTest.statusMutex.Lock()
+	defer localTest.statusMutex.Unlock()
+	localTest.testRun = testRun
+	if localTest.hungReason != "" {
+		localTest.markHung()
+	}
+}
+
+// getHungReason says why the JVM was found to be hung, or returns blank if it isn't.
+func (localTest *LocalTest) getHungReason() string {
+	localTest.statusMutex.Lock()
+	defer localTest.statusMutex.Unlock()
+	return localTest.hungReason
+}
+
 // This method is called by a thread monitoring the state of the JVM.
 // It can receive messages from the JVM launcher go routine.
-// This call never blocks waiting for anything.
+// This call only blocks once the test has finished, while its JVM exits.
 func (localTest *LocalTest) isCompleted() bool {
 
 	isComplete := false
 
-	if localTest.testRun != nil && localTest.testRun.GetStatus() == "finished" {
+	// A test whose output is being saved, or which is being profiled, or having its code coverage collected,
+	// isn't complete until its JVM has ended and everything it wrote has been saved.
+	isWaitingForJvmExit := localTest.outputCapture != nil || len(localTest.jvmExitArtifacts) > 0
+
+	if !isWaitingForJvmExit && localTest.getTestRun().GetStatus() == "finished" {
 		// The test is already complete.
 		// log.Printf("Test is already complete\n")
 		isComplete = true
@@ -258,11 +433,120 @@ func (localTest *LocalTest) isCompleted() bool {
 		}
 
 		localTest.updateTestStatusFromRasFile()
-		if localTest.testRun != nil && localTest.testRun.GetStatus() == "finished" {
-			// The test is already complete.
-			log.Printf("Test is already complete when it wasn't before.\n")
-			isComplete = true
+		if !isComplete && localTest.getTestRun().GetStatus() == "finished" {
+			if !isWaitingForJvmExit {
+				// The test is already complete.
+				log.Printf("Test is already complete when it wasn't before.\n")
+				isComplete = true
+			} else {
+				// The test has finished, so its JVM is about to exit.
+				isComplete = localTest.waitForJvmExit(JVM_EXIT_TIMEOUT)
+			}
+		}
+
+		if !isComplete && localTest.watchdog != nil && localTest.getHungReason() == "" {
+			expiredLimit := localTest.watchdog.getExpiredLimit()
+			if expiredLimit != "" {
+				isComplete = localTest.terminateHungJvm(expiredLimit)
+			}
 		}
 	}
 	return isComplete
 }
+
+// Blocks until the go routine waiting for the JVM says it has ended, and everything it wrote has been saved.
+// Returns false if that takes longer than the timeout.
+func (localTest *LocalTest) waitForJvmExit(timeout time.Duration) bool {
+	isExited := false
+	select {
+	case msg := <-localTest.reportingChannel:
+		log.Printf("Message received from JVM launch thread: %s\n", msg)
+		isExited = (msg == "DONE" || msg == "")
+	case <-time.After(timeout):
+		log.Printf("The test has finished, but its JVM has not exited yet.\n")
+	}
+	return isExited
+}
+
+// Terminates the JVM of a test which is hung, after saving a thread dump to show where it is stuck.
+// Returns true once the JVM has ended.
+func (localTest *LocalTest) terminateHungJvm(expiredLimit string) bool {
+	log.Printf("Test run %s is hung, as %s. Terminating its JVM.\n", localTest.getRunId(), expiredLimit)
+	localTest.statusMutex.Lock()
+	localTest.hungReason = expiredLimit
+	localTest.statusMutex.Unlock()
+
+	localTest.dumpThreads()
+
+	// The JVM is given the chance to end tidily, running its shutdown hooks, before it is killed.
+	isExited := false
+	err := localTest.process.Signal(os.Interrupt)
+	if err != nil {
+		log.Printf("Failed to interrupt the hung JVM. %s\n", err.Error())
+	} else {
+		isExited = localTest.waitForJvmExit(localTest.watchdog.terminationGracePeriod)
+	}
+
+	if !isExited {
+		log.Printf("The hung JVM did not end when interrupted, so it is being killed.\n")
+		err = localTest.process.Kill()
+		if err != nil {
+			log.Printf("Failed to kill the hung JVM. %s\n", err.Error())
+		}
+		isExited = localTest.waitForJvmExit(JVM_EXIT_TIMEOUT)
+	}
+
+	localTest.statusMutex.Lock()
+	localTest.markHung()
+	localTest.statusMutex.Unlock()
+	return isExited
+}
+
+// Saves a thread dump of the hung JVM into the RAS folder of the run, as ras/<runId>/thread-dump.txt
+// If that can't be done, the JVM is asked to write the thread dump into its own output instead, where it can be.
+func (localTest *LocalTest) dumpThreads() {
+	isSaved := false
+	runId, rasFolderPath := localTest.getDetectedRunRasFolder()
+
+	if rasFolderPath != "" {
+		runFolderPath := rasFolderPath + "/" + runId
+		threadDumpFilePath := runFolderPath + "/" + THREAD_DUMP_FILE_NAME
+
+		threadDump, err := localTest.watchdog.threadDumper.DumpThreads(localTest.process.GetPid())
+		if err == nil {
+			err = localTest.fileSystem.MkdirAll(runFolderPath)
+		}
+		if err == nil {
+			err = localTest.fileSystem.WriteBinaryFile(threadDumpFilePath, threadDump)
+		}
+
+		if err != nil {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_THREAD_DUMP_NOT_SAVED, runId, threadDumpFilePath, err.Error())
+			log.Println(err.Error())
+		} else {
+			log.Printf("Saved a thread dump of the hung JVM to '%s'\n", threadDumpFilePath)
+			localTest.threadDumpFilePath = threadDumpFilePath
+			isSaved = true
+		}
+	}
+
+	if !isSaved && threadDumpSignal != nil {
+		log.Printf("Asking the hung JVM to write a thread dump into its output.\n")
+		localTest.process.Signal(threadDumpSignal)
+	}
+}
+
+// A hung test is reported as finished, whatever its structure.json says, or even if it hasn't written one yet,
+// so it no longer holds up the others.
+// The statusMutex must be locked. The testRun may already have been handed out, so a copy is changed.
+func (localTest *LocalTest) markHung() {
+	var hungTestRun galasaapi.TestRun
+	if localTest.testRun == nil {
+		hungTestRun = *createSimulatedTestRun(localTest.runId)
+	} else {
+		hungTestRun = *localTest.testRun
+	}
+	hungTestRun.SetStatus("finished")
+	hungTestRun.SetResult(RESULT_HUNG)
+	localTest.testRun = &hungTestRun
+}
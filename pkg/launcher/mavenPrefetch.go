@@ -0,0 +1,450 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"strings"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+)
+
+// Each version of Galasa has an uber OBR which refers to every bundle of the Galasa framework and its managers.
+// It is launched alongside the OBRs of the tests.
+const (
+	GALASA_UBER_OBR_GROUP_ID    = "dev.galasa"
+	GALASA_UBER_OBR_ARTIFACT_ID = "dev.galasa.uber.obr"
+
+	OBR_EXTENSION    = "obr"
+	BUNDLE_EXTENSION = "jar"
+
+	// A Maven repository holds the SHA-1 checksum of each artifact next to it, in a file with this extension added.
+	MAVEN_CHECKSUM_EXTENSION = "sha1"
+)
+
+// An OBR is an XML file which lists the bundles it refers to, each with a URI such as
+// mvn:dev.galasa/dev.galasa.framework/0.38.0/jar
+type obrRepository struct {
+	Resources []obrResource `xml:"resource"`
+}
+
+type obrResource struct {
+	Uri string `xml:"uri,attr"`
+}
+
+// The outcome of making sure one OBR or bundle is in the local Maven repository.
+type resolvedArtifact struct {
+	contents     []byte
+	isFound      bool
+	isDownloaded bool
+	isVerified   bool
+}
+
+// What was found when the OBRs and all the bundles they refer to were resolved.
+type localRunDependencies struct {
+	artifactCount    int
+	downloadedCount  int
+	verifiedCount    int
+	missingArtifacts []string
+}
+
+// Finds the OBRs and bundles which a local run needs in the local Maven repository. When there is a remote
+// Maven repository, anything missing from the local one, or whose checksum doesn't match, is downloaded into it.
+type mavenArtifactResolver struct {
+	fileSystem  spi.FileSystem
+	localMaven  string
+	remoteMaven string
+
+	// When checksums aren't being verified, the bundles are only checked to be there.
+	isVerifyingChecksums bool
+}
+
+// GetGalasaUberObr returns the Maven coordinates of the uber OBR of a version of Galasa.
+// For example: mvn:dev.galasa/dev.galasa.uber.obr/0.38.0/obr
+func GetGalasaUberObr(galasaVersion string) string {
+	return "mvn:" + GALASA_UBER_OBR_GROUP_ID + "/" + GALASA_UBER_OBR_ARTIFACT_ID + "/" + galasaVersion + "/" + OBR_EXTENSION
+}
+
+// PrefetchLocalRunDependencies makes sure the OBRs given, the uber OBR of the Galasa version given, and every
+// bundle which they refer to, are in the local Maven repository, so that tests can later be run with --offline.
+// Anything missing is downloaded from the remote Maven repository, and the SHA-1 checksum of everything
+// is verified against the checksum held next to it.
+func PrefetchLocalRunDependencies(
+	fileSystem spi.FileSystem,
+	obrs []string,
+	galasaVersion string,
+	localMaven string,
+	remoteMaven string,
+	console spi.Console,
+) error {
+	var err error
+	var obrCoordinates []utils.MavenCoordinates
+	var dependencies *localRunDependencies
+
+	obrCoordinates, err = utils.ValidateObrs(obrs)
+	if err == nil {
+		localMaven, err = defaultLocalMavenIfNotSet(localMaven, fileSystem)
+	}
+
+	if err == nil {
+		resolver := &mavenArtifactResolver{
+			fileSystem:           fileSystem,
+			localMaven:           localMaven,
+			remoteMaven:          remoteMaven,
+			isVerifyingChecksums: true,
+		}
+		dependencies, err = resolver.resolveLocalRunDependencies(obrCoordinates, galasaVersion)
+		if err == nil {
+			err = resolver.resolveTestCatalogs(obrCoordinates, dependencies)
+		}
+	}
+
+	if err == nil {
+		if len(dependencies.missingArtifacts) > 0 {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_MAVEN_ARTIFACT_NOT_FOUND,
+				len(dependencies.missingArtifacts), localMaven, remoteMaven, dependencies.getMissingArtifactsList())
+		} else {
+			err = console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_PREFETCH_DONE.Template,
+				dependencies.artifactCount, localMaven, dependencies.downloadedCount, dependencies.verifiedCount))
+		}
+	}
+	return err
+}
+
+// CheckLocalRunDependenciesAreLocal fails if any of the OBRs given, the uber OBR of the Galasa version given,
+// or any bundle which they refer to, is missing from the local Maven repository.
+func CheckLocalRunDependenciesAreLocal(
+	fileSystem spi.FileSystem,
+	obrs []utils.MavenCoordinates,
+	galasaVersion string,
+	localMaven string,
+) error {
+	var err error
+	var dependencies *localRunDependencies
+
+	localMaven, err = defaultLocalMavenIfNotSet(localMaven, fileSystem)
+	if err == nil {
+		resolver := &mavenArtifactResolver{
+			fileSystem: fileSystem,
+			localMaven: localMaven,
+		}
+		dependencies, err = resolver.resolveLocalRunDependencies(obrs, galasaVersion)
+	}
+
+	if err == nil && len(dependencies.missingArtifacts) > 0 {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_OFFLINE_ARTIFACTS_MISSING,
+			len(dependencies.missingArtifacts), localMaven, dependencies.getMissingArtifactsList())
+	}
+	return err
+}
+
+// Lists every missing artifact, so the user can see all that is missing from one error.
+func (dependencies *localRunDependencies) getMissingArtifactsList() string {
+	return strings.Join(dependencies.missingArtifacts, ", ")
+}
+
+// Resolves each OBR, then each bundle it refers to. Any OBR or bundle which can't be found is
+// recorded as missing, so that everything missing can be reported at once.
+func (resolver *mavenArtifactResolver) resolveLocalRunDependencies(
+	obrs []utils.MavenCoordinates,
+	galasaVersion string,
+) (*localRunDependencies, error) {
+	var err error
+	dependencies := &localRunDependencies{missingArtifacts: make([]string, 0)}
+
+	var uberObr utils.MavenCoordinates
+	uberObr, err = utils.ValidateObr(GetGalasaUberObr(galasaVersion))
+
+	if err == nil {
+		artifactsToResolve := make([]utils.MavenCoordinates, 0, len(obrs)+1)
+		artifactsToResolve = append(artifactsToResolve, obrs...)
+		artifactsToResolve = append(artifactsToResolve, uberObr)
+
+		isQueued := make(map[utils.MavenCoordinates]bool)
+		for _, artifact := range artifactsToResolve {
+			isQueued[artifact] = true
+		}
+
+		for len(artifactsToResolve) > 0 && err == nil {
+			artifact := artifactsToResolve[0]
+			artifactsToResolve = artifactsToResolve[1:]
+
+			isObr := artifact.Classifier == OBR_EXTENSION
+
+			var resolved resolvedArtifact
+			resolved, err = resolver.resolve(artifact, "", isObr)
+			if err == nil {
+				dependencies.record(artifact, resolved)
+
+				if isObr && resolved.isFound {
+					var bundles []utils.MavenCoordinates
+					bundles, err = getObrBundles(resolved.contents, artifact, resolver.getLocalArtifactPath(artifact, ""))
+					for _, bundle := range bundles {
+						if !isQueued[bundle] {
+							isQueued[bundle] = true
+							artifactsToResolve = append(artifactsToResolve, bundle)
+						}
+					}
+				}
+			}
+		}
+	}
+	return dependencies, err
+}
+
+// Tests can be selected from the test catalogs of the OBRs, so those are fetched too. Not every OBR
+// has a test catalog, so one which can't be found isn't treated as missing.
+func (resolver *mavenArtifactResolver) resolveTestCatalogs(obrs []utils.MavenCoordinates, dependencies *localRunDependencies) error {
+	var err error
+	for _, obr := range obrs {
+		testCatalog := obr
+		testCatalog.Classifier = TEST_CATALOG_EXTENSION
+
+		var resolved resolvedArtifact
+		resolved, err = resolver.resolve(testCatalog, TEST_CATALOG_CLASSIFIER, false)
+		if err != nil {
+			break
+		}
+		if resolved.isFound {
+			dependencies.record(testCatalog, resolved)
+		}
+	}
+	return err
+}
+
+func (dependencies *localRunDependencies) record(artifact utils.MavenCoordinates, resolved resolvedArtifact) {
+	dependencies.artifactCount++
+	if !resolved.isFound {
+		dependencies.missingArtifacts = append(dependencies.missingArtifacts, getMavenUri(artifact))
+	}
+	if resolved.isDownloaded {
+		dependencies.downloadedCount++
+	}
+	if resolved.isVerified {
+		dependencies.verifiedCount++
+	}
+}
+
+// Looks for the artifact in the local Maven repository first. If it isn't there, or its checksum
+// doesn't match, it is downloaded from the remote Maven repository, if there is one.
+// The classifier is blank for the artifact itself, or names a file published next to it, such as its test catalog.
+// The contents of the artifact are only read if they are wanted, or if its checksum is being verified.
+func (resolver *mavenArtifactResolver) resolve(artifact utils.MavenCoordinates, classifier string, isContentsWanted bool) (resolvedArtifact, error) {
+	var err error
+	var resolved resolvedArtifact
+
+	localPath := resolver.getLocalArtifactPath(artifact, classifier)
+	resolved.isFound, err = resolver.fileSystem.Exists(localPath)
+
+	if err == nil && resolved.isFound && (isContentsWanted || resolver.isVerifyingChecksums) {
+		resolved.contents, err = resolver.fileSystem.ReadBinaryFile(localPath)
+	}
+
+	if err == nil && resolved.isFound && resolver.isVerifyingChecksums {
+		// An artifact installed by a local Maven build has no checksum, so can't be verified.
+		// Only one which came from a remote Maven repository can be.
+		var expectedChecksum string
+		expectedChecksum, err = resolver.readLocalChecksum(localPath)
+		if err == nil && expectedChecksum != "" {
+			if getSha1Checksum(resolved.contents) == expectedChecksum {
+				resolved.isVerified = true
+			} else {
+				log.Printf("The checksum of '%s' doesn't match '%s', so it will be downloaded again\n", localPath, localPath+"."+MAVEN_CHECKSUM_EXTENSION)
+				resolved.isFound = false
+			}
+		}
+	}
+
+	if err == nil && !resolved.isFound && resolver.remoteMaven != "" {
+		resolved, err = resolver.download(artifact, classifier, localPath)
+	}
+
+	if err == nil && !resolved.isFound {
+		log.Printf("The Maven artifact '%s' was not found at '%s'\n", getMavenUri(artifact), localPath)
+	}
+	return resolved, err
+}
+
+// Downloads the artifact and its checksum from the remote Maven repository into the local one.
+// Snapshot versions have a timestamp in their file names in a remote Maven repository, but not in a local one.
+func (resolver *mavenArtifactResolver) download(artifact utils.MavenCoordinates, classifier string, localPath string) (resolvedArtifact, error) {
+	var err error
+	var resolved resolvedArtifact
+
+	folderUrl := strings.TrimSuffix(resolver.remoteMaven, "/") + "/" + getMavenArtifactFolderPath(artifact)
+
+	fileVersion := artifact.Version
+	if strings.HasSuffix(artifact.Version, "-SNAPSHOT") {
+		var metadataContents []byte
+		var isMetadataFound bool
+		metadataUrl := folderUrl + "/maven-metadata.xml"
+		metadataContents, isMetadataFound, err = readUrl(metadataUrl)
+		if err != nil {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_MAVEN_ARTIFACT_DOWNLOAD_FAILED, getMavenUri(artifact), metadataUrl, err.Error())
+		} else if isMetadataFound {
+			fileVersion = getSnapshotFileVersion(metadataContents, artifact.Version, classifier, artifact.Classifier)
+		}
+	}
+
+	artifactUrl := folderUrl + "/" + getMavenArtifactFileName(artifact, classifier, fileVersion)
+	if err == nil {
+		log.Printf("Downloading the Maven artifact '%s' from '%s'\n", getMavenUri(artifact), artifactUrl)
+		resolved.contents, resolved.isFound, err = readUrl(artifactUrl)
+		if err != nil {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_MAVEN_ARTIFACT_DOWNLOAD_FAILED, getMavenUri(artifact), artifactUrl, err.Error())
+		}
+	}
+
+	var checksumText []byte
+	if err == nil && resolved.isFound {
+		resolved.isDownloaded = true
+
+		var isChecksumFound bool
+		checksumUrl := artifactUrl + "." + MAVEN_CHECKSUM_EXTENSION
+		checksumText, isChecksumFound, err = readUrl(checksumUrl)
+		if err != nil {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_MAVEN_ARTIFACT_DOWNLOAD_FAILED, getMavenUri(artifact), checksumUrl, err.Error())
+		} else if isChecksumFound {
+			expectedChecksum := parseChecksum(string(checksumText))
+			actualChecksum := getSha1Checksum(resolved.contents)
+			if actualChecksum != expectedChecksum {
+				err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_MAVEN_ARTIFACT_CHECKSUM_BAD, getMavenUri(artifact), artifactUrl, actualChecksum, expectedChecksum)
+			} else {
+				resolved.isVerified = true
+			}
+		} else {
+			log.Printf("No checksum was found for '%s', so it can't be verified\n", artifactUrl)
+		}
+	}
+
+	if err == nil && resolved.isFound {
+		err = resolver.save(artifact, localPath, resolved.contents, checksumText)
+	}
+	return resolved, err
+}
+
+// The checksum is saved after the artifact, so that an artifact which was only partly saved is downloaded again.
+func (resolver *mavenArtifactResolver) save(artifact utils.MavenCoordinates, localPath string, contents []byte, checksumText []byte) error {
+	var err error
+	fileSystem := resolver.fileSystem
+
+	folderPath := localPath[:strings.LastIndex(localPath, "/")]
+	err = fileSystem.MkdirAll(folderPath)
+	if err == nil {
+		err = fileSystem.WriteBinaryFile(localPath, contents)
+	}
+	if err == nil && checksumText != nil {
+		err = fileSystem.WriteBinaryFile(localPath+"."+MAVEN_CHECKSUM_EXTENSION, checksumText)
+	}
+
+	if err != nil {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_MAVEN_ARTIFACT_NOT_SAVED, getMavenUri(artifact), localPath, err.Error())
+	}
+	return err
+}
+
+// Returns a blank checksum if there isn't one.
+func (resolver *mavenArtifactResolver) readLocalChecksum(localPath string) (string, error) {
+	var err error
+	var checksum string
+	var isFound bool
+
+	checksumPath := localPath + "." + MAVEN_CHECKSUM_EXTENSION
+	isFound, err = resolver.fileSystem.Exists(checksumPath)
+	if err == nil && isFound {
+		var checksumText string
+		checksumText, err = resolver.fileSystem.ReadTextFile(checksumPath)
+		checksum = parseChecksum(checksumText)
+	}
+	return checksum, err
+}
+
+// For example: /home/me/.m2/repository/dev/galasa/dev.galasa.framework/0.38.0/dev.galasa.framework-0.38.0.jar
+func (resolver *mavenArtifactResolver) getLocalArtifactPath(artifact utils.MavenCoordinates, classifier string) string {
+	return fileUrlToFilePath(resolver.localMaven) + "/" + getMavenArtifactFolderPath(artifact) + "/" + getMavenArtifactFileName(artifact, classifier, artifact.Version)
+}
+
+// Finds the bundles an OBR refers to. Bundles which aren't referred to using Maven coordinates
+// can't be fetched from a Maven repository, so are left for the test JVM to find.
+func getObrBundles(obrContents []byte, obr utils.MavenCoordinates, obrLocation string) ([]utils.MavenCoordinates, error) {
+	var err error
+	bundles := make([]utils.MavenCoordinates, 0)
+
+	var repository obrRepository
+	err = xml.Unmarshal(obrContents, &repository)
+	if err != nil {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_OBR_NOT_READ, getMavenUri(obr), obrLocation, err.Error())
+	} else {
+		for _, resource := range repository.Resources {
+			bundle, isMavenUri := parseMavenUri(resource.Uri)
+			if isMavenUri {
+				bundles = append(bundles, bundle)
+			} else {
+				log.Printf("OBR '%s' refers to '%s', which isn't in a Maven repository\n", getMavenUri(obr), resource.Uri)
+			}
+		}
+	}
+	return bundles, err
+}
+
+// Parses a URI of the form mvn:<GROUP_ID>/<ARTIFACT_ID>/<VERSION>[/<TYPE>]. The type is a jar if it isn't given.
+func parseMavenUri(uri string) (utils.MavenCoordinates, bool) {
+	var coordinates utils.MavenCoordinates
+	isMavenUri := false
+
+	if strings.HasPrefix(uri, "mvn:") {
+		parts := strings.Split(strings.TrimPrefix(uri, "mvn:"), "/")
+		if len(parts) == 3 {
+			parts = append(parts, BUNDLE_EXTENSION)
+		}
+		if len(parts) == 4 && parts[0] != "" && parts[1] != "" && parts[2] != "" && parts[3] != "" {
+			isMavenUri = true
+			coordinates = utils.MavenCoordinates{
+				GroupId:    parts[0],
+				ArtifactId: parts[1],
+				Version:    parts[2],
+				Classifier: parts[3],
+			}
+		}
+	}
+	return coordinates, isMavenUri
+}
+
+// For example: mvn:dev.galasa/dev.galasa.framework/0.38.0/jar
+func getMavenUri(coordinates utils.MavenCoordinates) string {
+	return "mvn:" + coordinates.GroupId + "/" + coordinates.ArtifactId + "/" + coordinates.Version + "/" + coordinates.Classifier
+}
+
+// For example: dev.galasa.framework-0.38.0.jar, or dev.galasa.example.banking.obr-0.0.1-testcatalog.json
+// for a file with a classifier.
+func getMavenArtifactFileName(coordinates utils.MavenCoordinates, classifier string, fileVersion string) string {
+	fileName := coordinates.ArtifactId + "-" + fileVersion
+	if classifier != "" {
+		fileName += "-" + classifier
+	}
+	return fileName + "." + coordinates.Classifier
+}
+
+// A checksum file holds the checksum in hex, which may be followed by the name of the file it is for.
+func parseChecksum(checksumText string) string {
+	checksum := ""
+	fields := strings.Fields(checksumText)
+	if len(fields) > 0 {
+		checksum = strings.ToLower(fields[0])
+	}
+	return checksum
+}
+
+func getSha1Checksum(contents []byte) string {
+	checksum := sha1.Sum(contents)
+	return hex.EncodeToString(checksum[:])
+}
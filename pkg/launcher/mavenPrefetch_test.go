@@ -0,0 +1,308 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	PREFETCH_UBER_OBR_PATH = "/dev/galasa/dev.galasa.uber.obr/0.38.0/dev.galasa.uber.obr-0.38.0.obr"
+	PREFETCH_UBER_OBR      = `<repository name='Galasa'>
+		<resource id='dev.galasa.framework/0.38.0' uri='mvn:dev.galasa/dev.galasa.framework/0.38.0/jar'/>
+		<resource id='dev.galasa.core.manager/0.38.0' uri='mvn:dev.galasa/dev.galasa.core.manager/0.38.0/jar'/>
+		</repository>`
+
+	PREFETCH_BANKING_OBR_PATH = "/dev/galasa/example/banking/dev.galasa.example.banking.obr/0.0.1/dev.galasa.example.banking.obr-0.0.1.obr"
+	PREFETCH_BANKING_OBR      = `<repository name='Banking'>
+		<resource id='dev.galasa.example.banking.account/0.0.1' uri='mvn:dev.galasa.example.banking/dev.galasa.example.banking.account/0.0.1/jar'/>
+		<resource id='dev.galasa.framework/0.38.0' uri='mvn:dev.galasa/dev.galasa.framework/0.38.0/jar'/>
+		</repository>`
+
+	PREFETCH_FRAMEWORK_PATH    = "/dev/galasa/dev.galasa.framework/0.38.0/dev.galasa.framework-0.38.0.jar"
+	PREFETCH_CORE_MANAGER_PATH = "/dev/galasa/dev.galasa.core.manager/0.38.0/dev.galasa.core.manager-0.38.0.jar"
+	PREFETCH_ACCOUNT_PATH      = "/dev/galasa/example/banking/dev.galasa.example.banking.account/0.0.1/dev.galasa.example.banking.account-0.0.1.jar"
+	PREFETCH_CATALOG_PATH      = "/dev/galasa/example/banking/dev.galasa.example.banking.obr/0.0.1/dev.galasa.example.banking.obr-0.0.1-testcatalog.json"
+)
+
+// A remote Maven repository which publishes a checksum next to each of its files, and remembers which files were asked for.
+type mockMavenRepository struct {
+	files          map[string][]byte
+	requestedPaths []string
+}
+
+func newMockMavenRepository() *mockMavenRepository {
+	repository := &mockMavenRepository{files: make(map[string][]byte)}
+	repository.addFile(PREFETCH_UBER_OBR_PATH, []byte(PREFETCH_UBER_OBR))
+	repository.addFile(PREFETCH_BANKING_OBR_PATH, []byte(PREFETCH_BANKING_OBR))
+	repository.addFile(PREFETCH_FRAMEWORK_PATH, []byte("framework bundle"))
+	repository.addFile(PREFETCH_CORE_MANAGER_PATH, []byte("core manager bundle"))
+	repository.addFile(PREFETCH_ACCOUNT_PATH, []byte("account bundle"))
+	repository.addFile(PREFETCH_CATALOG_PATH, []byte(BANKING_TEST_CATALOG))
+	return repository
+}
+
+func (repository *mockMavenRepository) addFile(path string, contents []byte) {
+	repository.files[path] = contents
+	repository.files[path+".sha1"] = []byte(getSha1Checksum(contents) + "  " + path[strings.LastIndex(path, "/")+1:])
+}
+
+func (repository *mockMavenRepository) start() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		repository.requestedPaths = append(repository.requestedPaths, req.URL.Path)
+		contents, isFound := repository.files[req.URL.Path]
+		if isFound {
+			writer.Write(contents)
+		} else {
+			writer.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func assertLocalMavenHas(t *testing.T, fs spi.FileSystem, paths ...string) {
+	for _, path := range paths {
+		isExists, _ := fs.Exists("/localrepo" + path)
+		assert.True(t, isExists, "Expected '"+path+"' in the local Maven repository")
+	}
+}
+
+func TestPrefetchDownloadsTheObrsAndAllTheirBundlesIntoTheLocalMavenRepo(t *testing.T) {
+	// Given...
+	repository := newMockMavenRepository()
+	server := repository.start()
+	defer server.Close()
+	fs := files.NewMockFileSystem()
+	console := utils.NewMockConsole()
+
+	// When...
+	err := PrefetchLocalRunDependencies(fs, []string{BANKING_OBR}, "0.38.0", "file:///localrepo", server.URL, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assertLocalMavenHas(t, fs,
+		PREFETCH_BANKING_OBR_PATH, PREFETCH_BANKING_OBR_PATH+".sha1",
+		PREFETCH_UBER_OBR_PATH, PREFETCH_UBER_OBR_PATH+".sha1",
+		PREFETCH_FRAMEWORK_PATH, PREFETCH_CORE_MANAGER_PATH, PREFETCH_ACCOUNT_PATH, PREFETCH_CATALOG_PATH)
+	bundle, _ := fs.ReadTextFile("/localrepo" + PREFETCH_ACCOUNT_PATH)
+	assert.Equal(t, "account bundle", bundle)
+	assert.Contains(t, console.ReadText(),
+		"GAL2534I: All 6 OBRs and bundles needed to run the tests locally are in the local Maven repository 'file:///localrepo'. 6 were downloaded, and the checksums of 6 were verified.")
+}
+
+func TestPrefetchDownloadsABundleReferredToByTwoObrsOnce(t *testing.T) {
+	// Given...
+	repository := newMockMavenRepository()
+	server := repository.start()
+	defer server.Close()
+
+	// When...
+	err := PrefetchLocalRunDependencies(files.NewMockFileSystem(), []string{BANKING_OBR}, "0.38.0", "file:///localrepo", server.URL, utils.NewMockConsole())
+
+	// Then...
+	assert.Nil(t, err)
+	frameworkRequests := 0
+	for _, path := range repository.requestedPaths {
+		if path == PREFETCH_FRAMEWORK_PATH {
+			frameworkRequests++
+		}
+	}
+	assert.Equal(t, 1, frameworkRequests)
+}
+
+func TestPrefetchLeavesArtifactsAlreadyInTheLocalMavenRepoWhichHaveTheRightChecksum(t *testing.T) {
+	// Given...
+	repository := newMockMavenRepository()
+	server := repository.start()
+	defer server.Close()
+	fs := files.NewMockFileSystem()
+	fs.WriteTextFile("/localrepo"+PREFETCH_FRAMEWORK_PATH, "framework bundle")
+	fs.WriteBinaryFile("/localrepo"+PREFETCH_FRAMEWORK_PATH+".sha1", repository.files[PREFETCH_FRAMEWORK_PATH+".sha1"])
+	console := utils.NewMockConsole()
+
+	// When...
+	err := PrefetchLocalRunDependencies(fs, []string{}, "0.38.0", "file:///localrepo", server.URL, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.NotContains(t, repository.requestedPaths, PREFETCH_FRAMEWORK_PATH)
+	assert.Contains(t, console.ReadText(), "All 3 OBRs and bundles needed to run the tests locally are in the local Maven repository 'file:///localrepo'. 2 were downloaded, and the checksums of 3 were verified.")
+}
+
+func TestPrefetchLeavesArtifactsBuiltLocallyWithoutAChecksum(t *testing.T) {
+	// Given...
+	repository := newMockMavenRepository()
+	server := repository.start()
+	defer server.Close()
+	fs := files.NewMockFileSystem()
+	fs.WriteTextFile("/localrepo"+PREFETCH_BANKING_OBR_PATH, `<repository name='Banking'/>`)
+	console := utils.NewMockConsole()
+
+	// When...
+	err := PrefetchLocalRunDependencies(fs, []string{BANKING_OBR}, "0.38.0", "file:///localrepo", server.URL, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.NotContains(t, repository.requestedPaths, PREFETCH_BANKING_OBR_PATH)
+	assert.NotContains(t, repository.requestedPaths, PREFETCH_ACCOUNT_PATH)
+	assert.Contains(t, console.ReadText(), "All 5 OBRs and bundles needed to run the tests locally are in the local Maven repository 'file:///localrepo'. 4 were downloaded, and the checksums of 4 were verified.")
+}
+
+func TestPrefetchDownloadsAnArtifactAgainIfItsLocalChecksumDoesNotMatch(t *testing.T) {
+	// Given...
+	repository := newMockMavenRepository()
+	server := repository.start()
+	defer server.Close()
+	fs := files.NewMockFileSystem()
+	fs.WriteTextFile("/localrepo"+PREFETCH_FRAMEWORK_PATH, "framework bund")
+	fs.WriteBinaryFile("/localrepo"+PREFETCH_FRAMEWORK_PATH+".sha1", repository.files[PREFETCH_FRAMEWORK_PATH+".sha1"])
+
+	// When...
+	err := PrefetchLocalRunDependencies(fs, []string{}, "0.38.0", "file:///localrepo", server.URL, utils.NewMockConsole())
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Contains(t, repository.requestedPaths, PREFETCH_FRAMEWORK_PATH)
+	bundle, _ := fs.ReadTextFile("/localrepo" + PREFETCH_FRAMEWORK_PATH)
+	assert.Equal(t, "framework bundle", bundle)
+}
+
+func TestPrefetchOfAnArtifactWithTheWrongChecksumFails(t *testing.T) {
+	// Given...
+	repository := newMockMavenRepository()
+	repository.files[PREFETCH_CORE_MANAGER_PATH] = []byte("tampered core manager bundle")
+	server := repository.start()
+	defer server.Close()
+	fs := files.NewMockFileSystem()
+
+	// When...
+	err := PrefetchLocalRunDependencies(fs, []string{}, "0.38.0", "file:///localrepo", server.URL, utils.NewMockConsole())
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1318E: The Maven artifact 'mvn:dev.galasa/dev.galasa.core.manager/0.38.0/jar' downloaded from '"+server.URL+PREFETCH_CORE_MANAGER_PATH+"' is corrupt.")
+	isSaved, _ := fs.Exists("/localrepo" + PREFETCH_CORE_MANAGER_PATH)
+	assert.False(t, isSaved)
+}
+
+func TestPrefetchOfAMissingArtifactFails(t *testing.T) {
+	// Given...
+	repository := newMockMavenRepository()
+	delete(repository.files, PREFETCH_ACCOUNT_PATH)
+	delete(repository.files, PREFETCH_CORE_MANAGER_PATH)
+	server := repository.start()
+	defer server.Close()
+
+	// When...
+	err := PrefetchLocalRunDependencies(files.NewMockFileSystem(), []string{BANKING_OBR}, "0.38.0", "file:///localrepo", server.URL, utils.NewMockConsole())
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1316E: 2 Maven artifacts were not found in the local Maven repository 'file:///localrepo' or the remote Maven repository '"+server.URL+"'")
+	assert.Contains(t, err.Error(), "mvn:dev.galasa.example.banking/dev.galasa.example.banking.account/0.0.1/jar")
+	assert.Contains(t, err.Error(), "mvn:dev.galasa/dev.galasa.core.manager/0.38.0/jar")
+}
+
+func TestPrefetchOfAnObrWithNoTestCatalogIsOk(t *testing.T) {
+	// Given...
+	repository := newMockMavenRepository()
+	delete(repository.files, PREFETCH_CATALOG_PATH)
+	server := repository.start()
+	defer server.Close()
+	console := utils.NewMockConsole()
+
+	// When...
+	err := PrefetchLocalRunDependencies(files.NewMockFileSystem(), []string{BANKING_OBR}, "0.38.0", "file:///localrepo", server.URL, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Contains(t, console.ReadText(), "All 5 OBRs and bundles")
+}
+
+func TestPrefetchOfASnapshotSavesItWithoutItsTimestamp(t *testing.T) {
+	// Given...
+	repository := newMockMavenRepository()
+	repository.addFile("/dev/galasa/example/payments/dev.galasa.example.payments.obr/0.0.2-SNAPSHOT/maven-metadata.xml",
+		[]byte(`<metadata><versioning><snapshotVersions>
+			<snapshotVersion><extension>obr</extension><value>0.0.2-20241001.101010-3</value></snapshotVersion>
+			</snapshotVersions></versioning></metadata>`))
+	repository.addFile("/dev/galasa/example/payments/dev.galasa.example.payments.obr/0.0.2-SNAPSHOT/dev.galasa.example.payments.obr-0.0.2-20241001.101010-3.obr",
+		[]byte(`<repository name='Payments'/>`))
+	server := repository.start()
+	defer server.Close()
+	fs := files.NewMockFileSystem()
+
+	// When...
+	err := PrefetchLocalRunDependencies(fs, []string{PAYMENTS_OBR}, "0.38.0", "file:///localrepo", server.URL, utils.NewMockConsole())
+
+	// Then...
+	assert.Nil(t, err)
+	obr, _ := fs.ReadTextFile("/localrepo/dev/galasa/example/payments/dev.galasa.example.payments.obr/0.0.2-SNAPSHOT/dev.galasa.example.payments.obr-0.0.2-SNAPSHOT.obr")
+	assert.Equal(t, `<repository name='Payments'/>`, obr)
+}
+
+func TestPrefetchWithABadObrFails(t *testing.T) {
+	err := PrefetchLocalRunDependencies(files.NewMockFileSystem(), []string{"mvn:a/b/c"}, "0.38.0", "file:///localrepo", "", utils.NewMockConsole())
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1060E")
+}
+
+func TestCheckLocalRunDependenciesAreLocalPassesAfterAPrefetch(t *testing.T) {
+	// Given...
+	repository := newMockMavenRepository()
+	server := repository.start()
+	fs := files.NewMockFileSystem()
+	err := PrefetchLocalRunDependencies(fs, []string{BANKING_OBR}, "0.38.0", "file:///localrepo", server.URL, utils.NewMockConsole())
+	assert.Nil(t, err)
+	server.Close()
+	obrs, _ := utils.ValidateObrs([]string{BANKING_OBR})
+
+	// When...
+	err = CheckLocalRunDependenciesAreLocal(fs, obrs, "0.38.0", "file:///localrepo")
+
+	// Then...
+	assert.Nil(t, err)
+}
+
+func TestCheckLocalRunDependenciesAreLocalReportsWhatIsMissing(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	fs.WriteTextFile("/localrepo"+PREFETCH_UBER_OBR_PATH, PREFETCH_UBER_OBR)
+	fs.WriteTextFile("/localrepo"+PREFETCH_FRAMEWORK_PATH, "framework bundle")
+	obrs, _ := utils.ValidateObrs([]string{BANKING_OBR})
+
+	// When...
+	err := CheckLocalRunDependenciesAreLocal(fs, obrs, "0.38.0", "file:///localrepo")
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1321E: The tests can't be run with --offline, as 2 of the OBRs and bundles they need are missing "+
+		"from the local Maven repository 'file:///localrepo': "+BANKING_OBR+", mvn:dev.galasa/dev.galasa.core.manager/0.38.0/jar.")
+}
+
+func TestParseMavenUri(t *testing.T) {
+	coordinates, isMavenUri := parseMavenUri("mvn:dev.galasa/dev.galasa.framework/0.38.0/jar")
+	assert.True(t, isMavenUri)
+	assert.Equal(t, utils.MavenCoordinates{GroupId: "dev.galasa", ArtifactId: "dev.galasa.framework", Version: "0.38.0", Classifier: "jar"}, coordinates)
+
+	coordinates, isMavenUri = parseMavenUri("mvn:dev.galasa/dev.galasa.framework/0.38.0")
+	assert.True(t, isMavenUri)
+	assert.Equal(t, "jar", coordinates.Classifier)
+
+	_, isMavenUri = parseMavenUri("file:/bundles/dev.galasa.framework.jar")
+	assert.False(t, isMavenUri)
+
+	_, isMavenUri = parseMavenUri("mvn:dev.galasa/dev.galasa.framework")
+	assert.False(t, isMavenUri)
+}
@@ -0,0 +1,280 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+)
+
+// When an OBR is built by the Galasa Maven plugin, a test catalog describing every test in the OBR
+// is published next to it, as a Maven artifact with this classifier and extension.
+// For example: dev/galasa/example/banking/dev.galasa.example.banking.obr/0.0.1/dev.galasa.example.banking.obr-0.0.1-testcatalog.json
+const (
+	TEST_CATALOG_CLASSIFIER = "testcatalog"
+	TEST_CATALOG_EXTENSION  = "json"
+
+	// Each class in a merged test catalog records which OBR it came from, so it can be launched from that OBR.
+	TEST_CATALOG_CLASS_OBR_KEY = "obr"
+)
+
+// ObrTestCatalogProvider finds the tests which can be run locally, from the test catalogs published next to a set of OBRs.
+// Each OBR acts like the stream of an ecosystem: the tests of one OBR can be selected by using its Maven coordinates
+// as the stream name, or the tests of all the OBRs can be selected by using no stream at all.
+type ObrTestCatalogProvider struct {
+	fileSystem  spi.FileSystem
+	obrs        []string
+	localMaven  string
+	remoteMaven string
+}
+
+func NewObrTestCatalogProvider(fileSystem spi.FileSystem, obrs []string, localMaven string, remoteMaven string) *ObrTestCatalogProvider {
+	provider := new(ObrTestCatalogProvider)
+	provider.fileSystem = fileSystem
+	provider.obrs = obrs
+	provider.localMaven = localMaven
+	provider.remoteMaven = remoteMaven
+	return provider
+}
+
+// GetStreams returns the Maven coordinates of each OBR.
+func (provider *ObrTestCatalogProvider) GetStreams() ([]string, error) {
+	streams := make([]string, 0, len(provider.obrs))
+	streams = append(streams, provider.obrs...)
+	return streams, nil
+}
+
+// GetTestCatalog loads the test catalog of the OBR named by the stream, or of all the OBRs merged together if the stream is blank.
+func (provider *ObrTestCatalogProvider) GetTestCatalog(stream string) (TestCatalog, error) {
+	var err error
+	var testCatalog TestCatalog
+
+	obrs := provider.obrs
+	if stream != "" {
+		obrs = []string{stream}
+	}
+
+	if len(obrs) < 1 {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_OBR_FLAG_REQUIRED)
+	}
+
+	var localMaven string
+	if err == nil {
+		localMaven, err = defaultLocalMavenIfNotSet(provider.localMaven, provider.fileSystem)
+	}
+
+	if err == nil {
+		testCatalog = make(TestCatalog)
+		for _, obr := range obrs {
+			var coordinates utils.MavenCoordinates
+			coordinates, err = utils.ValidateObr(obr)
+
+			var obrTestCatalog TestCatalog
+			if err == nil {
+				obrTestCatalog, err = loadObrTestCatalog(provider.fileSystem, coordinates, obr, localMaven, provider.remoteMaven)
+			}
+
+			if err != nil {
+				break
+			}
+			mergeObrTestCatalog(testCatalog, obrTestCatalog, obr)
+		}
+	}
+	return testCatalog, err
+}
+
+// Looks for the test catalog in the local Maven repository first, then in the remote one.
+func loadObrTestCatalog(
+	fileSystem spi.FileSystem,
+	coordinates utils.MavenCoordinates,
+	obr string,
+	localMaven string,
+	remoteMaven string,
+) (TestCatalog, error) {
+	var err error
+	var testCatalog TestCatalog
+	var catalogContents []byte
+	var isFound bool
+
+	catalogFolderPath := getMavenArtifactFolderPath(coordinates)
+	catalogFileName := getTestCatalogFileName(coordinates, coordinates.Version)
+
+	localCatalogPath := fileUrlToFilePath(localMaven) + "/" + catalogFolderPath + "/" + catalogFileName
+	catalogLocation := localCatalogPath
+	isFound, err = fileSystem.Exists(localCatalogPath)
+	if err == nil && isFound {
+		log.Printf("Reading the test catalog of OBR '%s' from '%s'\n", obr, localCatalogPath)
+		var catalogText string
+		catalogText, err = fileSystem.ReadTextFile(localCatalogPath)
+		if err != nil {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_OBR_TEST_CATALOG_READ_FAILED, localCatalogPath, obr, err.Error())
+		}
+		catalogContents = []byte(catalogText)
+	}
+
+	if err == nil && !isFound && remoteMaven != "" {
+		catalogFolderUrl := strings.TrimSuffix(remoteMaven, "/") + "/" + catalogFolderPath
+		catalogLocation, catalogContents, isFound, err = fetchRemoteTestCatalog(catalogFolderUrl, coordinates, obr)
+	}
+
+	if err == nil && !isFound {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_OBR_TEST_CATALOG_NOT_FOUND, obr, localMaven, remoteMaven, catalogFileName)
+	}
+
+	if err == nil {
+		err = json.Unmarshal(catalogContents, &testCatalog)
+		if err != nil {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_OBR_TEST_CATALOG_READ_FAILED, catalogLocation, obr, err.Error())
+		}
+	}
+	return testCatalog, err
+}
+
+// Snapshot versions are deployed to a remote Maven repository with a timestamp in their file names,
+// which is found using the maven-metadata.xml file in the same folder.
+func fetchRemoteTestCatalog(catalogFolderUrl string, coordinates utils.MavenCoordinates, obr string) (string, []byte, bool, error) {
+	var err error
+	var catalogContents []byte
+	var isFound bool
+
+	fileVersion := coordinates.Version
+	if strings.HasSuffix(coordinates.Version, "-SNAPSHOT") {
+		var metadataContents []byte
+		metadataUrl := catalogFolderUrl + "/maven-metadata.xml"
+		metadataContents, isFound, err = getUrlContents(metadataUrl, obr)
+		if err == nil && isFound {
+			fileVersion = getSnapshotTestCatalogVersion(metadataContents, coordinates.Version)
+		}
+	}
+
+	catalogUrl := catalogFolderUrl + "/" + getTestCatalogFileName(coordinates, fileVersion)
+	if err == nil {
+		log.Printf("Fetching the test catalog of OBR '%s' from '%s'\n", obr, catalogUrl)
+		catalogContents, isFound, err = getUrlContents(catalogUrl, obr)
+	}
+	return catalogUrl, catalogContents, isFound, err
+}
+
+// Returns false if there is nothing at the URL.
+func getUrlContents(url string, obr string) ([]byte, bool, error) {
+	contents, isFound, err := readUrl(url)
+	if err != nil {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_OBR_TEST_CATALOG_READ_FAILED, url, obr, err.Error())
+	}
+	return contents, isFound, err
+}
+
+// Returns false if there is nothing at the URL. The error returned is left for the caller to explain.
+func readUrl(url string) ([]byte, bool, error) {
+	var err error
+	var contents []byte
+	isFound := false
+
+	var resp *http.Response
+	resp, err = http.Get(url)
+	if err == nil {
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusOK {
+			isFound = true
+			contents, err = io.ReadAll(resp.Body)
+		} else if resp.StatusCode != http.StatusNotFound {
+			err = errors.New(resp.Status)
+		}
+	}
+	return contents, isFound, err
+}
+
+type mavenMetadata struct {
+	SnapshotVersions []mavenSnapshotVersion `xml:"versioning>snapshotVersions>snapshotVersion"`
+}
+
+type mavenSnapshotVersion struct {
+	Classifier string `xml:"classifier"`
+	Extension  string `xml:"extension"`
+	Value      string `xml:"value"`
+}
+
+// Finds the timestamped version of the latest snapshot of the test catalog. The plain snapshot version
+// is returned if the metadata doesn't mention the test catalog.
+func getSnapshotTestCatalogVersion(metadataContents []byte, version string) string {
+	return getSnapshotFileVersion(metadataContents, version, TEST_CATALOG_CLASSIFIER, TEST_CATALOG_EXTENSION)
+}
+
+// Finds the timestamped version of the latest snapshot of the file with the classifier and extension given.
+// The plain snapshot version is returned if the metadata doesn't mention that file.
+func getSnapshotFileVersion(metadataContents []byte, version string, classifier string, extension string) string {
+	fileVersion := version
+	var metadata mavenMetadata
+	if xml.Unmarshal(metadataContents, &metadata) == nil {
+		for _, snapshotVersion := range metadata.SnapshotVersions {
+			if snapshotVersion.Classifier == classifier && snapshotVersion.Extension == extension {
+				fileVersion = snapshotVersion.Value
+			}
+		}
+	}
+	return fileVersion
+}
+
+// For example: dev/galasa/example/banking/dev.galasa.example.banking.obr/0.0.1
+func getMavenArtifactFolderPath(coordinates utils.MavenCoordinates) string {
+	return strings.ReplaceAll(coordinates.GroupId, ".", "/") + "/" + coordinates.ArtifactId + "/" + coordinates.Version
+}
+
+// For example: dev.galasa.example.banking.obr-0.0.1-testcatalog.json
+func getTestCatalogFileName(coordinates utils.MavenCoordinates, fileVersion string) string {
+	return fmt.Sprintf("%s-%s-%s.%s", coordinates.ArtifactId, fileVersion, TEST_CATALOG_CLASSIFIER, TEST_CATALOG_EXTENSION)
+}
+
+// Turns a URL like file:///Users/me/.m2/repository or file:///C:/Users/me/.m2/repository into a file path.
+func fileUrlToFilePath(fileUrl string) string {
+	filePath := strings.TrimSuffix(strings.TrimPrefix(fileUrl, "file://"), "/")
+	// Windows paths have a drive letter, which doesn't need a slash in front of it.
+	if len(filePath) > 2 && filePath[0] == '/' && filePath[2] == ':' {
+		filePath = filePath[1:]
+	}
+	// A URL made by adding a path which starts with a slash to file:/// has one slash too many.
+	for strings.HasPrefix(filePath, "//") {
+		filePath = filePath[1:]
+	}
+	return filePath
+}
+
+// Adds the sections of a test catalog, such as its classes and packages, to the merged catalog.
+// Each class is marked with the OBR it came from.
+func mergeObrTestCatalog(mergedCatalog TestCatalog, obrTestCatalog TestCatalog, obr string) {
+	for sectionName, section := range obrTestCatalog {
+		sectionEntries, isMap := section.(map[string]interface{})
+		if !isMap {
+			if _, isAlreadySet := mergedCatalog[sectionName]; !isAlreadySet {
+				mergedCatalog[sectionName] = section
+			}
+		} else {
+			mergedEntries, isMergedMap := mergedCatalog[sectionName].(map[string]interface{})
+			if !isMergedMap {
+				mergedEntries = make(map[string]interface{})
+				mergedCatalog[sectionName] = mergedEntries
+			}
+
+			for entryName, entry := range sectionEntries {
+				if classDef, isClassDef := entry.(map[string]interface{}); isClassDef && sectionName == "classes" {
+					classDef[TEST_CATALOG_CLASS_OBR_KEY] = obr
+				}
+				mergedEntries[entryName] = entry
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	BANKING_OBR  = "mvn:dev.galasa.example.banking/dev.galasa.example.banking.obr/0.0.1/obr"
+	PAYMENTS_OBR = "mvn:dev.galasa.example.payments/dev.galasa.example.payments.obr/0.0.2-SNAPSHOT/obr"
+
+	BANKING_TEST_CATALOG = `{
+		"name": "banking",
+		"classes": {
+			"dev.galasa.example.banking.account/dev.galasa.example.banking.account.TestAccount": {
+				"name": "dev.galasa.example.banking.account.TestAccount",
+				"bundle": "dev.galasa.example.banking.account",
+				"package": "dev.galasa.example.banking.account"
+			}
+		},
+		"packages": {
+			"dev.galasa.example.banking.account": ["dev.galasa.example.banking.account.TestAccount"]
+		}
+	}`
+
+	PAYMENTS_TEST_CATALOG = `{
+		"name": "payments",
+		"classes": {
+			"dev.galasa.example.payments/dev.galasa.example.payments.TestPayment": {
+				"name": "dev.galasa.example.payments.TestPayment",
+				"bundle": "dev.galasa.example.payments",
+				"package": "dev.galasa.example.payments"
+			}
+		}
+	}`
+)
+
+func TestGetStreamsReturnsTheObrs(t *testing.T) {
+	provider := NewObrTestCatalogProvider(files.NewMockFileSystem(), []string{BANKING_OBR, PAYMENTS_OBR}, "", "")
+
+	streams, err := provider.GetStreams()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{BANKING_OBR, PAYMENTS_OBR}, streams)
+}
+
+func TestGetTestCatalogReadsTheCatalogFromTheLocalMavenRepo(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	fs.WriteTextFile("/User/Home/testuser/.m2/repository/dev/galasa/example/banking/dev.galasa.example.banking.obr/0.0.1/dev.galasa.example.banking.obr-0.0.1-testcatalog.json", BANKING_TEST_CATALOG)
+	provider := NewObrTestCatalogProvider(fs, []string{BANKING_OBR}, "", "")
+
+	// When...
+	testCatalog, err := provider.GetTestCatalog("")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "banking", testCatalog["name"])
+	classes := testCatalog["classes"].(map[string]interface{})
+	classDef := classes["dev.galasa.example.banking.account/dev.galasa.example.banking.account.TestAccount"].(map[string]interface{})
+	assert.Equal(t, BANKING_OBR, classDef[TEST_CATALOG_CLASS_OBR_KEY])
+}
+
+func TestGetTestCatalogFetchesASnapshotCatalogFromTheRemoteMavenRepo(t *testing.T) {
+	// Given...
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/dev/galasa/example/payments/dev.galasa.example.payments.obr/0.0.2-SNAPSHOT/maven-metadata.xml":
+			writer.Write([]byte(`<metadata><versioning><snapshotVersions>
+				<snapshotVersion><extension>obr</extension><value>0.0.2-20241001.101010-3</value></snapshotVersion>
+				<snapshotVersion><classifier>testcatalog</classifier><extension>json</extension><value>0.0.2-20241001.101010-3</value></snapshotVersion>
+				</snapshotVersions></versioning></metadata>`))
+		case "/dev/galasa/example/payments/dev.galasa.example.payments.obr/0.0.2-SNAPSHOT/dev.galasa.example.payments.obr-0.0.2-20241001.101010-3-testcatalog.json":
+			writer.Write([]byte(PAYMENTS_TEST_CATALOG))
+		default:
+			writer.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewObrTestCatalogProvider(files.NewMockFileSystem(), []string{PAYMENTS_OBR}, "file:///localrepo", server.URL+"/")
+
+	// When...
+	testCatalog, err := provider.GetTestCatalog(PAYMENTS_OBR)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "payments", testCatalog["name"])
+}
+
+func TestGetTestCatalogMergesTheCatalogsOfAllTheObrs(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	fs.WriteTextFile("/localrepo/dev/galasa/example/banking/dev.galasa.example.banking.obr/0.0.1/dev.galasa.example.banking.obr-0.0.1-testcatalog.json", BANKING_TEST_CATALOG)
+	fs.WriteTextFile("/localrepo/dev/galasa/example/payments/dev.galasa.example.payments.obr/0.0.2-SNAPSHOT/dev.galasa.example.payments.obr-0.0.2-SNAPSHOT-testcatalog.json", PAYMENTS_TEST_CATALOG)
+	provider := NewObrTestCatalogProvider(fs, []string{BANKING_OBR, PAYMENTS_OBR}, "file:///localrepo", "")
+
+	// When...
+	testCatalog, err := provider.GetTestCatalog("")
+
+	// Then...
+	assert.Nil(t, err)
+	classes := testCatalog["classes"].(map[string]interface{})
+	assert.Len(t, classes, 2)
+	paymentClass := classes["dev.galasa.example.payments/dev.galasa.example.payments.TestPayment"].(map[string]interface{})
+	assert.Equal(t, PAYMENTS_OBR, paymentClass[TEST_CATALOG_CLASS_OBR_KEY])
+	assert.Contains(t, testCatalog["packages"], "dev.galasa.example.banking.account")
+	// The first catalog wins for anything which can't be merged.
+	assert.Equal(t, "banking", testCatalog["name"])
+}
+
+func TestGetTestCatalogWhichIsNowhereReturnsError(t *testing.T) {
+	provider := NewObrTestCatalogProvider(files.NewMockFileSystem(), []string{BANKING_OBR}, "file:///localrepo", "")
+
+	_, err := provider.GetTestCatalog("")
+
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1293E")
+	assert.ErrorContains(t, err, "dev.galasa.example.banking.obr-0.0.1-testcatalog.json")
+}
+
+func TestGetTestCatalogWithNoObrsReturnsError(t *testing.T) {
+	provider := NewObrTestCatalogProvider(files.NewMockFileSystem(), []string{}, "", "")
+
+	_, err := provider.GetTestCatalog("")
+
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1292E")
+}
+
+func TestGetTestCatalogWithBadJsonReturnsError(t *testing.T) {
+	fs := files.NewMockFileSystem()
+	fs.WriteTextFile("/localrepo/dev/galasa/example/banking/dev.galasa.example.banking.obr/0.0.1/dev.galasa.example.banking.obr-0.0.1-testcatalog.json", "not json")
+	provider := NewObrTestCatalogProvider(fs, []string{BANKING_OBR}, "file:///localrepo", "")
+
+	_, err := provider.GetTestCatalog("")
+
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1294E")
+}
+
+func TestFileUrlToFilePath(t *testing.T) {
+	assert.Equal(t, "/Users/me/.m2/repository", fileUrlToFilePath("file:///Users/me/.m2/repository/"))
+	assert.Equal(t, "C:/Users/me/.m2/repository", fileUrlToFilePath("file:///C:/Users/me/.m2/repository"))
+	assert.Equal(t, "/User/Home/testuser/.m2/repository", fileUrlToFilePath("file:////User/Home/testuser/.m2/repository"))
+}
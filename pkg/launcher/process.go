@@ -7,7 +7,9 @@ package launcher
 
 import (
 	"io"
+	"os"
 	"os/exec"
+	"sync"
 )
 
 //----------------------------------------------------------------------------------
@@ -21,15 +23,25 @@ type ProcessFactory interface {
 	NewProcess() Process
 }
 
-// A process is something which can be started, and waited upon.
+// A process is something which can be started, waited upon, and ended early.
 type Process interface {
 
-	// Start the process, giving it a command with arguments, and somewhere
-	// into which it can write to stdout and stderr.
-	Start(cmd string, args []string, stdOut io.Writer, stdErr io.Writer) error
+	// Start the process, giving it a command with arguments, environment variables of the
+	// form key=value on top of those it inherits, and somewhere into which it can write to stdout and stderr.
+	Start(cmd string, args []string, env []string, stdOut io.Writer, stdErr io.Writer) error
 
 	// Wait for the process to complete. This is a blocking call.
 	Wait() error
+
+	// Get the ID the operating system gave the process when it started.
+	GetPid() int
+
+	// Send a signal to the process, such as os.Interrupt to ask it to end.
+	// Not every signal can be sent on every operating system.
+	Signal(signal os.Signal) error
+
+	// End the process immediately.
+	Kill() error
 }
 
 //----------------------------------------------------------------------------------
@@ -58,8 +70,12 @@ func (*realProcessFactory) NewProcess() Process {
 }
 
 // Start the process.
-func (proc *realProcess) Start(cmd string, args []string, stdOut io.Writer, stdErr io.Writer) error {
+func (proc *realProcess) Start(cmd string, args []string, env []string, stdOut io.Writer, stdErr io.Writer) error {
 	proc.process = exec.Command(cmd, args...)
+	if len(env) > 0 {
+		// Where a variable is set twice, the last value wins.
+		proc.process.Env = append(os.Environ(), env...)
+	}
 	proc.process.Stdout = stdOut
 	proc.process.Stderr = stdErr
 
@@ -73,6 +89,18 @@ func (proc *realProcess) Wait() error {
 	return err
 }
 
+func (proc *realProcess) GetPid() int {
+	return proc.process.Process.Pid
+}
+
+func (proc *realProcess) Signal(signal os.Signal) error {
+	return proc.process.Process.Signal(signal)
+}
+
+func (proc *realProcess) Kill() error {
+	return proc.process.Process.Kill()
+}
+
 // ----------------------------------------------------------------------------------
 // A mock implementation which creates mock processes for use in unit testing.
 // ----------------------------------------------------------------------------------
@@ -93,6 +121,20 @@ type mockProcess struct {
 	stdErr io.Writer
 	cmd    string
 	args   []string
+	env    []string
+
+	// Set to simulate a JVM which hangs, so waiting for it blocks until it is interrupted or killed.
+	isHanging bool
+
+	// Set to simulate a hanging JVM which takes no notice of being interrupted, so it has to be killed.
+	isIgnoringInterrupts bool
+
+	// What was done to end the mock process early.
+	signals  []os.Signal
+	isKilled bool
+
+	ended     chan bool
+	endedOnce sync.Once
 }
 
 // Create a new mock process.
@@ -101,26 +143,57 @@ func (factory *mockProcessFactory) NewProcess() Process {
 }
 
 func NewMockProcess() *mockProcess {
-	return new(mockProcess)
+	mockProcess := new(mockProcess)
+	mockProcess.ended = make(chan bool)
+	return mockProcess
 }
 
 // Wait for the mock process to end.
 // The real equivalent would wait for a but probably.
-// The mock returns immediately without blocking.
+// The mock returns immediately without blocking, unless it is simulating a JVM which hangs.
 func (mockProcess *mockProcess) Wait() error {
 
-	// Waiting for the mock process causes it to simulate a shut-down.
-	mockProcess.stdOut.Write([]byte("d.g.f.Framework - Framework shutdown\n"))
+	if mockProcess.isHanging {
+		<-mockProcess.ended
+	}
+
+	// Waiting for the mock process causes it to simulate a shut-down, unless it was killed.
+	if !mockProcess.isKilled {
+		mockProcess.stdOut.Write([]byte("d.g.f.Framework - Framework shutdown\n"))
+	}
+	return nil
+}
+
+func (mockProcess *mockProcess) GetPid() int {
+	return 12345
+}
+
+func (mockProcess *mockProcess) Signal(signal os.Signal) error {
+	mockProcess.signals = append(mockProcess.signals, signal)
+	if signal == os.Interrupt && !mockProcess.isIgnoringInterrupts {
+		mockProcess.end()
+	}
+	return nil
+}
+
+func (mockProcess *mockProcess) Kill() error {
+	mockProcess.isKilled = true
+	mockProcess.end()
 	return nil
 }
 
-func (mockProcess *mockProcess) Start(cmd string, args []string, stdOut io.Writer, stdErr io.Writer) error {
+func (mockProcess *mockProcess) end() {
+	mockProcess.endedOnce.Do(func() { close(mockProcess.ended) })
+}
+
+func (mockProcess *mockProcess) Start(cmd string, args []string, env []string, stdOut io.Writer, stdErr io.Writer) error {
 
 	// Store the values received by the mock so they can be examined.
 	mockProcess.stdOut = stdOut
 	mockProcess.stdErr = stdErr
 	mockProcess.cmd = cmd
 	mockProcess.args = args
+	mockProcess.env = env
 
 	// Simulate some tracing which gets parsed.
 	mockProcess.stdOut.Write([]byte("Mock Process starting up.\n"))
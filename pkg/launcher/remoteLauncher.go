@@ -126,6 +126,11 @@ func (launcher *RemoteLauncher) GetRunsById(runId string) (*galasaapi.Run, error
 	return rasRun, err
 }
 
+// GetJvmConfiguration returns nil, as the ecosystem decides how the JVM of each test is configured.
+func (launcher *RemoteLauncher) GetJvmConfiguration() *JvmConfiguration {
+	return nil
+}
+
 // Gets the latest run based on the submission ID of that run.
 // For local runs, the submission ID is the same as the test run id.
 func (launcher *RemoteLauncher) GetRunsBySubmissionId(submissionId string, groupId string) (*galasaapi.Run, error) {
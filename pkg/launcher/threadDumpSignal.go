@@ -0,0 +1,17 @@
+//go:build !windows
+// +build !windows
+
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"os"
+	"syscall"
+)
+
+// A JVM writes a thread dump to its stdout when it is sent SIGQUIT.
+var threadDumpSignal os.Signal = syscall.SIGQUIT
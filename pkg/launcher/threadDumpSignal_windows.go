@@ -0,0 +1,13 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package launcher
+
+import (
+	"os"
+)
+
+// There is no signal which makes a JVM on Windows write a thread dump, so only jcmd can be used.
+var threadDumpSignal os.Signal = nil
@@ -0,0 +1,117 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ArtifactFilter decides which of a run's artifacts are wanted, based on the
+// glob patterns passed to the --include and --exclude flags.
+//
+// Patterns are matched against the artifact path, without any leading '/'.
+// '*' matches any characters except '/', '?' matches a single character except '/',
+// and '**' matches any characters including '/', so can span folders.
+// A pattern with no '/' in it is matched against the file name only, so '*.properties'
+// matches 'framework/cps_record.properties'.
+type ArtifactFilter struct {
+	includes []*regexp.Regexp
+	excludes []*regexp.Regexp
+}
+
+// NewArtifactFilter creates a filter. An artifact is wanted if it matches any of the
+// include patterns (or there are none), and doesn't match any of the exclude patterns.
+func NewArtifactFilter(includePatterns []string, excludePatterns []string) *ArtifactFilter {
+	filter := &ArtifactFilter{
+		includes: globsToRegexps(includePatterns),
+		excludes: globsToRegexps(excludePatterns),
+	}
+	return filter
+}
+
+// IsFiltering is true if any patterns were given, so some artifacts may not be wanted.
+func (filter *ArtifactFilter) IsFiltering() bool {
+	return filter != nil && (len(filter.includes) > 0 || len(filter.excludes) > 0)
+}
+
+// IsWanted returns true if the artifact with the given path passes the filter.
+// A nil filter wants everything.
+func (filter *ArtifactFilter) IsWanted(artifactPath string) bool {
+	isWanted := true
+	if filter != nil {
+		artifactPath = strings.TrimPrefix(artifactPath, "/")
+
+		if len(filter.includes) > 0 {
+			isWanted = isMatchingAnyGlob(artifactPath, filter.includes)
+		}
+
+		if isWanted && isMatchingAnyGlob(artifactPath, filter.excludes) {
+			isWanted = false
+		}
+	}
+	return isWanted
+}
+
+func isMatchingAnyGlob(artifactPath string, globRegexps []*regexp.Regexp) bool {
+	isMatching := false
+	for _, globRegexp := range globRegexps {
+		if globRegexp.MatchString(artifactPath) {
+			isMatching = true
+			break
+		}
+	}
+	return isMatching
+}
+
+func globsToRegexps(globs []string) []*regexp.Regexp {
+	globRegexps := make([]*regexp.Regexp, 0, len(globs))
+	for _, glob := range globs {
+		glob = strings.TrimPrefix(strings.TrimSpace(glob), "/")
+		if glob != "" {
+			globRegexps = append(globRegexps, globToRegexp(glob))
+		}
+	}
+	return globRegexps
+}
+
+// Every character other than the wildcards is quoted, so the resulting
+// regular expression always compiles.
+func globToRegexp(glob string) *regexp.Regexp {
+	var buff strings.Builder
+
+	buff.WriteString("^")
+	if !strings.Contains(glob, "/") {
+		// Match the file name in any folder.
+		buff.WriteString("(.*/)?")
+	}
+
+	remaining := glob
+	for remaining != "" {
+		switch {
+		case strings.HasPrefix(remaining, "**/"):
+			buff.WriteString("(.*/)?")
+			remaining = remaining[3:]
+		case strings.HasPrefix(remaining, "**"):
+			buff.WriteString(".*")
+			remaining = remaining[2:]
+		case strings.HasPrefix(remaining, "*"):
+			buff.WriteString("[^/]*")
+			remaining = remaining[1:]
+		case strings.HasPrefix(remaining, "?"):
+			buff.WriteString("[^/]")
+			remaining = remaining[1:]
+		default:
+			char, size := utf8.DecodeRuneInString(remaining)
+			buff.WriteString(regexp.QuoteMeta(string(char)))
+			remaining = remaining[size:]
+		}
+	}
+	buff.WriteString("$")
+
+	return regexp.MustCompile(buff.String())
+}
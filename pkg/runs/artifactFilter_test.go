@@ -0,0 +1,97 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNilArtifactFilterWantsEverything(t *testing.T) {
+	var filter *ArtifactFilter
+
+	assert.False(t, filter.IsFiltering())
+	assert.True(t, filter.IsWanted("/run.log"))
+}
+
+func TestArtifactFilterWithNoPatternsWantsEverything(t *testing.T) {
+	filter := NewArtifactFilter([]string{}, []string{" "})
+
+	assert.False(t, filter.IsFiltering())
+	assert.True(t, filter.IsWanted("/run.log"))
+	assert.True(t, filter.IsWanted("/framework/cps_record.properties"))
+}
+
+func TestArtifactFilterIncludeExactPathWithOrWithoutLeadingSlash(t *testing.T) {
+	filter := NewArtifactFilter([]string{"/framework/cps_record.properties"}, nil)
+
+	assert.True(t, filter.IsFiltering())
+	assert.True(t, filter.IsWanted("/framework/cps_record.properties"))
+	assert.True(t, filter.IsWanted("framework/cps_record.properties"))
+	assert.False(t, filter.IsWanted("/framework/other.properties"))
+	assert.False(t, filter.IsWanted("/run.log"))
+}
+
+func TestArtifactFilterSingleStarDoesNotCrossFolders(t *testing.T) {
+	filter := NewArtifactFilter([]string{"zos/*"}, nil)
+
+	assert.True(t, filter.IsWanted("/zos/job.txt"))
+	assert.False(t, filter.IsWanted("/zos/jobs/JOB123/SYSOUT.txt"))
+}
+
+func TestArtifactFilterDoubleStarCrossesFolders(t *testing.T) {
+	filter := NewArtifactFilter([]string{"zos/**"}, nil)
+
+	assert.True(t, filter.IsWanted("/zos/job.txt"))
+	assert.True(t, filter.IsWanted("/zos/jobs/JOB123/SYSOUT.txt"))
+	assert.False(t, filter.IsWanted("/zosmf/request.json"))
+}
+
+func TestArtifactFilterDoubleStarSlashMatchesNoFolders(t *testing.T) {
+	filter := NewArtifactFilter([]string{"**/*.gz"}, nil)
+
+	assert.True(t, filter.IsWanted("/term1.gz"))
+	assert.True(t, filter.IsWanted("/zos3270/terminals/term1/term1-00001.gz"))
+	assert.False(t, filter.IsWanted("/zos3270/terminals/term1/term1-00001.png"))
+}
+
+func TestArtifactFilterPatternWithoutSlashMatchesFileNameInAnyFolder(t *testing.T) {
+	filter := NewArtifactFilter([]string{"*.properties"}, nil)
+
+	assert.True(t, filter.IsWanted("/framework/cps_record.properties"))
+	assert.True(t, filter.IsWanted("/overrides.properties"))
+	assert.False(t, filter.IsWanted("/framework/properties.txt"))
+}
+
+func TestArtifactFilterQuestionMarkMatchesOneCharacter(t *testing.T) {
+	filter := NewArtifactFilter([]string{"term?.gz"}, nil)
+
+	assert.True(t, filter.IsWanted("/terminals/term1.gz"))
+	assert.False(t, filter.IsWanted("/terminals/term12.gz"))
+}
+
+func TestArtifactFilterRegexCharactersAreMatchedLiterally(t *testing.T) {
+	filter := NewArtifactFilter([]string{"job(1)+.txt"}, nil)
+
+	assert.True(t, filter.IsWanted("/zos/job(1)+.txt"))
+	assert.False(t, filter.IsWanted("/zos/job11.txt"))
+}
+
+func TestArtifactFilterExcludeBeatsInclude(t *testing.T) {
+	filter := NewArtifactFilter([]string{"zos/**"}, []string{"*.gz"})
+
+	assert.True(t, filter.IsWanted("/zos/jobs/SYSOUT.txt"))
+	assert.False(t, filter.IsWanted("/zos/jobs/JCL.gz"))
+}
+
+func TestArtifactFilterExcludeOnlyWantsEverythingElse(t *testing.T) {
+	filter := NewArtifactFilter(nil, []string{"zos3270/**"})
+
+	assert.True(t, filter.IsFiltering())
+	assert.True(t, filter.IsWanted("/run.log"))
+	assert.False(t, filter.IsWanted("/zos3270/terminals/term1/term1-00001.gz"))
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"log"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/spi"
+)
+
+const (
+	// Written into each run's download folder, next to the artifacts.
+	DOWNLOAD_MANIFEST_FILE_NAME = "galasactl-download-manifest.json"
+)
+
+// DownloadManifest records what was downloaded for a test run, so the download can be
+// checked later, and so a later incremental download knows what it can skip.
+type DownloadManifest struct {
+	RunName   string                  `json:"runName"`
+	RunId     string                  `json:"runId"`
+	Artifacts []DownloadManifestEntry `json:"artifacts"`
+}
+
+type DownloadManifestEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Sha256 string `json:"sha256"`
+}
+
+func NewDownloadManifest(run galasaapi.Run) *DownloadManifest {
+	manifest := &DownloadManifest{
+		RunName:   run.TestStructure.GetRunName(),
+		RunId:     run.GetRunId(),
+		Artifacts: make([]DownloadManifestEntry, 0),
+	}
+	return manifest
+}
+
+// GetEntry finds what was recorded about an artifact path. A nil manifest has no entries.
+func (manifest *DownloadManifest) GetEntry(artifactPath string) (DownloadManifestEntry, bool) {
+	var entry DownloadManifestEntry
+	isFound := false
+	if manifest != nil {
+		for _, possibleEntry := range manifest.Artifacts {
+			if possibleEntry.Path == artifactPath {
+				entry = possibleEntry
+				isFound = true
+				break
+			}
+		}
+	}
+	return entry, isFound
+}
+
+// Reads the manifest left by a previous download. A missing or unreadable manifest
+// just means nothing can be skipped, so gives nil rather than an error.
+func readDownloadManifest(fileSystem spi.FileSystem, manifestPath string) *DownloadManifest {
+	var manifest *DownloadManifest
+
+	isExisting, err := fileSystem.Exists(manifestPath)
+	if err == nil && isExisting {
+		var manifestBytes []byte
+		manifestBytes, err = fileSystem.ReadBinaryFile(manifestPath)
+		if err == nil {
+			manifest = new(DownloadManifest)
+			err = json.Unmarshal(manifestBytes, manifest)
+		}
+	}
+
+	if err != nil {
+		log.Printf("Could not read the download manifest '%s', so it is ignored. Reason: %v\n", manifestPath, err)
+		manifest = nil
+	}
+	return manifest
+}
+
+func writeDownloadManifest(fileSystem spi.FileSystem, manifestPath string, manifest *DownloadManifest) error {
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err == nil {
+		err = fileSystem.WriteBinaryFile(manifestPath, manifestBytes)
+	}
+
+	if err != nil {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_FAILED_TO_WRITE_DOWNLOAD_MANIFEST, manifestPath, err.Error())
+	}
+	return err
+}
+
+func getSha256(content []byte) string {
+	checksum := sha256.Sum256(content)
+	return hex.EncodeToString(checksum[:])
+}
+
+// A reader which works out the size and checksum of everything read through it,
+// so artifacts don't need reading back again once they have been written.
+type checksumReader struct {
+	reader io.Reader
+	hasher hash.Hash
+	size   int64
+}
+
+func newChecksumReader(reader io.Reader) *checksumReader {
+	return &checksumReader{reader: reader, hasher: sha256.New()}
+}
+
+func (checksummer *checksumReader) Read(buffer []byte) (int, error) {
+	bytesRead, err := checksummer.reader.Read(buffer)
+	if bytesRead > 0 {
+		checksummer.hasher.Write(buffer[:bytesRead])
+		checksummer.size += int64(bytesRead)
+	}
+	return bytesRead, err
+}
+
+func (checksummer *checksumReader) getManifestEntry(artifactPath string) DownloadManifestEntry {
+	return DownloadManifestEntry{
+		Path:   artifactPath,
+		Size:   checksummer.size,
+		Sha256: hex.EncodeToString(checksummer.hasher.Sum(nil)),
+	}
+}
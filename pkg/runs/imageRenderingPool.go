@@ -0,0 +1,225 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/galasa-dev/cli/pkg/embedded"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/images"
+	"github.com/galasa-dev/cli/pkg/spi"
+)
+
+// The 3270 terminal screens of a test run are rendered by a pool of workers while the rest of
+// its artifacts are still downloading, rather than one file at a time once the download is over.
+
+const (
+	// The number of terminal files rendered at the same time, unless the user says otherwise.
+	DEFAULT_RENDER_CONCURRENCY = 4
+
+	// Progress is reported each time this many more terminal files have been rendered.
+	RENDER_PROGRESS_INTERVAL = 100
+)
+
+// ImageRenderingOptions say how the 3270 terminal screens of the downloaded runs are rendered.
+type ImageRenderingOptions struct {
+	// The number of terminal files rendered at the same time.
+	Concurrency int
+	// No images or animations are drawn if set. The text of each screen is still written out if it is wanted.
+	IsSkippingImages bool
+	// nil if only the default images are wanted.
+	TerminalOutput *images.TerminalOutputOptions
+}
+
+// NewImageRenderingOptions checks how the user asked for the terminal screens to be rendered.
+func NewImageRenderingOptions(concurrency int, isSkippingImages bool, terminalOutputOptions *images.TerminalOutputOptions) (*ImageRenderingOptions, error) {
+	var err error
+	var options *ImageRenderingOptions
+
+	if concurrency < 1 {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_RENDER_CONCURRENCY, concurrency)
+	} else {
+		options = &ImageRenderingOptions{Concurrency: concurrency, IsSkippingImages: isSkippingImages, TerminalOutput: terminalOutputOptions}
+	}
+	return options, err
+}
+
+// A terminal file to render, or a run folder whose terminals are to be animated once all its artifacts are downloaded.
+type imageRenderingJob struct {
+	path                  string
+	isAnimatingFolder     bool
+	isReplacingAnimations bool
+}
+
+type imageRenderingPool struct {
+	fileSystem                  spi.FileSystem
+	console                     spi.Console
+	forceOverwriteExistingFiles bool
+
+	concurrency      int
+	isSkippingImages bool
+	renderOptions    *images.RenderOptions
+	animationOptions *images.AnimationOptions
+	textOptions      *images.TerminalTextOptions
+
+	jobQueue chan imageRenderingJob
+	workers  sync.WaitGroup
+
+	// Guards everything below, which the workers and the downloads all update.
+	mutex              sync.Mutex
+	submittedFileCount int
+	renderedFileCount  int
+	imageFileCount     int
+	textFileCount      int
+	animationFileCount int
+	firstErr           error
+}
+
+// newImageRenderingPool starts the workers, which wait for terminal files to be submitted as they are downloaded.
+// The console must be safe to write to from several goroutines at once.
+// If the options are nil, the default images are rendered DEFAULT_RENDER_CONCURRENCY at a time.
+func newImageRenderingPool(
+	fileSystem spi.FileSystem,
+	console spi.Console,
+	forceOverwriteExistingFiles bool,
+	options *ImageRenderingOptions,
+) *imageRenderingPool {
+	pool := new(imageRenderingPool)
+	pool.fileSystem = fileSystem
+	pool.console = console
+	pool.forceOverwriteExistingFiles = forceOverwriteExistingFiles
+	pool.concurrency = DEFAULT_RENDER_CONCURRENCY
+
+	if options != nil {
+		pool.concurrency = options.Concurrency
+		pool.isSkippingImages = options.IsSkippingImages
+		if options.TerminalOutput != nil {
+			pool.renderOptions = options.TerminalOutput.Render
+			pool.animationOptions = options.TerminalOutput.Animation
+			pool.textOptions = options.TerminalOutput.Text
+		}
+	}
+
+	if pool.isSkippingImages {
+		// Animations are made of images, so are skipped too.
+		pool.animationOptions = nil
+	}
+
+	if pool.isWorkWanted() {
+		// The downloads only have to wait if the workers fall well behind.
+		pool.jobQueue = make(chan imageRenderingJob, pool.concurrency*RENDER_PROGRESS_INTERVAL)
+		for i := 0; i < pool.concurrency; i++ {
+			pool.workers.Add(1)
+			go pool.renderSubmittedFiles()
+		}
+	}
+	return pool
+}
+
+// Nothing needs doing with the terminal files if images are skipped and their text isn't wanted.
+func (pool *imageRenderingPool) isWorkWanted() bool {
+	return !pool.isSkippingImages || pool.textOptions != nil
+}
+
+// submitFile queues a downloaded artifact to be rendered, if it is a 3270 terminal file.
+func (pool *imageRenderingPool) submitFile(filePath string) {
+	if pool.isWorkWanted() && strings.HasSuffix(filePath, ".gz") {
+		pool.mutex.Lock()
+		pool.submittedFileCount++
+		pool.mutex.Unlock()
+
+		pool.jobQueue <- imageRenderingJob{path: filePath}
+	}
+}
+
+// submitAnimations queues the terminals of a run folder to be animated, if animations are wanted.
+// The artifacts of the run must all have been downloaded.
+func (pool *imageRenderingPool) submitAnimations(folderPath string, isReplacingAnimations bool) {
+	if pool.animationOptions != nil {
+		pool.jobQueue <- imageRenderingJob{path: folderPath, isAnimatingFolder: true, isReplacingAnimations: isReplacingAnimations}
+	}
+}
+
+// finish waits for everything submitted to be rendered, then reports what was done.
+// Returns the first rendering failure, if there was one.
+func (pool *imageRenderingPool) finish() error {
+	var err error
+
+	if pool.isWorkWanted() {
+		close(pool.jobQueue)
+		pool.workers.Wait()
+
+		log.Printf("Expanded a total of %d image files.\n", pool.imageFileCount)
+		log.Printf("Wrote the text of a total of %d terminal screens.\n", pool.textFileCount)
+		if pool.animationOptions != nil {
+			log.Printf("Created a total of %d terminal animations.\n", pool.animationFileCount)
+		}
+
+		err = pool.firstErr
+		if err == nil && pool.renderedFileCount > 0 && !pool.isSkippingImages {
+			err = pool.console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_RENDER_DONE.Template, pool.imageFileCount, pool.renderedFileCount, pool.concurrency))
+		}
+	}
+	return err
+}
+
+// Each worker has a renderer of its own, so the fonts are loaded once per worker rather than
+// once per run, and no worker has to wait for another to finish drawing.
+func (pool *imageRenderingPool) renderSubmittedFiles() {
+	defer pool.workers.Done()
+
+	var renderer images.ImageRenderer
+	if !pool.isSkippingImages {
+		renderer = images.NewImageRendererWithOptions(embedded.GetReadOnlyFileSystem(), pool.renderOptions)
+	}
+
+	textOnlyOptions := &images.TerminalOutputOptions{Text: pool.textOptions}
+	expander := images.NewImageExpanderWithOptions(pool.fileSystem, renderer, pool.forceOverwriteExistingFiles, textOnlyOptions)
+	animationFileCount := 0
+
+	for job := range pool.jobQueue {
+		var err error
+		if job.isAnimatingFolder {
+			// An animation is made if it is missing, but is only replaced if the screens of a terminal were downloaded again.
+			animationOnlyOptions := &images.TerminalOutputOptions{Animation: pool.animationOptions}
+			animator := images.NewImageExpanderWithOptions(pool.fileSystem, renderer, job.isReplacingAnimations, animationOnlyOptions)
+			err = animator.AnimateTerminals(job.path)
+			animationFileCount += animator.GetAnimationFileCount()
+		} else {
+			err = expander.ExpandImage(job.path)
+		}
+		pool.recordJobDone(job, err)
+	}
+
+	pool.mutex.Lock()
+	pool.imageFileCount += expander.GetExpandedImageFileCount()
+	pool.textFileCount += expander.GetTextFileCount()
+	pool.animationFileCount += animationFileCount
+	pool.mutex.Unlock()
+}
+
+func (pool *imageRenderingPool) recordJobDone(job imageRenderingJob, err error) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	if err != nil {
+		log.Printf("Failed to render '%s'. Reason: %v\n", job.path, err)
+		if pool.firstErr == nil {
+			pool.firstErr = err
+		}
+	}
+
+	if !job.isAnimatingFolder {
+		pool.renderedFileCount++
+		if pool.renderedFileCount%RENDER_PROGRESS_INTERVAL == 0 {
+			pool.console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_RENDER_PROGRESS.Template, pool.renderedFileCount, pool.submittedFileCount))
+		}
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/images"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createDownloadedTerminalFile(t *testing.T, fs spi.FileSystem, filePath string) {
+	gzContents, err := files.NewOSFileSystem().ReadBinaryFile(EXAMPLE_TERMINAL_GZ_FILE_PATH)
+	assert.Nil(t, err)
+	fs.WriteBinaryFile(filePath, gzContents)
+}
+
+func TestNewImageRenderingOptionsWithNoConcurrencyReturnsError(t *testing.T) {
+	options, err := NewImageRenderingOptions(0, false, nil)
+
+	assert.Nil(t, options)
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1291E")
+}
+
+func TestNewImageRenderingOptionsKeepsTheTerminalOutputOptions(t *testing.T) {
+	terminalOutputOptions := &images.TerminalOutputOptions{Text: &images.TerminalTextOptions{}}
+
+	options, err := NewImageRenderingOptions(2, true, terminalOutputOptions)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &ImageRenderingOptions{Concurrency: 2, IsSkippingImages: true, TerminalOutput: terminalOutputOptions}, options)
+}
+
+func TestImageRenderingPoolRendersEachSubmittedTerminalFile(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	console := utils.NewMockConsole()
+	createDownloadedTerminalFile(t, fs, "U1/zos3270/terminals/term1/term1-00001.gz")
+	createDownloadedTerminalFile(t, fs, "U2/zos3270/terminals/term1/term1-00001.gz")
+	options, _ := NewImageRenderingOptions(2, false, nil)
+
+	// When...
+	pool := newImageRenderingPool(fs, console, false, options)
+	pool.submitFile("U1/zos3270/terminals/term1/term1-00001.gz")
+	pool.submitFile("U1/run.log")
+	pool.submitFile("U2/zos3270/terminals/term1/term1-00001.gz")
+	err := pool.finish()
+
+	// Then...
+	assert.Nil(t, err)
+	isImageWritten, _ := fs.Exists("U1/zos3270/images/term1/term1-00001.png")
+	assert.True(t, isImageWritten)
+	isImageWritten, _ = fs.Exists("U2/zos3270/images/term1/term1-00010.png")
+	assert.True(t, isImageWritten)
+	assert.Equal(t, "GAL2525I: Rendered 20 images of 3270 terminal screens from 2 terminal files, 2 at a time.\n", console.ReadText())
+}
+
+func TestImageRenderingPoolWithNoImagesOnlyWritesTheText(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	console := utils.NewMockConsole()
+	createDownloadedTerminalFile(t, fs, "U1/zos3270/terminals/term1/term1-00001.gz")
+	terminalOutputOptions, _ := images.NewTerminalOutputOptions("", images.DEFAULT_ANIMATION_FRAME_DELAY_MILLIS, images.TERMINAL_TEXT_FORMAT_PLAIN, nil)
+	options, _ := NewImageRenderingOptions(1, true, terminalOutputOptions)
+
+	// When...
+	pool := newImageRenderingPool(fs, console, false, options)
+	pool.submitFile("U1/zos3270/terminals/term1/term1-00001.gz")
+	err := pool.finish()
+
+	// Then...
+	assert.Nil(t, err)
+	isTextWritten, _ := fs.Exists("U1/zos3270/images/term1/term1-00001.txt")
+	assert.True(t, isTextWritten)
+	isImageWritten, _ := fs.Exists("U1/zos3270/images/term1/term1-00001.png")
+	assert.False(t, isImageWritten)
+	assert.Empty(t, console.ReadText())
+}
+
+func TestImageRenderingPoolWithNoImagesAndNoTextDoesNothing(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	console := utils.NewMockConsole()
+	createDownloadedTerminalFile(t, fs, "U1/zos3270/terminals/term1/term1-00001.gz")
+	options, _ := NewImageRenderingOptions(1, true, nil)
+
+	// When...
+	pool := newImageRenderingPool(fs, console, false, options)
+	pool.submitFile("U1/zos3270/terminals/term1/term1-00001.gz")
+	pool.submitAnimations("U1", false)
+	err := pool.finish()
+
+	// Then...
+	assert.Nil(t, err)
+	isImageFolderCreated, _ := fs.DirExists("U1/zos3270/images/term1")
+	assert.False(t, isImageFolderCreated)
+	assert.Empty(t, console.ReadText())
+}
+
+func TestImageRenderingPoolAnimatesTheTerminalsOfASubmittedFolder(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	console := utils.NewMockConsole()
+	createDownloadedTerminalFile(t, fs, "U1/zos3270/terminals/term1/term1-00001.gz")
+	terminalOutputOptions, _ := images.NewTerminalOutputOptions(images.ANIMATION_FORMAT_GIF, images.DEFAULT_ANIMATION_FRAME_DELAY_MILLIS, "", nil)
+	options, _ := NewImageRenderingOptions(2, false, terminalOutputOptions)
+
+	// When...
+	pool := newImageRenderingPool(fs, console, false, options)
+	pool.submitFile("U1/zos3270/terminals/term1/term1-00001.gz")
+	pool.submitAnimations("U1", false)
+	err := pool.finish()
+
+	// Then...
+	assert.Nil(t, err)
+	isAnimationWritten, _ := fs.Exists("U1/zos3270/images/term1/term1.gif")
+	assert.True(t, isAnimationWritten)
+}
+
+func TestImageRenderingPoolReportsProgress(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	console := utils.NewMockConsole()
+	options, _ := NewImageRenderingOptions(3, false, nil)
+
+	// When...
+	pool := newImageRenderingPool(fs, console, false, options)
+	for i := 0; i < RENDER_PROGRESS_INTERVAL; i++ {
+		// Terminal files outside a 'terminals' folder are passed over without being read.
+		pool.submitFile(fmt.Sprintf("U1/other/file%d.gz", i))
+	}
+	err := pool.finish()
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Contains(t, console.ReadText(), "GAL2524I: Progress: rendered 100 of the 100 3270 terminal files downloaded so far.\n")
+}
@@ -0,0 +1,449 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/launcher"
+	"github.com/galasa-dev/cli/pkg/props"
+	"github.com/galasa-dev/cli/pkg/runsformatter"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+)
+
+const (
+	HEADER_PRUNE_SIZE = "size"
+
+	// The properties in galasactl.properties which switch on pruning of the local RAS folder
+	// after each 'runs submit local'. Pruning is switched on when any of them is set.
+	PROPERTY_NAME_PRUNE_OLDER_THAN = "galasactl.local.ras.prune.older.than"
+	PROPERTY_NAME_PRUNE_KEEP_LAST  = "galasactl.local.ras.prune.keep.last"
+	PROPERTY_NAME_PRUNE_RESULT     = "galasactl.local.ras.prune.result"
+	PROPERTY_NAME_PRUNE_MAX_SIZE   = "galasactl.local.ras.prune.max.size"
+)
+
+var (
+	maxSizeRegex *regexp.Regexp = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([KMGT]?B)$`)
+
+	sizeUnits = []string{"B", "KB", "MB", "GB", "TB"}
+)
+
+// LocalRasPrunePolicy says which local test runs are pruned from a RAS folder.
+// A run is pruned only if it matches every policy which is set, so nothing is pruned when none is set.
+// Runs which haven't finished are never pruned.
+type LocalRasPrunePolicy struct {
+	// Runs queued longer ago than this are pruned. 0 if not set.
+	olderThanMins int
+
+	// The most recent runs which are never pruned. 0 if not set.
+	keepLast int
+
+	// The lower-case names of the results of the runs which are pruned. nil if not set.
+	wantedResults map[string]bool
+
+	// Only the oldest runs are pruned, until the RAS folder is no bigger than this. 0 if not set.
+	maxSizeBytes int64
+}
+
+// The names the user gave each policy by, so that a bad value can be pointed out.
+type prunePolicyNames struct {
+	olderThan string
+	keepLast  string
+	maxSize   string
+}
+
+// A local test run, and how much space its folder in the RAS uses.
+type localRunToPrune struct {
+	run       galasaapi.Run
+	runTime   time.Time
+	sizeBytes int64
+}
+
+// NewLocalRasPrunePolicy creates a prune policy from the flags of the `galasactl local ras prune` command.
+func NewLocalRasPrunePolicy(olderThan string, keepLast int, result string, maxSize string) (*LocalRasPrunePolicy, error) {
+	names := prunePolicyNames{olderThan: "--older-than", keepLast: "--keep-last", maxSize: "--max-size"}
+	return newLocalRasPrunePolicy(olderThan, strconv.Itoa(keepLast), result, maxSize, names)
+}
+
+// GetLocalRasAutoPrunePolicy reads the policy used to prune the local RAS folder after each `galasactl runs submit local`
+// from the galasactl.properties file in the Galasa home folder.
+// Returns nil if the file doesn't switch on pruning.
+func GetLocalRasAutoPrunePolicy(fileSystem spi.FileSystem, galasaHome spi.GalasaHome) (*LocalRasPrunePolicy, error) {
+	var err error
+	var policy *LocalRasPrunePolicy
+	var isExists bool
+
+	galasactlPropertiesFilePath := filepath.Join(galasaHome.GetNativeFolderPath(), "galasactl.properties")
+
+	isExists, err = fileSystem.Exists(galasactlPropertiesFilePath)
+	if err == nil && isExists {
+		var galasactlProperties props.JavaProperties
+		galasactlProperties, err = props.ReadPropertiesFile(fileSystem, galasactlPropertiesFilePath)
+		if err != nil {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_FAILED_TO_READ_FILE, galasactlPropertiesFilePath, err.Error())
+		} else {
+			olderThan := strings.TrimSpace(galasactlProperties[PROPERTY_NAME_PRUNE_OLDER_THAN])
+			keepLast := strings.TrimSpace(galasactlProperties[PROPERTY_NAME_PRUNE_KEEP_LAST])
+			result := strings.TrimSpace(galasactlProperties[PROPERTY_NAME_PRUNE_RESULT])
+			maxSize := strings.TrimSpace(galasactlProperties[PROPERTY_NAME_PRUNE_MAX_SIZE])
+
+			if olderThan != "" || keepLast != "" || result != "" || maxSize != "" {
+				names := prunePolicyNames{olderThan: PROPERTY_NAME_PRUNE_OLDER_THAN, keepLast: PROPERTY_NAME_PRUNE_KEEP_LAST, maxSize: PROPERTY_NAME_PRUNE_MAX_SIZE}
+				if keepLast == "" {
+					keepLast = "0"
+				}
+				policy, err = newLocalRasPrunePolicy(olderThan, keepLast, result, maxSize, names)
+			}
+		}
+	}
+
+	return policy, err
+}
+
+func newLocalRasPrunePolicy(olderThan string, keepLast string, result string, maxSize string, names prunePolicyNames) (*LocalRasPrunePolicy, error) {
+	var err error
+	policy := &LocalRasPrunePolicy{}
+
+	if olderThan != "" {
+		policy.olderThanMins, err = getMinutesFromOlderThan(olderThan, names.olderThan)
+	}
+
+	if err == nil {
+		policy.keepLast, err = strconv.Atoi(keepLast)
+		if err != nil || policy.keepLast < 0 {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_PRUNE_KEEP_LAST, keepLast, names.keepLast)
+		}
+	}
+
+	if err == nil {
+		policy.wantedResults = getWantedLocalResults(result)
+	}
+
+	if err == nil && maxSize != "" {
+		policy.maxSizeBytes, err = getBytesFromMaxSize(maxSize, names.maxSize)
+	}
+
+	return policy, err
+}
+
+// getMinutesFromOlderThan - Input value is '14d' or '12h' for example. Unlike an --age, there is no 'TO' part.
+func getMinutesFromOlderThan(olderThan string, name string) (int, error) {
+	var err error
+	var minutes int
+
+	if strings.Contains(olderThan, ":") || !agePartRegex.MatchString(olderThan) {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_PRUNE_OLDER_THAN, olderThan, name, GetTimeUnitsForErrorMessage())
+	} else {
+		minutes, err = getMinutesFromAgePart(olderThan, olderThan)
+		if err != nil || minutes == 0 {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_PRUNE_OLDER_THAN, olderThan, name, GetTimeUnitsForErrorMessage())
+		}
+	}
+	return minutes, err
+}
+
+// getBytesFromMaxSize - Input value is '5GB' or '500MB' for example. Each unit is 1024 times the one before.
+func getBytesFromMaxSize(maxSize string, name string) (int64, error) {
+	var err error
+	var bytes int64
+
+	sizeParts := maxSizeRegex.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(maxSize)))
+	if sizeParts == nil {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_PRUNE_MAX_SIZE, maxSize, name)
+	} else {
+		number, _ := strconv.ParseFloat(sizeParts[1], 64)
+		for index, unit := range sizeUnits {
+			if unit == sizeParts[2] {
+				bytes = int64(number * math.Pow(1024, float64(index)))
+			}
+		}
+		if bytes <= 0 {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_PRUNE_MAX_SIZE, maxSize, name)
+		}
+	}
+	return bytes, err
+}
+
+func (policy *LocalRasPrunePolicy) isSet() bool {
+	return policy.olderThanMins != 0 || policy.keepLast != 0 || policy.wantedResults != nil || policy.maxSizeBytes != 0
+}
+
+// PruneLocalRas - performs all the logic to implement the `galasactl local ras prune` command,
+// but in a unit-testable manner.
+//
+// The runs which are pruned are listed along with the space each used, and with --dry-run nothing is deleted.
+func PruneLocalRas(
+	policy *LocalRasPrunePolicy,
+	rasFolderPath string,
+	isDryRun bool,
+	fileSystem spi.FileSystem,
+	timeService spi.TimeService,
+	console spi.Console,
+) error {
+	var err error
+	var runsToPrune []localRunToPrune
+	var runCount int
+
+	log.Printf("PruneLocalRas entered.")
+
+	runsToPrune, runCount, err = getLocalRunsToPrune(policy, rasFolderPath, fileSystem, timeService)
+	if err == nil {
+		if len(runsToPrune) == 0 {
+			err = console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_PRUNE_NO_RUNS.Template, runCount, rasFolderPath))
+		} else {
+			prunedSizeBytes := getTotalSizeOfLocalRuns(runsToPrune)
+			err = console.WriteString(formatLocalRunsToPrune(runsToPrune))
+			if err == nil {
+				if isDryRun {
+					err = console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_PRUNE_DRY_RUN.Template,
+						len(runsToPrune), formatSize(prunedSizeBytes), rasFolderPath))
+				} else {
+					deleteLocalRuns(runsToPrune, rasFolderPath, fileSystem)
+					err = console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_PRUNE_DONE.Template,
+						len(runsToPrune), formatSize(prunedSizeBytes), rasFolderPath))
+				}
+			}
+		}
+	}
+
+	log.Printf("PruneLocalRas exiting. err is %v", err)
+	return err
+}
+
+// AutoPruneLocalRas prunes the local RAS folder after the tests submitted by `galasactl runs submit local` are complete.
+// Unlike `galasactl local ras prune`, it only says anything when runs were pruned, and a missing RAS folder
+// just means no tests got far enough to save any results.
+func AutoPruneLocalRas(
+	policy *LocalRasPrunePolicy,
+	rasFolderPath string,
+	fileSystem spi.FileSystem,
+	timeService spi.TimeService,
+	console spi.Console,
+) error {
+	var err error
+	var isExists bool
+	var runsToPrune []localRunToPrune
+
+	log.Printf("AutoPruneLocalRas entered.")
+
+	isExists, err = fileSystem.DirExists(rasFolderPath)
+	if err == nil && isExists {
+		runsToPrune, _, err = getLocalRunsToPrune(policy, rasFolderPath, fileSystem, timeService)
+	}
+
+	if err == nil && len(runsToPrune) > 0 {
+		prunedSizeBytes := getTotalSizeOfLocalRuns(runsToPrune)
+		deleteLocalRuns(runsToPrune, rasFolderPath, fileSystem)
+		err = console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_PRUNE_DONE.Template,
+			len(runsToPrune), formatSize(prunedSizeBytes), rasFolderPath))
+	}
+
+	log.Printf("AutoPruneLocalRas exiting. err is %v", err)
+	return err
+}
+
+// getLocalRunsToPrune works out which runs in the RAS folder the policy prunes, oldest first,
+// and how much space each of them uses. Also returns how many runs there are.
+//
+// The RAS folder can hold gigabytes of old runs, so the files of every run are only looked at
+// when the policy has a maximum size. Otherwise only the folders of the runs to prune are.
+func getLocalRunsToPrune(
+	policy *LocalRasPrunePolicy,
+	rasFolderPath string,
+	fileSystem spi.FileSystem,
+	timeService spi.TimeService,
+) ([]localRunToPrune, int, error) {
+	var err error
+	var allRuns []galasaapi.Run
+	var runSizes map[string]int64
+	var rasSizeBytes int64
+	runsToPrune := make([]localRunToPrune, 0)
+
+	allRuns, err = launcher.GetLocalRuns(fileSystem, rasFolderPath)
+	if err == nil && policy.maxSizeBytes != 0 {
+		runSizes, rasSizeBytes, err = getSizesOfLocalRuns(fileSystem, rasFolderPath)
+	}
+
+	if err == nil && policy.isSet() {
+		localRuns := make([]localRunToPrune, 0, len(allRuns))
+		for _, run := range allRuns {
+			// A run with no time it was queued or started is treated as the oldest of all.
+			runTime, _ := getLocalRunTime(run)
+			localRuns = append(localRuns, localRunToPrune{run: run, runTime: runTime, sizeBytes: runSizes[run.GetRunId()]})
+		}
+
+		// Newest first, so that the most recent runs can be kept.
+		sort.SliceStable(localRuns, func(i, j int) bool {
+			return localRuns[i].runTime.After(localRuns[j].runTime)
+		})
+
+		olderThanTime := timeService.Now().Add(-(time.Duration(policy.olderThanMins) * time.Minute))
+		prunableRuns := make([]localRunToPrune, 0)
+		for index, localRun := range localRuns {
+			if index >= policy.keepLast && isLocalRunPrunable(localRun, policy, olderThanTime) {
+				prunableRuns = append(prunableRuns, localRun)
+			}
+		}
+
+		// Oldest first, so that a --max-size prunes only the oldest runs it needs to.
+		sizeLeftBytes := rasSizeBytes
+		for index := len(prunableRuns) - 1; index >= 0; index-- {
+			if policy.maxSizeBytes == 0 || sizeLeftBytes > policy.maxSizeBytes {
+				runsToPrune = append(runsToPrune, prunableRuns[index])
+				sizeLeftBytes -= prunableRuns[index].sizeBytes
+			}
+		}
+	}
+
+	if err == nil && policy.maxSizeBytes == 0 {
+		for index := range runsToPrune {
+			if err == nil {
+				runsToPrune[index].sizeBytes, err = getSizeOfLocalRun(fileSystem, rasFolderPath, runsToPrune[index].run.GetRunId())
+			}
+		}
+	}
+
+	log.Printf("%d of the %d local runs in the RAS folder '%s' are to be pruned.\n", len(runsToPrune), len(allRuns), rasFolderPath)
+	return runsToPrune, len(allRuns), err
+}
+
+func isLocalRunPrunable(localRun localRunToPrune, policy *LocalRasPrunePolicy, olderThanTime time.Time) bool {
+	// A run which hasn't finished may still be writing into its folder.
+	isPrunable := localRun.run.TestStructure.GetStatus() == launcher.LOCAL_RUN_STATUS_FINISHED
+
+	if isPrunable && policy.wantedResults != nil {
+		isPrunable = policy.wantedResults[strings.ToLower(localRun.run.TestStructure.GetResult())]
+	}
+
+	if isPrunable && policy.olderThanMins != 0 {
+		isPrunable = localRun.runTime.Before(olderThanTime)
+	}
+
+	return isPrunable
+}
+
+// getSizesOfLocalRuns adds up the sizes of the files in each run folder within the RAS folder.
+// Also returns the size of everything in the RAS folder.
+func getSizesOfLocalRuns(fileSystem spi.FileSystem, rasFolderPath string) (map[string]int64, int64, error) {
+	var err error
+	var filePaths []string
+	var rasSizeBytes int64
+	runSizes := make(map[string]int64)
+	separator := fileSystem.GetFilePathSeparator()
+
+	filePaths, err = fileSystem.GetAllFilePaths(rasFolderPath)
+	for _, filePath := range filePaths {
+		if err == nil {
+			relativePath := strings.TrimPrefix(strings.TrimPrefix(filePath, rasFolderPath), separator)
+			runId := strings.Split(relativePath, separator)[0]
+
+			var fileSizeBytes int64
+			fileSizeBytes, err = fileSystem.GetFileSize(filePath)
+			if err != nil {
+				err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_LOCAL_RUN_SIZE_NOT_READ, runId, err.Error())
+			} else {
+				runSizes[runId] += fileSizeBytes
+				rasSizeBytes += fileSizeBytes
+			}
+		}
+	}
+	return runSizes, rasSizeBytes, err
+}
+
+// getSizeOfLocalRun adds up the sizes of the files in the folder of a single run.
+func getSizeOfLocalRun(fileSystem spi.FileSystem, rasFolderPath string, runId string) (int64, error) {
+	var err error
+	var filePaths []string
+	var runSizeBytes int64
+	separator := fileSystem.GetFilePathSeparator()
+
+	// The folder of run L1 mustn't be confused with the folder of run L10.
+	filePaths, err = fileSystem.GetAllFilePaths(rasFolderPath + separator + runId + separator)
+	for _, filePath := range filePaths {
+		if err == nil {
+			var fileSizeBytes int64
+			fileSizeBytes, err = fileSystem.GetFileSize(filePath)
+			if err != nil {
+				err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_LOCAL_RUN_SIZE_NOT_READ, runId, err.Error())
+			} else {
+				runSizeBytes += fileSizeBytes
+			}
+		}
+	}
+	return runSizeBytes, err
+}
+
+func getTotalSizeOfLocalRuns(localRuns []localRunToPrune) int64 {
+	var totalSizeBytes int64
+	for _, localRun := range localRuns {
+		totalSizeBytes += localRun.sizeBytes
+	}
+	return totalSizeBytes
+}
+
+func deleteLocalRuns(localRuns []localRunToPrune, rasFolderPath string, fileSystem spi.FileSystem) {
+	separator := fileSystem.GetFilePathSeparator()
+	for _, localRun := range localRuns {
+		// The folder of run L1 mustn't be confused with the folder of run L10.
+		runFolderPath := rasFolderPath + separator + localRun.run.GetRunId() + separator
+		log.Printf("Pruning local run folder '%s'\n", runFolderPath)
+		fileSystem.DeleteDir(runFolderPath)
+	}
+}
+
+// formatLocalRunsToPrune renders a table of the runs which a prune removes, and the space each uses.
+func formatLocalRunsToPrune(localRuns []localRunToPrune) string {
+	var table [][]string
+	buff := strings.Builder{}
+
+	headers := []string{runsformatter.HEADER_SUBMITTED_TIME, runsformatter.HEADER_RUNNAME, runsformatter.HEADER_RESULT, runsformatter.HEADER_TEST_NAME, HEADER_PRUNE_SIZE}
+	table = append(table, headers)
+
+	for _, localRun := range localRuns {
+		submittedTime := ""
+		if !localRun.runTime.IsZero() {
+			submittedTime = localRun.runTime.UTC().Format("2006-01-02 15:04:05")
+		}
+		testStructure := localRun.run.GetTestStructure()
+		line := []string{submittedTime, localRun.run.GetRunId(), testStructure.GetResult(), testStructure.GetTestName(), formatSize(localRun.sizeBytes)}
+		table = append(table, line)
+	}
+
+	columnLengths := utils.CalculateMaxLengthOfEachColumn(table)
+	utils.WriteFormattedTableToStringBuilder(table, &buff, columnLengths)
+	buff.WriteString("\n")
+
+	return buff.String()
+}
+
+// formatSize renders a number of bytes in the largest unit it is at least 1 of, such as '1.5 GB'.
+func formatSize(sizeBytes int64) string {
+	size := float64(sizeBytes)
+	unitIndex := 0
+	for size >= 1024 && unitIndex < len(sizeUnits)-1 {
+		size = size / 1024
+		unitIndex++
+	}
+
+	var formattedSize string
+	if unitIndex == 0 {
+		formattedSize = fmt.Sprintf("%d %s", sizeBytes, sizeUnits[unitIndex])
+	} else {
+		formattedSize = fmt.Sprintf("%.1f %s", size, sizeUnits[unitIndex])
+	}
+	return formattedSize
+}
@@ -0,0 +1,303 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertLocalRunsLeft(t *testing.T, fs spi.FileSystem, expectedRunNames []string, prunedRunNames []string) {
+	for _, runName := range expectedRunNames {
+		isExists, _ := fs.Exists("/my/ras/" + runName + "/structure.json")
+		assert.True(t, isExists, "Run "+runName+" should not have been pruned")
+	}
+	for _, runName := range prunedRunNames {
+		isExists, _ := fs.Exists("/my/ras/" + runName + "/structure.json")
+		assert.False(t, isExists, "Run "+runName+" should have been pruned")
+	}
+}
+
+// Records which folders are walked and which files are sized, so tests can check the RAS folder isn't read more than it needs to be.
+type fileSizeRecordingFileSystem struct {
+	spi.FileSystem
+	walkedFolderPaths []string
+	sizedFilePaths    []string
+}
+
+func (fs *fileSizeRecordingFileSystem) GetAllFilePaths(rootPath string) ([]string, error) {
+	fs.walkedFolderPaths = append(fs.walkedFolderPaths, rootPath)
+	return fs.FileSystem.GetAllFilePaths(rootPath)
+}
+
+func (fs *fileSizeRecordingFileSystem) GetFileSize(filePath string) (int64, error) {
+	fs.sizedFilePaths = append(fs.sizedFilePaths, filePath)
+	return fs.FileSystem.GetFileSize(filePath)
+}
+
+func TestPruneLocalRasOlderThanPrunesOnlyTheOldRuns(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	console := utils.NewMockConsole()
+	policy, err := NewLocalRasPrunePolicy("2d", 0, "", "")
+	assert.Nil(t, err)
+
+	// When...
+	err = PruneLocalRas(policy, "/my/ras", false, fs, timeService, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assertLocalRunsLeft(t, fs, []string{"L1", "L2"}, []string{"L3"})
+	output := console.ReadText()
+	assert.Contains(t, output, "L3")
+	assert.Contains(t, output, "GAL2533I: Pruned 1 local test runs")
+}
+
+func TestPruneLocalRasKeepLastKeepsTheMostRecentRuns(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	console := utils.NewMockConsole()
+	policy, _ := NewLocalRasPrunePolicy("", 1, "", "")
+
+	// When...
+	err := PruneLocalRas(policy, "/my/ras", false, fs, timeService, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assertLocalRunsLeft(t, fs, []string{"L1"}, []string{"L2", "L3"})
+}
+
+func TestPruneLocalRasResultPrunesOnlyRunsWithThatResult(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	console := utils.NewMockConsole()
+	policy, _ := NewLocalRasPrunePolicy("", 0, "passed,Hung", "")
+
+	// When...
+	err := PruneLocalRas(policy, "/my/ras", false, fs, timeService, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assertLocalRunsLeft(t, fs, []string{"L2"}, []string{"L1", "L3"})
+}
+
+func TestPruneLocalRasPrunesOnlyRunsWhichMatchEveryPolicy(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	console := utils.NewMockConsole()
+	policy, _ := NewLocalRasPrunePolicy("30m", 0, "Passed,Failed", "")
+
+	// When...
+	err := PruneLocalRas(policy, "/my/ras", false, fs, timeService, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assertLocalRunsLeft(t, fs, []string{"L3"}, []string{"L1", "L2"})
+}
+
+func TestPruneLocalRasMaxSizePrunesTheOldestRunsUntilSmallEnough(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	fs.WriteBinaryFile("/my/ras/L1/artifacts/big.bin", make([]byte, 1024))
+	fs.WriteBinaryFile("/my/ras/L2/artifacts/big.bin", make([]byte, 1024))
+	fs.WriteBinaryFile("/my/ras/L3/artifacts/big.bin", make([]byte, 1024))
+	console := utils.NewMockConsole()
+	policy, _ := NewLocalRasPrunePolicy("", 0, "", "3KB")
+
+	// When...
+	err := PruneLocalRas(policy, "/my/ras", false, fs, timeService, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assertLocalRunsLeft(t, fs, []string{"L1", "L2"}, []string{"L3"})
+}
+
+func TestPruneLocalRasWithoutMaxSizeOnlyReadsTheFilesOfTheRunsToPrune(t *testing.T) {
+	// Given...
+	mockFs, timeService := createLocalRas(t)
+	mockFs.WriteBinaryFile("/my/ras/L1/artifacts/big.bin", make([]byte, 1024))
+	mockFs.WriteBinaryFile("/my/ras/L3/artifacts/big.bin", make([]byte, 2048))
+	fs := &fileSizeRecordingFileSystem{FileSystem: mockFs}
+	console := utils.NewMockConsole()
+	policy, _ := NewLocalRasPrunePolicy("2d", 0, "", "")
+
+	// When...
+	err := PruneLocalRas(policy, "/my/ras", true, fs, timeService, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/my/ras/L3/"}, fs.walkedFolderPaths)
+	for _, filePath := range fs.sizedFilePaths {
+		assert.True(t, strings.HasPrefix(filePath, "/my/ras/L3/"), "Only the files of the run to prune should be sized, not "+filePath)
+	}
+	assert.Contains(t, console.ReadText(), "GAL2532I: 1 local test runs using 2.")
+}
+
+func TestPruneLocalRasWithMaxSizeReadsTheFilesOfEveryRun(t *testing.T) {
+	// Given...
+	mockFs, timeService := createLocalRas(t)
+	fs := &fileSizeRecordingFileSystem{FileSystem: mockFs}
+	console := utils.NewMockConsole()
+	policy, _ := NewLocalRasPrunePolicy("", 0, "", "1KB")
+
+	// When...
+	err := PruneLocalRas(policy, "/my/ras", true, fs, timeService, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/my/ras"}, fs.walkedFolderPaths)
+}
+
+func TestPruneLocalRasDryRunListsRunsWithSizesButDeletesNothing(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	fs.WriteBinaryFile("/my/ras/L3/artifacts/big.bin", make([]byte, 2048))
+	console := utils.NewMockConsole()
+	policy, _ := NewLocalRasPrunePolicy("2d", 0, "", "")
+
+	// When...
+	err := PruneLocalRas(policy, "/my/ras", true, fs, timeService, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assertLocalRunsLeft(t, fs, []string{"L1", "L2", "L3"}, []string{})
+	output := console.ReadText()
+	assert.Contains(t, output, "submitted-time(UTC)")
+	assert.Contains(t, output, "2024-05-03 12:00:00 L3")
+	assert.Contains(t, output, "2.")
+	assert.Contains(t, output, " KB")
+	assert.Contains(t, output, "GAL2532I: 1 local test runs using 2.")
+	assert.Contains(t, output, "Nothing was deleted, because --dry-run was used.")
+}
+
+func TestPruneLocalRasNeverPrunesRunsWhichHaveNotFinished(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	structureJson, _ := fs.ReadTextFile("/my/ras/L3/structure.json")
+	fs.WriteTextFile("/my/ras/L3/structure.json", strings.Replace(structureJson, `"finished"`, `"running"`, 1))
+	console := utils.NewMockConsole()
+	policy, _ := NewLocalRasPrunePolicy("2d", 0, "", "")
+
+	// When...
+	err := PruneLocalRas(policy, "/my/ras", false, fs, timeService, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assertLocalRunsLeft(t, fs, []string{"L1", "L2", "L3"}, []string{})
+	assert.Contains(t, console.ReadText(), "GAL2531I: None of the 3 local test runs in the RAS folder '/my/ras' matched the prune policies")
+}
+
+func TestPruneLocalRasDoesNotConfuseRunL1WithRunL10(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	writeLocalRun(t, fs, "L10", "Failed", timeService.Now().Add(-time.Minute))
+	console := utils.NewMockConsole()
+	policy, _ := NewLocalRasPrunePolicy("", 0, "Passed", "")
+
+	// When...
+	err := PruneLocalRas(policy, "/my/ras", false, fs, timeService, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assertLocalRunsLeft(t, fs, []string{"L10", "L2", "L3"}, []string{"L1"})
+}
+
+func TestNewLocalRasPrunePolicyWithBadValuesReturnsErrors(t *testing.T) {
+	_, err := NewLocalRasPrunePolicy("14", 0, "", "")
+	assert.Contains(t, err.Error(), "GAL1312E: Invalid value '14' for '--older-than'")
+
+	_, err = NewLocalRasPrunePolicy("2d:1d", 0, "", "")
+	assert.Contains(t, err.Error(), "GAL1312E")
+
+	_, err = NewLocalRasPrunePolicy("", -1, "", "")
+	assert.Contains(t, err.Error(), "GAL1313E: Invalid value '-1' for '--keep-last'")
+
+	_, err = NewLocalRasPrunePolicy("", 0, "", "5 parsecs")
+	assert.Contains(t, err.Error(), "GAL1314E: Invalid value '5 parsecs' for '--max-size'")
+
+	_, err = NewLocalRasPrunePolicy("", 0, "", "0GB")
+	assert.Contains(t, err.Error(), "GAL1314E")
+}
+
+func TestGetBytesFromMaxSizeUnderstandsEachUnit(t *testing.T) {
+	for maxSize, expectedBytes := range map[string]int64{
+		"100B":  100,
+		"2kb":   2048,
+		"1.5MB": 1536 * 1024,
+		"5GB":   5 * 1024 * 1024 * 1024,
+		"1 TB":  1024 * 1024 * 1024 * 1024,
+	} {
+		bytes, err := getBytesFromMaxSize(maxSize, "--max-size")
+		assert.Nil(t, err)
+		assert.Equal(t, expectedBytes, bytes, maxSize)
+	}
+}
+
+func TestFormatSizeUsesTheLargestWholeUnit(t *testing.T) {
+	assert.Equal(t, "0 B", formatSize(0))
+	assert.Equal(t, "1023 B", formatSize(1023))
+	assert.Equal(t, "1.5 KB", formatSize(1536))
+	assert.Equal(t, "5.0 GB", formatSize(5*1024*1024*1024))
+}
+
+func TestGetLocalRasAutoPrunePolicyReadsGalasactlProperties(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	galasaHome, _ := utils.NewGalasaHome(fs, utils.NewMockEnv(), "")
+	fs.WriteTextFile(galasaHome.GetNativeFolderPath()+"/galasactl.properties",
+		"GALASA_TOKEN=my:token\n"+PROPERTY_NAME_PRUNE_KEEP_LAST+"=2\n")
+	console := utils.NewMockConsole()
+
+	// When...
+	policy, err := GetLocalRasAutoPrunePolicy(fs, galasaHome)
+	assert.Nil(t, err)
+	assert.NotNil(t, policy)
+	err = AutoPruneLocalRas(policy, "/my/ras", fs, timeService, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assertLocalRunsLeft(t, fs, []string{"L1", "L2"}, []string{"L3"})
+	output := console.ReadText()
+	assert.NotContains(t, output, "submitted-time(UTC)")
+	assert.Contains(t, output, "GAL2533I: Pruned 1 local test runs")
+}
+
+func TestGetLocalRasAutoPrunePolicyWithoutPruneSettingsReturnsNil(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	galasaHome, _ := utils.NewGalasaHome(fs, utils.NewMockEnv(), "")
+
+	// When...
+	policyWithoutFile, errWithoutFile := GetLocalRasAutoPrunePolicy(fs, galasaHome)
+	fs.WriteTextFile(galasaHome.GetNativeFolderPath()+"/galasactl.properties", "GALASA_TOKEN=my:token\n")
+	policyWithoutSettings, errWithoutSettings := GetLocalRasAutoPrunePolicy(fs, galasaHome)
+
+	// Then...
+	assert.Nil(t, errWithoutFile)
+	assert.Nil(t, policyWithoutFile)
+	assert.Nil(t, errWithoutSettings)
+	assert.Nil(t, policyWithoutSettings)
+}
+
+func TestGetLocalRasAutoPrunePolicyWithBadPropertyNamesTheProperty(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	galasaHome, _ := utils.NewGalasaHome(fs, utils.NewMockEnv(), "")
+	fs.WriteTextFile(galasaHome.GetNativeFolderPath()+"/galasactl.properties", PROPERTY_NAME_PRUNE_MAX_SIZE+"=lots\n")
+
+	// When...
+	_, err := GetLocalRasAutoPrunePolicy(fs, galasaHome)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1314E: Invalid value 'lots' for '"+PROPERTY_NAME_PRUNE_MAX_SIZE+"'")
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/spi"
+)
+
+// DownloadLocalRuns - performs all the logic to implement the `galasactl local runs download` command,
+// but in a unit-testable manner.
+//
+// The folder of each selected run in the RAS is copied into a folder named after the run, and the
+// terminal screens it holds are rendered into images, just as `galasactl runs download` does for
+// runs in an ecosystem.
+func DownloadLocalRuns(
+	query LocalRunsQuery,
+	forceDownload bool,
+	fileSystem spi.FileSystem,
+	timeService spi.TimeService,
+	console spi.Console,
+	runDownloadTargetFolder string,
+	renderingOptions *ImageRenderingOptions,
+) error {
+	var err error
+	var runs []galasaapi.Run
+
+	log.Printf("DownloadLocalRuns entered.")
+
+	runs, err = getLocalRunsMatchingQuery(query, fileSystem, timeService)
+	if err == nil {
+		if len(runs) == 0 {
+			err = console.WriteString(galasaErrors.GALASA_INFO_BULK_DOWNLOAD_NO_RUNS.Template)
+		} else {
+			// The rendering workers report their progress while the other runs are still being copied.
+			syncConsole := &synchronizedConsole{console: console}
+			renderingPool := newImageRenderingPool(fileSystem, syncConsole, forceDownload, renderingOptions)
+
+			for _, run := range runs {
+				if err == nil {
+					err = copyLocalRunToDirectory(run, query.RasFolderPath, forceDownload, fileSystem, syncConsole, runDownloadTargetFolder, renderingPool)
+				}
+			}
+
+			// Whatever was copied finishes rendering, even if a later copy failed.
+			renderErr := renderingPool.finish()
+			if err == nil {
+				err = renderErr
+			}
+		}
+	}
+
+	log.Printf("DownloadLocalRuns exiting. err is %v", err)
+	return err
+}
+
+func copyLocalRunToDirectory(
+	run galasaapi.Run,
+	rasFolderPath string,
+	forceDownload bool,
+	fileSystem spi.FileSystem,
+	console spi.Console,
+	runDownloadTargetFolder string,
+	renderingPool *imageRenderingPool,
+) error {
+	var err error
+	var sourceFilePaths []string
+
+	runId := run.GetRunId()
+	separator := fileSystem.GetFilePathSeparator()
+	runFolderPath := rasFolderPath + separator + runId
+	directoryName := getRunDownloadFolderPath(runDownloadTargetFolder, runId)
+	filePathsCreated := make([]string, 0)
+
+	sourceFilePaths, err = fileSystem.GetAllFilePaths(runFolderPath)
+	for _, sourceFilePath := range sourceFilePaths {
+		// The folder of run L1 mustn't be confused with the folder of run L10.
+		if err == nil && strings.HasPrefix(sourceFilePath, runFolderPath+separator) {
+			relativePath := strings.TrimPrefix(sourceFilePath, runFolderPath+separator)
+			targetFilePath := filepath.Join(directoryName, relativePath)
+
+			err = copyLocalRunFile(fileSystem, sourceFilePath, targetFilePath, forceDownload)
+			if err == nil {
+				filePathsCreated = append(filePathsCreated, targetFilePath)
+				// Each terminal file is rendered as soon as it has been copied.
+				renderingPool.submitFile(targetFilePath)
+			}
+		}
+	}
+
+	if err == nil {
+		// An animation needs all the screens of its terminal, so waits until the whole run is copied.
+		isReplacingAnimations := forceDownload && isAnyTerminalDownloaded(filePathsCreated)
+		renderingPool.submitAnimations(directoryName, isReplacingAnimations)
+	}
+
+	if len(filePathsCreated) > 0 {
+		msg := fmt.Sprintf(galasaErrors.GALASA_INFO_FOLDER_DOWNLOADED_TO.Template, len(filePathsCreated), directoryName)
+		if consoleErr := console.WriteString(msg); err == nil {
+			err = consoleErr
+		}
+	}
+	return err
+}
+
+func copyLocalRunFile(fileSystem spi.FileSystem, sourceFilePath string, targetFilePath string, shouldOverwrite bool) error {
+	var err error
+	var fileExists bool
+	var contents []byte
+
+	fileExists, err = fileSystem.Exists(targetFilePath)
+	if err == nil && fileExists && !shouldOverwrite {
+		// The --force flag was not provided, throw an error.
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_CANNOT_OVERWRITE_FILE, targetFilePath)
+	}
+
+	if err == nil {
+		contents, err = fileSystem.ReadBinaryFile(sourceFilePath)
+	}
+
+	if err == nil {
+		targetDirectoryPath := filepath.Dir(targetFilePath)
+		err = fileSystem.MkdirAll(targetDirectoryPath)
+		if err != nil {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_FAILED_TO_CREATE_FOLDERS, targetDirectoryPath, err.Error())
+		}
+	}
+
+	if err == nil {
+		log.Printf("Copying '%s' to '%s'\n", sourceFilePath, targetFilePath)
+		err = fileSystem.WriteBinaryFile(targetFilePath, contents)
+		if err != nil {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_FAILED_TO_WRITE_FILE, targetFilePath, err.Error())
+		}
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadLocalRunsCopiesTheRunFolderAndRendersItsTerminals(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	createDownloadedTerminalFile(t, fs, "/my/ras/L1/zos3270/terminals/term1/term1-00001.gz")
+	fs.WriteTextFile("/my/ras/L10/run.log", "The run log of another run")
+	console := utils.NewMockConsole()
+	options, _ := NewImageRenderingOptions(1, false, nil)
+	query := LocalRunsQuery{RasFolderPath: "/my/ras", RunName: "L1"}
+
+	// When...
+	err := DownloadLocalRuns(query, false, fs, timeService, console, "/downloads", options)
+
+	// Then...
+	assert.Nil(t, err)
+	runLog, _ := fs.ReadTextFile("/downloads/L1/run.log")
+	assert.Equal(t, "The run log of L1", runLog)
+	isImageWritten, _ := fs.Exists("/downloads/L1/zos3270/images/term1/term1-00001.png")
+	assert.True(t, isImageWritten)
+	isOtherRunCopied, _ := fs.DirExists("/downloads/L10")
+	assert.False(t, isOtherRunCopied)
+
+	output := console.ReadText()
+	assert.Contains(t, output, "GAL2501I: Downloaded 3 artifacts to folder '/downloads/L1'\n")
+	assert.Contains(t, output, "GAL2525I")
+}
+
+func TestDownloadLocalRunsByResultCopiesEachMatchingRun(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	console := utils.NewMockConsole()
+	options, _ := NewImageRenderingOptions(1, true, nil)
+	query := LocalRunsQuery{RasFolderPath: "/my/ras", Result: "Failed,Hung"}
+
+	// When...
+	err := DownloadLocalRuns(query, false, fs, timeService, console, "/downloads", options)
+
+	// Then...
+	assert.Nil(t, err)
+	isL1Copied, _ := fs.Exists("/downloads/L1/run.log")
+	assert.False(t, isL1Copied)
+	isL2Copied, _ := fs.Exists("/downloads/L2/run.log")
+	assert.True(t, isL2Copied)
+	isL3Copied, _ := fs.Exists("/downloads/L3/structure.json")
+	assert.True(t, isL3Copied)
+	assert.Equal(t,
+		"GAL2501I: Downloaded 2 artifacts to folder '/downloads/L2'\n"+
+			"GAL2501I: Downloaded 2 artifacts to folder '/downloads/L3'\n", console.ReadText())
+}
+
+func TestDownloadLocalRunsWhichMatchNothingSaysSo(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	console := utils.NewMockConsole()
+	options, _ := NewImageRenderingOptions(1, true, nil)
+	query := LocalRunsQuery{RasFolderPath: "/my/ras", Result: "EnvFail"}
+
+	// When...
+	err := DownloadLocalRuns(query, false, fs, timeService, console, "/downloads", options)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Contains(t, console.ReadText(), "GAL2509I")
+}
+
+func TestDownloadLocalRunsWontOverwriteFilesWithoutForce(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	fs.WriteTextFile("/downloads/L1/run.log", "An earlier copy")
+	console := utils.NewMockConsole()
+	options, _ := NewImageRenderingOptions(1, true, nil)
+	query := LocalRunsQuery{RasFolderPath: "/my/ras", RunName: "L1"}
+
+	// When...
+	err := DownloadLocalRuns(query, false, fs, timeService, console, "/downloads", options)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1036E")
+}
+
+func TestDownloadLocalRunsWithForceOverwritesFiles(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	fs.WriteTextFile("/downloads/L1/run.log", "An earlier copy")
+	console := utils.NewMockConsole()
+	options, _ := NewImageRenderingOptions(1, true, nil)
+	query := LocalRunsQuery{RasFolderPath: "/my/ras", RunName: "L1"}
+
+	// When...
+	err := DownloadLocalRuns(query, true, fs, timeService, console, "/downloads", options)
+
+	// Then...
+	assert.Nil(t, err)
+	runLog, _ := fs.ReadTextFile("/downloads/L1/run.log")
+	assert.Equal(t, "The run log of L1", runLog)
+}
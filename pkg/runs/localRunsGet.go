@@ -0,0 +1,180 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/launcher"
+	"github.com/galasa-dev/cli/pkg/runsformatter"
+	"github.com/galasa-dev/cli/pkg/spi"
+)
+
+// LocalRunsQuery selects which of the local test runs in a RAS folder are wanted.
+// Blank filters select every run.
+type LocalRunsQuery struct {
+	RasFolderPath string
+	RunName       string
+	Age           string
+	Result        string
+}
+
+// GetLocalRuns - performs all the logic to implement the `galasactl local runs get` command,
+// but in a unit-testable manner.
+func GetLocalRuns(
+	query LocalRunsQuery,
+	outputFormatString string,
+	fileSystem spi.FileSystem,
+	timeService spi.TimeService,
+	console spi.Console,
+) error {
+	var err error
+	var chosenFormatter runsformatter.RunsFormatter
+	var runs []galasaapi.Run
+
+	log.Printf("GetLocalRuns entered.")
+
+	chosenFormatter, err = validateOutputFormatFlagValue(outputFormatString, validFormatters)
+	if err == nil {
+		runs, err = getLocalRunsMatchingQuery(query, fileSystem, timeService)
+	}
+
+	if err == nil {
+		var outputText string
+		log.Printf("There are %v results to display in total.\n", len(runs))
+
+		formattableTests := formattableTestsFromLocalRuns(runs, query.RasFolderPath, fileSystem.GetFilePathSeparator())
+		outputText, err = chosenFormatter.FormatRuns(formattableTests)
+		if err == nil {
+			err = writeOutput(outputText, console)
+		}
+	}
+
+	log.Printf("GetLocalRuns exiting. err is %v", err)
+	return err
+}
+
+// getLocalRunsMatchingQuery reads the local runs in the RAS folder, keeping those which the query selects.
+func getLocalRunsMatchingQuery(query LocalRunsQuery, fileSystem spi.FileSystem, timeService spi.TimeService) ([]galasaapi.Run, error) {
+	var err error
+	var fromAge int
+	var toAge int
+	var wantedResults map[string]bool
+	var allRuns []galasaapi.Run
+	runs := make([]galasaapi.Run, 0)
+
+	if query.RunName != "" {
+		// Validate the runName as best we can before reading the RAS.
+		err = ValidateRunName(query.RunName)
+	}
+
+	if err == nil && query.Age != "" {
+		fromAge, toAge, err = getTimesFromAge(query.Age)
+	}
+
+	if err == nil {
+		wantedResults = getWantedLocalResults(query.Result)
+	}
+
+	if err == nil {
+		allRuns, err = launcher.GetLocalRuns(fileSystem, query.RasFolderPath)
+	}
+
+	if err == nil {
+		now := timeService.Now()
+		for _, run := range allRuns {
+			if isLocalRunWanted(run, query.RunName, fromAge, toAge, wantedResults, now) {
+				runs = append(runs, run)
+			}
+		}
+
+		if query.RunName != "" && len(runs) == 0 {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_LOCAL_RUN_NOT_FOUND, query.RunName, query.RasFolderPath)
+		}
+	}
+
+	return runs, err
+}
+
+func isLocalRunWanted(
+	run galasaapi.Run,
+	runName string,
+	fromAgeMins int,
+	toAgeMins int,
+	wantedResults map[string]bool,
+	now time.Time,
+) bool {
+	isWanted := true
+
+	if runName != "" && run.TestStructure.GetRunName() != runName {
+		isWanted = false
+	}
+
+	if isWanted && wantedResults != nil {
+		isWanted = wantedResults[strings.ToLower(run.TestStructure.GetResult())]
+	}
+
+	if isWanted && fromAgeMins != 0 {
+		isWanted = isLocalRunWithinAge(run, fromAgeMins, toAgeMins, now)
+	}
+
+	return isWanted
+}
+
+// getWantedLocalResults returns the lower-case names of the results wanted, or nil if any result is wanted.
+// There is no ecosystem to ask for the valid result names, so any result is allowed.
+func getWantedLocalResults(resultList string) map[string]bool {
+	var wantedResults map[string]bool
+	if resultList != "" {
+		wantedResults = make(map[string]bool)
+		for _, result := range strings.Split(resultList, ",") {
+			wantedResults[strings.ToLower(strings.TrimSpace(result))] = true
+		}
+	}
+	return wantedResults
+}
+
+// A run is as old as when it was queued, which a run that never got going might not have recorded,
+// so such a run is aged from when it started instead.
+func getLocalRunTime(run galasaapi.Run) (time.Time, error) {
+	runTimeString := run.TestStructure.GetQueued()
+	if runTimeString == "" {
+		runTimeString = run.TestStructure.GetStartTime()
+	}
+	return time.Parse(time.RFC3339, runTimeString)
+}
+
+func isLocalRunWithinAge(run galasaapi.Run, fromAgeMins int, toAgeMins int, now time.Time) bool {
+	isWithinAge := false
+
+	runTime, err := getLocalRunTime(run)
+	if err != nil {
+		log.Printf("Local run '%s' has no time it was queued or started, so is too old for any --age.\n", run.GetRunId())
+	} else {
+		fromTime := now.Add(-(time.Duration(fromAgeMins) * time.Minute))
+		isWithinAge = !runTime.Before(fromTime)
+		if isWithinAge && toAgeMins != 0 {
+			toTime := now.Add(-(time.Duration(toAgeMins) * time.Minute))
+			isWithinAge = !runTime.After(toTime)
+		}
+	}
+	return isWithinAge
+}
+
+// Local runs have no API server, so their run logs are the files in the RAS folder instead.
+func formattableTestsFromLocalRuns(runs []galasaapi.Run, rasFolderPath string, separator string) []runsformatter.FormattableTest {
+	apiServerUrl := ""
+	formattableTests := FormattableTestFromGalasaApi(runs, apiServerUrl)
+	for index := range formattableTests {
+		test := &formattableTests[index]
+		test.RunLogPath = rasFolderPath + separator + test.RunId + separator + launcher.LOCAL_RUN_LOG_FILE_NAME
+	}
+	return formattableTests
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// The local RAS used by these tests holds 3 runs, queued 1 hour, 1 day and 1 week before the mock time.
+func createLocalRas(t *testing.T) (spi.FileSystem, spi.TimeService) {
+	now := time.Date(2024, time.May, 10, 12, 0, 0, 0, time.UTC)
+	fs := files.NewMockFileSystem()
+	fs.MkdirAll("/my/ras")
+
+	writeLocalRun(t, fs, "L1", "Passed", now.Add(-1*time.Hour))
+	writeLocalRun(t, fs, "L2", "Failed", now.Add(-24*time.Hour))
+	writeLocalRun(t, fs, "L3", "Hung", now.Add(-7*24*time.Hour))
+
+	return fs, utils.NewOverridableMockTimeService(now)
+}
+
+func writeLocalRun(t *testing.T, fs spi.FileSystem, runName string, result string, queued time.Time) {
+	structureJson := `{
+		"runName": "` + runName + `",
+		"bundle": "dev.galasa.example.banking.account",
+		"testName": "dev.galasa.example.banking.account.TestAccount",
+		"requestor": "testuser",
+		"status": "finished",
+		"result": "` + result + `",
+		"queued": "` + queued.Format(time.RFC3339) + `",
+		"startTime": "` + queued.Add(time.Second).Format(time.RFC3339) + `",
+		"endTime": "` + queued.Add(2*time.Second).Format(time.RFC3339) + `"
+	}`
+	err := fs.WriteTextFile("/my/ras/"+runName+"/structure.json", structureJson)
+	assert.Nil(t, err)
+	err = fs.WriteTextFile("/my/ras/"+runName+"/run.log", "The run log of "+runName)
+	assert.Nil(t, err)
+}
+
+func TestGetLocalRunsWithNoFiltersShowsEveryRun(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	console := utils.NewMockConsole()
+
+	// When...
+	err := GetLocalRuns(LocalRunsQuery{RasFolderPath: "/my/ras"}, "summary", fs, timeService, console)
+
+	// Then...
+	assert.Nil(t, err)
+	output := console.ReadText()
+	assert.Contains(t, output, "L1")
+	assert.Contains(t, output, "L2")
+	assert.Contains(t, output, "L3")
+	assert.Contains(t, output, "Total:3 Passed:1 Failed:1 Hung:1")
+}
+
+func TestGetLocalRunsByNameShowsTheRunLogInTheRas(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	console := utils.NewMockConsole()
+	query := LocalRunsQuery{RasFolderPath: "/my/ras", RunName: "L2"}
+
+	// When...
+	err := GetLocalRuns(query, "raw", fs, timeService, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "L2|finished|Failed|2024-05-09T12:00:00Z|2024-05-09T12:00:01Z|2024-05-09T12:00:02Z|1000|"+
+		"dev.galasa.example.banking.account.TestAccount|testuser|dev.galasa.example.banking.account||/my/ras/L2/run.log\n", console.ReadText())
+}
+
+func TestGetLocalRunsByUnknownNameReturnsError(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	console := utils.NewMockConsole()
+	query := LocalRunsQuery{RasFolderPath: "/my/ras", RunName: "L99"}
+
+	// When...
+	err := GetLocalRuns(query, "summary", fs, timeService, console)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1311E")
+	assert.Empty(t, console.ReadText())
+}
+
+func TestGetLocalRunsByAgeShowsOnlyRunsOfThatAge(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	console := utils.NewMockConsole()
+	query := LocalRunsQuery{RasFolderPath: "/my/ras", Age: "2d:2h"}
+
+	// When...
+	err := GetLocalRuns(query, "raw", fs, timeService, console)
+
+	// Then...
+	assert.Nil(t, err)
+	output := console.ReadText()
+	assert.NotContains(t, output, "L1|")
+	assert.Contains(t, output, "L2|")
+	assert.NotContains(t, output, "L3|")
+}
+
+func TestGetLocalRunsByResultIsCaseInsensitive(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	console := utils.NewMockConsole()
+	query := LocalRunsQuery{RasFolderPath: "/my/ras", Result: "passed,HUNG"}
+
+	// When...
+	err := GetLocalRuns(query, "raw", fs, timeService, console)
+
+	// Then...
+	assert.Nil(t, err)
+	output := console.ReadText()
+	assert.Contains(t, output, "L1|")
+	assert.NotContains(t, output, "L2|")
+	assert.Contains(t, output, "L3|")
+}
+
+func TestGetLocalRunsWithBadAgeReturnsError(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	console := utils.NewMockConsole()
+	query := LocalRunsQuery{RasFolderPath: "/my/ras", Age: "1h:1d"}
+
+	// When...
+	err := GetLocalRuns(query, "summary", fs, timeService, console)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1077E")
+}
+
+func TestGetLocalRunsWithBadFormatReturnsError(t *testing.T) {
+	// Given...
+	fs, timeService := createLocalRas(t)
+	console := utils.NewMockConsole()
+
+	// When...
+	err := GetLocalRuns(LocalRunsQuery{RasFolderPath: "/my/ras"}, "unknown", fs, timeService, console)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1067E")
+}
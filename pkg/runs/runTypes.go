@@ -5,6 +5,10 @@
  */
 package runs
 
+import (
+	"github.com/galasa-dev/cli/pkg/launcher"
+)
+
 type TestRun struct {
 	Name           string            `yaml:"name" json:"name"`
 	Bundle         string            `yaml:"bundle" json:"bundle"`
@@ -22,6 +26,9 @@ type TestRun struct {
 	Group          string            `yaml:"group" json:"group"`
 	SubmissionId   string            `yaml:"submissionId" json:"submissionId"`
 	RunId          string            `yaml:"runId,omitempty" json:"runId,omitempty"`
+
+	// How the JVM of a local run was configured. Not set for runs in the ecosystem.
+	Jvm *launcher.JvmConfiguration `yaml:"jvm,omitempty" json:"jvm,omitempty"`
 }
 
 type TestMethod struct {
@@ -0,0 +1,247 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/embedded"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+)
+
+const (
+	HEADER_ARTIFACT_PATH         = "path"
+	HEADER_ARTIFACT_CONTENT_TYPE = "content-type"
+	HEADER_ARTIFACT_SIZE         = "size"
+
+	// Used when the server didn't tell us how big an artifact is.
+	ARTIFACT_SIZE_UNKNOWN int64 = -1
+)
+
+// RunArtifact describes one of the artifacts stored in the RAS for a test run.
+type RunArtifact struct {
+	Path        string
+	ContentType string
+
+	// In bytes, or ARTIFACT_SIZE_UNKNOWN
+	Size int64
+}
+
+// The size of each artifact is in the artifact list the server sends back, but isn't
+// part of the galasaapi.ArtifactIndexEntry structure, so gets picked out separately.
+// Some servers send the size as a number, and some as a string.
+type artifactIndexEntrySize struct {
+	Path string          `json:"path"`
+	Size json.RawMessage `json:"size"`
+}
+
+// ListArtifacts - performs all the logic to implement the `galasactl runs artifacts list` command,
+// but in a unit-testable manner.
+func ListArtifacts(
+	runName string,
+	timeService spi.TimeService,
+	console spi.Console,
+	commsClient api.APICommsClient,
+) error {
+	var err error
+	var run *galasaapi.Run
+	var artifacts []RunArtifact
+
+	log.Printf("ListArtifacts entered.")
+
+	err = ValidateRunName(runName)
+
+	if err == nil {
+		run, err = getLatestRunByName(runName, galasaErrors.GALASA_ERROR_LIST_ARTIFACTS_RUN_NOT_FOUND, timeService, commsClient)
+	}
+
+	if err == nil {
+		artifacts, err = GetArtifactsFromRestApi(run.GetRunId(), commsClient)
+	}
+
+	if err == nil {
+		err = writeOutput(FormatArtifactTree(artifacts), console)
+	}
+
+	log.Printf("ListArtifacts exiting. err is %v", err)
+	return err
+}
+
+// Retrieves the details of all artifacts for a given test run using its runId.
+func GetArtifactsFromRestApi(runId string, commsClient api.APICommsClient) ([]RunArtifact, error) {
+
+	var err error
+	var artifacts []RunArtifact
+	log.Println("Retrieving artifacts for the given run")
+
+	var restApiVersion string
+	restApiVersion, err = embedded.GetGalasactlRestApiVersion()
+
+	if err == nil {
+		err = commsClient.RunAuthenticatedCommandWithRateLimitRetries(func(apiClient *galasaapi.APIClient) error {
+			var err error
+			var httpResponse *http.Response
+			var artifactsList []galasaapi.ArtifactIndexEntry
+
+			artifacts = make([]RunArtifact, 0)
+
+			artifactsList, httpResponse, err = apiClient.ResultArchiveStoreAPIApi.
+				GetRasRunArtifactList(context.Background(), runId).
+				ClientApiVersion(restApiVersion).
+				Execute()
+
+			var statusCode int
+			if httpResponse != nil {
+				defer httpResponse.Body.Close()
+				statusCode = httpResponse.StatusCode
+			}
+
+			if err != nil {
+				err = galasaErrors.NewGalasaErrorWithHttpStatusCode(statusCode, galasaErrors.GALASA_ERROR_RETRIEVING_ARTIFACTS_FAILED, err.Error())
+			} else {
+				sizesByPath := getArtifactSizesFromResponse(httpResponse)
+				for _, artifactEntry := range artifactsList {
+					artifact := RunArtifact{
+						Path:        artifactEntry.GetPath(),
+						ContentType: artifactEntry.GetContentType(),
+						Size:        ARTIFACT_SIZE_UNKNOWN,
+					}
+					size, isSizeKnown := sizesByPath[artifact.Path]
+					if isSizeKnown {
+						artifact.Size = size
+					}
+					artifacts = append(artifacts, artifact)
+				}
+			}
+			log.Printf("%v artifact(s) found\n", len(artifacts))
+			return err
+		})
+	}
+
+	return artifacts, err
+}
+
+// The sizes are a nice-to-have, so any problem getting them is logged rather than
+// failing the whole command.
+func getArtifactSizesFromResponse(httpResponse *http.Response) map[string]int64 {
+	sizesByPath := make(map[string]int64)
+
+	if httpResponse != nil && httpResponse.Body != nil {
+		responseBody, err := io.ReadAll(httpResponse.Body)
+		if err == nil {
+			var entries []artifactIndexEntrySize
+			err = json.Unmarshal(responseBody, &entries)
+			if err == nil {
+				for _, entry := range entries {
+					sizeString := strings.Trim(string(entry.Size), "\"")
+					size, parseErr := strconv.ParseInt(sizeString, 10, 64)
+					if parseErr == nil && size >= 0 {
+						sizesByPath[entry.Path] = size
+					}
+				}
+			}
+		}
+
+		if err != nil {
+			log.Printf("Could not get the sizes of the artifacts from the server's response. Reason: %v\n", err)
+		}
+	}
+	return sizesByPath
+}
+
+// FormatArtifactTree renders the artifacts as a tree of folders and files, with the
+// total size of each folder, so the user can see where the bulk of a run's artifacts are.
+func FormatArtifactTree(artifacts []RunArtifact) string {
+	var table [][]string
+	buff := strings.Builder{}
+
+	sortedArtifacts := make([]RunArtifact, len(artifacts))
+	copy(sortedArtifacts, artifacts)
+	sort.Slice(sortedArtifacts, func(i, j int) bool {
+		return strings.TrimPrefix(sortedArtifacts[i].Path, "/") < strings.TrimPrefix(sortedArtifacts[j].Path, "/")
+	})
+
+	folderSizes := getArtifactFolderSizes(sortedArtifacts)
+
+	headers := []string{HEADER_ARTIFACT_PATH, HEADER_ARTIFACT_CONTENT_TYPE, HEADER_ARTIFACT_SIZE}
+	table = append(table, headers)
+
+	var totalSize int64
+	foldersShown := make(map[string]bool)
+	for _, artifact := range sortedArtifacts {
+		pathParts := strings.Split(strings.TrimPrefix(artifact.Path, "/"), "/")
+
+		// Show each folder the first time something inside it is shown.
+		folderPath := ""
+		for depth, folderName := range pathParts[:len(pathParts)-1] {
+			folderPath += folderName + "/"
+			if !foldersShown[folderPath] {
+				foldersShown[folderPath] = true
+				line := []string{
+					strings.Repeat("  ", depth) + folderName + "/",
+					"",
+					strconv.FormatInt(folderSizes[folderPath], 10),
+				}
+				table = append(table, line)
+			}
+		}
+
+		line := []string{
+			strings.Repeat("  ", len(pathParts)-1) + pathParts[len(pathParts)-1],
+			artifact.ContentType,
+			formatArtifactSize(artifact.Size),
+		}
+		table = append(table, line)
+
+		if artifact.Size != ARTIFACT_SIZE_UNKNOWN {
+			totalSize += artifact.Size
+		}
+	}
+
+	columnLengths := utils.CalculateMaxLengthOfEachColumn(table)
+	utils.WriteFormattedTableToStringBuilder(table, &buff, columnLengths)
+
+	buff.WriteString("\n")
+	buff.WriteString(fmt.Sprintf("Total:%d Size:%d\n", len(artifacts), totalSize))
+
+	return buff.String()
+}
+
+// The size of a folder is the total of the known sizes of everything in it, at any depth.
+func getArtifactFolderSizes(artifacts []RunArtifact) map[string]int64 {
+	folderSizes := make(map[string]int64)
+	for _, artifact := range artifacts {
+		if artifact.Size != ARTIFACT_SIZE_UNKNOWN {
+			pathParts := strings.Split(strings.TrimPrefix(artifact.Path, "/"), "/")
+			folderPath := ""
+			for _, folderName := range pathParts[:len(pathParts)-1] {
+				folderPath += folderName + "/"
+				folderSizes[folderPath] += artifact.Size
+			}
+		}
+	}
+	return folderSizes
+}
+
+func formatArtifactSize(size int64) string {
+	formattedSize := ""
+	if size != ARTIFACT_SIZE_UNKNOWN {
+		formattedSize = strconv.FormatInt(size, 10)
+	}
+	return formattedSize
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatArtifactTreeShowsFoldersWithTotalSizes(t *testing.T) {
+	// Given...
+	artifacts := []RunArtifact{
+		{Path: "/run.log", ContentType: "text/plain", Size: 203},
+		{Path: "/zos/jobs/SYSOUT.txt", ContentType: "text/plain", Size: 4096},
+		{Path: "/framework/cps_record.properties", ContentType: "text/plain", Size: 2048},
+		{Path: "/zos/jobs/JCL.gz", ContentType: "application/x-gzip", Size: ARTIFACT_SIZE_UNKNOWN},
+		{Path: "/zos/summary.txt", ContentType: "text/plain", Size: 10},
+	}
+
+	// When...
+	output := FormatArtifactTree(artifacts)
+
+	// Then...
+	expected := "path                    content-type       size\n" +
+		"framework/                                 2048\n" +
+		"  cps_record.properties text/plain         2048\n" +
+		"run.log                 text/plain         203\n" +
+		"zos/                                       4106\n" +
+		"  jobs/                                    4096\n" +
+		"    JCL.gz              application/x-gzip \n" +
+		"    SYSOUT.txt          text/plain         4096\n" +
+		"  summary.txt           text/plain         10\n" +
+		"\n" +
+		"Total:5 Size:6357\n"
+	assert.Equal(t, expected, output)
+}
+
+func TestFormatArtifactTreeWithNoArtifactsShowsTotal(t *testing.T) {
+	// When...
+	output := FormatArtifactTree([]RunArtifact{})
+
+	// Then...
+	assert.Equal(t, "path content-type size\n\nTotal:0 Size:0\n", output)
+}
+
+func TestListArtifactsWithBadRunNameReturnsError(t *testing.T) {
+	// Given...
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient("http://dummy.server")
+
+	// When...
+	err := ListArtifacts("not a run name", mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1075E")
+}
+
+func TestListArtifactsWithUnknownRunReturnsError(t *testing.T) {
+	// Given...
+	runName := "U27"
+
+	getRunsInteraction := utils.NewHttpInteraction("/ras/runs", http.MethodGet)
+	getRunsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		WriteMockRasRunsResponse(t, writer, req, runName, []string{})
+	}
+
+	server := utils.NewMockHttpServer(t, []utils.HttpInteraction{getRunsInteraction})
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := ListArtifacts(runName, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1254E")
+}
+
+func TestListArtifactsShowsSizesSentAsNumbersOrStrings(t *testing.T) {
+	// Given...
+	runName := "U27"
+	runId := "xxx987xxx"
+
+	getRunsInteraction := utils.NewHttpInteraction("/ras/runs", http.MethodGet)
+	getRunsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		WriteMockRasRunsResponse(t, writer, req, runName, []string{RUN_U27V2})
+	}
+
+	getArtifactsInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/artifacts", http.MethodGet)
+	getArtifactsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.Write([]byte(`[
+			{ "path": "/run.log", "contentType": "text/plain", "size": 203 },
+			{ "path": "/framework/cps_record.properties", "contentType": "text/plain", "size": "2048" },
+			{ "path": "/framework/mystery.bin", "contentType": "application/octet-stream" }
+		]`))
+	}
+
+	server := utils.NewMockHttpServer(t, []utils.HttpInteraction{getRunsInteraction, getArtifactsInteraction})
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := ListArtifacts(runName, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+	expected := "path                    content-type             size\n" +
+		"framework/                                       2048\n" +
+		"  cps_record.properties text/plain               2048\n" +
+		"  mystery.bin           application/octet-stream \n" +
+		"run.log                 text/plain               203\n" +
+		"\n" +
+		"Total:3 Size:2251\n"
+	assert.Equal(t, expected, mockConsole.ReadText())
+}
+
+func TestListArtifactsWhenServerFailsReturnsError(t *testing.T) {
+	// Given...
+	runName := "U27"
+	runId := "xxx987xxx"
+
+	getRunsInteraction := utils.NewHttpInteraction("/ras/runs", http.MethodGet)
+	getRunsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		WriteMockRasRunsResponse(t, writer, req, runName, []string{RUN_U27V2})
+	}
+
+	getArtifactsInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/artifacts", http.MethodGet)
+	getArtifactsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		writer.WriteHeader(http.StatusInternalServerError)
+	}
+
+	server := utils.NewMockHttpServer(t, []utils.HttpInteraction{getRunsInteraction, getArtifactsInteraction})
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := ListArtifacts(runName, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1073E")
+}
@@ -0,0 +1,462 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/embedded"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/props"
+	"github.com/galasa-dev/cli/pkg/runsformatter"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+)
+
+const (
+	COMPARE_FORMAT_SUMMARY = "summary"
+	COMPARE_FORMAT_JSON    = "json"
+
+	HEADER_COMPARE_FIELD    = "field"
+	HEADER_COMPARE_RUN_A    = "run-A"
+	HEADER_COMPARE_RUN_B    = "run-B"
+	HEADER_COMPARE_DIFFERS  = "differs"
+	HEADER_COMPARE_PROPERTY = "property"
+
+	// Shown in place of a value which one of the runs doesn't have.
+	COMPARE_VALUE_NOT_SET = "(not set)"
+	COMPARE_DIFFERS_MARK  = "*"
+
+	// What run-specific parts of the run logs are replaced with, so they don't show up as differences.
+	RUN_LOG_NORMALISED_TIMESTAMP = "<TIMESTAMP>"
+	RUN_LOG_NORMALISED_RUN_NAME  = "<RUN-NAME>"
+	RUN_LOG_NORMALISED_RUN_ID    = "<RUN-ID>"
+	RUN_LOG_NORMALISED_UUID      = "<UUID>"
+
+	// Diffing run logs takes time which grows with their length multiplied by how much they differ,
+	// so run logs with more lines than this between them aren't diffed.
+	MAX_RUN_LOG_LINES_TO_DIFF = 40000
+)
+
+var (
+	runLogTimestampPatterns = []*regexp.Regexp{
+		// eg: 2024-05-23T10:11:12.345Z
+		regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}([.,]\d+)?(Z|[+-]\d{2}:?\d{2})?`),
+		// eg: 23/05/2024 10:11:12.345
+		regexp.MustCompile(`\d{2}/\d{2}/\d{4} \d{2}:\d{2}:\d{2}([.,]\d+)?`),
+		// eg: 10:11:12.345
+		regexp.MustCompile(`\b\d{2}:\d{2}:\d{2}([.,]\d+)?\b`),
+	}
+
+	runLogUuidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+)
+
+// The result of comparing two test runs. Run A is the first run named, and run B the second.
+type RunComparison struct {
+	RunA           ComparedRun          `json:"runA"`
+	RunB           ComparedRun          `json:"runB"`
+	Methods        []MethodComparison   `json:"methods"`
+	CpsDifferences []PropertyDifference `json:"cpsDifferences"`
+	RunLogDiff     string               `json:"runLogDiff"`
+
+	// Set when the run logs were too long to be diffed, in which case the diff is blank.
+	IsRunLogDiffSkipped bool `json:"runLogDiffSkipped,omitempty"`
+	RunLogLineCountA    int  `json:"runLogLineCountA"`
+	RunLogLineCountB    int  `json:"runLogLineCountB"`
+}
+
+type ComparedRun struct {
+	Name           string `json:"name"`
+	RunId          string `json:"runId"`
+	Bundle         string `json:"bundle"`
+	TestName       string `json:"testName"`
+	Stream         string `json:"stream"`
+	Status         string `json:"status"`
+	Result         string `json:"result"`
+	DurationMillis *int64 `json:"durationMillis,omitempty"`
+}
+
+// How a test method went in each run. A run which didn't run the method has no outcome for it.
+type MethodComparison struct {
+	MethodName string         `json:"methodName"`
+	RunA       *MethodOutcome `json:"runA,omitempty"`
+	RunB       *MethodOutcome `json:"runB,omitempty"`
+}
+
+type MethodOutcome struct {
+	Result         string `json:"result"`
+	DurationMillis *int64 `json:"durationMillis,omitempty"`
+}
+
+// A CPS property which has a different value in each run. A run which didn't use the property has no value for it.
+type PropertyDifference struct {
+	Name   string  `json:"name"`
+	ValueA *string `json:"valueA,omitempty"`
+	ValueB *string `json:"valueB,omitempty"`
+}
+
+// Everything gathered about one of the runs being compared.
+type compareRunData struct {
+	run       galasaapi.Run
+	cpsRecord props.JavaProperties
+	runLog    string
+}
+
+// RunsCompare - performs all the logic to implement the `galasactl runs compare` command,
+// but in a unit-testable manner.
+func RunsCompare(
+	runNames []string,
+	outputFormat string,
+	timeService spi.TimeService,
+	console spi.Console,
+	commsClient api.APICommsClient,
+) error {
+	var err error
+	var restApiVersion string
+	var comparison *RunComparison
+	var output string
+
+	log.Printf("RunsCompare entered.")
+
+	if len(runNames) != 2 {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_COMPARE_NEEDS_TWO_RUNS, len(runNames))
+	}
+
+	for _, runName := range runNames {
+		if err == nil {
+			err = ValidateRunName(runName)
+		}
+	}
+
+	if err == nil && outputFormat != COMPARE_FORMAT_SUMMARY && outputFormat != COMPARE_FORMAT_JSON {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_OUTPUT_FORMAT, outputFormat, COMPARE_FORMAT_SUMMARY+", "+COMPARE_FORMAT_JSON)
+	}
+
+	if err == nil {
+		restApiVersion, err = embedded.GetGalasactlRestApiVersion()
+	}
+
+	if err == nil {
+		var runA *compareRunData
+		var runB *compareRunData
+		runA, err = getCompareRunData(runNames[0], restApiVersion, timeService, commsClient)
+		if err == nil {
+			runB, err = getCompareRunData(runNames[1], restApiVersion, timeService, commsClient)
+		}
+
+		if err == nil {
+			comparison = CompareRuns(runA.run, runA.cpsRecord, runA.runLog, runB.run, runB.cpsRecord, runB.runLog)
+		}
+	}
+
+	if err == nil {
+		if outputFormat == COMPARE_FORMAT_JSON {
+			var jsonBytes []byte
+			jsonBytes, err = json.MarshalIndent(comparison, "", "  ")
+			if err == nil {
+				output = string(jsonBytes) + "\n"
+			}
+		} else {
+			output = FormatRunComparison(comparison)
+		}
+	}
+
+	if err == nil {
+		err = console.WriteString(output)
+	}
+
+	log.Printf("RunsCompare exiting. err is %v", err)
+	return err
+}
+
+func getCompareRunData(
+	runName string,
+	restApiVersion string,
+	timeService spi.TimeService,
+	commsClient api.APICommsClient,
+) (*compareRunData, error) {
+	var err error
+	var run *galasaapi.Run
+	var data *compareRunData
+
+	run, err = getLatestRunByName(runName, galasaErrors.GALASA_ERROR_COMPARE_RUN_NOT_FOUND, timeService, commsClient)
+	if err == nil {
+		data = &compareRunData{run: *run}
+		data.runLog, err = getRunLogFromRestApi(run.GetRunId(), runName, commsClient, restApiVersion)
+		if err == nil {
+			data.cpsRecord = getCpsRecord(run.GetRunId(), commsClient)
+		}
+	}
+	return data, err
+}
+
+// CompareRuns works out how two test runs differ, given each run along with its CPS record and run log.
+func CompareRuns(
+	runA galasaapi.Run, cpsRecordA props.JavaProperties, runLogA string,
+	runB galasaapi.Run, cpsRecordB props.JavaProperties, runLogB string,
+) *RunComparison {
+	comparison := &RunComparison{
+		RunA:           newComparedRun(runA, cpsRecordA),
+		RunB:           newComparedRun(runB, cpsRecordB),
+		Methods:        compareMethods(runA.TestStructure.GetMethods(), runB.TestStructure.GetMethods()),
+		CpsDifferences: compareProperties(cpsRecordA, cpsRecordB),
+	}
+
+	linesA := NormaliseRunLog(runLogA, runA.TestStructure.GetRunName(), runA.GetRunId())
+	linesB := NormaliseRunLog(runLogB, runB.TestStructure.GetRunName(), runB.GetRunId())
+	comparison.RunLogLineCountA = len(linesA)
+	comparison.RunLogLineCountB = len(linesB)
+	if len(linesA)+len(linesB) > MAX_RUN_LOG_LINES_TO_DIFF {
+		comparison.IsRunLogDiffSkipped = true
+	} else {
+		comparison.RunLogDiff = utils.FormatUnifiedDiff(utils.DiffLines(linesA, linesB),
+			comparison.RunA.Name, comparison.RunB.Name, utils.DEFAULT_DIFF_CONTEXT_LINES)
+	}
+
+	return comparison
+}
+
+func newComparedRun(run galasaapi.Run, cpsRecord props.JavaProperties) ComparedRun {
+	testStructure := run.GetTestStructure()
+	stream, _ := getTestStreamFromCpsRecord(cpsRecord)
+	return ComparedRun{
+		Name:           testStructure.GetRunName(),
+		RunId:          run.GetRunId(),
+		Bundle:         testStructure.GetBundle(),
+		TestName:       testStructure.GetTestName(),
+		Stream:         stream,
+		Status:         testStructure.GetStatus(),
+		Result:         testStructure.GetResult(),
+		DurationMillis: getDurationMillis(testStructure.GetStartTime(), testStructure.GetEndTime()),
+	}
+}
+
+// Methods are listed in the order run A ran them, followed by any which only run B ran.
+func compareMethods(methodsA []galasaapi.TestMethod, methodsB []galasaapi.TestMethod) []MethodComparison {
+	comparisons := make([]MethodComparison, 0)
+	comparisonIndexes := make(map[string]int)
+
+	for _, method := range methodsA {
+		comparisonIndexes[method.GetMethodName()] = len(comparisons)
+		comparisons = append(comparisons, MethodComparison{MethodName: method.GetMethodName(), RunA: newMethodOutcome(method)})
+	}
+
+	for _, method := range methodsB {
+		index, isInRunA := comparisonIndexes[method.GetMethodName()]
+		if isInRunA {
+			comparisons[index].RunB = newMethodOutcome(method)
+		} else {
+			comparisons = append(comparisons, MethodComparison{MethodName: method.GetMethodName(), RunB: newMethodOutcome(method)})
+		}
+	}
+	return comparisons
+}
+
+func newMethodOutcome(method galasaapi.TestMethod) *MethodOutcome {
+	return &MethodOutcome{
+		Result:         method.GetResult(),
+		DurationMillis: getDurationMillis(method.GetStartTime(), method.GetEndTime()),
+	}
+}
+
+// Returns nil if the duration isn't known, for example because the method hasn't finished.
+func getDurationMillis(startTime string, endTime string) *int64 {
+	var durationMillis *int64
+	start, startErr := time.Parse(time.RFC3339, startTime)
+	end, endErr := time.Parse(time.RFC3339, endTime)
+	if startErr == nil && endErr == nil {
+		millis := end.Sub(start).Milliseconds()
+		durationMillis = &millis
+	}
+	return durationMillis
+}
+
+// Only the properties which differ are returned, sorted by name.
+func compareProperties(propertiesA props.JavaProperties, propertiesB props.JavaProperties) []PropertyDifference {
+	differences := make([]PropertyDifference, 0)
+
+	names := make([]string, 0, len(propertiesA)+len(propertiesB))
+	for name := range propertiesA {
+		names = append(names, name)
+	}
+	for name := range propertiesB {
+		if _, isInA := propertiesA[name]; !isInA {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		valueA, isInA := propertiesA[name]
+		valueB, isInB := propertiesB[name]
+		if isInA != isInB || valueA != valueB {
+			difference := PropertyDifference{Name: name}
+			if isInA {
+				difference.ValueA = &valueA
+			}
+			if isInB {
+				difference.ValueB = &valueB
+			}
+			differences = append(differences, difference)
+		}
+	}
+	return differences
+}
+
+// NormaliseRunLog splits a run log into lines, replacing the parts which are bound to differ from
+// one run to the next, such as timestamps and the run's own name and ID, so they don't hide the real differences.
+func NormaliseRunLog(runLog string, runName string, runId string) []string {
+	var runNamePattern *regexp.Regexp
+	if runName != "" {
+		runNamePattern = regexp.MustCompile(`\b` + regexp.QuoteMeta(runName) + `\b`)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(runLog, "\n"), "\n")
+	if runLog == "" {
+		lines = []string{}
+	}
+
+	for index, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if runId != "" {
+			line = strings.ReplaceAll(line, runId, RUN_LOG_NORMALISED_RUN_ID)
+		}
+		line = runLogUuidPattern.ReplaceAllString(line, RUN_LOG_NORMALISED_UUID)
+		for _, timestampPattern := range runLogTimestampPatterns {
+			line = timestampPattern.ReplaceAllString(line, RUN_LOG_NORMALISED_TIMESTAMP)
+		}
+		if runNamePattern != nil {
+			line = runNamePattern.ReplaceAllString(line, RUN_LOG_NORMALISED_RUN_NAME)
+		}
+		lines[index] = line
+	}
+	return lines
+}
+
+// FormatRunComparison renders a comparison as tables, followed by a unified diff of the run logs.
+func FormatRunComparison(comparison *RunComparison) string {
+	buff := strings.Builder{}
+
+	writeComparedRuns(comparison.RunA, comparison.RunB, &buff)
+
+	buff.WriteString("\n")
+	writeMethodComparisons(comparison.Methods, &buff)
+
+	buff.WriteString("\n")
+	writePropertyDifferences(comparison.CpsDifferences, &buff)
+
+	buff.WriteString("\n")
+	if comparison.IsRunLogDiffSkipped {
+		buff.WriteString(fmt.Sprintf("The run logs weren't compared, as they have %d and %d lines, which is more than the %d lines in total which can be compared.\n",
+			comparison.RunLogLineCountA, comparison.RunLogLineCountB, MAX_RUN_LOG_LINES_TO_DIFF))
+	} else if comparison.RunLogDiff == "" {
+		buff.WriteString("The run logs are the same, once timestamps and run IDs are ignored.\n")
+	} else {
+		buff.WriteString("Run log differences, ignoring timestamps and run IDs:\n")
+		buff.WriteString(comparison.RunLogDiff)
+	}
+
+	return buff.String()
+}
+
+func writeComparedRuns(runA ComparedRun, runB ComparedRun, buff *strings.Builder) {
+	table := [][]string{
+		{HEADER_COMPARE_FIELD, HEADER_COMPARE_RUN_A, HEADER_COMPARE_RUN_B, HEADER_COMPARE_DIFFERS},
+		{runsformatter.HEADER_RUNNAME, runA.Name, runB.Name, ""},
+		newCompareRow(runsformatter.HEADER_BUNDLE, runA.Bundle, runB.Bundle),
+		newCompareRow(runsformatter.HEADER_TEST_NAME, runA.TestName, runB.TestName),
+		newCompareRow("stream", runA.Stream, runB.Stream),
+		newCompareRow(runsformatter.HEADER_STATUS, runA.Status, runB.Status),
+		newCompareRow(runsformatter.HEADER_RESULT, runA.Result, runB.Result),
+		{runsformatter.HEADER_DURATION, formatDurationMillis(runA.DurationMillis), formatDurationMillis(runB.DurationMillis), ""},
+	}
+	columnLengths := utils.CalculateMaxLengthOfEachColumn(table)
+	utils.WriteFormattedTableToStringBuilder(table, buff, columnLengths)
+}
+
+func writeMethodComparisons(methods []MethodComparison, buff *strings.Builder) {
+	if len(methods) == 0 {
+		buff.WriteString("Neither run has any test methods.\n")
+	} else {
+		table := [][]string{{
+			runsformatter.HEADER_METHOD_NAME,
+			runsformatter.HEADER_RESULT + "-A",
+			runsformatter.HEADER_RESULT + "-B",
+			runsformatter.HEADER_DURATION + "-A",
+			runsformatter.HEADER_DURATION + "-B",
+			HEADER_COMPARE_DIFFERS,
+		}}
+
+		for _, method := range methods {
+			resultA, durationA := formatMethodOutcome(method.RunA)
+			resultB, durationB := formatMethodOutcome(method.RunB)
+			differs := ""
+			if resultA != resultB {
+				differs = COMPARE_DIFFERS_MARK
+			}
+			table = append(table, []string{method.MethodName, resultA, resultB, durationA, durationB, differs})
+		}
+
+		columnLengths := utils.CalculateMaxLengthOfEachColumn(table)
+		utils.WriteFormattedTableToStringBuilder(table, buff, columnLengths)
+	}
+}
+
+func writePropertyDifferences(differences []PropertyDifference, buff *strings.Builder) {
+	if len(differences) == 0 {
+		buff.WriteString("The CPS properties used by the runs are the same.\n")
+	} else {
+		table := [][]string{{HEADER_COMPARE_PROPERTY, HEADER_COMPARE_RUN_A, HEADER_COMPARE_RUN_B}}
+		for _, difference := range differences {
+			table = append(table, []string{difference.Name, formatPropertyValue(difference.ValueA), formatPropertyValue(difference.ValueB)})
+		}
+		columnLengths := utils.CalculateMaxLengthOfEachColumn(table)
+		utils.WriteFormattedTableToStringBuilder(table, buff, columnLengths)
+	}
+}
+
+func newCompareRow(field string, valueA string, valueB string) []string {
+	differs := ""
+	if valueA != valueB {
+		differs = COMPARE_DIFFERS_MARK
+	}
+	return []string{field, valueA, valueB, differs}
+}
+
+func formatMethodOutcome(outcome *MethodOutcome) (string, string) {
+	result := COMPARE_VALUE_NOT_SET
+	duration := ""
+	if outcome != nil {
+		result = outcome.Result
+		duration = formatDurationMillis(outcome.DurationMillis)
+	}
+	return result, duration
+}
+
+func formatDurationMillis(durationMillis *int64) string {
+	duration := ""
+	if durationMillis != nil {
+		duration = strconv.FormatInt(*durationMillis, 10)
+	}
+	return duration
+}
+
+func formatPropertyValue(value *string) string {
+	formattedValue := COMPARE_VALUE_NOT_SET
+	if value != nil {
+		formattedValue = *value
+	}
+	return formattedValue
+}
@@ -0,0 +1,298 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/props"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createMockTestMethod(methodName string, result string, startTime string, endTime string) galasaapi.TestMethod {
+	method := *galasaapi.NewTestMethod()
+	method.SetMethodName(methodName)
+	method.SetResult(result)
+	method.SetStartTime(startTime)
+	method.SetEndTime(endTime)
+	return method
+}
+
+func createMockRunToCompare(runName string, runId string, result string, methods []galasaapi.TestMethod) galasaapi.Run {
+	run := createMockRun(runName, runId)
+	run.TestStructure.SetStatus("finished")
+	run.TestStructure.SetResult(result)
+	run.TestStructure.SetBundle("dev.galasa.example")
+	run.TestStructure.SetTestName("dev.galasa.example.MyTest")
+	run.TestStructure.SetStartTime("2024-01-01T10:00:00Z")
+	run.TestStructure.SetEndTime("2024-01-01T10:00:05Z")
+	run.TestStructure.SetMethods(methods)
+	return run
+}
+
+func TestCompareRunsShowsMethodResultsAndDurations(t *testing.T) {
+	// Given...
+	runA := createMockRunToCompare("U1", "id-U1", "Passed", []galasaapi.TestMethod{
+		createMockTestMethod("testOne", "Passed", "2024-01-01T10:00:00Z", "2024-01-01T10:00:01Z"),
+		createMockTestMethod("testTwo", "Passed", "2024-01-01T10:00:01Z", "2024-01-01T10:00:03Z"),
+	})
+	runB := createMockRunToCompare("U2", "id-U2", "Failed", []galasaapi.TestMethod{
+		createMockTestMethod("testOne", "Passed", "2024-01-02T10:00:00Z", "2024-01-02T10:00:01Z"),
+		createMockTestMethod("testTwo", "Failed", "2024-01-02T10:00:01Z", "2024-01-02T10:00:05Z"),
+		createMockTestMethod("testThree", "Passed", "2024-01-02T10:00:05Z", ""),
+	})
+
+	// When...
+	comparison := CompareRuns(runA, props.JavaProperties{}, "", runB, props.JavaProperties{}, "")
+
+	// Then...
+	assert.Equal(t, 3, len(comparison.Methods))
+	assert.Equal(t, "testTwo", comparison.Methods[1].MethodName)
+	assert.Equal(t, "Passed", comparison.Methods[1].RunA.Result)
+	assert.Equal(t, int64(2000), *comparison.Methods[1].RunA.DurationMillis)
+	assert.Equal(t, "Failed", comparison.Methods[1].RunB.Result)
+	assert.Equal(t, int64(4000), *comparison.Methods[1].RunB.DurationMillis)
+
+	assert.Equal(t, "testThree", comparison.Methods[2].MethodName)
+	assert.Nil(t, comparison.Methods[2].RunA)
+	assert.Nil(t, comparison.Methods[2].RunB.DurationMillis)
+
+	assert.Equal(t, "Passed", comparison.RunA.Result)
+	assert.Equal(t, "Failed", comparison.RunB.Result)
+	assert.Equal(t, int64(5000), *comparison.RunA.DurationMillis)
+}
+
+func TestCompareRunsShowsOnlyCpsPropertiesWhichDiffer(t *testing.T) {
+	// Given...
+	runA := createMockRunToCompare("U1", "id-U1", "Passed", nil)
+	runB := createMockRunToCompare("U2", "id-U2", "Passed", nil)
+	cpsRecordA := props.JavaProperties{
+		"framework.test.stream.streamA.obr": "mvn:a/a/1/obr",
+		"zos.image.IMG1.ipv4.hostname":      "host1",
+		"same.property":                     "same",
+	}
+	cpsRecordB := props.JavaProperties{
+		"framework.test.stream.streamB.obr": "mvn:a/a/1/obr",
+		"zos.image.IMG1.ipv4.hostname":      "host2",
+		"same.property":                     "same",
+	}
+
+	// When...
+	comparison := CompareRuns(runA, cpsRecordA, "", runB, cpsRecordB, "")
+
+	// Then...
+	assert.Equal(t, "streamA", comparison.RunA.Stream)
+	assert.Equal(t, "streamB", comparison.RunB.Stream)
+
+	assert.Equal(t, 3, len(comparison.CpsDifferences))
+	assert.Equal(t, "framework.test.stream.streamA.obr", comparison.CpsDifferences[0].Name)
+	assert.Equal(t, "mvn:a/a/1/obr", *comparison.CpsDifferences[0].ValueA)
+	assert.Nil(t, comparison.CpsDifferences[0].ValueB)
+	assert.Equal(t, "framework.test.stream.streamB.obr", comparison.CpsDifferences[1].Name)
+	assert.Nil(t, comparison.CpsDifferences[1].ValueA)
+	assert.Equal(t, "zos.image.IMG1.ipv4.hostname", comparison.CpsDifferences[2].Name)
+	assert.Equal(t, "host1", *comparison.CpsDifferences[2].ValueA)
+	assert.Equal(t, "host2", *comparison.CpsDifferences[2].ValueB)
+}
+
+func TestNormaliseRunLogReplacesTimestampsAndRunSpecificIds(t *testing.T) {
+	// Given...
+	runLog := "01/01/2024 10:00:00.123 INFO Run U1 started, id run-id-1\n" +
+		"2024-01-01T10:00:01.5Z Allocated slot 0a1b2c3d-1111-2222-3333-444455556666\n" +
+		"Ran U12 as well\n"
+
+	// When...
+	lines := NormaliseRunLog(runLog, "U1", "run-id-1")
+
+	// Then...
+	assert.Equal(t, []string{
+		"<TIMESTAMP> INFO Run <RUN-NAME> started, id <RUN-ID>",
+		"<TIMESTAMP> Allocated slot <UUID>",
+		"Ran U12 as well",
+	}, lines)
+}
+
+func TestCompareRunsOnlyDiffsRealRunLogDifferences(t *testing.T) {
+	// Given...
+	runA := createMockRunToCompare("U1", "id-U1", "Passed", nil)
+	runB := createMockRunToCompare("U2", "id-U2", "Failed", nil)
+	runLogA := "10:00:00 Run U1 started\n10:00:01 Connected\n10:00:02 Test passed\n"
+	runLogB := "11:30:00 Run U2 started\n11:30:01 Connected\n11:30:09 Test failed\n"
+
+	// When...
+	comparison := CompareRuns(runA, props.JavaProperties{}, runLogA, runB, props.JavaProperties{}, runLogB)
+
+	// Then...
+	assert.Equal(t, "--- U1\n"+
+		"+++ U2\n"+
+		"@@ -1,3 +1,3 @@\n"+
+		" <TIMESTAMP> Run <RUN-NAME> started\n"+
+		" <TIMESTAMP> Connected\n"+
+		"-<TIMESTAMP> Test passed\n"+
+		"+<TIMESTAMP> Test failed\n", comparison.RunLogDiff)
+}
+
+func TestCompareRunsWithVeryLongRunLogsSkipsTheRunLogDiff(t *testing.T) {
+	// Given...
+	runA := createMockRunToCompare("U1", "id-U1", "Passed", nil)
+	runB := createMockRunToCompare("U2", "id-U2", "Failed", nil)
+	runLogA := strings.Repeat("Connected to the first system\n", 25000)
+	runLogB := strings.Repeat("Connected to the second system\n", 25000)
+
+	// When...
+	comparison := CompareRuns(runA, props.JavaProperties{}, runLogA, runB, props.JavaProperties{}, runLogB)
+
+	// Then...
+	assert.True(t, comparison.IsRunLogDiffSkipped)
+	assert.Equal(t, "", comparison.RunLogDiff)
+	assert.Contains(t, FormatRunComparison(comparison),
+		"The run logs weren't compared, as they have 25000 and 25000 lines, which is more than the 40000 lines in total which can be compared.")
+}
+
+func TestFormatRunComparisonShowsTablesAndRunLogDiff(t *testing.T) {
+	// Given...
+	runA := createMockRunToCompare("U1", "id-U1", "Passed", []galasaapi.TestMethod{
+		createMockTestMethod("testOne", "Passed", "2024-01-01T10:00:00Z", "2024-01-01T10:00:01Z"),
+	})
+	runB := createMockRunToCompare("U2", "id-U2", "Failed", []galasaapi.TestMethod{
+		createMockTestMethod("testOne", "Failed", "2024-01-01T10:00:00Z", "2024-01-01T10:00:02Z"),
+	})
+	cpsRecordA := props.JavaProperties{"my.property": "a"}
+
+	comparison := CompareRuns(runA, cpsRecordA, "line\n", runB, props.JavaProperties{}, "other line\n")
+
+	// When...
+	output := FormatRunComparison(comparison)
+
+	// Then...
+	assert.Equal(t, "field        run-A                     run-B                     differs\n"+
+		"name         U1                        U2                        \n"+
+		"bundle       dev.galasa.example        dev.galasa.example        \n"+
+		"test-name    dev.galasa.example.MyTest dev.galasa.example.MyTest \n"+
+		"stream                                                           \n"+
+		"status       finished                  finished                  \n"+
+		"result       Passed                    Failed                    *\n"+
+		"duration(ms) 5000                      5000                      \n"+
+		"\n"+
+		"method  result-A result-B duration(ms)-A duration(ms)-B differs\n"+
+		"testOne Passed   Failed   1000           2000           *\n"+
+		"\n"+
+		"property    run-A run-B\n"+
+		"my.property a     (not set)\n"+
+		"\n"+
+		"Run log differences, ignoring timestamps and run IDs:\n"+
+		"--- U1\n"+
+		"+++ U2\n"+
+		"@@ -1,1 +1,1 @@\n"+
+		"-line\n"+
+		"+other line\n", output)
+}
+
+func newCompareRunInteractions(t *testing.T, run galasaapi.Run, runLog string) []utils.HttpInteraction {
+	runBytes, _ := json.Marshal(run)
+	runName := run.TestStructure.GetRunName()
+
+	getRunsInteraction := utils.NewHttpInteraction("/ras/runs", http.MethodGet)
+	getRunsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		WriteMockRasRunsResponse(t, writer, req, runName, []string{string(runBytes)})
+	}
+
+	getRunLogInteraction := utils.NewHttpInteraction("/ras/runs/"+run.GetRunId()+"/runlog", http.MethodGet)
+	getRunLogInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		writer.Header().Set("Content-Type", "text/plain")
+		writer.Write([]byte(runLog))
+	}
+
+	return []utils.HttpInteraction{
+		getRunsInteraction,
+		getRunLogInteraction,
+		newGetCpsRecordInteraction(run.GetRunId(), "my.property="+runName+"\n"),
+	}
+}
+
+func TestRunsCompareWithJsonFormatWritesComparisonAsJson(t *testing.T) {
+	// Given...
+	runA := createMockRunToCompare("U1", "id-U1", "Passed", nil)
+	runB := createMockRunToCompare("U2", "id-U2", "Passed", nil)
+
+	interactions := newCompareRunInteractions(t, runA, "same\n")
+	interactions = append(interactions, newCompareRunInteractions(t, runB, "same\n")...)
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	console := utils.NewMockConsole()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := RunsCompare([]string{"U1", "U2"}, "json", utils.NewMockTimeService(), console, commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+
+	var comparison RunComparison
+	err = json.Unmarshal([]byte(console.ReadText()), &comparison)
+	assert.Nil(t, err)
+	assert.Equal(t, "U1", comparison.RunA.Name)
+	assert.Equal(t, "U2", comparison.RunB.Name)
+	assert.Equal(t, "", comparison.RunLogDiff)
+	assert.Equal(t, 1, len(comparison.CpsDifferences))
+	assert.Equal(t, "U1", *comparison.CpsDifferences[0].ValueA)
+	assert.Equal(t, "U2", *comparison.CpsDifferences[0].ValueB)
+}
+
+func TestRunsCompareWithOneRunReturnsError(t *testing.T) {
+	// Given...
+	console := utils.NewMockConsole()
+	commsClient := api.NewMockAPICommsClient("http://dummy.server")
+
+	// When...
+	err := RunsCompare([]string{"U1"}, "summary", utils.NewMockTimeService(), console, commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1272E: Two test runs are needed for a comparison, but 1 were given.")
+}
+
+func TestRunsCompareWithBadFormatReturnsError(t *testing.T) {
+	// Given...
+	console := utils.NewMockConsole()
+	commsClient := api.NewMockAPICommsClient("http://dummy.server")
+
+	// When...
+	err := RunsCompare([]string{"U1", "U2"}, "yaml", utils.NewMockTimeService(), console, commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1067E: Unsupported value 'yaml' for parameter --format. Supported values are: summary, json.")
+}
+
+func TestRunsCompareWithUnknownRunReturnsError(t *testing.T) {
+	// Given...
+	getRunsInteraction := utils.NewHttpInteraction("/ras/runs", http.MethodGet)
+	getRunsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		WriteMockRasRunsResponse(t, writer, req, "U1", []string{})
+	}
+
+	server := utils.NewMockHttpServer(t, []utils.HttpInteraction{getRunsInteraction})
+	defer server.Server.Close()
+
+	console := utils.NewMockConsole()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := RunsCompare([]string{"U1", "U2"}, "summary", utils.NewMockTimeService(), console, commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1273E: The run named 'U1' could not be compared because it was not found")
+}
@@ -0,0 +1,278 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/embedded"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/runsformatter"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+)
+
+const (
+	// The number of test runs deleted at the same time, unless the user says otherwise.
+	DEFAULT_DELETE_PARALLEL_COUNT = 4
+
+	HEADER_DELETE_STATUS = "status"
+	HEADER_DELETE_REASON = "reason"
+
+	DELETE_STATUS_DELETED = "deleted"
+	DELETE_STATUS_FAILED  = "failed"
+)
+
+// A single test run to delete as part of a bulk delete, and how it went.
+type runDeleteJob struct {
+	run galasaapi.Run
+	err error
+}
+
+// RunsDeleteByQuery - performs all the logic to implement the `galasactl runs delete`
+// command when test runs are selected by a query rather than by name, but in a unit-testable manner.
+//
+// Only finished test runs are deleted. Runs which are still queued or running are skipped.
+// Unless isConfirmed is set, the user is asked to confirm the deletion before anything is deleted.
+// A failure to delete one test run does not stop the others being deleted. A summary of
+// which runs were deleted and which failed is written out at the end.
+func RunsDeleteByQuery(
+	group string,
+	requestor string,
+	result string,
+	age string,
+	parallelCount int,
+	isDryRun bool,
+	isConfirmed bool,
+	console spi.Console,
+	stdIn spi.StdIn,
+	commsClient api.APICommsClient,
+	timeService spi.TimeService,
+	byteReader spi.ByteReader,
+) error {
+	var err error
+	var fromAge int
+	var toAge int
+	var runs []galasaapi.Run
+	var activeRunCount int
+
+	log.Printf("RunsDeleteByQuery entered.")
+
+	if age == "" && group == "" {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_NO_TEST_RUN_IDENTIFIER_FLAG_SPECIFIED)
+	}
+
+	if err == nil && parallelCount < 1 {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_DELETE_PARALLEL_COUNT, parallelCount)
+	}
+
+	if err == nil && age != "" {
+		fromAge, toAge, err = getTimesFromAge(age)
+	}
+
+	if err == nil && group != "" {
+		group, err = validateGroupname(group)
+	}
+
+	if err == nil && result != "" {
+		result, err = ValidateResultParameter(result, commsClient)
+	}
+
+	if err == nil {
+		runName := ""
+		shouldGetActive := false
+		runs, err = GetRunsFromRestApi(runName, requestor, result, fromAge, toAge, shouldGetActive, timeService, commsClient, group)
+	}
+
+	if err == nil {
+		runs, activeRunCount = removeActiveRuns(runs)
+		if activeRunCount > 0 {
+			err = console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_BULK_DELETE_ACTIVE_SKIPPED.Template, activeRunCount))
+		}
+	}
+
+	if err == nil {
+		if len(runs) == 0 {
+			err = console.WriteString(galasaErrors.GALASA_INFO_BULK_DELETE_NO_RUNS.Template)
+		} else if isDryRun {
+			err = console.WriteString(formatRunsToDelete(runs))
+			if err == nil {
+				err = console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_BULK_DELETE_DRY_RUN.Template, len(runs)))
+			}
+		} else {
+			isDeleteWanted := isConfirmed
+			if !isDeleteWanted {
+				isDeleteWanted, err = confirmRunsDelete(len(runs), console, stdIn)
+			}
+
+			if err == nil {
+				if !isDeleteWanted {
+					err = console.WriteString(galasaErrors.GALASA_INFO_BULK_DELETE_NOT_CONFIRMED.Template)
+				} else {
+					err = deleteRunsInParallel(runs, activeRunCount, parallelCount, console, commsClient, byteReader)
+				}
+			}
+		}
+	}
+
+	log.Printf("RunsDeleteByQuery exiting. err is %v", err)
+	return err
+}
+
+// The query can't select only finished runs, so the runs which are still queued or running are dropped here,
+// so that the records of runs which are still writing to the RAS are not deleted from under them.
+func removeActiveRuns(runs []galasaapi.Run) ([]galasaapi.Run, int) {
+	finishedRuns := make([]galasaapi.Run, 0, len(runs))
+	activeRunCount := 0
+	for _, run := range runs {
+		testStructure := run.GetTestStructure()
+		if testStructure.GetStatus() == RUN_STATUS_FINISHED {
+			finishedRuns = append(finishedRuns, run)
+		} else {
+			log.Printf("Run '%s' has status '%s', so will not be deleted.\n", testStructure.GetRunName(), testStructure.GetStatus())
+			activeRunCount++
+		}
+	}
+	return finishedRuns, activeRunCount
+}
+
+// Asks the user whether the runs should really be deleted. Anything other than 'y' or 'yes' means no.
+func confirmRunsDelete(runCount int, console spi.Console, stdIn spi.StdIn) (bool, error) {
+	var err error
+	var answer string
+	isDeleteWanted := false
+
+	err = console.WriteString(fmt.Sprintf("Delete %d test runs? This cannot be undone. [y/N]: ", runCount))
+	if err == nil {
+		answer, err = stdIn.ReadLine()
+		if err != nil {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_DELETE_CONFIRMATION_NOT_READ, runCount, err.Error())
+		} else {
+			answer = strings.ToLower(strings.TrimSpace(answer))
+			isDeleteWanted = (answer == "y" || answer == "yes")
+		}
+	}
+	log.Printf("Was the delete of %d runs confirmed ? %v\n", runCount, isDeleteWanted)
+	return isDeleteWanted, err
+}
+
+// Deletes the runs using a pool of workers, then writes a summary of how it went.
+func deleteRunsInParallel(
+	runs []galasaapi.Run,
+	activeRunCount int,
+	parallelCount int,
+	console spi.Console,
+	commsClient api.APICommsClient,
+	byteReader spi.ByteReader,
+) error {
+	var err error
+	var restApiVersion string
+
+	restApiVersion, err = embedded.GetGalasactlRestApiVersion()
+	if err == nil {
+		jobs := make([]*runDeleteJob, 0, len(runs))
+		jobQueue := make(chan *runDeleteJob, len(runs))
+		for _, run := range runs {
+			job := &runDeleteJob{run: run}
+			jobs = append(jobs, job)
+			jobQueue <- job
+		}
+		close(jobQueue)
+
+		syncConsole := &synchronizedConsole{console: console}
+		syncConsole.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_BULK_DELETE_STARTING.Template, len(jobs), parallelCount))
+
+		var progressMutex sync.Mutex
+		doneCount := 0
+		failedCount := 0
+
+		var workers sync.WaitGroup
+		for i := 0; i < parallelCount; i++ {
+			workers.Add(1)
+			go func() {
+				defer workers.Done()
+				for job := range jobQueue {
+					job.err = deleteRun(job.run, commsClient, byteReader, restApiVersion)
+					if job.err != nil {
+						log.Printf("Failed to delete run '%s'. Reason: %v\n", job.run.TestStructure.GetRunName(), job.err)
+					}
+
+					progressMutex.Lock()
+					doneCount++
+					if job.err != nil {
+						failedCount++
+					}
+					syncConsole.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_BULK_DELETE_PROGRESS.Template, doneCount, len(jobs), failedCount))
+					progressMutex.Unlock()
+				}
+			}()
+		}
+		workers.Wait()
+
+		err = console.WriteString(formatRunDeleteSummary(jobs, activeRunCount))
+		if err == nil && failedCount > 0 {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_BULK_DELETE_FAILED, failedCount, len(jobs))
+		}
+	}
+	return err
+}
+
+// formatRunsToDelete renders a table of the runs which a delete would remove.
+func formatRunsToDelete(runs []galasaapi.Run) string {
+	var table [][]string
+	buff := strings.Builder{}
+
+	headers := []string{runsformatter.HEADER_RUNNAME, runsformatter.HEADER_REQUESTOR, runsformatter.HEADER_RESULT, runsformatter.HEADER_TEST_NAME}
+	table = append(table, headers)
+
+	for _, run := range runs {
+		testStructure := run.GetTestStructure()
+		line := []string{testStructure.GetRunName(), testStructure.GetRequestor(), testStructure.GetResult(), testStructure.GetTestName()}
+		table = append(table, line)
+	}
+
+	columnLengths := utils.CalculateMaxLengthOfEachColumn(table)
+	utils.WriteFormattedTableToStringBuilder(table, &buff, columnLengths)
+	buff.WriteString("\n")
+
+	return buff.String()
+}
+
+// formatRunDeleteSummary renders a table of which runs were deleted and which failed, and why,
+// and how many active runs were skipped.
+func formatRunDeleteSummary(jobs []*runDeleteJob, activeRunCount int) string {
+	var table [][]string
+	buff := strings.Builder{}
+
+	headers := []string{runsformatter.HEADER_RUNNAME, HEADER_DELETE_STATUS, HEADER_DELETE_REASON}
+	table = append(table, headers)
+
+	failedCount := 0
+	for _, job := range jobs {
+		status := DELETE_STATUS_DELETED
+		reason := ""
+		if job.err != nil {
+			status = DELETE_STATUS_FAILED
+			reason = job.err.Error()
+			failedCount++
+		}
+		line := []string{job.run.TestStructure.GetRunName(), status, reason}
+		table = append(table, line)
+	}
+
+	columnLengths := utils.CalculateMaxLengthOfEachColumn(table)
+	utils.WriteFormattedTableToStringBuilder(table, &buff, columnLengths)
+
+	buff.WriteString("\n")
+	buff.WriteString(fmt.Sprintf("Total:%d Deleted:%d Failed:%d SkippedActive:%d\n", len(jobs), len(jobs)-failedCount, failedCount, activeRunCount))
+
+	return buff.String()
+}
@@ -0,0 +1,341 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createMockOldRunJson(runName string, runId string) string {
+	return createMockRunJsonWithStatus(runName, runId, RUN_STATUS_FINISHED)
+}
+
+func createMockRunJsonWithStatus(runName string, runId string, status string) string {
+	run := createMockRun(runName, runId)
+	run.TestStructure.SetStatus(status)
+	run.TestStructure.SetResult("Passed")
+	run.TestStructure.SetRequestor("myuser")
+	run.TestStructure.SetTestName("dev.galasa.example.MyTest")
+	runBytes, _ := json.Marshal(run)
+	return string(runBytes)
+}
+
+func newGetRunsByQueryInteraction(t *testing.T, runJsons []string) utils.HttpInteraction {
+	getRunsInteraction := utils.NewHttpInteraction("/ras/runs", http.MethodGet)
+	getRunsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "myuser", req.URL.Query().Get("requestor"))
+		assert.NotEmpty(t, req.URL.Query().Get("from"))
+		WriteMockRasRunsResponse(t, writer, req, "", runJsons)
+	}
+	return getRunsInteraction
+}
+
+// Runs listed in failingRunIds fail to be deleted.
+func newRunsDeleteByQueryTestInteractions(t *testing.T, runIds []string, runJsons []string, failingRunIds []string) []utils.HttpInteraction {
+	interactions := []utils.HttpInteraction{newGetRunsByQueryInteraction(t, runJsons)}
+
+	for _, runId := range runIds {
+		isFailing := false
+		for _, failingRunId := range failingRunIds {
+			isFailing = isFailing || failingRunId == runId
+		}
+
+		deleteRunInteraction := utils.NewHttpInteraction("/ras/runs/"+runId, http.MethodDelete)
+		deleteRunInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+			if isFailing {
+				writer.WriteHeader(http.StatusInternalServerError)
+			} else {
+				writer.WriteHeader(http.StatusNoContent)
+			}
+		}
+		interactions = append(interactions, deleteRunInteraction)
+	}
+	return interactions
+}
+
+func TestRunsDeleteByQueryWithYesDeletesEveryRunInParallel(t *testing.T) {
+	// Given...
+	runIds := []string{"id-U1", "id-U2", "id-U3"}
+	runJsons := []string{
+		createMockOldRunJson("U1", "id-U1"),
+		createMockOldRunJson("U2", "id-U2"),
+		createMockOldRunJson("U3", "id-U3"),
+	}
+	interactions := newRunsDeleteByQueryTestInteractions(t, runIds, runJsons, []string{})
+
+	// The runs are deleted at the same time, so the order of requests can't be relied on.
+	server := utils.NewMockHttpServerWithUnorderedInteractions(t, interactions)
+	defer server.Server.Close()
+
+	console := utils.NewMockConsole()
+	stdIn := utils.NewMockStdIn()
+	mockTimeService := utils.NewMockTimeService()
+	mockByteReader := utils.NewMockByteReader()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := RunsDeleteByQuery("", "myuser", "", "30d", 2, false, true, console, stdIn, commsClient, mockTimeService, mockByteReader)
+
+	// Then...
+	assert.Nil(t, err)
+
+	output := console.ReadText()
+	assert.NotContains(t, output, "[y/N]")
+	assert.Contains(t, output, "GAL2516I: Deleting 3 test runs, 2 at a time.")
+	assert.Contains(t, output, "GAL2517I: Progress: 3 of 3 test runs done, 0 failed.")
+	assert.Contains(t, output, "name status  reason\n"+
+		"U1   deleted \n"+
+		"U2   deleted \n"+
+		"U3   deleted \n"+
+		"\n"+
+		"Total:3 Deleted:3 Failed:0 SkippedActive:0\n")
+}
+
+func TestRunsDeleteByQueryOnlyDeletesFinishedRuns(t *testing.T) {
+	// Given...
+	runJsons := []string{
+		createMockOldRunJson("U1", "id-U1"),
+		createMockRunJsonWithStatus("U2", "id-U2", "queued"),
+		createMockOldRunJson("U3", "id-U3"),
+		createMockRunJsonWithStatus("U4", "id-U4", "running"),
+	}
+
+	// Only the finished runs have a delete interaction, so deleting an active run fails the test.
+	interactions := newRunsDeleteByQueryTestInteractions(t, []string{"id-U1", "id-U3"}, runJsons, []string{})
+
+	server := utils.NewMockHttpServerWithUnorderedInteractions(t, interactions)
+	defer server.Server.Close()
+
+	console := utils.NewMockConsole()
+	stdIn := utils.NewMockStdIn()
+	mockTimeService := utils.NewMockTimeService()
+	mockByteReader := utils.NewMockByteReader()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := RunsDeleteByQuery("", "myuser", "", "30d", 2, false, true, console, stdIn, commsClient, mockTimeService, mockByteReader)
+
+	// Then...
+	assert.Nil(t, err)
+
+	output := console.ReadText()
+	assert.Contains(t, output, "GAL2535I: 2 test runs which matched the query are still queued or running, so will not be deleted.")
+	assert.Contains(t, output, "GAL2516I: Deleting 2 test runs, 2 at a time.")
+	assert.Contains(t, output, "name status  reason\n"+
+		"U1   deleted \n"+
+		"U3   deleted \n"+
+		"\n"+
+		"Total:2 Deleted:2 Failed:0 SkippedActive:2\n")
+}
+
+func TestRunsDeleteByQueryDryRunDoesNotListActiveRuns(t *testing.T) {
+	// Given...
+	runJsons := []string{createMockOldRunJson("U1", "id-U1"), createMockRunJsonWithStatus("U2", "id-U2", "running")}
+	interactions := []utils.HttpInteraction{newGetRunsByQueryInteraction(t, runJsons)}
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	console := utils.NewMockConsole()
+	stdIn := utils.NewMockStdIn()
+	mockTimeService := utils.NewMockTimeService()
+	mockByteReader := utils.NewMockByteReader()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := RunsDeleteByQuery("", "myuser", "", "30d", 4, true, false, console, stdIn, commsClient, mockTimeService, mockByteReader)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "GAL2535I: 1 test runs which matched the query are still queued or running, so will not be deleted.\n"+
+		"name requestor result test-name\n"+
+		"U1   myuser    Passed dev.galasa.example.MyTest\n"+
+		"\n"+
+		"GAL2514I: 1 test runs would be deleted. Nothing was deleted, because --dry-run was used.\n", console.ReadText())
+}
+
+func TestRunsDeleteByQueryCarriesOnAfterAFailureAndReportsIt(t *testing.T) {
+	// Given...
+	runIds := []string{"id-U1", "id-U2"}
+	runJsons := []string{
+		createMockOldRunJson("U1", "id-U1"),
+		createMockOldRunJson("U2", "id-U2"),
+	}
+	interactions := newRunsDeleteByQueryTestInteractions(t, runIds, runJsons, []string{"id-U1"})
+
+	server := utils.NewMockHttpServerWithUnorderedInteractions(t, interactions)
+	defer server.Server.Close()
+
+	console := utils.NewMockConsole()
+	stdIn := utils.NewMockStdIn()
+	mockTimeService := utils.NewMockTimeService()
+	mockByteReader := utils.NewMockByteReader()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := RunsDeleteByQuery("", "myuser", "", "30d", 1, false, true, console, stdIn, commsClient, mockTimeService, mockByteReader)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1262E: Failed to delete 1 out of 2 test runs.")
+
+	output := console.ReadText()
+	assert.Contains(t, output, "U1   failed  GAL1159E")
+	assert.Contains(t, output, "U2   deleted \n")
+	assert.Contains(t, output, "Total:2 Deleted:1 Failed:1 SkippedActive:0\n")
+}
+
+func TestRunsDeleteByQueryAsksForConfirmationAndDeletesWhenUserSaysYes(t *testing.T) {
+	// Given...
+	runIds := []string{"id-U1"}
+	runJsons := []string{createMockOldRunJson("U1", "id-U1")}
+	interactions := newRunsDeleteByQueryTestInteractions(t, runIds, runJsons, []string{})
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	console := utils.NewMockConsole()
+	stdIn := utils.NewMockStdIn(" Yes ")
+	mockTimeService := utils.NewMockTimeService()
+	mockByteReader := utils.NewMockByteReader()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := RunsDeleteByQuery("", "myuser", "", "30d", 4, false, false, console, stdIn, commsClient, mockTimeService, mockByteReader)
+
+	// Then...
+	assert.Nil(t, err)
+
+	output := console.ReadText()
+	assert.Contains(t, output, "Delete 1 test runs? This cannot be undone. [y/N]: ")
+	assert.Contains(t, output, "Total:1 Deleted:1 Failed:0 SkippedActive:0\n")
+}
+
+func TestRunsDeleteByQueryDeletesNothingWhenUserSaysNo(t *testing.T) {
+	// Given...
+	runJsons := []string{createMockOldRunJson("U1", "id-U1")}
+
+	// The mock server fails the test if any run is deleted.
+	interactions := []utils.HttpInteraction{newGetRunsByQueryInteraction(t, runJsons)}
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	console := utils.NewMockConsole()
+	stdIn := utils.NewMockStdIn("")
+	mockTimeService := utils.NewMockTimeService()
+	mockByteReader := utils.NewMockByteReader()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := RunsDeleteByQuery("", "myuser", "", "30d", 4, false, false, console, stdIn, commsClient, mockTimeService, mockByteReader)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "Delete 1 test runs? This cannot be undone. [y/N]: GAL2515I: Nothing was deleted.\n", console.ReadText())
+}
+
+func TestRunsDeleteByQueryWithNoAnswerAvailableReturnsError(t *testing.T) {
+	// Given...
+	runJsons := []string{createMockOldRunJson("U1", "id-U1"), createMockOldRunJson("U2", "id-U2")}
+	interactions := []utils.HttpInteraction{newGetRunsByQueryInteraction(t, runJsons)}
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	console := utils.NewMockConsole()
+	// Nothing to read, as when stdin is not a terminal.
+	stdIn := utils.NewMockStdIn()
+	mockTimeService := utils.NewMockTimeService()
+	mockByteReader := utils.NewMockByteReader()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := RunsDeleteByQuery("", "myuser", "", "30d", 4, false, false, console, stdIn, commsClient, mockTimeService, mockByteReader)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1263E: Could not read the answer to whether 2 test runs should be deleted, so nothing was deleted. Use the '--yes' flag")
+}
+
+func TestRunsDeleteByQueryDryRunListsRunsWithoutDeletingThem(t *testing.T) {
+	// Given...
+	runJsons := []string{createMockOldRunJson("U1", "id-U1"), createMockOldRunJson("U22", "id-U22")}
+	interactions := []utils.HttpInteraction{newGetRunsByQueryInteraction(t, runJsons)}
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	console := utils.NewMockConsole()
+	stdIn := utils.NewMockStdIn()
+	mockTimeService := utils.NewMockTimeService()
+	mockByteReader := utils.NewMockByteReader()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := RunsDeleteByQuery("", "myuser", "", "30d", 4, true, false, console, stdIn, commsClient, mockTimeService, mockByteReader)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "name requestor result test-name\n"+
+		"U1   myuser    Passed dev.galasa.example.MyTest\n"+
+		"U22  myuser    Passed dev.galasa.example.MyTest\n"+
+		"\n"+
+		"GAL2514I: 2 test runs would be deleted. Nothing was deleted, because --dry-run was used.\n", console.ReadText())
+}
+
+func TestRunsDeleteByQueryWithNoMatchingRunsSaysSo(t *testing.T) {
+	// Given...
+	interactions := []utils.HttpInteraction{newGetRunsByQueryInteraction(t, []string{})}
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	console := utils.NewMockConsole()
+	stdIn := utils.NewMockStdIn()
+	mockTimeService := utils.NewMockTimeService()
+	mockByteReader := utils.NewMockByteReader()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := RunsDeleteByQuery("", "myuser", "", "30d", 4, false, false, console, stdIn, commsClient, mockTimeService, mockByteReader)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "GAL2513I: No test runs matched the query, so there is nothing to delete.\n", console.ReadText())
+}
+
+func TestRunsDeleteByQueryWithoutAgeOrGroupReturnsError(t *testing.T) {
+	// Given...
+	console := utils.NewMockConsole()
+	commsClient := api.NewMockAPICommsClient("http://dummy.server")
+
+	// When...
+	err := RunsDeleteByQuery("", "myuser", "", "", 4, false, true, console, utils.NewMockStdIn(), commsClient, utils.NewMockTimeService(), utils.NewMockByteReader())
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1079E")
+}
+
+func TestRunsDeleteByQueryWithBadParallelCountReturnsError(t *testing.T) {
+	// Given...
+	console := utils.NewMockConsole()
+	commsClient := api.NewMockAPICommsClient("http://dummy.server")
+
+	// When...
+	err := RunsDeleteByQuery("", "myuser", "", "30d", 0, false, true, console, utils.NewMockStdIn(), commsClient, utils.NewMockTimeService(), utils.NewMockByteReader())
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1261E: Invalid '--parallel' value '0' provided.")
+}
@@ -20,8 +20,8 @@ import (
 	"github.com/galasa-dev/cli/pkg/api"
 	"github.com/galasa-dev/cli/pkg/embedded"
 	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/files"
 	"github.com/galasa-dev/cli/pkg/galasaapi"
-	"github.com/galasa-dev/cli/pkg/images"
 	"github.com/galasa-dev/cli/pkg/spi"
 )
 
@@ -30,19 +30,29 @@ import (
 func DownloadArtifacts(
 	runName string,
 	forceDownload bool,
+	isIncremental bool,
 	fileSystem spi.FileSystem,
 	timeService spi.TimeService,
 	console spi.Console,
 	commsClient api.APICommsClient,
 	runDownloadTargetFolder string,
+	artifactFilter *ArtifactFilter,
+	renderingOptions *ImageRenderingOptions,
+	archivePath string,
 ) error {
 
 	var err error
 	var runs []galasaapi.Run
+	var folderPathsDownloaded []string
+	var renderingPool *imageRenderingPool
 
 	if runName != "" {
 		err = ValidateRunName(runName)
 	}
+	if err == nil && archivePath != "" {
+		// Find out about a bad archive name before spending time downloading.
+		_, err = files.GetArchiveFormat(archivePath)
+	}
 	if err == nil {
 		requestorParameter := ""
 		resultParameter := ""
@@ -52,66 +62,95 @@ func DownloadArtifacts(
 		shouldGetActive := false
 		runs, err = GetRunsFromRestApi(runName, requestorParameter, resultParameter, fromAgeHours, toAgeHours, shouldGetActive, timeService, commsClient, group)
 		if err == nil {
+			// The rendering workers report their progress while the artifacts are still downloading.
+			console = &synchronizedConsole{console: console}
+			// An incremental download replaces artifacts which changed, so their images need replacing too.
+			renderingPool = newImageRenderingPool(fileSystem, console, forceDownload || isIncremental, renderingOptions)
+
 			if len(runs) > 1 {
 				// get list of runs that are reRuns - get list of runs that are reRuns of each other
 				// create a map of lists of reRuns - key is queued time, value is the run
 				reRunsByQueuedTime := createMapOfReRuns(runs)
 
-				err = downloadReRunArtfifacts(
+				folderPathsDownloaded, err = downloadReRunArtfifacts(
 					reRunsByQueuedTime,
 					forceDownload,
+					isIncremental,
 					fileSystem,
 					commsClient,
 					console,
 					timeService,
 					runDownloadTargetFolder,
+					artifactFilter,
+					renderingPool,
 				)
 
 			} else if len(runs) == 1 {
 				var folderName string
 				folderName, err = nameDownloadFolder(runs[0], runName, timeService)
 				if err == nil {
-					err = downloadArtifactsAndRenderImagesToDirectory(commsClient, folderName, runs[0], fileSystem, forceDownload, console, runDownloadTargetFolder)
+					var folderPath string
+					folderPath, err = downloadArtifactsAndRenderImagesToDirectory(commsClient, folderName, runs[0], fileSystem, forceDownload, isIncremental, console, runDownloadTargetFolder, artifactFilter, renderingPool)
+					folderPathsDownloaded = append(folderPathsDownloaded, folderPath)
 				}
 			} else {
 				log.Printf("No artifacts to download for run: '%s'\n", runName)
 				err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_NO_ARTIFACTS_TO_DOWNLOAD, runName)
 			}
+
+			// Whatever was downloaded finishes rendering, even if a later download failed.
+			renderErr := renderingPool.finish()
+			if err == nil {
+				err = renderErr
+			}
 		}
 	}
 
+	if err == nil && archivePath != "" {
+		err = archiveRunDownloadFolders(fileSystem, archivePath, runDownloadTargetFolder, folderPathsDownloaded, timeService, console)
+	}
+
 	return err
 }
 
 func downloadReRunArtfifacts(
 	reRunsByQueuedTime map[string][]galasaapi.Run,
 	forceDownload bool,
+	isIncremental bool,
 	fileSystem spi.FileSystem,
 	commsClient api.APICommsClient,
 	console spi.Console,
 	timeService spi.TimeService,
 	runDownloadTargetFolder string,
-) error {
+	artifactFilter *ArtifactFilter,
+	renderingPool *imageRenderingPool,
+) ([]string, error) {
 	var err error
+	folderPathsDownloaded := make([]string, 0)
 	for _, reRunsList := range reRunsByQueuedTime {
 		if err == nil {
 			for reRunIndex, reRun := range reRunsList {
 				if err == nil {
 					directoryName := nameReRunArtifactDownloadDirectory(reRun, reRunIndex, timeService)
-					err = downloadArtifactsAndRenderImagesToDirectory(
+					var folderPath string
+					folderPath, err = downloadArtifactsAndRenderImagesToDirectory(
 						commsClient,
 						directoryName,
 						reRun,
 						fileSystem,
 						forceDownload,
+						isIncremental,
 						console,
 						runDownloadTargetFolder,
+						artifactFilter,
+						renderingPool,
 					)
+					folderPathsDownloaded = append(folderPathsDownloaded, folderPath)
 				}
 			}
 		}
 	}
-	return err
+	return folderPathsDownloaded, err
 }
 
 func createMapOfReRuns(runs []galasaapi.Run) map[string][]galasaapi.Run {
@@ -159,55 +198,55 @@ func nameDownloadFolder(run galasaapi.Run, runName string, timeService spi.TimeS
 	return directoryName, err
 }
 
+// Returns the path of the folder the artifacts were downloaded into.
 func downloadArtifactsAndRenderImagesToDirectory(
 	commsClient api.APICommsClient,
 	directoryName string,
 	run galasaapi.Run,
 	fileSystem spi.FileSystem,
 	forceDownload bool,
+	isIncremental bool,
 	console spi.Console,
 	runDownloadTargetFolder string,
-) error {
+	artifactFilter *ArtifactFilter,
+	renderingPool *imageRenderingPool,
+) (string, error) {
 	var err error
 
-	// We want to base the directory we download to on the destination folder.
-	// If the destination folder is "." (current folder/relative)
-	// then ignore, as prefixing with a "." just adds noise when we log and
-	// print out the path.
-	if runDownloadTargetFolder != "." {
-		directoryName = filepath.Join(runDownloadTargetFolder, directoryName)
-	}
+	directoryName = getRunDownloadFolderPath(runDownloadTargetFolder, directoryName)
 
+	// Each terminal file is rendered as soon as it has been downloaded.
 	var filePathsCreated []string
-	filePathsCreated, err = downloadArtifactsToDirectory(commsClient, directoryName, run, fileSystem, forceDownload, console)
+	filePathsCreated, err = downloadArtifactsToDirectory(commsClient, directoryName, run, fileSystem, forceDownload, isIncremental, console, artifactFilter, renderingPool)
 
 	if err == nil {
-		renderImages(fileSystem, filePathsCreated, forceDownload)
+		// An animation needs all the screens of its terminal, so waits until the whole run is downloaded.
+		isReplacingAnimations := (forceDownload || isIncremental) && isAnyTerminalDownloaded(filePathsCreated)
+		renderingPool.submitAnimations(directoryName, isReplacingAnimations)
 	}
-	return err
+	return directoryName, err
 }
 
-func renderImages(fileSystem spi.FileSystem, filePathsCreated []string, forceOverwriteExistingFiles bool) error {
-	var err error
-
-	embeddedFileSystem := embedded.GetReadOnlyFileSystem()
-	renderer := images.NewImageRenderer(embeddedFileSystem)
-	expander := images.NewImageExpander(fileSystem, renderer, forceOverwriteExistingFiles)
+// We want to base the directory we download to on the destination folder.
+// If the destination folder is "." (current folder/relative)
+// then ignore, as prefixing with a "." just adds noise when we log and
+// print out the path.
+func getRunDownloadFolderPath(runDownloadTargetFolder string, directoryName string) string {
+	if runDownloadTargetFolder != "." {
+		directoryName = filepath.Join(runDownloadTargetFolder, directoryName)
+	}
+	return directoryName
+}
 
+func isAnyTerminalDownloaded(filePathsCreated []string) bool {
+	isDownloaded := false
 	for _, filePath := range filePathsCreated {
-		err = expander.ExpandImage(filePath)
-		if err != nil {
+		if strings.HasSuffix(filePath, ".gz") {
+			isDownloaded = true
 			break
 		}
 	}
-
-	if err == nil {
-		// Write out a status string to the console about how many files were rendered.
-		count := expander.GetExpandedImageFileCount()
-		log.Printf("Expanded a total of %d image files.\n", count)
-	}
-
-	return err
+	return isDownloaded
 }
 
 func downloadArtifactsToDirectory(
@@ -216,44 +255,79 @@ func downloadArtifactsToDirectory(
 	run galasaapi.Run,
 	fileSystem spi.FileSystem,
 	forceDownload bool,
+	isIncremental bool,
 	console spi.Console,
+	artifactFilter *ArtifactFilter,
+	renderingPool *imageRenderingPool,
 ) (filePathsCreated []string, err error) {
 
 	runId := run.GetRunId()
 	filePathsCreated = make([]string, 0)
 
 	filesWrittenOkCount := 0
+	filesResumedCount := 0
+	filesUpToDateCount := 0
+
+	manifestPath := filepath.Join(directoryName, DOWNLOAD_MANIFEST_FILE_NAME)
+	manifest := NewDownloadManifest(run)
+	var previousManifest *DownloadManifest
+	if isIncremental {
+		previousManifest = readDownloadManifest(fileSystem, manifestPath)
+	}
 
-	artifactPaths, err := GetArtifactPathsFromRestApi(runId, commsClient)
+	artifacts, err := GetArtifactsFromRestApi(runId, commsClient)
 	if err == nil {
-		for _, artifactPath := range artifactPaths {
+		wantedArtifacts := getWantedArtifacts(artifacts, artifactFilter)
+		if len(wantedArtifacts) == 0 && len(artifacts) > 0 {
+			msg := fmt.Sprintf(galasaErrors.GALASA_INFO_NO_ARTIFACTS_MATCHED.Template, len(artifacts), run.TestStructure.GetRunName())
+			err = console.WriteString(msg)
+		}
+
+		for _, artifact := range wantedArtifacts {
 			if err == nil {
-				var artifactData io.Reader
-				var httpResponse *http.Response
-				var isArtifactDataEmpty bool
-				artifactData, isArtifactDataEmpty, httpResponse, err = GetFileFromRestApi(runId, strings.TrimPrefix(artifactPath, "/"), commsClient)
-				if err == nil {
-					if !isArtifactDataEmpty {
+				targetFilePath := filepath.Join(directoryName, artifact.Path)
 
-						targetFilePath := filepath.Join(directoryName, artifactPath)
+				action := ARTIFACT_ACTION_DOWNLOAD
+				var existingContent []byte
+				if isIncremental {
+					action, existingContent, err = getIncrementalArtifactAction(fileSystem, targetFilePath, artifact, previousManifest)
+				}
 
-						err = WriteArtifactToFileSystem(fileSystem, targetFilePath, artifactPath, artifactData, forceDownload, console)
+				var manifestEntry *DownloadManifestEntry
+				if err == nil {
+					switch action {
+					case ARTIFACT_ACTION_SKIP:
+						log.Printf("Artifact '%s' is already downloaded to '%s', so is skipped.\n", artifact.Path, targetFilePath)
+						previousEntry, _ := previousManifest.GetEntry(artifact.Path)
+						manifestEntry = &previousEntry
+						filesUpToDateCount += 1
+					case ARTIFACT_ACTION_RESUME:
+						manifestEntry, err = resumeArtifactDownload(commsClient, runId, artifact.Path, targetFilePath, existingContent, fileSystem, console)
 						if err == nil {
-							filesWrittenOkCount += 1
-							filePathsCreated = append(filePathsCreated, targetFilePath)
+							filesResumedCount += 1
 						}
+					default:
+						// An incremental download is allowed to replace files which are out of date.
+						manifestEntry, err = downloadArtifact(commsClient, runId, artifact.Path, targetFilePath, fileSystem, forceDownload || isIncremental, console)
 					}
 				}
 
-				closeErr := httpResponse.Body.Close()
-				// The first error is most important so needs preserving...
-				if closeErr != nil && err == nil {
-					err = galasaErrors.NewGalasaErrorWithHttpStatusCode(httpResponse.StatusCode, galasaErrors.GALASA_ERROR_HTTP_RESPONSE_CLOSE_FAILED, closeErr.Error())
+				if err == nil && manifestEntry != nil {
+					manifest.Artifacts = append(manifest.Artifacts, *manifestEntry)
+					if action != ARTIFACT_ACTION_SKIP {
+						filesWrittenOkCount += 1
+						filePathsCreated = append(filePathsCreated, targetFilePath)
+						renderingPool.submitFile(targetFilePath)
+					}
 				}
 			}
 		}
 	}
 
+	if err == nil && len(manifest.Artifacts) > 0 {
+		err = writeDownloadManifest(fileSystem, manifestPath, manifest)
+	}
+
 	// Write out the number of files downloaded to the folder xxx
 	if filesWrittenOkCount > 0 {
 		msg := fmt.Sprintf(
@@ -261,6 +335,9 @@ func downloadArtifactsToDirectory(
 			filesWrittenOkCount,
 			directoryName,
 		)
+		if filesResumedCount > 0 {
+			msg += fmt.Sprintf(galasaErrors.GALASA_INFO_ARTIFACTS_RESUMED.Template, filesResumedCount, directoryName)
+		}
 		consoleErr := console.WriteString(msg)
 		// Console error is not as important to report as the original error if there was one.
 		if consoleErr != nil && err == nil {
@@ -268,46 +345,78 @@ func downloadArtifactsToDirectory(
 		}
 	}
 
+	if filesUpToDateCount > 0 {
+		msg := fmt.Sprintf(galasaErrors.GALASA_INFO_ARTIFACTS_ALREADY_DOWNLOADED.Template, filesUpToDateCount, directoryName)
+		consoleErr := console.WriteString(msg)
+		if consoleErr != nil && err == nil {
+			err = consoleErr
+		}
+	}
+
 	return filePathsCreated, err
 }
 
+// Downloads a whole artifact. Returns nil if the server sent no content, so nothing was written.
+func downloadArtifact(
+	commsClient api.APICommsClient,
+	runId string,
+	artifactPath string,
+	targetFilePath string,
+	fileSystem spi.FileSystem,
+	forceDownload bool,
+	console spi.Console,
+) (*DownloadManifestEntry, error) {
+	var manifestEntry *DownloadManifestEntry
+	var artifactData io.Reader
+	var httpResponse *http.Response
+	var isArtifactDataEmpty bool
+
+	artifactData, isArtifactDataEmpty, httpResponse, err := GetFileFromRestApi(runId, strings.TrimPrefix(artifactPath, "/"), commsClient)
+	if err == nil {
+		if !isArtifactDataEmpty {
+			checksummer := newChecksumReader(artifactData)
+			err = WriteArtifactToFileSystem(fileSystem, targetFilePath, artifactPath, checksummer, forceDownload, console)
+			if err == nil {
+				entry := checksummer.getManifestEntry(artifactPath)
+				manifestEntry = &entry
+			}
+		}
+	}
+
+	if httpResponse != nil {
+		closeErr := httpResponse.Body.Close()
+		// The first error is most important so needs preserving...
+		if closeErr != nil && err == nil {
+			err = galasaErrors.NewGalasaErrorWithHttpStatusCode(httpResponse.StatusCode, galasaErrors.GALASA_ERROR_HTTP_RESPONSE_CLOSE_FAILED, closeErr.Error())
+		}
+	}
+	return manifestEntry, err
+}
+
+func getWantedArtifacts(artifacts []RunArtifact, artifactFilter *ArtifactFilter) []RunArtifact {
+	wantedArtifacts := make([]RunArtifact, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		if artifactFilter.IsWanted(artifact.Path) {
+			wantedArtifacts = append(wantedArtifacts, artifact)
+		} else {
+			log.Printf("Artifact '%s' was filtered out, so will not be downloaded.\n", artifact.Path)
+		}
+	}
+	return wantedArtifacts
+}
+
 // Retrieves the paths of all artifacts for a given test run using its runId.
 func GetArtifactPathsFromRestApi(runId string, commsClient api.APICommsClient) ([]string, error) {
 
 	var err error
 	var artifactPaths []string
-	log.Println("Retrieving artifact paths for the given run")
-
-	var restApiVersion string
-	restApiVersion, err = embedded.GetGalasactlRestApiVersion()
+	var artifacts []RunArtifact
 
+	artifacts, err = GetArtifactsFromRestApi(runId, commsClient)
 	if err == nil {
-		err = commsClient.RunAuthenticatedCommandWithRateLimitRetries(func(apiClient *galasaapi.APIClient) error {
-			var err error
-			var httpResponse *http.Response
-			var artifactsList []galasaapi.ArtifactIndexEntry
-	
-			artifactsList, httpResponse, err = apiClient.ResultArchiveStoreAPIApi.
-				GetRasRunArtifactList(context.Background(), runId).
-				ClientApiVersion(restApiVersion).
-				Execute()
-	
-			var statusCode int
-			if httpResponse != nil {
-				defer httpResponse.Body.Close()
-				statusCode = httpResponse.StatusCode
-			}
-	
-			if err != nil {
-				err = galasaErrors.NewGalasaErrorWithHttpStatusCode(statusCode, galasaErrors.GALASA_ERROR_RETRIEVING_ARTIFACTS_FAILED, err.Error())
-			} else {
-				for _, artifact := range artifactsList {
-					artifactPaths = append(artifactPaths, artifact.GetPath())
-				}
-			}
-			log.Printf("%v artifact path(s) found\n", len(artifactPaths))
-			return err
-		})
+		for _, artifact := range artifacts {
+			artifactPaths = append(artifactPaths, artifact.Path)
+		}
 	}
 
 	return artifactPaths, err
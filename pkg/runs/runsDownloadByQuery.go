@@ -0,0 +1,302 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/runsformatter"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+)
+
+const (
+	// The number of test runs downloaded at the same time, unless the user says otherwise.
+	DEFAULT_DOWNLOAD_PARALLEL_COUNT = 4
+
+	HEADER_DOWNLOAD_STATUS = "status"
+	HEADER_DOWNLOAD_FOLDER = "folder"
+	HEADER_DOWNLOAD_REASON = "reason"
+
+	DOWNLOAD_STATUS_OK     = "ok"
+	DOWNLOAD_STATUS_FAILED = "failed"
+)
+
+// A single test run to download as part of a bulk download, and how it went.
+type runDownloadJob struct {
+	run        galasaapi.Run
+	folderName string
+	folderPath string
+	err        error
+}
+
+// The downloads write to the console from several goroutines at once, so each
+// write is serialised to stop messages being mixed together.
+type synchronizedConsole struct {
+	console spi.Console
+	mutex   sync.Mutex
+}
+
+func (syncConsole *synchronizedConsole) WriteString(text string) error {
+	syncConsole.mutex.Lock()
+	defer syncConsole.mutex.Unlock()
+	return syncConsole.console.WriteString(text)
+}
+
+func (syncConsole *synchronizedConsole) Write(p []byte) (int, error) {
+	syncConsole.mutex.Lock()
+	defer syncConsole.mutex.Unlock()
+	return syncConsole.console.Write(p)
+}
+
+// DownloadArtifactsByQuery - performs all the logic to implement the `galasactl runs download`
+// command when test runs are selected by a query rather than by name, but in a unit-testable manner.
+//
+// A failure to download one test run does not stop the others being downloaded. A summary of
+// which runs succeeded and which failed is written out at the end.
+func DownloadArtifactsByQuery(
+	group string,
+	requestor string,
+	result string,
+	age string,
+	parallelCount int,
+	forceDownload bool,
+	isIncremental bool,
+	fileSystem spi.FileSystem,
+	timeService spi.TimeService,
+	console spi.Console,
+	commsClient api.APICommsClient,
+	runDownloadTargetFolder string,
+	artifactFilter *ArtifactFilter,
+	renderingOptions *ImageRenderingOptions,
+	archivePath string,
+) error {
+	var err error
+	var fromAge int
+	var toAge int
+	var runs []galasaapi.Run
+
+	log.Printf("DownloadArtifactsByQuery entered.")
+
+	if age == "" && group == "" {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_NO_TEST_RUN_IDENTIFIER_FLAG_SPECIFIED)
+	}
+
+	if err == nil && parallelCount < 1 {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_DOWNLOAD_PARALLEL_COUNT, parallelCount)
+	}
+
+	if err == nil && archivePath != "" {
+		_, err = files.GetArchiveFormat(archivePath)
+	}
+
+	if err == nil && age != "" {
+		fromAge, toAge, err = getTimesFromAge(age)
+	}
+
+	if err == nil && group != "" {
+		group, err = validateGroupname(group)
+	}
+
+	if err == nil && result != "" {
+		result, err = ValidateResultParameter(result, commsClient)
+	}
+
+	if err == nil {
+		runName := ""
+		shouldGetActive := false
+		runs, err = GetRunsFromRestApi(runName, requestor, result, fromAge, toAge, shouldGetActive, timeService, commsClient, group)
+	}
+
+	if err == nil {
+		if len(runs) == 0 {
+			err = console.WriteString(galasaErrors.GALASA_INFO_BULK_DOWNLOAD_NO_RUNS.Template)
+		} else {
+			jobs := createRunDownloadJobs(runs, timeService)
+
+			syncConsole := &synchronizedConsole{console: console}
+			// All the runs share one pool of rendering workers, so the fonts are only loaded once per worker.
+			renderingPool := newImageRenderingPool(fileSystem, syncConsole, forceDownload || isIncremental, renderingOptions)
+			downloadRunsInParallel(jobs, parallelCount, forceDownload, isIncremental, fileSystem, syncConsole, commsClient, runDownloadTargetFolder, artifactFilter, renderingPool)
+			renderErr := renderingPool.finish()
+
+			err = console.WriteString(formatRunDownloadSummary(jobs))
+			if err == nil && archivePath != "" {
+				// Whatever could be downloaded is archived, even if some runs failed.
+				folderPaths := getDownloadedFolderPaths(jobs)
+				if len(folderPaths) > 0 {
+					err = archiveRunDownloadFolders(fileSystem, archivePath, runDownloadTargetFolder, folderPaths, timeService, console)
+				}
+			}
+			if err == nil {
+				failedCount := countFailedRunDownloads(jobs)
+				if failedCount > 0 {
+					err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_BULK_DOWNLOAD_FAILED, failedCount, len(jobs))
+				}
+			}
+			if err == nil {
+				err = renderErr
+			}
+		}
+	}
+
+	log.Printf("DownloadArtifactsByQuery exiting. err is %v", err)
+	return err
+}
+
+// Works out which folder each run gets downloaded to. Runs which share a name
+// (re-runs, or unrelated runs which happen to re-use a name) get numbered folders,
+// in the same way as when a single run name is downloaded.
+func createRunDownloadJobs(runs []galasaapi.Run, timeService spi.TimeService) []*runDownloadJob {
+	runsByName := make(map[string][]galasaapi.Run)
+	for _, run := range runs {
+		runName := run.TestStructure.GetRunName()
+		runsByName[runName] = append(runsByName[runName], run)
+	}
+
+	runNames := make([]string, 0, len(runsByName))
+	for runName := range runsByName {
+		runNames = append(runNames, runName)
+	}
+	sort.Strings(runNames)
+
+	jobs := make([]*runDownloadJob, 0, len(runs))
+	for _, runName := range runNames {
+		runsWithSameName := runsByName[runName]
+		if len(runsWithSameName) == 1 {
+			folderName, _ := nameDownloadFolder(runsWithSameName[0], runName, timeService)
+			jobs = append(jobs, &runDownloadJob{run: runsWithSameName[0], folderName: folderName})
+		} else {
+			sort.SliceStable(runsWithSameName, func(i, j int) bool {
+				return runsWithSameName[i].TestStructure.GetQueued() < runsWithSameName[j].TestStructure.GetQueued()
+			})
+			for reRunIndex, reRun := range runsWithSameName {
+				folderName := nameReRunArtifactDownloadDirectory(reRun, reRunIndex, timeService)
+				jobs = append(jobs, &runDownloadJob{run: reRun, folderName: folderName})
+			}
+		}
+	}
+	return jobs
+}
+
+// Downloads the artifacts of each run using a pool of workers, recording the outcome
+// in each job rather than stopping at the first failure.
+func downloadRunsInParallel(
+	jobs []*runDownloadJob,
+	parallelCount int,
+	forceDownload bool,
+	isIncremental bool,
+	fileSystem spi.FileSystem,
+	console spi.Console,
+	commsClient api.APICommsClient,
+	runDownloadTargetFolder string,
+	artifactFilter *ArtifactFilter,
+	renderingPool *imageRenderingPool,
+) {
+	console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_BULK_DOWNLOAD_STARTING.Template, len(jobs), parallelCount))
+
+	jobQueue := make(chan *runDownloadJob, len(jobs))
+	for _, job := range jobs {
+		jobQueue <- job
+	}
+	close(jobQueue)
+
+	var progressMutex sync.Mutex
+	doneCount := 0
+	failedCount := 0
+
+	var workers sync.WaitGroup
+	for i := 0; i < parallelCount; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for job := range jobQueue {
+				job.folderPath, job.err = downloadArtifactsAndRenderImagesToDirectory(
+					commsClient,
+					job.folderName,
+					job.run,
+					fileSystem,
+					forceDownload,
+					isIncremental,
+					console,
+					runDownloadTargetFolder,
+					artifactFilter,
+					renderingPool,
+				)
+				if job.err != nil {
+					log.Printf("Failed to download run '%s'. Reason: %v\n", job.run.TestStructure.GetRunName(), job.err)
+				}
+
+				progressMutex.Lock()
+				doneCount++
+				if job.err != nil {
+					failedCount++
+				}
+				console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_BULK_DOWNLOAD_PROGRESS.Template, doneCount, len(jobs), failedCount))
+				progressMutex.Unlock()
+			}
+		}()
+	}
+	workers.Wait()
+}
+
+func getDownloadedFolderPaths(jobs []*runDownloadJob) []string {
+	folderPaths := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		if job.err == nil {
+			folderPaths = append(folderPaths, job.folderPath)
+		}
+	}
+	return folderPaths
+}
+
+func countFailedRunDownloads(jobs []*runDownloadJob) int {
+	failedCount := 0
+	for _, job := range jobs {
+		if job.err != nil {
+			failedCount++
+		}
+	}
+	return failedCount
+}
+
+// formatRunDownloadSummary renders a table of which runs were downloaded and which failed, and why.
+func formatRunDownloadSummary(jobs []*runDownloadJob) string {
+	var table [][]string
+	buff := strings.Builder{}
+
+	headers := []string{runsformatter.HEADER_RUNNAME, HEADER_DOWNLOAD_STATUS, HEADER_DOWNLOAD_FOLDER, HEADER_DOWNLOAD_REASON}
+	table = append(table, headers)
+
+	failedCount := 0
+	for _, job := range jobs {
+		status := DOWNLOAD_STATUS_OK
+		reason := ""
+		if job.err != nil {
+			status = DOWNLOAD_STATUS_FAILED
+			reason = job.err.Error()
+			failedCount++
+		}
+		line := []string{job.run.TestStructure.GetRunName(), status, job.folderName, reason}
+		table = append(table, line)
+	}
+
+	columnLengths := utils.CalculateMaxLengthOfEachColumn(table)
+	utils.WriteFormattedTableToStringBuilder(table, &buff, columnLengths)
+
+	buff.WriteString("\n")
+	buff.WriteString(fmt.Sprintf("Total:%d Succeeded:%d Failed:%d\n", len(jobs), len(jobs)-failedCount, failedCount))
+
+	return buff.String()
+}
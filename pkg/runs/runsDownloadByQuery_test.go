@@ -0,0 +1,234 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createMockFinishedRunJson(runName string, runId string, queuedTime string) string {
+	run := createMockRun(runName, runId)
+	run.TestStructure.SetResult("Passed")
+	run.TestStructure.SetQueued(queuedTime)
+	runBytes, _ := json.Marshal(run)
+	return string(runBytes)
+}
+
+// Each run gets one artifact, named after its run id. Runs listed in failingRunIds fail to list their artifacts.
+func newRunsDownloadByQueryTestInteractions(t *testing.T, runIds []string, runJsons []string, failingRunIds []string) []utils.HttpInteraction {
+	getRunsInteraction := utils.NewHttpInteraction("/ras/runs", http.MethodGet)
+	getRunsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "myGroup", req.URL.Query().Get("group"))
+		WriteMockRasRunsResponse(t, writer, req, "", runJsons)
+	}
+	interactions := []utils.HttpInteraction{getRunsInteraction}
+
+	for _, runId := range runIds {
+		artifactPath := "/" + runId + ".txt"
+		isFailing := false
+		for _, failingRunId := range failingRunIds {
+			isFailing = isFailing || failingRunId == runId
+		}
+
+		getArtifactsInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/artifacts", http.MethodGet)
+		getArtifactsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+			if isFailing {
+				writer.WriteHeader(http.StatusInternalServerError)
+			} else {
+				writer.Header().Set("Content-Type", "application/json")
+				artifactsBytes, _ := json.Marshal([]MockArtifact{*NewMockArtifact(artifactPath, "text/plain", 10)})
+				writer.Write(artifactsBytes)
+			}
+		}
+
+		downloadInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/files"+artifactPath, http.MethodGet)
+		downloadInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+			writer.Header().Set("Content-Disposition", "attachment")
+			writer.Write([]byte(artifactPath))
+		}
+
+		interactions = append(interactions, getArtifactsInteraction, downloadInteraction)
+	}
+	return interactions
+}
+
+func TestRunsDownloadByQueryDownloadsEveryRunInParallel(t *testing.T) {
+	// Given...
+	runIds := []string{"id-U1", "id-U2", "id-U3"}
+	runJsons := []string{
+		createMockFinishedRunJson("U1", "id-U1", "2023-05-10T06:00:13.043037Z"),
+		createMockFinishedRunJson("U2", "id-U2", "2023-05-10T06:00:14.043037Z"),
+		createMockFinishedRunJson("U3", "id-U3", "2023-05-10T06:00:15.043037Z"),
+	}
+	interactions := newRunsDownloadByQueryTestInteractions(t, runIds, runJsons, []string{})
+
+	// The runs are downloaded at the same time, so the order of requests can't be relied on.
+	server := utils.NewMockHttpServerWithUnorderedInteractions(t, interactions)
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := DownloadArtifactsByQuery("myGroup", "", "", "", 2, false, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
+
+	// Then...
+	assert.Nil(t, err)
+
+	for index, runName := range []string{"U1", "U2", "U3"} {
+		artifactExists, _ := mockFileSystem.Exists(runName + "/" + runIds[index] + ".txt")
+		assert.True(t, artifactExists, "artifact of run %s was not downloaded", runName)
+	}
+
+	output := mockConsole.ReadText()
+	assert.Contains(t, output, "GAL2507I: Downloading the artifacts of 3 test runs, 2 at a time.")
+	assert.Contains(t, output, "GAL2508I: Progress: 3 of 3 test runs done, 0 failed.")
+	assert.Contains(t, output, "name status folder reason\n"+
+		"U1   ok     U1     \n"+
+		"U2   ok     U2     \n"+
+		"U3   ok     U3     \n"+
+		"\n"+
+		"Total:3 Succeeded:3 Failed:0\n")
+}
+
+func TestRunsDownloadByQueryCarriesOnAfterAFailureAndReportsIt(t *testing.T) {
+	// Given...
+	runIds := []string{"id-U1", "id-U2", "id-U3"}
+	runJsons := []string{
+		createMockFinishedRunJson("U1", "id-U1", "2023-05-10T06:00:13.043037Z"),
+		createMockFinishedRunJson("U2", "id-U2", "2023-05-10T06:00:14.043037Z"),
+		createMockFinishedRunJson("U3", "id-U3", "2023-05-10T06:00:15.043037Z"),
+	}
+	interactions := newRunsDownloadByQueryTestInteractions(t, runIds, runJsons, []string{"id-U1"})
+
+	server := utils.NewMockHttpServerWithUnorderedInteractions(t, interactions)
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := DownloadArtifactsByQuery("myGroup", "", "", "", 1, false, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1256E: Failed to download the artifacts of 1 out of 3 test runs.")
+
+	u1Exists, _ := mockFileSystem.Exists("U1/id-U1.txt")
+	assert.False(t, u1Exists)
+	u3Exists, _ := mockFileSystem.Exists("U3/id-U3.txt")
+	assert.True(t, u3Exists)
+
+	output := mockConsole.ReadText()
+	assert.Contains(t, output, "GAL2508I: Progress: 1 of 3 test runs done, 1 failed.")
+	assert.Contains(t, output, "U1   failed U1     GAL1073E")
+	assert.Contains(t, output, "U2   ok     U2")
+	assert.Contains(t, output, "Total:3 Succeeded:2 Failed:1\n")
+}
+
+func TestRunsDownloadByQueryGivesReRunsNumberedFolders(t *testing.T) {
+	// Given...
+	runIds := []string{"id-U1a", "id-U1b"}
+	runJsons := []string{
+		createMockFinishedRunJson("U1", "id-U1b", "2023-05-10T07:00:00.000000Z"),
+		createMockFinishedRunJson("U1", "id-U1a", "2023-05-10T06:00:00.000000Z"),
+	}
+	interactions := newRunsDownloadByQueryTestInteractions(t, runIds, runJsons, []string{})
+
+	server := utils.NewMockHttpServerWithUnorderedInteractions(t, interactions)
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := DownloadArtifactsByQuery("myGroup", "", "", "", 4, false, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
+
+	// Then...
+	assert.Nil(t, err)
+	firstExists, _ := mockFileSystem.Exists("U1-1/id-U1a.txt")
+	assert.True(t, firstExists)
+	secondExists, _ := mockFileSystem.Exists("U1-2/id-U1b.txt")
+	assert.True(t, secondExists)
+}
+
+func TestRunsDownloadByQueryWithNoMatchingRunsSaysSo(t *testing.T) {
+	// Given...
+	interactions := newRunsDownloadByQueryTestInteractions(t, []string{}, []string{}, []string{})
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := DownloadArtifactsByQuery("myGroup", "", "", "", 4, false, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "GAL2509I: No test runs matched the query, so there is nothing to download.\n", mockConsole.ReadText())
+}
+
+func TestRunsDownloadByQueryWithoutAgeOrGroupReturnsError(t *testing.T) {
+	// Given...
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient("http://dummy.server")
+
+	// When...
+	err := DownloadArtifactsByQuery("", "myRequestor", "", "", 4, false, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1079E")
+}
+
+func TestRunsDownloadByQueryWithZeroParallelReturnsError(t *testing.T) {
+	// Given...
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient("http://dummy.server")
+
+	// When...
+	err := DownloadArtifactsByQuery("", "", "", "1d", 0, false, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1255E: Invalid '--parallel' value '0' provided.")
+}
+
+func TestRunsDownloadByQueryWithBadAgeReturnsError(t *testing.T) {
+	// Given...
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient("http://dummy.server")
+
+	// When...
+	err := DownloadArtifactsByQuery("", "", "", "1y", 4, false, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1078E")
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/embedded"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/spi"
+)
+
+// What an incremental download does with each artifact.
+const (
+	ARTIFACT_ACTION_DOWNLOAD = "download"
+	ARTIFACT_ACTION_SKIP     = "skip"
+	ARTIFACT_ACTION_RESUME   = "resume"
+)
+
+// Works out whether an artifact which may already have been downloaded needs downloading again.
+//
+// An artifact is skipped if the file is there, is the size the server says it should be,
+// and has the size and checksum recorded in the manifest of the previous download.
+// A file which is shorter than the server says it should be was only partly downloaded,
+// so the rest of it is fetched. Anything else is downloaded again from the start.
+//
+// When resuming, the content which is already there is returned too.
+func getIncrementalArtifactAction(
+	fileSystem spi.FileSystem,
+	targetFilePath string,
+	artifact RunArtifact,
+	previousManifest *DownloadManifest,
+) (string, []byte, error) {
+	action := ARTIFACT_ACTION_DOWNLOAD
+	var existingContent []byte
+
+	isExisting, err := fileSystem.Exists(targetFilePath)
+	if err == nil && isExisting {
+		existingContent, err = fileSystem.ReadBinaryFile(targetFilePath)
+		if err == nil {
+			existingSize := int64(len(existingContent))
+			isSizeKnown := artifact.Size != ARTIFACT_SIZE_UNKNOWN
+
+			previousEntry, isInPreviousManifest := previousManifest.GetEntry(artifact.Path)
+
+			if isInPreviousManifest &&
+				previousEntry.Size == existingSize &&
+				(!isSizeKnown || artifact.Size == existingSize) &&
+				previousEntry.Sha256 == getSha256(existingContent) {
+
+				action = ARTIFACT_ACTION_SKIP
+
+			} else if isSizeKnown && existingSize > 0 && existingSize < artifact.Size {
+				action = ARTIFACT_ACTION_RESUME
+			}
+		}
+	}
+
+	if action != ARTIFACT_ACTION_RESUME {
+		// Only needed when resuming.
+		existingContent = nil
+	}
+
+	log.Printf("Incremental download action for artifact '%s' is '%s'\n", artifact.Path, action)
+	return action, existingContent, err
+}
+
+// Fetches the rest of a partly downloaded artifact, and writes out the whole artifact.
+func resumeArtifactDownload(
+	commsClient api.APICommsClient,
+	runId string,
+	artifactPath string,
+	targetFilePath string,
+	existingContent []byte,
+	fileSystem spi.FileSystem,
+	console spi.Console,
+) (*DownloadManifestEntry, error) {
+	var manifestEntry *DownloadManifestEntry
+
+	remainingContent, isPartialContent, err := getFileRangeFromRestApi(runId, strings.TrimPrefix(artifactPath, "/"), int64(len(existingContent)), commsClient)
+	if err == nil {
+		var content io.Reader = bytes.NewReader(remainingContent)
+		if isPartialContent {
+			content = io.MultiReader(bytes.NewReader(existingContent), content)
+		} else {
+			log.Printf("The server sent all of artifact '%s' rather than just the missing part, so it is replaced.\n", artifactPath)
+		}
+
+		checksummer := newChecksumReader(content)
+		overwrite := true
+		err = WriteArtifactToFileSystem(fileSystem, targetFilePath, artifactPath, checksummer, overwrite, console)
+		if err == nil {
+			entry := checksummer.getManifestEntry(artifactPath)
+			manifestEntry = &entry
+		}
+	}
+	return manifestEntry, err
+}
+
+// Gets the part of an artifact from the given offset onwards, using an HTTP range request.
+// The generated API client can't ask for a range, so the request is sent directly.
+// A server which ignores the range sends the whole artifact, which is reported by
+// isPartialContent being false.
+func getFileRangeFromRestApi(
+	runId string,
+	artifactPath string,
+	offset int64,
+	commsClient api.APICommsClient,
+) ([]byte, bool, error) {
+	var content []byte
+	isPartialContent := false
+
+	log.Printf("Resuming the download of artifact '%s' from byte %d\n", artifactPath, offset)
+
+	restApiVersion, err := embedded.GetGalasactlRestApiVersion()
+	if err == nil {
+		artifactUrl := commsClient.GetBootstrapData().ApiServerURL + "/ras/runs/" + runId + "/files/" + artifactPath
+
+		err = commsClient.RunAuthenticatedCommandWithRateLimitRetries(func(apiClient *galasaapi.APIClient) error {
+			var err error
+			var bearerToken string
+			var req *http.Request
+
+			bearerToken, err = commsClient.GetAuthenticator().GetBearerToken()
+			if err == nil {
+				req, err = http.NewRequest(http.MethodGet, artifactUrl, nil)
+			}
+
+			if err == nil {
+				req.Header.Set("ClientApiVersion", restApiVersion)
+				req.Header.Set("Authorization", "Bearer "+bearerToken)
+				req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+
+				var resp *http.Response
+				client := &http.Client{}
+
+				resp, err = client.Do(req)
+				if err == nil {
+					defer resp.Body.Close()
+
+					statusCode := resp.StatusCode
+					if statusCode == http.StatusPartialContent || statusCode == http.StatusOK {
+						isPartialContent = statusCode == http.StatusPartialContent
+						content, err = io.ReadAll(resp.Body)
+						if err != nil {
+							err = galasaErrors.NewGalasaErrorWithHttpStatusCode(statusCode, galasaErrors.GALASA_ERROR_RESUMING_ARTIFACT_DOWNLOAD_FAILED, artifactPath, err.Error())
+						}
+					} else {
+						reason := fmt.Sprintf("http response status code: %d", statusCode)
+						err = galasaErrors.NewGalasaErrorWithHttpStatusCode(statusCode, galasaErrors.GALASA_ERROR_RESUMING_ARTIFACT_DOWNLOAD_FAILED, artifactPath, reason)
+					}
+				} else {
+					err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_RESUMING_ARTIFACT_DOWNLOAD_FAILED, artifactPath, err.Error())
+				}
+			}
+			return err
+		})
+	}
+	return content, isPartialContent, err
+}
+
+// Bundles the folders of the downloaded runs into a single archive file. The files are
+// stored relative to the destination folder, so each run keeps its own folder in the archive.
+func archiveRunDownloadFolders(
+	fileSystem spi.FileSystem,
+	archivePath string,
+	runDownloadTargetFolder string,
+	folderPaths []string,
+	timeService spi.TimeService,
+	console spi.Console,
+) error {
+	var err error
+	filePaths := make([]string, 0)
+
+	for _, folderPath := range folderPaths {
+		var filePathsInFolder []string
+		filePathsInFolder, err = fileSystem.GetAllFilePaths(folderPath + fileSystem.GetFilePathSeparator())
+		if err != nil {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_FAILED_TO_CREATE_ARCHIVE, archivePath, err.Error())
+			break
+		}
+		filePaths = append(filePaths, filePathsInFolder...)
+	}
+
+	if err == nil {
+		sort.Strings(filePaths)
+		err = files.WriteArchive(fileSystem, archivePath, filepath.Clean(runDownloadTargetFolder), filePaths, timeService.Now())
+	}
+
+	if err == nil {
+		err = console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_ARCHIVE_WRITTEN.Template, len(filePaths), archivePath))
+	}
+	return err
+}
@@ -0,0 +1,316 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	RUN_LOG_CONTENT        = "hello world"
+	RUN_LOG_CONTENT_SHA256 = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+)
+
+func newIncrementalDownloadRunAndArtifactsInteractions(t *testing.T, runName string, runId string, runLogSize int) []utils.HttpInteraction {
+	getRunsInteraction := utils.NewHttpInteraction("/ras/runs", http.MethodGet)
+	getRunsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		WriteMockRasRunsResponse(t, writer, req, runName, []string{RUN_U27V2})
+	}
+
+	getArtifactsInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/artifacts", http.MethodGet)
+	getArtifactsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		artifactsBytes, _ := json.Marshal([]MockArtifact{*NewMockArtifact("/run.log", "text/plain", runLogSize)})
+		writer.Write(artifactsBytes)
+	}
+	return []utils.HttpInteraction{getRunsInteraction, getArtifactsInteraction}
+}
+
+func writeMockDownloadManifest(fileSystem spi.FileSystem, manifestPath string, entries ...DownloadManifestEntry) {
+	manifest := DownloadManifest{RunName: "U27", RunId: "xxx987xxx", Artifacts: entries}
+	manifestBytes, _ := json.Marshal(manifest)
+	fileSystem.WriteBinaryFile(manifestPath, manifestBytes)
+}
+
+func readMockDownloadManifest(t *testing.T, fileSystem spi.FileSystem, manifestPath string) DownloadManifest {
+	var manifest DownloadManifest
+	manifestBytes, err := fileSystem.ReadBinaryFile(manifestPath)
+	assert.Nil(t, err)
+	err = json.Unmarshal(manifestBytes, &manifest)
+	assert.Nil(t, err)
+	return manifest
+}
+
+func TestRunsDownloadWritesManifestWithSizeAndChecksum(t *testing.T) {
+	// Given...
+	runName := "U27"
+	runId := "xxx987xxx"
+
+	interactions := newIncrementalDownloadRunAndArtifactsInteractions(t, runName, runId, len(RUN_LOG_CONTENT))
+	downloadInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/files/run.log", http.MethodGet)
+	downloadInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		writer.Header().Set("Content-Disposition", "attachment")
+		writer.Write([]byte(RUN_LOG_CONTENT))
+	}
+	interactions = append(interactions, downloadInteraction)
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := DownloadArtifacts(runName, false, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
+
+	// Then...
+	assert.Nil(t, err)
+
+	manifest := readMockDownloadManifest(t, mockFileSystem, "U27/"+DOWNLOAD_MANIFEST_FILE_NAME)
+	assert.Equal(t, "U27", manifest.RunName)
+	assert.Equal(t, runId, manifest.RunId)
+	assert.Equal(t, []DownloadManifestEntry{{Path: "/run.log", Size: 11, Sha256: RUN_LOG_CONTENT_SHA256}}, manifest.Artifacts)
+}
+
+func TestRunsDownloadIncrementalSkipsArtifactsWhichMatchTheManifest(t *testing.T) {
+	// Given...
+	runName := "U27"
+	runId := "xxx987xxx"
+
+	// The mock server fails the test if the artifact is downloaded again.
+	interactions := newIncrementalDownloadRunAndArtifactsInteractions(t, runName, runId, len(RUN_LOG_CONTENT))
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	mockFileSystem.WriteTextFile("U27/run.log", RUN_LOG_CONTENT)
+	writeMockDownloadManifest(mockFileSystem, "U27/"+DOWNLOAD_MANIFEST_FILE_NAME,
+		DownloadManifestEntry{Path: "/run.log", Size: 11, Sha256: RUN_LOG_CONTENT_SHA256})
+
+	// When...
+	err := DownloadArtifacts(runName, false, true, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "GAL2510I: 1 artifacts in folder 'U27' were already up to date, so were not downloaded again.\n", mockConsole.ReadText())
+
+	manifest := readMockDownloadManifest(t, mockFileSystem, "U27/"+DOWNLOAD_MANIFEST_FILE_NAME)
+	assert.Equal(t, []DownloadManifestEntry{{Path: "/run.log", Size: 11, Sha256: RUN_LOG_CONTENT_SHA256}}, manifest.Artifacts)
+}
+
+func TestRunsDownloadIncrementalReplacesArtifactWhichDoesNotMatchTheManifest(t *testing.T) {
+	// Given...
+	runName := "U27"
+	runId := "xxx987xxx"
+
+	interactions := newIncrementalDownloadRunAndArtifactsInteractions(t, runName, runId, len(RUN_LOG_CONTENT))
+	downloadInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/files/run.log", http.MethodGet)
+	downloadInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		writer.Header().Set("Content-Disposition", "attachment")
+		writer.Write([]byte(RUN_LOG_CONTENT))
+	}
+	interactions = append(interactions, downloadInteraction)
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// Same size as the real thing, but somebody has changed it since it was downloaded.
+	mockFileSystem.WriteTextFile("U27/run.log", "HELLO WORLD")
+	writeMockDownloadManifest(mockFileSystem, "U27/"+DOWNLOAD_MANIFEST_FILE_NAME,
+		DownloadManifestEntry{Path: "/run.log", Size: 11, Sha256: RUN_LOG_CONTENT_SHA256})
+
+	// When...
+	err := DownloadArtifacts(runName, false, true, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
+
+	// Then...
+	assert.Nil(t, err)
+	runLog, _ := mockFileSystem.ReadTextFile("U27/run.log")
+	assert.Equal(t, RUN_LOG_CONTENT, runLog)
+	assert.Equal(t, "GAL2501I: Downloaded 1 artifacts to folder 'U27'\n", mockConsole.ReadText())
+}
+
+func TestRunsDownloadIncrementalResumesPartlyDownloadedArtifact(t *testing.T) {
+	// Given...
+	runName := "U27"
+	runId := "xxx987xxx"
+
+	interactions := newIncrementalDownloadRunAndArtifactsInteractions(t, runName, runId, len(RUN_LOG_CONTENT))
+	resumeInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/files/run.log", http.MethodGet)
+	resumeInteraction.ValidateRequestFunc = func(t *testing.T, req *http.Request) {
+		assert.Equal(t, "bytes=6-", req.Header.Get("Range"))
+	}
+	resumeInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		writer.WriteHeader(http.StatusPartialContent)
+		writer.Write([]byte("world"))
+	}
+	interactions = append(interactions, resumeInteraction)
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	mockFileSystem.WriteTextFile("U27/run.log", "hello ")
+
+	// When...
+	err := DownloadArtifacts(runName, false, true, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
+
+	// Then...
+	assert.Nil(t, err)
+	runLog, _ := mockFileSystem.ReadTextFile("U27/run.log")
+	assert.Equal(t, RUN_LOG_CONTENT, runLog)
+
+	assert.Equal(t, "GAL2501I: Downloaded 1 artifacts to folder 'U27'\n"+
+		"GAL2511I: Resumed the download of 1 partly downloaded artifacts in folder 'U27'.\n", mockConsole.ReadText())
+
+	manifest := readMockDownloadManifest(t, mockFileSystem, "U27/"+DOWNLOAD_MANIFEST_FILE_NAME)
+	assert.Equal(t, []DownloadManifestEntry{{Path: "/run.log", Size: 11, Sha256: RUN_LOG_CONTENT_SHA256}}, manifest.Artifacts)
+}
+
+func TestRunsDownloadIncrementalResumeReplacesFileIfServerIgnoresRange(t *testing.T) {
+	// Given...
+	runName := "U27"
+	runId := "xxx987xxx"
+
+	interactions := newIncrementalDownloadRunAndArtifactsInteractions(t, runName, runId, len(RUN_LOG_CONTENT))
+	resumeInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/files/run.log", http.MethodGet)
+	resumeInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		writer.Write([]byte(RUN_LOG_CONTENT))
+	}
+	interactions = append(interactions, resumeInteraction)
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	mockFileSystem.WriteTextFile("U27/run.log", "hello ")
+
+	// When...
+	err := DownloadArtifacts(runName, false, true, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
+
+	// Then...
+	assert.Nil(t, err)
+	runLog, _ := mockFileSystem.ReadTextFile("U27/run.log")
+	assert.Equal(t, RUN_LOG_CONTENT, runLog)
+}
+
+func TestRunsDownloadIncrementalResumeFailureReturnsError(t *testing.T) {
+	// Given...
+	runName := "U27"
+	runId := "xxx987xxx"
+
+	interactions := newIncrementalDownloadRunAndArtifactsInteractions(t, runName, runId, len(RUN_LOG_CONTENT))
+	resumeInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/files/run.log", http.MethodGet)
+	resumeInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		writer.WriteHeader(http.StatusInternalServerError)
+	}
+	interactions = append(interactions, resumeInteraction)
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	mockFileSystem.WriteTextFile("U27/run.log", "hello ")
+
+	// When...
+	err := DownloadArtifacts(runName, false, true, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1260E: Failed to resume the download of artifact 'run.log'. Reason: http response status code: 500")
+
+	// The partly downloaded file is left alone, so it can be resumed again later.
+	runLog, _ := mockFileSystem.ReadTextFile("U27/run.log")
+	assert.Equal(t, "hello ", runLog)
+}
+
+func TestRunsDownloadWithArchiveBundlesTheRunFolder(t *testing.T) {
+	// Given...
+	runName := "U27"
+	runId := "xxx987xxx"
+
+	interactions := newIncrementalDownloadRunAndArtifactsInteractions(t, runName, runId, len(RUN_LOG_CONTENT))
+	downloadInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/files/run.log", http.MethodGet)
+	downloadInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		writer.Header().Set("Content-Disposition", "attachment")
+		writer.Write([]byte(RUN_LOG_CONTENT))
+	}
+	interactions = append(interactions, downloadInteraction)
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := DownloadArtifacts(runName, false, false, mockFileSystem, mockTimeService, mockConsole, commsClient, "/downloads", nil, nil, "/defects/U27.zip")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Contains(t, mockConsole.ReadText(), "GAL2512I: Archived 2 files into '/defects/U27.zip'.\n")
+
+	archiveBytes, err := mockFileSystem.ReadBinaryFile("/defects/U27.zip")
+	assert.Nil(t, err)
+	zipReader, err := zip.NewReader(bytes.NewReader(archiveBytes), int64(len(archiveBytes)))
+	assert.Nil(t, err)
+
+	entryNames := make([]string, 0)
+	for _, entry := range zipReader.File {
+		entryNames = append(entryNames, entry.Name)
+	}
+	assert.Equal(t, []string{"U27/" + DOWNLOAD_MANIFEST_FILE_NAME, "U27/run.log"}, entryNames)
+}
+
+func TestRunsDownloadWithBadArchiveNameFailsBeforeDownloading(t *testing.T) {
+	// Given...
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient("http://dummy.server")
+
+	// When...
+	err := DownloadArtifacts("U27", false, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "U27.rar")
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1257E")
+}
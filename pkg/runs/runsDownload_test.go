@@ -332,7 +332,7 @@ func TestRunsDownloadFailingFileWriteReturnsError(t *testing.T) {
     commsClient := api.NewMockAPICommsClient(apiServerUrl)
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, ".")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
 
 	// Then...
 	assert.Contains(t, err.Error(), "GAL1042")
@@ -406,7 +406,7 @@ func TestRunsDownloadFailingFileCreationReturnsError(t *testing.T) {
     commsClient := api.NewMockAPICommsClient(apiServerUrl)
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, ".")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
 
 	// Then...
 	assert.Contains(t, err.Error(), "GAL1042")
@@ -480,7 +480,7 @@ func TestRunsDownloadFailingFolderCreationReturnsError(t *testing.T) {
     commsClient := api.NewMockAPICommsClient(apiServerUrl)
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, ".")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
 
 	// Then...
 	assert.Contains(t, err.Error(), "GAL1041")
@@ -558,7 +558,7 @@ func TestRunsDownloadExistingFileForceOverwritesMultipleArtifactsToFileSystem(t
 	mockFileSystem.WriteTextFile(runName+dummyRunLog.Path, "dummy log")
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, ".")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
 
 	// Then...
 	assert.Nil(t, err)
@@ -648,7 +648,7 @@ func TestRunsDownloadExistingFileNoForceReturnsError(t *testing.T) {
 	mockFileSystem.WriteTextFile(runName+separator+"run.log", "dummy log")
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, ".")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
 
 	// Then...
 	assert.NotNil(t, err)
@@ -733,7 +733,7 @@ func TestRunsDownloadWritesMultipleArtifactsToFileSystem(t *testing.T) {
     commsClient := api.NewMockAPICommsClient(apiServerUrl)
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, ".")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
 
 	// Then...
 	downloadedTxtArtifactExists, _ := mockFileSystem.Exists(runName + dummyTxtArtifact.Path)
@@ -813,7 +813,7 @@ func TestRunsDownloadWritesSingleArtifactToFileSystem(t *testing.T) {
     commsClient := api.NewMockAPICommsClient(apiServerUrl)
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, ".")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
 
 	// Then...
 	separator := string(os.PathSeparator)
@@ -889,7 +889,7 @@ func TestFailingGetFileRequestReturnsError(t *testing.T) {
 	forceDownload := false
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, ".")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
 
 	// Then...
 	assert.Contains(t, err.Error(), "GAL1074")
@@ -965,7 +965,7 @@ func TestUnauthorizedGetFileRequestReauthenticatesOk(t *testing.T) {
 	forceDownload := false
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, ".")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
 
 	// Then...
 	assert.Nil(t, err)
@@ -1025,7 +1025,7 @@ func TestFailingGetArtifactsRequestReturnsError(t *testing.T) {
 	forceDownload := false
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, ".")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
 
 	// Then...
 	assert.Contains(t, err.Error(), "GAL1073")
@@ -1113,7 +1113,7 @@ func TestRunsDownloadMultipleReRunsWithCorrectOrderFolders(t *testing.T) {
     commsClient := api.NewMockAPICommsClient(apiServerUrl)
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, ".")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
 
 	// Then...
 	// U27-1-2023-2023-05-10T06:00:13 	(test did not finish)
@@ -1262,7 +1262,7 @@ func TestRunsDownloadMultipleSetsOfUnrelatedReRunsWithCorrectOrderFolders(t *tes
 	mockTimeService.AdvanceClock(time.Second)
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, ".")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
 
 	// Then...
 	// U27-1-2023-05-10T06:00:13 	(test did not finish)
@@ -1333,7 +1333,7 @@ func TestRunsDownloadWithValidRunNameNoArtifacts(t *testing.T) {
     commsClient := api.NewMockAPICommsClient(apiServerUrl)
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, ".")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
 	// Then...
 
 	assert.Contains(t, err.Error(), "GAL1083E")
@@ -1355,7 +1355,7 @@ func TestRunsDownloadWithInvalidRunName(t *testing.T) {
     commsClient := api.NewMockAPICommsClient("api-server-url")
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, ".")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
 
 	// Then...
 
@@ -1424,7 +1424,7 @@ func TestRunsDownloadAddsTimestampToFolderIfRunNotFinished(t *testing.T) {
     commsClient := api.NewMockAPICommsClient(apiServerUrl)
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, ".")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", nil, nil, "")
 
 	// Then...
 	run1FolderName := runName + "-" + mockTimeService.Now().Format("2006-01-02_15:04:05")
@@ -1503,7 +1503,7 @@ func TestRunsDownloadWritesSingleArtifactToDestinationFolder(t *testing.T) {
     commsClient := api.NewMockAPICommsClient(apiServerUrl)
 
 	// When...
-	err := DownloadArtifacts(runName, forceDownload, mockFileSystem, mockTimeService, mockConsole, commsClient, "/myfolder")
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, "/myfolder", nil, nil, "")
 
 	// Then...
 	downloadedArtifactExists, _ := mockFileSystem.Exists("/myfolder/" + runName + dummyArtifact.Path)
@@ -1515,3 +1515,110 @@ func TestRunsDownloadWritesSingleArtifactToDestinationFolder(t *testing.T) {
 	assert.Contains(t, textGotBack, "GAL2501I")
 	assert.Contains(t, textGotBack, "/myfolder/"+runName)
 }
+
+func newRunsDownloadFilterTestInteractions(t *testing.T, runName string, runId string, mockArtifacts []MockArtifact, expectedDownloads []MockArtifact) []utils.HttpInteraction {
+	getRunsInteraction := utils.NewHttpInteraction("/ras/runs", http.MethodGet)
+	getRunsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		WriteMockRasRunsResponse(t, writer, req, runName, []string{RUN_U27V2})
+	}
+
+	getArtifactsInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/artifacts", http.MethodGet)
+	getArtifactsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		artifactsBytes, _ := json.Marshal(mockArtifacts)
+		writer.Write(artifactsBytes)
+	}
+
+	interactions := []utils.HttpInteraction{
+		getRunsInteraction,
+		getArtifactsInteraction,
+	}
+
+	for _, expectedDownload := range expectedDownloads {
+		artifactPath := expectedDownload.Path
+		downloadInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/files"+artifactPath, http.MethodGet)
+		downloadInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+			writer.Header().Set("Content-Disposition", "attachment")
+			writer.Write([]byte(artifactPath))
+		}
+		interactions = append(interactions, downloadInteraction)
+	}
+	return interactions
+}
+
+func TestRunsDownloadWithIncludeAndExcludeOnlyDownloadsWantedArtifacts(t *testing.T) {
+	// Given ...
+	runName := "U27"
+	runId := "xxx987xxx"
+	forceDownload := false
+
+	cpsRecord := NewMockArtifact("/framework/cps_record.properties", "text/plain", 2048)
+	jobOutput := NewMockArtifact("/zos/jobs/JOB123/SYSOUT.txt", "text/plain", 4096)
+	jobJcl := NewMockArtifact("/zos/jobs/JOB123/JCL.gz", "application/x-gzip", 100)
+	runLog := NewMockArtifact("/run.log", "text/plain", 203)
+	mockArtifacts := []MockArtifact{*cpsRecord, *jobOutput, *jobJcl, *runLog}
+
+	// The mock server fails the test if any other artifact is asked for.
+	interactions := newRunsDownloadFilterTestInteractions(t, runName, runId, mockArtifacts, []MockArtifact{*cpsRecord, *jobOutput})
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+	mockConsole := utils.NewMockConsole()
+	mockFileSystem := files.NewMockFileSystem()
+
+	filter := NewArtifactFilter([]string{"*.properties", "zos/**"}, []string{"**/*.gz"})
+
+	// When...
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", filter, nil, "")
+
+	// Then...
+	assert.Nil(t, err)
+
+	cpsRecordExists, _ := mockFileSystem.Exists(runName + cpsRecord.Path)
+	assert.True(t, cpsRecordExists)
+	jobOutputExists, _ := mockFileSystem.Exists(runName + jobOutput.Path)
+	assert.True(t, jobOutputExists)
+	jobJclExists, _ := mockFileSystem.Exists(runName + jobJcl.Path)
+	assert.False(t, jobJclExists)
+	runLogExists, _ := mockFileSystem.Exists(runName + runLog.Path)
+	assert.False(t, runLogExists)
+
+	assert.Contains(t, mockConsole.ReadText(), "GAL2501I: Downloaded 2 artifacts")
+}
+
+func TestRunsDownloadWithIncludeMatchingNothingSaysSo(t *testing.T) {
+	// Given ...
+	runName := "U27"
+	runId := "xxx987xxx"
+	forceDownload := false
+
+	runLog := NewMockArtifact("/run.log", "text/plain", 203)
+	mockArtifacts := []MockArtifact{*runLog}
+
+	interactions := newRunsDownloadFilterTestInteractions(t, runName, runId, mockArtifacts, []MockArtifact{})
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+	mockConsole := utils.NewMockConsole()
+	mockFileSystem := files.NewMockFileSystem()
+
+	filter := NewArtifactFilter([]string{"framework/cps_record.properties"}, nil)
+
+	// When...
+	err := DownloadArtifacts(runName, forceDownload, false, mockFileSystem, mockTimeService, mockConsole, commsClient, ".", filter, nil, "")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "GAL2506I: None of the 1 artifacts of run 'U27' matched the --include and --exclude filters, so nothing was downloaded.\n", mockConsole.ReadText())
+}
+
+func TestIsAnyTerminalDownloadedOnlyCountsGzFiles(t *testing.T) {
+	assert.False(t, isAnyTerminalDownloaded([]string{"U1/run.log", "U1/zos3270/images/term1/term1-00001.png"}))
+	assert.True(t, isAnyTerminalDownloaded([]string{"U1/run.log", "U1/zos3270/terminals/term1/term1-00001.gz"}))
+}
@@ -0,0 +1,386 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+)
+
+const (
+	HEADER_FLAKY_RANK         = "rank"
+	HEADER_FLAKY_SCORE        = "score"
+	HEADER_FLAKY_FLIPS        = "flips"
+	HEADER_FLAKY_OBSERVATIONS = "runs"
+	HEADER_FLAKY_BUNDLE       = "bundle"
+	HEADER_FLAKY_TEST_CLASS   = "test-class"
+	HEADER_FLAKY_METHOD       = "method"
+	HEADER_FLAKY_STREAM       = "stream"
+	HEADER_FLAKY_EVIDENCE     = "evidence"
+
+	// The number of CPS records fetched at the same time, to find the stream each run used.
+	FLAKY_CPS_RECORD_PARALLEL_COUNT = DEFAULT_DOWNLOAD_PARALLEL_COUNT
+)
+
+var (
+	// Results which count as a 'pass' or a 'fail' when looking for flips.
+	// Anything else (EnvFail, Ignored, UNKNOWN...) says nothing about flakiness so is skipped.
+	flakyPassingResults = []string{"Passed", "Success"}
+	flakyFailingResults = []string{"Failed", "Failure", "Failed With Defects"}
+)
+
+// FlakyTest holds the flakiness analysis of a single test method.
+type FlakyTest struct {
+	Bundle     string
+	TestClass  string
+	MethodName string
+
+	// The test stream the runs were loaded from, as recorded in their CPS records.
+	// Results are only compared between runs which were loaded from the same stream.
+	Stream string
+
+	// The number of runs in which this method either passed or failed.
+	Observations int
+
+	// The number of times the result changed from pass to fail, or fail to pass,
+	// from one run to the next.
+	Flips int
+
+	// Flips as a fraction of the opportunities to flip. 0 means stable, 1 means the result
+	// changed every single time.
+	Score float64
+
+	// The names of the runs either side of each flip, in the order they ran.
+	EvidenceRunNames []string
+}
+
+// The CPS record of a single run to fetch, and the stream found in it.
+type flakyStreamJob struct {
+	runId  string
+	stream string
+}
+
+// A single pass or fail result of a test method within a run.
+type flakyObservation struct {
+	runName   string
+	startTime string
+	isPassing bool
+}
+
+// FindFlakyTests - performs all the logic to implement the `galasactl runs flaky` command,
+// but in a unit-testable manner.
+func FindFlakyTests(
+	age string,
+	requestor string,
+	group string,
+	topCount int,
+	portfolioFilename string,
+	stream string,
+	timeService spi.TimeService,
+	console spi.Console,
+	commsClient api.APICommsClient,
+	fileSystem spi.FileSystem,
+) error {
+	var err error
+	var fromAge int
+	var toAge int
+
+	log.Printf("FindFlakyTests entered.")
+
+	if age == "" {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_FLAKY_AGE_NOT_SPECIFIED)
+	}
+
+	if err == nil {
+		fromAge, toAge, err = getTimesFromAge(age)
+	}
+
+	if err == nil && group != "" {
+		group, err = validateGroupname(group)
+	}
+
+	if err == nil && topCount < 0 {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_FLAKY_TOP_COUNT, topCount)
+	}
+
+	if err == nil {
+		var runs []galasaapi.Run
+		runName := ""
+		result := ""
+		shouldGetActive := false
+		runs, err = GetRunsFromRestApi(runName, requestor, result, fromAge, toAge, shouldGetActive, timeService, commsClient, group)
+		if err == nil {
+			runs = getFinishedRuns(runs)
+
+			// The search results don't carry the method-level detail we need.
+			runs, err = GetRunDetailsFromRasSearchRuns(runs, commsClient)
+			if err == nil {
+				streamsByRunId := getStreamsOfRuns(runs, FLAKY_CPS_RECORD_PARALLEL_COUNT, commsClient)
+
+				flakyTests := AnalyseFlakyTests(runs, streamsByRunId)
+				if topCount > 0 && len(flakyTests) > topCount {
+					flakyTests = flakyTests[:topCount]
+				}
+
+				err = writeOutput(FormatFlakyTests(flakyTests, len(runs)), console)
+
+				if err == nil && portfolioFilename != "" {
+					portfolio := NewPortfolio()
+					AddFlakyTestsToPortfolio(flakyTests, stream, portfolio)
+					err = WritePortfolio(fileSystem, portfolioFilename, portfolio)
+					if err != nil {
+						err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_FLAKY_PORTFOLIO_WRITE_FAILED, portfolioFilename, err.Error())
+					}
+				}
+			}
+		}
+	}
+
+	log.Printf("FindFlakyTests exiting. err is %v", err)
+	return err
+}
+
+// AnalyseFlakyTests groups the method results of the given runs by bundle, test class and method,
+// then scores each method by how often its result flipped between pass and fail from one run to the next.
+// Only methods which flipped at least once are returned, the flakiest first.
+//
+// A test which passes on one stream and fails on another isn't flaky, so results are also grouped
+// by the stream each run was loaded from. Runs whose stream isn't known are grouped together.
+//
+// The RAS doesn't record which overrides a run was given, and the other properties in a run's CPS record
+// depend on how far the test got, so they aren't used to group the results.
+func AnalyseFlakyTests(runs []galasaapi.Run, streamsByRunId map[string]string) []FlakyTest {
+
+	observationsByKey := make(map[string][]flakyObservation)
+	testsByKey := make(map[string]*FlakyTest)
+
+	for _, run := range runs {
+		testStructure := run.GetTestStructure()
+		stream := streamsByRunId[run.GetRunId()]
+
+		for _, method := range testStructure.GetMethods() {
+			isPassing, isPassOrFail := getFlakyPassOrFail(method.GetResult())
+			if isPassOrFail {
+				testClass := method.GetClassName()
+				if testClass == "" {
+					testClass = testStructure.GetTestName()
+				}
+
+				flakyTest := FlakyTest{
+					Bundle:     testStructure.GetBundle(),
+					TestClass:  testClass,
+					MethodName: method.GetMethodName(),
+					Stream:     stream,
+				}
+				key := getFlakyTestKey(flakyTest)
+				if testsByKey[key] == nil {
+					testsByKey[key] = &flakyTest
+				}
+
+				observation := flakyObservation{
+					runName:   testStructure.GetRunName(),
+					startTime: testStructure.GetStartTime(),
+					isPassing: isPassing,
+				}
+				observationsByKey[key] = append(observationsByKey[key], observation)
+			}
+		}
+	}
+
+	flakyTests := make([]FlakyTest, 0)
+	for key, observations := range observationsByKey {
+		flakyTest := testsByKey[key]
+		scoreObservations(flakyTest, observations)
+		if flakyTest.Flips > 0 {
+			flakyTests = append(flakyTests, *flakyTest)
+		}
+	}
+
+	sort.Slice(flakyTests, func(i, j int) bool {
+		first := flakyTests[i]
+		second := flakyTests[j]
+		if first.Score != second.Score {
+			return first.Score > second.Score
+		}
+		if first.Flips != second.Flips {
+			return first.Flips > second.Flips
+		}
+		return getFlakyTestKey(first) < getFlakyTestKey(second)
+	})
+
+	return flakyTests
+}
+
+func scoreObservations(flakyTest *FlakyTest, observations []flakyObservation) {
+
+	// Timestamps are in RFC3339 format, so sort in time order as strings.
+	sort.SliceStable(observations, func(i, j int) bool {
+		return observations[i].startTime < observations[j].startTime
+	})
+
+	evidenceSeen := make(map[string]bool)
+	flakyTest.EvidenceRunNames = make([]string, 0)
+	flakyTest.Observations = len(observations)
+
+	for index := 1; index < len(observations); index++ {
+		previous := observations[index-1]
+		current := observations[index]
+		if previous.isPassing != current.isPassing {
+			flakyTest.Flips++
+			for _, runName := range []string{previous.runName, current.runName} {
+				if !evidenceSeen[runName] {
+					evidenceSeen[runName] = true
+					flakyTest.EvidenceRunNames = append(flakyTest.EvidenceRunNames, runName)
+				}
+			}
+		}
+	}
+
+	if len(observations) > 1 {
+		flakyTest.Score = float64(flakyTest.Flips) / float64(len(observations)-1)
+	}
+}
+
+func getFlakyPassOrFail(result string) (isPassing bool, isPassOrFail bool) {
+	for _, passingResult := range flakyPassingResults {
+		if strings.EqualFold(result, passingResult) {
+			isPassing = true
+			isPassOrFail = true
+		}
+	}
+	for _, failingResult := range flakyFailingResults {
+		if strings.EqualFold(result, failingResult) {
+			isPassOrFail = true
+		}
+	}
+	return isPassing, isPassOrFail
+}
+
+func getFlakyTestKey(flakyTest FlakyTest) string {
+	return flakyTest.Bundle + "/" + flakyTest.TestClass + "#" + flakyTest.MethodName + "@" + flakyTest.Stream
+}
+
+// Finds the stream each run was loaded from, by fetching the CPS records of the runs using a pool of workers.
+// A run whose CPS record can't be read is given an empty stream.
+func getStreamsOfRuns(runs []galasaapi.Run, parallelCount int, commsClient api.APICommsClient) map[string]string {
+	jobs := make([]*flakyStreamJob, 0, len(runs))
+	jobQueue := make(chan *flakyStreamJob, len(runs))
+	for _, run := range runs {
+		job := &flakyStreamJob{runId: run.GetRunId()}
+		jobs = append(jobs, job)
+		jobQueue <- job
+	}
+	close(jobQueue)
+
+	var workers sync.WaitGroup
+	for i := 0; i < parallelCount; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for job := range jobQueue {
+				job.stream, _ = getTestStreamFromCpsRecord(getCpsRecord(job.runId, commsClient))
+			}
+		}()
+	}
+	workers.Wait()
+
+	streamsByRunId := make(map[string]string)
+	for _, job := range jobs {
+		streamsByRunId[job.runId] = job.stream
+	}
+	return streamsByRunId
+}
+
+func getFinishedRuns(runs []galasaapi.Run) []galasaapi.Run {
+	finishedRuns := make([]galasaapi.Run, 0, len(runs))
+	for _, run := range runs {
+		if run.TestStructure.GetResult() != "" {
+			finishedRuns = append(finishedRuns, run)
+		}
+	}
+	return finishedRuns
+}
+
+// FormatFlakyTests renders the ranked list of flaky tests as a table the user can read.
+func FormatFlakyTests(flakyTests []FlakyTest, runsAnalysedCount int) string {
+	buff := strings.Builder{}
+
+	if len(flakyTests) == 0 {
+		buff.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_NO_FLAKY_TESTS_FOUND.Template, runsAnalysedCount))
+	} else {
+		var table [][]string
+
+		headers := []string{
+			HEADER_FLAKY_RANK,
+			HEADER_FLAKY_SCORE,
+			HEADER_FLAKY_FLIPS,
+			HEADER_FLAKY_OBSERVATIONS,
+			HEADER_FLAKY_BUNDLE,
+			HEADER_FLAKY_TEST_CLASS,
+			HEADER_FLAKY_METHOD,
+			HEADER_FLAKY_STREAM,
+			HEADER_FLAKY_EVIDENCE,
+		}
+		table = append(table, headers)
+
+		for index, flakyTest := range flakyTests {
+			line := []string{
+				strconv.Itoa(index + 1),
+				strconv.FormatFloat(flakyTest.Score, 'f', 2, 64),
+				strconv.Itoa(flakyTest.Flips),
+				strconv.Itoa(flakyTest.Observations),
+				flakyTest.Bundle,
+				flakyTest.TestClass,
+				flakyTest.MethodName,
+				flakyTest.Stream,
+				strings.Join(flakyTest.EvidenceRunNames, ","),
+			}
+			table = append(table, line)
+		}
+
+		columnLengths := utils.CalculateMaxLengthOfEachColumn(table)
+		utils.WriteFormattedTableToStringBuilder(table, &buff, columnLengths)
+
+		buff.WriteString("\n")
+		buff.WriteString(fmt.Sprintf("Total:%d RunsAnalysed:%d\n", len(flakyTests), runsAnalysedCount))
+	}
+
+	return buff.String()
+}
+
+// AddFlakyTestsToPortfolio adds each test class containing a flaky method to the portfolio,
+// so they can be re-run for investigation with `runs submit`.
+// The stream given is used if there is one, otherwise each class uses the stream it was flaky on.
+func AddFlakyTestsToPortfolio(flakyTests []FlakyTest, stream string, portfolio *Portfolio) {
+	classesAdded := make(map[string]bool)
+	for _, flakyTest := range flakyTests {
+		classStream := stream
+		if classStream == "" {
+			classStream = flakyTest.Stream
+		}
+		classKey := flakyTest.Bundle + "/" + flakyTest.TestClass + "@" + classStream
+		if !classesAdded[classKey] {
+			classesAdded[classKey] = true
+			portfolioClass := PortfolioClass{
+				Bundle:    flakyTest.Bundle,
+				Class:     flakyTest.TestClass,
+				Stream:    classStream,
+				Overrides: make(map[string]string),
+			}
+			portfolio.Classes = append(portfolio.Classes, portfolioClass)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createFlakyTestRun(runName string, startTime string, methodResults map[string]string) galasaapi.Run {
+	run := *galasaapi.NewRun()
+	run.SetRunId("id-" + runName)
+
+	testStructure := *galasaapi.NewTestStructure()
+	testStructure.SetRunName(runName)
+	testStructure.SetBundle("myBundle")
+	testStructure.SetTestName("my.package.MyTest")
+	testStructure.SetStartTime(startTime)
+	testStructure.SetResult("Passed")
+
+	methods := make([]galasaapi.TestMethod, 0)
+	for methodName, result := range methodResults {
+		method := *galasaapi.NewTestMethod()
+		method.SetClassName("my.package.MyTest")
+		method.SetMethodName(methodName)
+		method.SetResult(result)
+		methods = append(methods, method)
+	}
+	testStructure.SetMethods(methods)
+
+	run.SetTestStructure(testStructure)
+	return run
+}
+
+func createFlakyCpsRecord(stream string, extraProperties string) string {
+	return "framework.test.stream." + stream + ".location=https://my.server/" + stream + "/testcatalog.json\n" +
+		"framework.test.stream." + stream + ".obr=mvn:dev.galasa.example/dev.galasa.example.obr/0.0.1/obr\n" +
+		extraProperties
+}
+
+func createFlakyRunJson(runName string, startTime string, methodName string, methodResult string) string {
+	return fmt.Sprintf(`{
+		"runId": "id-%[1]s",
+		"testStructure": {
+			"runName": "%[1]s",
+			"bundle": "myBundle",
+			"testName": "my.package.MyTest",
+			"status": "finished",
+			"result": "%[4]s",
+			"startTime": "%[2]s",
+			"methods": [{
+				"className": "my.package.MyTest",
+				"methodName": "%[3]s",
+				"type": "test",
+				"result": "%[4]s"
+			}]
+		}
+	}`, runName, startTime, methodName, methodResult)
+}
+
+func newFlakyRunsServletMock(t *testing.T, runJsonById map[string]string, cpsRecordById map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/ras/runs" {
+			runs := make([]string, 0)
+			for _, runJson := range runJsonById {
+				runs = append(runs, runJson)
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(fmt.Sprintf(`{ "pageSize": 100, "amountOfRuns": %d, "runs": [ %s ] }`, len(runs), strings.Join(runs, ","))))
+		} else if strings.HasSuffix(r.URL.Path, "/files/"+CPS_RECORD_ARTIFACT_PATH) {
+			runId := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/ras/runs/"), "/files/"+CPS_RECORD_ARTIFACT_PATH)
+			cpsRecord, isKnown := cpsRecordById[runId]
+			if isKnown {
+				w.Header().Set("Content-Disposition", "attachment")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(cpsRecord))
+			} else {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		} else {
+			runId := strings.TrimPrefix(r.URL.Path, "/ras/runs/")
+			runJson, isKnown := runJsonById[runId]
+			assert.True(t, isKnown, "Unexpected run id requested: "+runId)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(runJson))
+		}
+	}))
+	return server
+}
+
+func TestAnalyseFlakyTestsWithStableResultsFindsNothing(t *testing.T) {
+	// Given...
+	runs := []galasaapi.Run{
+		createFlakyTestRun("U1", "2024-01-01T10:00:00Z", map[string]string{"testA": "Passed"}),
+		createFlakyTestRun("U2", "2024-01-02T10:00:00Z", map[string]string{"testA": "Passed"}),
+		createFlakyTestRun("U3", "2024-01-03T10:00:00Z", map[string]string{"testA": "Passed"}),
+	}
+
+	// When...
+	flakyTests := AnalyseFlakyTests(runs, nil)
+
+	// Then...
+	assert.Empty(t, flakyTests)
+}
+
+func TestAnalyseFlakyTestsCountsFlipsInTimeOrder(t *testing.T) {
+	// Given...
+	// Deliberately out of time-order, the analysis should sort them.
+	runs := []galasaapi.Run{
+		createFlakyTestRun("U3", "2024-01-03T10:00:00Z", map[string]string{"testA": "Passed"}),
+		createFlakyTestRun("U1", "2024-01-01T10:00:00Z", map[string]string{"testA": "Passed"}),
+		createFlakyTestRun("U2", "2024-01-02T10:00:00Z", map[string]string{"testA": "Failed"}),
+		createFlakyTestRun("U4", "2024-01-04T10:00:00Z", map[string]string{"testA": "Passed"}),
+	}
+
+	// When...
+	flakyTests := AnalyseFlakyTests(runs, nil)
+
+	// Then...
+	assert.Len(t, flakyTests, 1)
+	flakyTest := flakyTests[0]
+	assert.Equal(t, "myBundle", flakyTest.Bundle)
+	assert.Equal(t, "my.package.MyTest", flakyTest.TestClass)
+	assert.Equal(t, "testA", flakyTest.MethodName)
+	assert.Equal(t, 4, flakyTest.Observations)
+	assert.Equal(t, 2, flakyTest.Flips)
+	assert.InDelta(t, 2.0/3.0, flakyTest.Score, 0.001)
+	assert.Equal(t, []string{"U1", "U2", "U3"}, flakyTest.EvidenceRunNames)
+}
+
+func TestAnalyseFlakyTestsIgnoresResultsWhichAreNotPassOrFail(t *testing.T) {
+	// Given...
+	runs := []galasaapi.Run{
+		createFlakyTestRun("U1", "2024-01-01T10:00:00Z", map[string]string{"testA": "Passed"}),
+		createFlakyTestRun("U2", "2024-01-02T10:00:00Z", map[string]string{"testA": "EnvFail"}),
+		createFlakyTestRun("U3", "2024-01-03T10:00:00Z", map[string]string{"testA": "Ignored"}),
+		createFlakyTestRun("U4", "2024-01-04T10:00:00Z", map[string]string{"testA": "Success"}),
+	}
+
+	// When...
+	flakyTests := AnalyseFlakyTests(runs, nil)
+
+	// Then...
+	assert.Empty(t, flakyTests)
+}
+
+func TestAnalyseFlakyTestsRanksFlakiestFirst(t *testing.T) {
+	// Given...
+	runs := []galasaapi.Run{
+		createFlakyTestRun("U1", "2024-01-01T10:00:00Z", map[string]string{"testA": "Passed", "testB": "Passed"}),
+		createFlakyTestRun("U2", "2024-01-02T10:00:00Z", map[string]string{"testA": "Passed", "testB": "Failed"}),
+		createFlakyTestRun("U3", "2024-01-03T10:00:00Z", map[string]string{"testA": "Failed", "testB": "Passed"}),
+	}
+
+	// When...
+	flakyTests := AnalyseFlakyTests(runs, nil)
+
+	// Then...
+	assert.Len(t, flakyTests, 2)
+	assert.Equal(t, "testB", flakyTests[0].MethodName)
+	assert.Equal(t, 2, flakyTests[0].Flips)
+	assert.Equal(t, "testA", flakyTests[1].MethodName)
+	assert.Equal(t, 1, flakyTests[1].Flips)
+}
+
+func TestAnalyseFlakyTestsDoesNotCompareResultsFromDifferentStreams(t *testing.T) {
+	// Given...
+	runs := []galasaapi.Run{
+		createFlakyTestRun("U1", "2024-01-01T10:00:00Z", map[string]string{"testA": "Passed"}),
+		createFlakyTestRun("U2", "2024-01-02T10:00:00Z", map[string]string{"testA": "Failed"}),
+		createFlakyTestRun("U3", "2024-01-03T10:00:00Z", map[string]string{"testA": "Passed"}),
+		createFlakyTestRun("U4", "2024-01-04T10:00:00Z", map[string]string{"testA": "Failed"}),
+	}
+	streamsByRunId := map[string]string{
+		"id-U1": "streamA",
+		"id-U2": "streamB",
+		"id-U3": "streamA",
+		"id-U4": "streamB",
+	}
+
+	// When...
+	flakyTests := AnalyseFlakyTests(runs, streamsByRunId)
+
+	// Then...
+	assert.Empty(t, flakyTests)
+}
+
+func TestAnalyseFlakyTestsComparesResultsFromTheSameStream(t *testing.T) {
+	// Given...
+	runs := []galasaapi.Run{
+		createFlakyTestRun("U1", "2024-01-01T10:00:00Z", map[string]string{"testA": "Passed"}),
+		createFlakyTestRun("U2", "2024-01-02T10:00:00Z", map[string]string{"testA": "Failed"}),
+	}
+	streamsByRunId := map[string]string{
+		"id-U1": "myStream",
+		"id-U2": "myStream",
+	}
+
+	// When...
+	flakyTests := AnalyseFlakyTests(runs, streamsByRunId)
+
+	// Then...
+	assert.Len(t, flakyTests, 1)
+	assert.Equal(t, "myStream", flakyTests[0].Stream)
+	assert.Equal(t, 1, flakyTests[0].Flips)
+}
+
+func TestFormatFlakyTestsWithNoFlakyTestsSaysSo(t *testing.T) {
+	// When...
+	output := FormatFlakyTests([]FlakyTest{}, 12)
+
+	// Then...
+	assert.Equal(t, "GAL2505I: No flaky tests were found in 12 finished test runs.\n", output)
+}
+
+func TestFormatFlakyTestsRendersRankedTable(t *testing.T) {
+	// Given...
+	flakyTests := []FlakyTest{
+		{
+			Bundle:           "myBundle",
+			TestClass:        "my.package.MyTest",
+			MethodName:       "testA",
+			Stream:           "myStream",
+			Observations:     4,
+			Flips:            2,
+			Score:            2.0 / 3.0,
+			EvidenceRunNames: []string{"U1", "U2", "U3"},
+		},
+	}
+
+	// When...
+	output := FormatFlakyTests(flakyTests, 4)
+
+	// Then...
+	expected := "rank score flips runs bundle   test-class        method stream   evidence\n" +
+		"1    0.67  2     4    myBundle my.package.MyTest testA  myStream U1,U2,U3\n" +
+		"\n" +
+		"Total:1 RunsAnalysed:4\n"
+	assert.Equal(t, expected, output)
+}
+
+func TestAddFlakyTestsToPortfolioAddsEachClassOnce(t *testing.T) {
+	// Given...
+	flakyTests := []FlakyTest{
+		{Bundle: "myBundle", TestClass: "my.package.MyTest", MethodName: "testA"},
+		{Bundle: "myBundle", TestClass: "my.package.MyTest", MethodName: "testB"},
+		{Bundle: "myBundle", TestClass: "my.package.MyOtherTest", MethodName: "testC"},
+	}
+	portfolio := NewPortfolio()
+
+	// When...
+	AddFlakyTestsToPortfolio(flakyTests, "myStream", portfolio)
+
+	// Then...
+	assert.Len(t, portfolio.Classes, 2)
+	assert.Equal(t, "my.package.MyTest", portfolio.Classes[0].Class)
+	assert.Equal(t, "myBundle", portfolio.Classes[0].Bundle)
+	assert.Equal(t, "myStream", portfolio.Classes[0].Stream)
+	assert.Equal(t, "my.package.MyOtherTest", portfolio.Classes[1].Class)
+}
+
+func TestAddFlakyTestsToPortfolioWithoutStreamUsesTheStreamOfEachFlakyTest(t *testing.T) {
+	// Given...
+	flakyTests := []FlakyTest{
+		{Bundle: "myBundle", TestClass: "my.package.MyTest", MethodName: "testA", Stream: "streamA"},
+		{Bundle: "myBundle", TestClass: "my.package.MyTest", MethodName: "testA", Stream: "streamB"},
+	}
+	portfolio := NewPortfolio()
+
+	// When...
+	AddFlakyTestsToPortfolio(flakyTests, "", portfolio)
+
+	// Then...
+	assert.Len(t, portfolio.Classes, 2)
+	assert.Equal(t, "streamA", portfolio.Classes[0].Stream)
+	assert.Equal(t, "streamB", portfolio.Classes[1].Stream)
+}
+
+func TestFindFlakyTestsWithoutAgeReturnsError(t *testing.T) {
+	// Given...
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient("http://dummy.server")
+
+	// When...
+	err := FindFlakyTests("", "", "", 0, "", "", mockTimeService, mockConsole, commsClient, mockFileSystem)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1247E")
+}
+
+func TestFindFlakyTestsWithNegativeTopCountReturnsError(t *testing.T) {
+	// Given...
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient("http://dummy.server")
+
+	// When...
+	err := FindFlakyTests("30d", "", "", -1, "", "", mockTimeService, mockConsole, commsClient, mockFileSystem)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1248E")
+}
+
+func TestFindFlakyTestsListsFlakyTestsAndWritesPortfolio(t *testing.T) {
+	// Given...
+	runJsonById := map[string]string{
+		"id-U1": createFlakyRunJson("U1", "2024-01-01T10:00:00Z", "testA", "Passed"),
+		"id-U2": createFlakyRunJson("U2", "2024-01-02T10:00:00Z", "testA", "Failed"),
+		"id-U3": createFlakyRunJson("U3", "2024-01-03T10:00:00Z", "testA", "Passed"),
+	}
+	server := newFlakyRunsServletMock(t, runJsonById, nil)
+	defer server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient(server.URL)
+
+	// When...
+	err := FindFlakyTests("30d", "", "", 0, "flaky.yaml", "myStream", mockTimeService, mockConsole, commsClient, mockFileSystem)
+
+	// Then...
+	assert.Nil(t, err)
+	output := mockConsole.ReadText()
+	assert.Contains(t, output, "1    1.00  2     3    myBundle my.package.MyTest testA         U1,U2,U3")
+	assert.Contains(t, output, "Total:1 RunsAnalysed:3")
+
+	portfolio, err := ReadPortfolio(mockFileSystem, "flaky.yaml")
+	assert.Nil(t, err)
+	assert.Len(t, portfolio.Classes, 1)
+	assert.Equal(t, "my.package.MyTest", portfolio.Classes[0].Class)
+	assert.Equal(t, "myStream", portfolio.Classes[0].Stream)
+}
+
+func TestFindFlakyTestsDoesNotReportTestWhichPassesOnOneStreamAndFailsOnAnother(t *testing.T) {
+	// Given...
+	runJsonById := map[string]string{
+		"id-U1": createFlakyRunJson("U1", "2024-01-01T10:00:00Z", "testA", "Passed"),
+		"id-U2": createFlakyRunJson("U2", "2024-01-02T10:00:00Z", "testA", "Failed"),
+		"id-U3": createFlakyRunJson("U3", "2024-01-03T10:00:00Z", "testA", "Passed"),
+		"id-U4": createFlakyRunJson("U4", "2024-01-04T10:00:00Z", "testA", "Failed"),
+	}
+	cpsRecordById := map[string]string{
+		"id-U1": createFlakyCpsRecord("streamA", ""),
+		"id-U2": createFlakyCpsRecord("streamB", ""),
+		"id-U3": createFlakyCpsRecord("streamA", ""),
+		"id-U4": createFlakyCpsRecord("streamB", ""),
+	}
+	server := newFlakyRunsServletMock(t, runJsonById, cpsRecordById)
+	defer server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient(server.URL)
+
+	// When...
+	err := FindFlakyTests("30d", "", "", 0, "", "", mockTimeService, mockConsole, commsClient, mockFileSystem)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "GAL2505I: No flaky tests were found in 4 finished test runs.\n", mockConsole.ReadText())
+}
+
+func TestFindFlakyTestsReportsTestWhoseFailingRunReadMoreOrFewerCpsProperties(t *testing.T) {
+	// Given...
+	runJsonById := map[string]string{
+		"id-U1": createFlakyRunJson("U1", "2024-01-01T10:00:00Z", "testA", "Passed"),
+		"id-U2": createFlakyRunJson("U2", "2024-01-02T10:00:00Z", "testA", "Failed"),
+		"id-U3": createFlakyRunJson("U3", "2024-01-03T10:00:00Z", "testA", "Passed"),
+		"id-U4": createFlakyRunJson("U4", "2024-01-04T10:00:00Z", "testA", "Failed"),
+	}
+
+	// Failing runs stop early, so don't read all the properties the passing runs do, or read extra ones.
+	cpsRecordById := map[string]string{
+		"id-U1": createFlakyCpsRecord("myStream", "zos.image.IMAGE1.ipv4.hostname=my.host\n"),
+		"id-U2": createFlakyCpsRecord("myStream", ""),
+		"id-U3": createFlakyCpsRecord("myStream", "zos.image.IMAGE1.ipv4.hostname=my.host\n"),
+		"id-U4": createFlakyCpsRecord("myStream", "zos.image.IMAGE1.ipv4.hostname=my.host\nzos.dse.tag.PRIMARY.imageid=IMAGE1\n"),
+	}
+	server := newFlakyRunsServletMock(t, runJsonById, cpsRecordById)
+	defer server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	mockFileSystem := files.NewMockFileSystem()
+	commsClient := api.NewMockAPICommsClient(server.URL)
+
+	// When...
+	err := FindFlakyTests("30d", "", "", 0, "", "", mockTimeService, mockConsole, commsClient, mockFileSystem)
+
+	// Then...
+	assert.Nil(t, err)
+	output := mockConsole.ReadText()
+	assert.Contains(t, output, "1    1.00  3     4    myBundle my.package.MyTest testA  myStream U1,U2,U3,U4")
+	assert.Contains(t, output, "Total:1 RunsAnalysed:4")
+}
@@ -0,0 +1,296 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/embedded"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/spi"
+)
+
+const (
+	DEFAULT_RUN_LOG_POLL_INTERVAL_SECONDS int = 5
+
+	// Bold red, then back to normal.
+	RUN_LOG_HIGHLIGHT_START = "\033[1;31m"
+	RUN_LOG_HIGHLIGHT_END   = "\033[0m"
+
+	RUN_STATUS_FINISHED = "finished"
+)
+
+// Decides which lines of the run log the user gets to see, and how they look.
+type runLogFilter struct {
+	grepRegex       *regexp.Regexp
+	shouldHighlight bool
+}
+
+// RunsLog - performs all the logic to implement the `galasactl runs log` command,
+// but in a unit-testable manner.
+func RunsLog(
+	runName string,
+	shouldFollow bool,
+	tailLineCount int,
+	grepPattern string,
+	shouldHighlight bool,
+	pollIntervalSeconds int,
+	timeService spi.TimeService,
+	console spi.Console,
+	commsClient api.APICommsClient,
+) error {
+	var err error
+	var filter *runLogFilter
+	var run *galasaapi.Run
+
+	log.Printf("RunsLog entered.")
+
+	err = ValidateRunName(runName)
+
+	if err == nil && tailLineCount < 0 {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_RUN_LOG_TAIL, tailLineCount)
+	}
+
+	if err == nil {
+		filter, err = newRunLogFilter(grepPattern, shouldHighlight)
+	}
+
+	if err == nil {
+		run, err = getLatestRunByName(runName, galasaErrors.GALASA_ERROR_RUN_LOG_RUN_NOT_FOUND, timeService, commsClient)
+	}
+
+	if err == nil {
+		if pollIntervalSeconds < 1 {
+			pollIntervalSeconds = DEFAULT_RUN_LOG_POLL_INTERVAL_SECONDS
+		}
+
+		var restApiVersion string
+		restApiVersion, err = embedded.GetGalasactlRestApiVersion()
+		if err == nil {
+			err = writeRunLog(*run, runName, shouldFollow, tailLineCount, filter, pollIntervalSeconds, restApiVersion, timeService, console, commsClient)
+		}
+	}
+
+	log.Printf("RunsLog exiting. err is %v", err)
+	return err
+}
+
+// More than one run can have the same name, when a test has been re-run.
+// The most recent attempt is the one whose log the user is most likely to want.
+func getLatestRunByName(
+	runName string,
+	runNotFoundMessageType *galasaErrors.MessageType,
+	timeService spi.TimeService,
+	commsClient api.APICommsClient,
+) (*galasaapi.Run, error) {
+	var err error
+	var latestRun *galasaapi.Run
+	var runs []galasaapi.Run
+
+	requestorParameter := ""
+	resultParameter := ""
+	group := ""
+	fromAgeMins := 0
+	toAgeMins := 0
+	shouldGetActive := false
+	runs, err = GetRunsFromRestApi(runName, requestorParameter, resultParameter, fromAgeMins, toAgeMins, shouldGetActive, timeService, commsClient, group)
+	if err == nil {
+		for index, run := range runs {
+			if latestRun == nil || isRunLaterThan(run, *latestRun) {
+				latestRun = &runs[index]
+			}
+		}
+
+		if latestRun == nil {
+			err = galasaErrors.NewGalasaError(runNotFoundMessageType, runName)
+		}
+	}
+	return latestRun, err
+}
+
+// Timestamps are in RFC3339 format, so can be compared as strings.
+func isRunLaterThan(run galasaapi.Run, otherRun galasaapi.Run) bool {
+	testStructure := run.GetTestStructure()
+	otherTestStructure := otherRun.GetTestStructure()
+
+	isLater := testStructure.GetQueued() > otherTestStructure.GetQueued()
+	if testStructure.GetQueued() == otherTestStructure.GetQueued() {
+		isLater = testStructure.GetStartTime() > otherTestStructure.GetStartTime()
+	}
+	return isLater
+}
+
+func writeRunLog(
+	run galasaapi.Run,
+	runName string,
+	shouldFollow bool,
+	tailLineCount int,
+	filter *runLogFilter,
+	pollIntervalSeconds int,
+	restApiVersion string,
+	timeService spi.TimeService,
+	console spi.Console,
+	commsClient api.APICommsClient,
+) error {
+	var err error
+	var runLog string
+
+	runId := run.GetRunId()
+	testStructure := run.GetTestStructure()
+	isFinished := !shouldFollow || testStructure.GetStatus() == RUN_STATUS_FINISHED
+
+	// How much of the run log has been shown to the user so far.
+	var offset int
+
+	runLog, err = getRunLogFromRestApi(runId, runName, commsClient, restApiVersion)
+	if err == nil {
+		var lines []string
+		lines, offset = getNewRunLogLines(runLog, offset, isFinished)
+		lines = filter.apply(lines)
+		if tailLineCount > 0 && len(lines) > tailLineCount {
+			lines = lines[len(lines)-tailLineCount:]
+		}
+		err = writeRunLogLines(lines, console)
+	}
+
+	for err == nil && !isFinished {
+		timeService.Sleep(time.Duration(pollIntervalSeconds) * time.Second)
+
+		// Check the status before getting the log, so the last log we get is complete.
+		var latestRun *galasaapi.Run
+		latestRun, err = getRunByRunIdFromRestApi(runId, commsClient, restApiVersion)
+		if err == nil {
+			if latestRun != nil {
+				latestTestStructure := latestRun.GetTestStructure()
+				isFinished = latestTestStructure.GetStatus() == RUN_STATUS_FINISHED
+			}
+
+			runLog, err = getRunLogFromRestApi(runId, runName, commsClient, restApiVersion)
+			if err == nil {
+				var lines []string
+				lines, offset = getNewRunLogLines(runLog, offset, isFinished)
+				err = writeRunLogLines(filter.apply(lines), console)
+			}
+		}
+	}
+
+	return err
+}
+
+// Gets the lines of the run log which haven't been shown yet, along with the new offset
+// of the part of the log which has been shown.
+// While the run is still going, the last line may only be partly written, so it is held
+// back until it is complete or the run finishes.
+func getNewRunLogLines(runLog string, offset int, isFinished bool) ([]string, int) {
+	lines := make([]string, 0)
+
+	if len(runLog) < offset {
+		// The log is shorter than we've already shown, so the run must have been reset and started again.
+		log.Printf("Run log has shrunk from %v to %v bytes. Showing it from the start.", offset, len(runLog))
+		offset = 0
+	}
+
+	newContent := runLog[offset:]
+	if !isFinished {
+		lastNewLineIndex := strings.LastIndex(newContent, "\n")
+		newContent = newContent[:lastNewLineIndex+1]
+	}
+	offset += len(newContent)
+
+	newContent = strings.TrimSuffix(newContent, "\n")
+	if newContent != "" {
+		lines = strings.Split(newContent, "\n")
+	}
+	return lines, offset
+}
+
+func writeRunLogLines(lines []string, console spi.Console) error {
+	var err error
+	if len(lines) > 0 {
+		err = console.WriteString(strings.Join(lines, "\n") + "\n")
+	}
+	return err
+}
+
+func getRunLogFromRestApi(
+	runId string,
+	runName string,
+	commsClient api.APICommsClient,
+	restApiVersion string,
+) (string, error) {
+	var err error
+	var runLog string
+
+	err = commsClient.RunAuthenticatedCommandWithRateLimitRetries(func(apiClient *galasaapi.APIClient) error {
+		var err error
+		var httpResponse *http.Response
+		var context context.Context = nil
+
+		log.Printf("Getting the run log for run %v\n", runId)
+		runLog, httpResponse, err = apiClient.ResultArchiveStoreAPIApi.GetRasRunLog(context, runId).ClientApiVersion(restApiVersion).Execute()
+
+		var statusCode int
+		if httpResponse != nil {
+			defer httpResponse.Body.Close()
+			statusCode = httpResponse.StatusCode
+		}
+
+		if err != nil {
+			err = galasaErrors.NewGalasaErrorWithHttpStatusCode(statusCode, galasaErrors.GALASA_ERROR_RETRIEVING_RUN_LOG_FAILED, runName, err.Error())
+		} else {
+			if statusCode != http.StatusOK {
+				err = galasaErrors.NewGalasaErrorWithHttpStatusCode(statusCode, galasaErrors.GALASA_ERROR_RETRIEVING_RUN_LOG_FAILED, runName, "Unexpected http status code "+strconv.Itoa(statusCode))
+			}
+		}
+		return err
+	})
+	return runLog, err
+}
+
+func newRunLogFilter(grepPattern string, shouldHighlight bool) (*runLogFilter, error) {
+	var err error
+	filter := &runLogFilter{shouldHighlight: shouldHighlight}
+
+	if grepPattern != "" {
+		filter.grepRegex, err = regexp.Compile(grepPattern)
+		if err != nil {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_INVALID_RUN_LOG_GREP, grepPattern, err.Error())
+		}
+	}
+	return filter, err
+}
+
+// Drops the lines which don't match the --grep pattern, highlighting the matching parts
+// of the lines which are left.
+func (filter *runLogFilter) apply(lines []string) []string {
+	filteredLines := lines
+	if filter.grepRegex != nil {
+		filteredLines = make([]string, 0)
+		for _, line := range lines {
+			if filter.grepRegex.MatchString(line) {
+				if filter.shouldHighlight {
+					line = filter.grepRegex.ReplaceAllStringFunc(line, func(match string) string {
+						highlighted := match
+						if match != "" {
+							highlighted = RUN_LOG_HIGHLIGHT_START + match + RUN_LOG_HIGHLIGHT_END
+						}
+						return highlighted
+					})
+				}
+				filteredLines = append(filteredLines, line)
+			}
+		}
+	}
+	return filteredLines
+}
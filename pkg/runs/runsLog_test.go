@@ -0,0 +1,324 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createRunLogRunJson(runId string, runName string, status string, queuedTime string) string {
+	return fmt.Sprintf(`{
+		"runId": "%s",
+		"testStructure": {
+			"runName": "%s",
+			"bundle": "myBundle",
+			"testName": "my.package.MyTest",
+			"status": "%s",
+			"queued": "%s"
+		}
+	}`, runId, runName, status, queuedTime)
+}
+
+// A mock RAS which serves a run, or re-runs of it, along with a run log which grows
+// each time it is asked for. The run finishes once the last of the logs has been served.
+type runLogServletMock struct {
+	t                *testing.T
+	runJsons         []string
+	runId            string
+	runName          string
+	runLogs          []string
+	runLogCallCount  int
+	runLogStatusCode int
+}
+
+func newRunLogServletMock(t *testing.T, runId string, runName string, runLogs ...string) *runLogServletMock {
+	return &runLogServletMock{
+		t:                t,
+		runJsons:         []string{createRunLogRunJson(runId, runName, "running", "2024-01-01T10:00:00Z")},
+		runId:            runId,
+		runName:          runName,
+		runLogs:          runLogs,
+		runLogStatusCode: http.StatusOK,
+	}
+}
+
+func (mock *runLogServletMock) isFinished() bool {
+	return mock.runLogCallCount >= len(mock.runLogs)-1
+}
+
+func (mock *runLogServletMock) start() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ras/runs":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(fmt.Sprintf(`{ "pageSize": 100, "amountOfRuns": %d, "runs": [ %s ] }`,
+				len(mock.runJsons), strings.Join(mock.runJsons, ","))))
+
+		case "/ras/runs/" + mock.runId:
+			status := "running"
+			if mock.isFinished() {
+				status = "finished"
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(createRunLogRunJson(mock.runId, mock.runName, status, "2024-01-01T10:00:00Z")))
+
+		case "/ras/runs/" + mock.runId + "/runlog":
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(mock.runLogStatusCode)
+			if mock.runLogStatusCode == http.StatusOK {
+				w.Write([]byte(mock.runLogs[mock.runLogCallCount]))
+				if mock.runLogCallCount < len(mock.runLogs)-1 {
+					mock.runLogCallCount++
+				}
+			}
+
+		default:
+			assert.Fail(mock.t, "Unexpected request to "+r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestRunsLogWithNoRunNameReturnsError(t *testing.T) {
+	// Given...
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient("http://dummy.server")
+
+	// When...
+	err := RunsLog("", false, 0, "", true, 0, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1075E")
+}
+
+func TestRunsLogWithNegativeTailReturnsError(t *testing.T) {
+	// Given...
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient("http://dummy.server")
+
+	// When...
+	err := RunsLog("U123", false, -1, "", true, 0, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1252E")
+}
+
+func TestRunsLogWithBadGrepPatternReturnsError(t *testing.T) {
+	// Given...
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient("http://dummy.server")
+
+	// When...
+	err := RunsLog("U123", false, 0, "ERROR(", true, 0, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1253E")
+}
+
+func TestRunsLogWithUnknownRunReturnsError(t *testing.T) {
+	// Given...
+	mock := newRunLogServletMock(t, "id-123", "U123", "")
+	mock.runJsons = []string{}
+	server := mock.start()
+	defer server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.URL)
+
+	// When...
+	err := RunsLog("U123", false, 0, "", true, 0, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1250E")
+}
+
+func TestRunsLogShowsWholeRunLog(t *testing.T) {
+	// Given...
+	mock := newRunLogServletMock(t, "id-123", "U123", "line 1\nline 2\nline 3\n")
+	server := mock.start()
+	defer server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.URL)
+
+	// When...
+	err := RunsLog("U123", false, 0, "", true, 0, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "line 1\nline 2\nline 3\n", mockConsole.ReadText())
+}
+
+func TestRunsLogWithTailShowsLastLines(t *testing.T) {
+	// Given...
+	mock := newRunLogServletMock(t, "id-123", "U123", "line 1\nline 2\nline 3\n")
+	server := mock.start()
+	defer server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.URL)
+
+	// When...
+	err := RunsLog("U123", false, 2, "", true, 0, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "line 2\nline 3\n", mockConsole.ReadText())
+}
+
+func TestRunsLogWithGrepShowsMatchingLinesHighlighted(t *testing.T) {
+	// Given...
+	mock := newRunLogServletMock(t, "id-123", "U123", "INFO started\nERROR broken\nINFO stopped\nWARN ERROR again")
+	server := mock.start()
+	defer server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.URL)
+
+	// When...
+	err := RunsLog("U123", false, 0, "ERROR", true, 0, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t,
+		RUN_LOG_HIGHLIGHT_START+"ERROR"+RUN_LOG_HIGHLIGHT_END+" broken\n"+
+			"WARN "+RUN_LOG_HIGHLIGHT_START+"ERROR"+RUN_LOG_HIGHLIGHT_END+" again\n",
+		mockConsole.ReadText())
+}
+
+func TestRunsLogWithGrepAndNoHighlightShowsPlainMatchingLines(t *testing.T) {
+	// Given...
+	mock := newRunLogServletMock(t, "id-123", "U123", "INFO started\nERROR broken\nINFO stopped\n")
+	server := mock.start()
+	defer server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.URL)
+
+	// When...
+	err := RunsLog("U123", false, 0, "ERR.R|stopped", false, 0, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "ERROR broken\nINFO stopped\n", mockConsole.ReadText())
+}
+
+func TestRunsLogFollowShowsOnlyNewLinesUntilRunFinishes(t *testing.T) {
+	// Given...
+	mock := newRunLogServletMock(t, "id-123", "U123",
+		"line 1\nline 2 is half",
+		"line 1\nline 2 is half written\nline 3\n",
+		"line 1\nline 2 is half written\nline 3\nline 4",
+	)
+	server := mock.start()
+	defer server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	startTime := mockTimeService.Now()
+	commsClient := api.NewMockAPICommsClient(server.URL)
+
+	// When...
+	err := RunsLog("U123", true, 0, "", true, 10, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "line 1\nline 2 is half written\nline 3\nline 4\n", mockConsole.ReadText())
+	assert.Equal(t, 20*time.Second, mockTimeService.Now().Sub(startTime))
+}
+
+func TestRunsLogWithoutFollowDoesNotPollActiveRun(t *testing.T) {
+	// Given...
+	mock := newRunLogServletMock(t, "id-123", "U123", "line 1\nline 2 is half", "line 1\nline 2 is half written\n")
+	server := mock.start()
+	defer server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	startTime := mockTimeService.Now()
+	commsClient := api.NewMockAPICommsClient(server.URL)
+
+	// When...
+	err := RunsLog("U123", false, 0, "", true, 0, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "line 1\nline 2 is half\n", mockConsole.ReadText())
+	assert.Equal(t, startTime, mockTimeService.Now())
+}
+
+func TestRunsLogPicksLatestReRun(t *testing.T) {
+	// Given...
+	mock := newRunLogServletMock(t, "id-new", "U123", "the latest attempt\n")
+	mock.runJsons = []string{
+		createRunLogRunJson("id-old", "U123", "finished", "2024-01-01T10:00:00Z"),
+		createRunLogRunJson("id-new", "U123", "finished", "2024-01-02T10:00:00Z"),
+	}
+	server := mock.start()
+	defer server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.URL)
+
+	// When...
+	err := RunsLog("U123", false, 0, "", true, 0, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "the latest attempt\n", mockConsole.ReadText())
+}
+
+func TestRunsLogWhenServerFailsReturnsError(t *testing.T) {
+	// Given...
+	mock := newRunLogServletMock(t, "id-123", "U123", "")
+	mock.runLogStatusCode = http.StatusInternalServerError
+	server := mock.start()
+	defer server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.URL)
+
+	// When...
+	err := RunsLog("U123", false, 0, "", true, 0, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1251E")
+}
+
+func TestGetNewRunLogLinesAfterLogShrinksStartsAgain(t *testing.T) {
+	// When...
+	lines, offset := getNewRunLogLines("restarted\n", 100, false)
+
+	// Then...
+	assert.Equal(t, []string{"restarted"}, lines)
+	assert.Equal(t, 10, offset)
+}
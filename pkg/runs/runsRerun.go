@@ -0,0 +1,196 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/props"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+)
+
+const (
+	// The artifact holding the CPS properties which a test run used while it ran.
+	CPS_RECORD_ARTIFACT_PATH = "framework/cps_record.properties"
+
+	// Test stream properties look like framework.test.stream.<stream name>.<setting>
+	CPS_TEST_STREAM_PROPERTY_PREFIX = "framework.test.stream."
+	CPS_TEST_STREAM_OBR_SUFFIX      = ".obr"
+)
+
+// The details of a finished test run which are needed to submit the same test again.
+type RerunDetails struct {
+	RunName   string
+	Bundle    string
+	Class     string
+	Stream    string
+	Obr       string
+	Requestor string
+	Group     string
+}
+
+// GetRerunDetails - finds out how the named test run was launched, so that it can be launched again.
+//
+// The RAS doesn't record the test stream or OBR of a run directly, so they are worked out from
+// the CPS properties the run used. A stream passed in takes precedence over the recorded one,
+// as do any OBRs passed in for a local launch. A stream is needed to launch a test remotely,
+// and an OBR to launch it locally.
+func GetRerunDetails(
+	runName string,
+	stream string,
+	localObrs []string,
+	isLocal bool,
+	timeService spi.TimeService,
+	commsClient api.APICommsClient,
+) (*RerunDetails, error) {
+	var err error
+	var run *galasaapi.Run
+	var details *RerunDetails
+
+	log.Printf("GetRerunDetails entered. runName: %s", runName)
+
+	run, err = getLatestRunByName(runName, galasaErrors.GALASA_ERROR_RERUN_RUN_NOT_FOUND, timeService, commsClient)
+	if err == nil {
+		testStructure := run.GetTestStructure()
+		if testStructure.GetStatus() != "finished" {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_RERUN_RUN_NOT_FINISHED, runName, testStructure.GetStatus())
+		} else {
+			details = &RerunDetails{
+				RunName:   runName,
+				Bundle:    testStructure.GetBundle(),
+				Class:     testStructure.GetTestName(),
+				Stream:    stream,
+				Requestor: testStructure.GetRequestor(),
+				Group:     testStructure.GetGroup(),
+			}
+
+			isObrFromCommandLine := isLocal && len(localObrs) > 0
+			if details.Stream == "" || !isObrFromCommandLine {
+				cpsRecord := getCpsRecord(run.GetRunId(), commsClient)
+				recordedStream, recordedObr := getTestStreamFromCpsRecord(cpsRecord)
+				if details.Stream == "" {
+					details.Stream = recordedStream
+				}
+				if !isObrFromCommandLine {
+					details.Obr = recordedObr
+				}
+			}
+
+			if isLocal && details.Obr == "" && !isObrFromCommandLine {
+				err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_RERUN_OBR_UNKNOWN, runName)
+			} else if !isLocal && details.Stream == "" {
+				err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_RERUN_STREAM_UNKNOWN, runName)
+			}
+		}
+	}
+
+	log.Printf("GetRerunDetails exiting. details: %v err: %v", details, err)
+	return details, err
+}
+
+// Older runs, or runs which failed early, may not have a CPS record. That isn't an error in
+// itself, as the user can still say which stream or OBR to use, so an empty set of properties is returned.
+func getCpsRecord(runId string, commsClient api.APICommsClient) props.JavaProperties {
+	cpsRecord := props.JavaProperties{}
+
+	artifactData, isEmpty, httpResponse, err := GetFileFromRestApi(runId, CPS_RECORD_ARTIFACT_PATH, commsClient)
+	if err == nil && !isEmpty {
+		var contents []byte
+		contents, err = io.ReadAll(artifactData)
+		if err == nil {
+			cpsRecord = props.ReadProperties(string(contents))
+		}
+	}
+
+	if httpResponse != nil {
+		closeResponseBody(httpResponse)
+	}
+
+	if err != nil {
+		log.Printf("Could not read the CPS record of run %s. Reason: %v\n", runId, err)
+	}
+	return cpsRecord
+}
+
+func closeResponseBody(httpResponse *http.Response) {
+	closeErr := httpResponse.Body.Close()
+	if closeErr != nil {
+		log.Printf("Failed to close the http response body. Reason: %v\n", closeErr)
+	}
+}
+
+// Returns the name of the test stream which the run used, and the OBR from that stream.
+func getTestStreamFromCpsRecord(cpsRecord props.JavaProperties) (string, string) {
+	stream := ""
+	obr := ""
+	for key, value := range cpsRecord {
+		if strings.HasPrefix(key, CPS_TEST_STREAM_PROPERTY_PREFIX) {
+			streamSetting := strings.TrimPrefix(key, CPS_TEST_STREAM_PROPERTY_PREFIX)
+			lastDotIndex := strings.LastIndex(streamSetting, ".")
+			if lastDotIndex > 0 {
+				// The stream with an OBR is the one the test was loaded from.
+				if strings.HasSuffix(key, CPS_TEST_STREAM_OBR_SUFFIX) {
+					stream = streamSetting[:lastDotIndex]
+					obr = value
+				} else if stream == "" {
+					stream = streamSetting[:lastDotIndex]
+				}
+			}
+		}
+	}
+	log.Printf("Test stream found in the CPS record: '%s', obr: '%s'\n", stream, obr)
+	return stream, obr
+}
+
+// ExecuteRerun submits a test which has run before so that it runs again in the same way,
+// then monitors and reports on it like any other submitted test.
+//
+// The RAS doesn't record which overrides the original run was given, so only the
+// overrides passed in the parameters are used.
+func (submitter *Submitter) ExecuteRerun(
+	originalRun *RerunDetails,
+	params *utils.RunsSubmitCmdValues,
+) error {
+	var err error
+
+	submitter.correctPollingParams(params)
+
+	// Keep the new run together with the original one, unless the user wants otherwise.
+	if params.GroupName == "" {
+		params.GroupName = originalRun.Group
+	}
+
+	err = submitter.correctGroupAndFileParams(params)
+	if err == nil {
+		var runOverrides map[string]string
+		runOverrides, err = submitter.buildOverrideMap(*params)
+		if err == nil {
+			err = submitter.console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_RERUN_STARTING.Template,
+				originalRun.Bundle, originalRun.Class, originalRun.RunName, originalRun.Requestor, params.GroupName))
+		}
+
+		if err == nil {
+			portfolio := NewPortfolio()
+			portfolio.Classes = append(portfolio.Classes, PortfolioClass{
+				Bundle:    originalRun.Bundle,
+				Class:     originalRun.Class,
+				Stream:    originalRun.Stream,
+				Obr:       originalRun.Obr,
+				Overrides: make(map[string]string),
+			})
+			err = submitter.executePortfolio(portfolio, runOverrides, *params)
+		}
+	}
+	return err
+}
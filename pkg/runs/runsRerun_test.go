@@ -0,0 +1,293 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/images"
+	"github.com/galasa-dev/cli/pkg/launcher"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	RERUN_CPS_RECORD = "framework.resultarchive.store=couchdb:https://my.couchdb\n" +
+		"framework.test.stream.myStream.location=https://my.server/testcatalog.json\n" +
+		"framework.test.stream.myStream.obr=mvn:dev.galasa.example/dev.galasa.example.obr/0.0.1/obr\n"
+)
+
+func createMockRunToRerunJson(runName string, runId string, status string) string {
+	run := createMockRun(runName, runId)
+	run.TestStructure.SetStatus(status)
+	run.TestStructure.SetBundle("dev.galasa.example")
+	run.TestStructure.SetTestName("dev.galasa.example.MyTest")
+	run.TestStructure.SetRequestor("originalUser")
+	run.TestStructure.SetGroup("originalGroup")
+	runBytes, _ := json.Marshal(run)
+	return string(runBytes)
+}
+
+func newGetRunToRerunInteraction(t *testing.T, runName string, runJson string) utils.HttpInteraction {
+	getRunsInteraction := utils.NewHttpInteraction("/ras/runs", http.MethodGet)
+	getRunsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		WriteMockRasRunsResponse(t, writer, req, runName, []string{runJson})
+	}
+	return getRunsInteraction
+}
+
+func newGetCpsRecordInteraction(runId string, cpsRecord string) utils.HttpInteraction {
+	cpsRecordInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/files/framework/cps_record.properties", http.MethodGet)
+	cpsRecordInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		if cpsRecord == "" {
+			writer.WriteHeader(http.StatusNotFound)
+		} else {
+			writer.Header().Set("Content-Disposition", "attachment")
+			writer.Write([]byte(cpsRecord))
+		}
+	}
+	return cpsRecordInteraction
+}
+
+func TestGetRerunDetailsFindsStreamAndObrInCpsRecord(t *testing.T) {
+	// Given...
+	interactions := []utils.HttpInteraction{
+		newGetRunToRerunInteraction(t, "U1", createMockRunToRerunJson("U1", "id-U1", "finished")),
+		newGetCpsRecordInteraction("id-U1", RERUN_CPS_RECORD),
+	}
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	details, err := GetRerunDetails("U1", "", []string{}, false, utils.NewMockTimeService(), commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, &RerunDetails{
+		RunName:   "U1",
+		Bundle:    "dev.galasa.example",
+		Class:     "dev.galasa.example.MyTest",
+		Stream:    "myStream",
+		Obr:       "mvn:dev.galasa.example/dev.galasa.example.obr/0.0.1/obr",
+		Requestor: "originalUser",
+		Group:     "originalGroup",
+	}, details)
+}
+
+func TestGetRerunDetailsStreamPassedInTakesPrecedence(t *testing.T) {
+	// Given...
+	interactions := []utils.HttpInteraction{
+		newGetRunToRerunInteraction(t, "U1", createMockRunToRerunJson("U1", "id-U1", "finished")),
+		newGetCpsRecordInteraction("id-U1", RERUN_CPS_RECORD),
+	}
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	details, err := GetRerunDetails("U1", "otherStream", []string{}, false, utils.NewMockTimeService(), commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "otherStream", details.Stream)
+}
+
+func TestGetRerunDetailsWithoutCpsRecordOrStreamReturnsError(t *testing.T) {
+	// Given...
+	interactions := []utils.HttpInteraction{
+		newGetRunToRerunInteraction(t, "U1", createMockRunToRerunJson("U1", "id-U1", "finished")),
+		newGetCpsRecordInteraction("id-U1", ""),
+	}
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	_, err := GetRerunDetails("U1", "", []string{}, false, utils.NewMockTimeService(), commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1269E: The test stream used by run 'U1' could not be found in its records. Use the '--stream' flag")
+}
+
+func TestGetRerunDetailsLocallyWithoutObrReturnsError(t *testing.T) {
+	// Given...
+	interactions := []utils.HttpInteraction{
+		newGetRunToRerunInteraction(t, "U1", createMockRunToRerunJson("U1", "id-U1", "finished")),
+		newGetCpsRecordInteraction("id-U1", ""),
+	}
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	_, err := GetRerunDetails("U1", "myStream", []string{}, true, utils.NewMockTimeService(), commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1270E: The OBR used by run 'U1' could not be found in its records. Use the '--obr' flag")
+}
+
+func TestGetRerunDetailsLocallyWithObrsAndStreamDoesNotReadCpsRecord(t *testing.T) {
+	// Given...
+	// The mock server fails the test if the CPS record is asked for.
+	interactions := []utils.HttpInteraction{
+		newGetRunToRerunInteraction(t, "U1", createMockRunToRerunJson("U1", "id-U1", "finished")),
+	}
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	details, err := GetRerunDetails("U1", "myStream", []string{"mvn:a/b/1/obr"}, true, utils.NewMockTimeService(), commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "myStream", details.Stream)
+	assert.Equal(t, "", details.Obr, "The OBRs from the command line are used instead")
+}
+
+func TestGetRerunDetailsOfActiveRunReturnsError(t *testing.T) {
+	// Given...
+	interactions := []utils.HttpInteraction{
+		newGetRunToRerunInteraction(t, "U1", createMockRunToRerunJson("U1", "id-U1", "running")),
+	}
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	_, err := GetRerunDetails("U1", "", []string{}, false, utils.NewMockTimeService(), commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1268E: The run named 'U1' could not be rerun because it has not finished yet. Its status is 'running'.")
+}
+
+func TestGetRerunDetailsOfUnknownRunReturnsError(t *testing.T) {
+	// Given...
+	getRunsInteraction := utils.NewHttpInteraction("/ras/runs", http.MethodGet)
+	getRunsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		WriteMockRasRunsResponse(t, writer, req, "U1", []string{})
+	}
+
+	server := utils.NewMockHttpServer(t, []utils.HttpInteraction{getRunsInteraction})
+	defer server.Server.Close()
+
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	_, err := GetRerunDetails("U1", "", []string{}, false, utils.NewMockTimeService(), commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1267E: The run named 'U1' could not be rerun because it was not found")
+}
+
+func newRerunTestSubmitter(t *testing.T, mockLauncher launcher.Launcher, console *utils.MockConsole) *Submitter {
+	mockFileSystem := files.NewMockFileSystem()
+	env := utils.NewMockEnv()
+
+	galasaHome, err := utils.NewGalasaHome(mockFileSystem, env, "")
+	if err != nil {
+		assert.Fail(t, "Should not have failed! message = %s", err.Error())
+	}
+
+	return NewSubmitter(
+		galasaHome,
+		mockFileSystem,
+		mockLauncher,
+		utils.NewMockTimeService(),
+		utils.NewRealTimedSleeper(),
+		env,
+		console,
+		images.NewImageExpanderNullImpl(),
+	)
+}
+
+func newRerunDetailsForTest() *RerunDetails {
+	return &RerunDetails{
+		RunName:   "U1",
+		Bundle:    "dev.galasa.example",
+		Class:     "dev.galasa.example.MyTest",
+		Stream:    "myStream",
+		Obr:       "mvn:dev.galasa.example/dev.galasa.example.obr/0.0.1/obr",
+		Requestor: "originalUser",
+		Group:     "originalGroup",
+	}
+}
+
+func TestExecuteRerunSubmitsTheSameTestInTheOriginalGroup(t *testing.T) {
+	// Given...
+	mockLauncher := launcher.NewMockLauncher()
+	console := utils.NewMockConsole()
+	submitter := newRerunTestSubmitter(t, mockLauncher, console)
+
+	params := &utils.RunsSubmitCmdValues{
+		Overrides: []string{"a=b"},
+	}
+
+	// When...
+	err := submitter.ExecuteRerun(newRerunDetailsForTest(), params)
+
+	// Then...
+	assert.Nil(t, err)
+
+	launches := mockLauncher.GetRecordedLaunchRecords()
+	assert.Equal(t, 1, len(launches))
+	if len(launches) > 0 {
+		assert.Equal(t, "originalGroup", launches[0].GroupName)
+		assert.Equal(t, "dev.galasa.example/dev.galasa.example.MyTest", launches[0].ClassName)
+		assert.Equal(t, "myStream", launches[0].Stream)
+		assert.Equal(t, "mvn:dev.galasa.example/dev.galasa.example.obr/0.0.1/obr", launches[0].ObrFromPortfolio)
+		assert.Equal(t, "b", launches[0].Overrides["a"])
+	}
+
+	output := console.ReadText()
+	assert.Contains(t, output, "GAL2520I: Rerunning test 'dev.galasa.example/dev.galasa.example.MyTest' from run 'U1',"+
+		" which was originally requested by 'originalUser', in group 'originalGroup'.")
+	assert.Contains(t, output, "dev.galasa.example/dev.galasa.example.MyTest")
+}
+
+func TestExecuteRerunUsesGroupPassedIn(t *testing.T) {
+	// Given...
+	mockLauncher := launcher.NewMockLauncher()
+	console := utils.NewMockConsole()
+	submitter := newRerunTestSubmitter(t, mockLauncher, console)
+
+	params := &utils.RunsSubmitCmdValues{
+		GroupName: "newGroup",
+	}
+
+	// When...
+	err := submitter.ExecuteRerun(newRerunDetailsForTest(), params)
+
+	// Then...
+	assert.Nil(t, err)
+
+	launches := mockLauncher.GetRecordedLaunchRecords()
+	assert.Equal(t, 1, len(launches))
+	if len(launches) > 0 {
+		assert.Equal(t, "newGroup", launches[0].GroupName)
+	}
+}
@@ -0,0 +1,327 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/images"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+)
+
+const (
+	HEADER_SCREEN_DIFF_RESULT = "result"
+	HEADER_SCREEN_DIFF_IMAGE  = "diff-image"
+
+	SCREEN_DIFF_RESULT_SAME      = "same"
+	SCREEN_DIFF_RESULT_DIFFERENT = "different"
+	SCREEN_DIFF_RESULT_ONLY_IN   = "only in %s"
+
+	TERMINALS_DIFF_FIRST_DIVERGENT_SCREEN = "First divergent screen:%s sequence %d"
+	TERMINALS_DIFF_FIRST_CHANGED_CELL     = " at row %d column %d"
+	TERMINALS_DIFF_SUMMARY                = "Screens compared:%d same:%d different:%d only in %s:%d only in %s:%d\n"
+)
+
+// ScreenComparison is how one 3270 terminal screen differs between two runs.
+// Screens are paired up by their terminal ID and sequence number. A run which doesn't have the screen has no image for it.
+type ScreenComparison struct {
+	TerminalId string
+	Sequence   int
+	ImageA     *images.TerminalImage
+	ImageB     *images.TerminalImage
+	// The text of screen A turned into the text of screen B, in unified diff format. Empty if the screens are the same.
+	TextDiff string
+	// Where the characters of the screens differ. Rows and columns count from 0.
+	ChangedCells []images.ScreenCell
+	// Where the image highlighting the differences was written, if there is one.
+	DiffImagePath string
+}
+
+func (comparison ScreenComparison) IsSame() bool {
+	return comparison.ImageA != nil && comparison.ImageB != nil && comparison.TextDiff == ""
+}
+
+// The screen file name, as used for the rendered images, without the extension. eg: term1-00002
+func (comparison ScreenComparison) getScreenName() string {
+	return fmt.Sprintf("%s-%05d", comparison.TerminalId, comparison.Sequence)
+}
+
+// GetTerminalsDiffFolderPath returns the folder the images highlighting how the screens of two runs differ are written to.
+func GetTerminalsDiffFolderPath(downloadFolderPath string, runNameA string, runNameB string) string {
+	return getRunDownloadFolderPath(downloadFolderPath, fmt.Sprintf("terminals-diff-%s-%s", runNameA, runNameB))
+}
+
+// TerminalsDiff - performs all the logic to implement the `galasactl runs terminals diff` command,
+// but in a unit-testable manner.
+// Runs which have been downloaded into the download folder are read from there. Other runs are fetched
+// from the Galasa service, so the comms client is only needed if one of the runs has not been downloaded.
+func TerminalsDiff(
+	runNames []string,
+	terminalId string,
+	isShowingHiddenFields bool,
+	downloadFolderPath string,
+	fileSystem spi.FileSystem,
+	renderer images.ImageRenderer,
+	timeService spi.TimeService,
+	console spi.Console,
+	commsClient api.APICommsClient,
+) error {
+	var err error
+	var terminalsA []images.Terminal
+	var terminalsB []images.Terminal
+	var output string
+
+	log.Printf("TerminalsDiff entered.")
+
+	if len(runNames) != 2 {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_TERMINALS_DIFF_NEEDS_TWO_RUNS, len(runNames))
+	}
+
+	for _, runName := range runNames {
+		if err == nil {
+			err = ValidateRunName(runName)
+		}
+	}
+
+	if err == nil {
+		terminalsA, err = loadRunTerminals(runNames[0], downloadFolderPath, fileSystem, timeService, commsClient)
+	}
+
+	if err == nil {
+		terminalsB, err = loadRunTerminals(runNames[1], downloadFolderPath, fileSystem, timeService, commsClient)
+	}
+
+	if err == nil {
+		comparisons := CompareTerminalScreens(terminalsA, terminalsB, runNames[0], runNames[1], terminalId, isShowingHiddenFields)
+
+		if len(comparisons) == 0 {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_TERMINALS_DIFF_NO_SCREENS, runNames[0], runNames[1])
+		} else if isEveryScreenTheSame(comparisons) {
+			output = fmt.Sprintf(galasaErrors.GALASA_INFO_TERMINALS_DIFF_SAME.Template, len(comparisons), runNames[1], runNames[0])
+		} else {
+			diffFolderPath := GetTerminalsDiffFolderPath(downloadFolderPath, runNames[0], runNames[1])
+			err = writeScreenDiffImages(comparisons, diffFolderPath, fileSystem, renderer)
+			if err == nil {
+				output = FormatScreenComparisons(comparisons, runNames[0], runNames[1])
+			}
+		}
+	}
+
+	if err == nil {
+		err = console.WriteString(output)
+	}
+
+	log.Printf("TerminalsDiff exiting. err is %v", err)
+	return err
+}
+
+// Reads the 3270 terminals of a run from its download folder if it has been downloaded, otherwise from the Galasa service.
+func loadRunTerminals(
+	runName string,
+	downloadFolderPath string,
+	fileSystem spi.FileSystem,
+	timeService spi.TimeService,
+	commsClient api.APICommsClient,
+) ([]images.Terminal, error) {
+	var terminals []images.Terminal
+
+	folderPath, isDownloaded, err := GetDownloadedRunFolder(fileSystem, downloadFolderPath, runName)
+	if err == nil {
+		if isDownloaded {
+			log.Printf("Reading the terminals of run %s from folder %s\n", runName, folderPath)
+			terminals, err = readDownloadedTerminals(fileSystem, folderPath)
+		} else {
+			log.Printf("Fetching the terminals of run %s from the Galasa service\n", runName)
+			terminals, err = getTerminalsFromRestApi(runName, galasaErrors.GALASA_ERROR_TERMINALS_DIFF_RUN_NOT_FOUND, timeService, commsClient)
+		}
+	}
+	return terminals, err
+}
+
+// CompareTerminalScreens pairs up the screens of two runs by terminal ID and sequence number, and works out
+// how each pair differs. The comparisons are sorted by terminal ID, then sequence number.
+// If a terminal ID is given, only the screens of that terminal are compared.
+// Hidden fields, such as passwords, are left blank when they are compared, unless they are to be shown.
+func CompareTerminalScreens(
+	terminalsA []images.Terminal,
+	terminalsB []images.Terminal,
+	runNameA string,
+	runNameB string,
+	terminalId string,
+	isShowingHiddenFields bool,
+) []ScreenComparison {
+	comparisonsByScreen := make(map[string]*ScreenComparison)
+
+	getComparison := func(terminal images.Terminal, terminalImage images.TerminalImage) *ScreenComparison {
+		key := terminal.Id + "/" + strconv.Itoa(terminalImage.Sequence)
+		comparison, isFound := comparisonsByScreen[key]
+		if !isFound {
+			comparison = &ScreenComparison{TerminalId: terminal.Id, Sequence: terminalImage.Sequence}
+			comparisonsByScreen[key] = comparison
+		}
+		return comparison
+	}
+
+	for _, terminal := range terminalsA {
+		if terminalId == "" || terminal.Id == terminalId {
+			for index := range terminal.Images {
+				getComparison(terminal, terminal.Images[index]).ImageA = &terminal.Images[index]
+			}
+		}
+	}
+
+	for _, terminal := range terminalsB {
+		if terminalId == "" || terminal.Id == terminalId {
+			for index := range terminal.Images {
+				getComparison(terminal, terminal.Images[index]).ImageB = &terminal.Images[index]
+			}
+		}
+	}
+
+	comparisons := make([]ScreenComparison, 0, len(comparisonsByScreen))
+	for _, comparison := range comparisonsByScreen {
+		if comparison.ImageA != nil && comparison.ImageB != nil {
+			comparison.TextDiff = diffScreenText(*comparison, runNameA, runNameB, isShowingHiddenFields)
+			comparison.ChangedCells = images.FindChangedScreenCells(*comparison.ImageA, *comparison.ImageB, isShowingHiddenFields)
+		}
+		comparisons = append(comparisons, *comparison)
+	}
+
+	sort.Slice(comparisons, func(i, j int) bool {
+		if comparisons[i].TerminalId != comparisons[j].TerminalId {
+			return comparisons[i].TerminalId < comparisons[j].TerminalId
+		}
+		return comparisons[i].Sequence < comparisons[j].Sequence
+	})
+	return comparisons
+}
+
+// The screens are compared as text, including their status lines, so a different key pressed on the same screen shows up too.
+func diffScreenText(comparison ScreenComparison, runNameA string, runNameB string, isShowingHiddenFields bool) string {
+	textOptions := images.TerminalTextOptions{IsShowingHiddenFields: isShowingHiddenFields}
+	linesA := strings.Split(strings.TrimSuffix(images.RenderTerminalImageAsText(*comparison.ImageA, textOptions), "\n"), "\n")
+	linesB := strings.Split(strings.TrimSuffix(images.RenderTerminalImageAsText(*comparison.ImageB, textOptions), "\n"), "\n")
+
+	screenName := comparison.getScreenName()
+	return utils.FormatUnifiedDiff(utils.DiffLines(linesA, linesB), runNameA+"/"+screenName, runNameB+"/"+screenName, utils.DEFAULT_DIFF_CONTEXT_LINES)
+}
+
+func isEveryScreenTheSame(comparisons []ScreenComparison) bool {
+	isSame := true
+	for _, comparison := range comparisons {
+		if !comparison.IsSame() {
+			isSame = false
+			break
+		}
+	}
+	return isSame
+}
+
+// Writes an image of each pair of screens which differ, side by side with the differences highlighted.
+// Screens which only one of the runs has get no image.
+func writeScreenDiffImages(
+	comparisons []ScreenComparison,
+	diffFolderPath string,
+	fileSystem spi.FileSystem,
+	renderer images.ImageRenderer,
+) error {
+	var err error
+	var writer images.ImageFileWriter
+
+	for index := range comparisons {
+		comparison := &comparisons[index]
+		if err == nil && comparison.ImageA != nil && comparison.ImageB != nil && !comparison.IsSame() {
+
+			if writer == nil {
+				err = fileSystem.MkdirAll(diffFolderPath)
+				writer = images.NewImageFileWriter(fileSystem, diffFolderPath, true)
+			}
+
+			pngFileName := comparison.getScreenName() + ".png"
+			if err == nil {
+				var pngBytes []byte
+				pngBytes, err = images.RenderScreenDiffToPng(renderer, *comparison.ImageA, *comparison.ImageB)
+				if err == nil {
+					err = writer.WriteImageFile(pngFileName, pngBytes)
+				}
+			}
+
+			diffImagePath := filepath.Join(diffFolderPath, pngFileName)
+			if err == nil {
+				comparison.DiffImagePath = diffImagePath
+			} else {
+				err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_TERMINALS_DIFF_IMAGE_FAILED, comparison.getScreenName(), diffImagePath, err.Error())
+			}
+		}
+	}
+	return err
+}
+
+// FormatScreenComparisons renders the comparisons as a table, followed by the text diff of each pair of screens which differ,
+// and a summary naming the first screen which differs.
+func FormatScreenComparisons(comparisons []ScreenComparison, runNameA string, runNameB string) string {
+	buff := strings.Builder{}
+
+	table := [][]string{{HEADER_TERMINAL_ID, HEADER_SCREEN_SEQUENCE, HEADER_SCREEN_DIFF_RESULT, HEADER_SCREEN_DIFF_IMAGE}}
+	var firstDivergentScreen *ScreenComparison
+	sameCount := 0
+	differentCount := 0
+	onlyInACount := 0
+	onlyInBCount := 0
+
+	for index, comparison := range comparisons {
+		var result string
+		if comparison.ImageB == nil {
+			result = fmt.Sprintf(SCREEN_DIFF_RESULT_ONLY_IN, runNameA)
+			onlyInACount++
+		} else if comparison.ImageA == nil {
+			result = fmt.Sprintf(SCREEN_DIFF_RESULT_ONLY_IN, runNameB)
+			onlyInBCount++
+		} else if comparison.IsSame() {
+			result = SCREEN_DIFF_RESULT_SAME
+			sameCount++
+		} else {
+			result = SCREEN_DIFF_RESULT_DIFFERENT
+			differentCount++
+		}
+
+		if firstDivergentScreen == nil && !comparison.IsSame() {
+			firstDivergentScreen = &comparisons[index]
+		}
+
+		table = append(table, []string{comparison.TerminalId, strconv.Itoa(comparison.Sequence), result, comparison.DiffImagePath})
+	}
+
+	columnLengths := utils.CalculateMaxLengthOfEachColumn(table)
+	utils.WriteFormattedTableToStringBuilder(table, &buff, columnLengths)
+
+	for _, comparison := range comparisons {
+		if comparison.TextDiff != "" {
+			buff.WriteString("\n")
+			buff.WriteString(comparison.TextDiff)
+		}
+	}
+
+	buff.WriteString("\n")
+	if firstDivergentScreen != nil {
+		buff.WriteString(fmt.Sprintf(TERMINALS_DIFF_FIRST_DIVERGENT_SCREEN, firstDivergentScreen.TerminalId, firstDivergentScreen.Sequence))
+		if len(firstDivergentScreen.ChangedCells) > 0 {
+			firstChangedCell := firstDivergentScreen.ChangedCells[0]
+			buff.WriteString(fmt.Sprintf(TERMINALS_DIFF_FIRST_CHANGED_CELL, firstChangedCell.Row, firstChangedCell.Column))
+		}
+		buff.WriteString("\n")
+	}
+	buff.WriteString(fmt.Sprintf(TERMINALS_DIFF_SUMMARY, len(comparisons), sameCount, differentCount, runNameA, onlyInACount, runNameB, onlyInBCount))
+	return buff.String()
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/embedded"
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/images"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTerminalWithScreens(terminalId string, screens ...images.Terminal) images.Terminal {
+	terminal := images.Terminal{Id: terminalId, Images: make([]images.TerminalImage, 0)}
+	for sequence, screen := range screens {
+		terminalImage := screen.Images[0]
+		terminalImage.Id = terminalId + "-" + string(rune('1'+sequence))
+		terminalImage.Sequence = sequence + 1
+		terminal.Images = append(terminal.Images, terminalImage)
+	}
+	return terminal
+}
+
+// The example terminal, with the text of one of the fields on its first screen changed.
+func createChangedExampleTerminalGz(t *testing.T) []byte {
+	gzContents, err := files.NewOSFileSystem().ReadBinaryFile(EXAMPLE_TERMINAL_GZ_FILE_PATH)
+	assert.Nil(t, err)
+
+	jsonContents, err := gunzip(gzContents)
+	assert.Nil(t, err)
+
+	terminal, err := images.ConvertJsonBytesToTerminal(jsonContents)
+	assert.Nil(t, err)
+	terminal.Images[0].Fields[0].Contents = []images.FieldContents{{Text: "CHANGED"}}
+
+	jsonContents, err = json.Marshal(terminal)
+	assert.Nil(t, err)
+
+	var buff bytes.Buffer
+	writer := gzip.NewWriter(&buff)
+	writer.Write(jsonContents)
+	writer.Close()
+	return buff.Bytes()
+}
+
+func TestCompareTerminalScreensPairsScreensByTerminalAndSequence(t *testing.T) {
+	// Given...
+	terminalsA := []images.Terminal{
+		createTerminalWithScreens("term1",
+			createTerminalWithRows("term1", 1, "LOGON"),
+			createTerminalWithRows("term1", 2, "READY"),
+			createTerminalWithRows("term1", 3, "LOGOFF"),
+		),
+	}
+	terminalsB := []images.Terminal{
+		createTerminalWithScreens("term2", createTerminalWithRows("term2", 1, "LOGON")),
+		createTerminalWithScreens("term1",
+			createTerminalWithRows("term1", 1, "LOGON"),
+			createTerminalWithRows("term1", 2, "NOT READY"),
+		),
+	}
+
+	// When...
+	comparisons := CompareTerminalScreens(terminalsA, terminalsB, "U1", "U2", "", false)
+
+	// Then...
+	assert.Len(t, comparisons, 4)
+
+	assert.Equal(t, "term1", comparisons[0].TerminalId)
+	assert.Equal(t, 1, comparisons[0].Sequence)
+	assert.True(t, comparisons[0].IsSame())
+
+	assert.Equal(t, 2, comparisons[1].Sequence)
+	assert.False(t, comparisons[1].IsSame())
+	assert.Equal(t, images.ScreenCell{Row: 0, Column: 0}, comparisons[1].ChangedCells[0])
+	assert.Contains(t, comparisons[1].TextDiff, "--- U1/term1-00002\n+++ U2/term1-00002\n")
+	assert.Contains(t, comparisons[1].TextDiff, "\n-READY               \n+NOT READY           \n")
+
+	assert.Equal(t, 3, comparisons[2].Sequence)
+	assert.NotNil(t, comparisons[2].ImageA)
+	assert.Nil(t, comparisons[2].ImageB)
+
+	assert.Equal(t, "term2", comparisons[3].TerminalId)
+	assert.Nil(t, comparisons[3].ImageA)
+	assert.NotNil(t, comparisons[3].ImageB)
+}
+
+func TestCompareTerminalScreensCanOnlyCompareOneTerminal(t *testing.T) {
+	terminalsA := []images.Terminal{createTerminalWithRows("term1", 1, "LOGON"), createTerminalWithRows("term2", 1, "LOGON")}
+	terminalsB := []images.Terminal{createTerminalWithRows("term1", 1, "LOGON"), createTerminalWithRows("term2", 1, "READY")}
+
+	comparisons := CompareTerminalScreens(terminalsA, terminalsB, "U1", "U2", "term2", false)
+
+	assert.Len(t, comparisons, 1)
+	assert.Equal(t, "term2", comparisons[0].TerminalId)
+	assert.False(t, comparisons[0].IsSame())
+}
+
+func TestFormatScreenComparisonsShowsATableDiffsAndTheFirstDivergentScreen(t *testing.T) {
+	// Given...
+	terminalsA := []images.Terminal{createTerminalWithScreens("term1",
+		createTerminalWithRows("term1", 1, "LOGON"),
+		createTerminalWithRows("term1", 2, "READY"),
+		createTerminalWithRows("term1", 3, "LOGOFF"),
+	)}
+	terminalsB := []images.Terminal{createTerminalWithScreens("term1",
+		createTerminalWithRows("term1", 1, "LOGON"),
+		createTerminalWithRows("term1", 2, "REDDY"),
+	)}
+	comparisons := CompareTerminalScreens(terminalsA, terminalsB, "U1", "U2", "", false)
+	comparisons[1].DiffImagePath = "terminals-diff-U1-U2/term1-00002.png"
+
+	// When...
+	output := FormatScreenComparisons(comparisons, "U1", "U2")
+
+	// Then...
+	assert.Equal(t, "terminal sequence result     diff-image\n"+
+		"term1    1        same       \n"+
+		"term1    2        different  terminals-diff-U1-U2/term1-00002.png\n"+
+		"term1    3        only in U1 \n"+
+		"\n"+
+		"--- U1/term1-00002\n"+
+		"+++ U2/term1-00002\n"+
+		"@@ -1,2 +1,2 @@\n"+
+		" term1-2 - 20x1 - Outbound - \n"+
+		"-READY               \n"+
+		"+REDDY               \n"+
+		"\n"+
+		"First divergent screen:term1 sequence 2 at row 0 column 2\n"+
+		"Screens compared:3 same:1 different:1 only in U1:1 only in U2:0\n", output)
+}
+
+func TestTerminalsDiffOfDownloadedRunsWritesTheDiffImages(t *testing.T) {
+	// Given...
+	gzContents, err := files.NewOSFileSystem().ReadBinaryFile(EXAMPLE_TERMINAL_GZ_FILE_PATH)
+	assert.Nil(t, err)
+
+	fs := files.NewMockFileSystem()
+	fs.MkdirAll("/downloads/U1")
+	fs.MkdirAll("/downloads/U2")
+	fs.WriteBinaryFile("/downloads/U1/zos3270/terminals/term1/term1-00001.gz", gzContents)
+	fs.WriteBinaryFile("/downloads/U2/zos3270/terminals/term1/term1-00001.gz", createChangedExampleTerminalGz(t))
+
+	console := utils.NewMockConsole()
+	renderer := images.NewImageRenderer(embedded.GetReadOnlyFileSystem())
+
+	// When...
+	err = TerminalsDiff([]string{"U1", "U2"}, "", false, "/downloads", fs, renderer, utils.NewMockTimeService(), console, nil)
+
+	// Then...
+	assert.Nil(t, err)
+	output := console.ReadText()
+	assert.Contains(t, output, "term1    1        different /downloads/terminals-diff-U1-U2/term1-00001.png\n")
+	assert.Contains(t, output, "term1    2        same      \n")
+	assert.Contains(t, output, "+++ U2/term1-00001\n")
+	assert.Contains(t, output, "First divergent screen:term1 sequence 1 at row ")
+	assert.Contains(t, output, "Screens compared:10 same:9 different:1 only in U1:0 only in U2:0\n")
+
+	isExists, _ := fs.Exists("/downloads/terminals-diff-U1-U2/term1-00001.png")
+	assert.True(t, isExists)
+	isExists, _ = fs.Exists("/downloads/terminals-diff-U1-U2/term1-00002.png")
+	assert.False(t, isExists)
+}
+
+func TestTerminalsDiffOfTheSameScreensSaysSo(t *testing.T) {
+	// Given...
+	gzContents, _ := files.NewOSFileSystem().ReadBinaryFile(EXAMPLE_TERMINAL_GZ_FILE_PATH)
+	fs := files.NewMockFileSystem()
+	fs.MkdirAll("/downloads/U1")
+	fs.MkdirAll("/downloads/U2")
+	fs.WriteBinaryFile("/downloads/U1/zos3270/terminals/term1/term1-00001.gz", gzContents)
+	fs.WriteBinaryFile("/downloads/U2/zos3270/terminals/term1/term1-00001.gz", gzContents)
+	console := utils.NewMockConsole()
+
+	// When...
+	err := TerminalsDiff([]string{"U1", "U2"}, "", false, "/downloads", fs, nil, utils.NewMockTimeService(), console, nil)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "GAL2523I: All 10 3270 terminal screens of run 'U2' are the same as those of run 'U1'.\n", console.ReadText())
+}
+
+func TestTerminalsDiffWithNoScreensToCompareReturnsError(t *testing.T) {
+	gzContents, _ := files.NewOSFileSystem().ReadBinaryFile(EXAMPLE_TERMINAL_GZ_FILE_PATH)
+	fs := files.NewMockFileSystem()
+	fs.MkdirAll("/downloads/U1")
+	fs.MkdirAll("/downloads/U2")
+	fs.WriteBinaryFile("/downloads/U1/zos3270/terminals/term1/term1-00001.gz", gzContents)
+	fs.WriteBinaryFile("/downloads/U2/zos3270/terminals/term1/term1-00001.gz", gzContents)
+
+	err := TerminalsDiff([]string{"U1", "U2"}, "term9", false, "/downloads", fs, nil, utils.NewMockTimeService(), utils.NewMockConsole(), nil)
+
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1287E")
+}
+
+func TestTerminalsDiffWithOneRunReturnsError(t *testing.T) {
+	err := TerminalsDiff([]string{"U1"}, "", false, ".", files.NewMockFileSystem(), nil, utils.NewMockTimeService(), utils.NewMockConsole(), nil)
+
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1285E")
+}
@@ -0,0 +1,362 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/images"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+)
+
+const (
+	HEADER_TERMINAL_ID       = "terminal"
+	HEADER_SCREEN_SEQUENCE   = "sequence"
+	HEADER_SCREEN_ROW        = "row"
+	HEADER_SCREEN_COLUMN     = "column"
+	HEADER_SCREEN_ROW_TEXT   = "text"
+	TERMINALS_SEARCH_SUMMARY = "Total matches:%d in %d of %d screens\n"
+)
+
+// TerminalSearchMatch is one place the searched-for text was found on a 3270 terminal screen.
+// Rows and columns count from 0, the same as in the terminal descriptions the screens come from.
+type TerminalSearchMatch struct {
+	TerminalId string
+	Sequence   int
+	Row        int
+	Column     int
+	// The whole row the text was found on, without trailing spaces.
+	RowText string
+}
+
+// GetDownloadedRunFolder returns the folder 'runs download' puts a run into, and whether
+// the 3270 terminals of the run have been downloaded there.
+func GetDownloadedRunFolder(fileSystem spi.FileSystem, downloadFolderPath string, runName string) (string, bool, error) {
+	var err error
+	isDownloaded := false
+	folderPath := getRunDownloadFolderPath(downloadFolderPath, runName)
+
+	isDownloaded, err = fileSystem.DirExists(folderPath)
+	if err == nil && isDownloaded {
+		var terminalFilePaths []string
+		terminalFilePaths, err = getDownloadedTerminalFilePaths(fileSystem, folderPath)
+		isDownloaded = len(terminalFilePaths) > 0
+	}
+	return folderPath, isDownloaded, err
+}
+
+// SearchDownloadedTerminals - performs all the logic to implement the `galasactl runs terminals search` command
+// for a run whose artifacts have already been downloaded, so works without any access to the Galasa service.
+func SearchDownloadedTerminals(
+	folderPath string,
+	runName string,
+	searchText string,
+	terminalId string,
+	isIgnoringCase bool,
+	isShowingHiddenFields bool,
+	fileSystem spi.FileSystem,
+	console spi.Console,
+) error {
+	var err error
+	var terminals []images.Terminal
+
+	log.Printf("SearchDownloadedTerminals entered. Folder: %s\n", folderPath)
+
+	err = validateTerminalsSearch(runName, searchText)
+
+	if err == nil {
+		terminals, err = readDownloadedTerminals(fileSystem, folderPath)
+	}
+
+	if err == nil {
+		err = writeTerminalsSearchResults(terminals, runName, searchText, terminalId, isIgnoringCase, isShowingHiddenFields, console)
+	}
+
+	log.Printf("SearchDownloadedTerminals exiting. err is %v", err)
+	return err
+}
+
+// SearchTerminalsFromRestApi - performs all the logic to implement the `galasactl runs terminals search` command
+// for a run which has not been downloaded, fetching its 3270 terminals from the Galasa service.
+func SearchTerminalsFromRestApi(
+	runName string,
+	searchText string,
+	terminalId string,
+	isIgnoringCase bool,
+	isShowingHiddenFields bool,
+	timeService spi.TimeService,
+	console spi.Console,
+	commsClient api.APICommsClient,
+) error {
+	var err error
+	var terminals []images.Terminal
+
+	log.Printf("SearchTerminalsFromRestApi entered.")
+
+	err = validateTerminalsSearch(runName, searchText)
+
+	if err == nil {
+		terminals, err = getTerminalsFromRestApi(runName, galasaErrors.GALASA_ERROR_TERMINALS_SEARCH_RUN_NOT_FOUND, timeService, commsClient)
+	}
+
+	if err == nil {
+		err = writeTerminalsSearchResults(terminals, runName, searchText, terminalId, isIgnoringCase, isShowingHiddenFields, console)
+	}
+
+	log.Printf("SearchTerminalsFromRestApi exiting. err is %v", err)
+	return err
+}
+
+func validateTerminalsSearch(runName string, searchText string) error {
+	err := ValidateRunName(runName)
+	if err == nil && strings.TrimSpace(searchText) == "" {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_TERMINALS_SEARCH_TEXT_BLANK)
+	}
+	return err
+}
+
+// Reads every 3270 terminal which 'runs download' put into a run's folder.
+func readDownloadedTerminals(fileSystem spi.FileSystem, folderPath string) ([]images.Terminal, error) {
+	var err error
+	var terminalFilePaths []string
+	terminals := make([]images.Terminal, 0)
+
+	terminalFilePaths, err = getDownloadedTerminalFilePaths(fileSystem, folderPath)
+
+	for _, terminalFilePath := range terminalFilePaths {
+		if err == nil {
+			var content []byte
+			content, err = files.NewGzipFile(fileSystem, terminalFilePath).ReadBytes()
+			if err != nil {
+				err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_TERMINALS_SEARCH_READ_FAILED, terminalFilePath, err.Error())
+			} else {
+				var terminal images.Terminal
+				terminal, err = images.ConvertJsonBytesToTerminal(content)
+				terminals = append(terminals, terminal)
+			}
+		}
+	}
+	return terminals, err
+}
+
+// Fetches every 3270 terminal of the latest run with the given name from the Galasa service.
+func getTerminalsFromRestApi(
+	runName string,
+	runNotFoundMessageType *galasaErrors.MessageType,
+	timeService spi.TimeService,
+	commsClient api.APICommsClient,
+) ([]images.Terminal, error) {
+	var err error
+	var run *galasaapi.Run
+	var artifacts []RunArtifact
+	terminals := make([]images.Terminal, 0)
+
+	run, err = getLatestRunByName(runName, runNotFoundMessageType, timeService, commsClient)
+
+	if err == nil {
+		artifacts, err = GetArtifactsFromRestApi(run.GetRunId(), commsClient)
+	}
+
+	for _, artifact := range artifacts {
+		_, isTerminalFile := getTerminalImageFolderPath(artifact.Path)
+		if isTerminalFile && err == nil {
+			var terminal images.Terminal
+			terminal, err = getTerminalFromRestApi(run.GetRunId(), artifact.Path, commsClient)
+			terminals = append(terminals, terminal)
+		}
+	}
+	return terminals, err
+}
+
+// The terminals are in zos3270/terminals/term1/term1-00001.gz files, in sorted order.
+func getDownloadedTerminalFilePaths(fileSystem spi.FileSystem, folderPath string) ([]string, error) {
+	terminalFilePaths := make([]string, 0)
+
+	filePaths, err := fileSystem.GetAllFilePaths(folderPath)
+	if err == nil {
+		for _, filePath := range filePaths {
+			_, isTerminalFile := getTerminalImageFolderPath(filepath.ToSlash(filePath))
+			if isTerminalFile {
+				terminalFilePaths = append(terminalFilePaths, filePath)
+			}
+		}
+		sort.Strings(terminalFilePaths)
+	}
+	return terminalFilePaths, err
+}
+
+func getTerminalFromRestApi(runId string, artifactPath string, commsClient api.APICommsClient) (images.Terminal, error) {
+	var terminal images.Terminal
+	var content []byte
+
+	artifactData, isEmpty, httpResponse, err := GetFileFromRestApi(runId, strings.TrimPrefix(artifactPath, "/"), commsClient)
+	if err == nil && !isEmpty {
+		content, err = io.ReadAll(artifactData)
+		if err == nil {
+			content, err = gunzip(content)
+		}
+
+		if err != nil {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_TERMINALS_SEARCH_READ_FAILED, artifactPath, err.Error())
+		} else {
+			terminal, err = images.ConvertJsonBytesToTerminal(content)
+		}
+	}
+
+	if httpResponse != nil {
+		closeResponseBody(httpResponse)
+	}
+	return terminal, err
+}
+
+func gunzip(content []byte) ([]byte, error) {
+	var unzippedContent []byte
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err == nil {
+		defer reader.Close()
+		unzippedContent, err = io.ReadAll(reader)
+	}
+	return unzippedContent, err
+}
+
+func writeTerminalsSearchResults(
+	terminals []images.Terminal,
+	runName string,
+	searchText string,
+	terminalId string,
+	isIgnoringCase bool,
+	isShowingHiddenFields bool,
+	console spi.Console,
+) error {
+	var output string
+
+	screenCount := 0
+	for _, terminal := range terminals {
+		if terminalId == "" || terminal.Id == terminalId {
+			screenCount += len(terminal.Images)
+		}
+	}
+
+	matches := SearchTerminalScreens(terminals, searchText, terminalId, isIgnoringCase, isShowingHiddenFields)
+	if len(matches) == 0 {
+		output = fmt.Sprintf(galasaErrors.GALASA_INFO_TERMINALS_SEARCH_NO_MATCHES.Template, searchText, screenCount, runName)
+	} else {
+		output = FormatTerminalSearchMatches(matches, screenCount)
+	}
+
+	return console.WriteString(output)
+}
+
+// SearchTerminalScreens finds every place some text appears on the screens of a set of terminals.
+// The text is searched for on each row of a screen on its own, so text which wraps onto the next row is not found.
+// If a terminal ID is given, only that terminal's screens are searched.
+// Hidden fields, such as passwords, are left blank, so they aren't searched, unless they are to be shown.
+func SearchTerminalScreens(
+	terminals []images.Terminal,
+	searchText string,
+	terminalId string,
+	isIgnoringCase bool,
+	isShowingHiddenFields bool,
+) []TerminalSearchMatch {
+	matches := make([]TerminalSearchMatch, 0)
+
+	searchRunes := []rune(searchText)
+	if isIgnoringCase {
+		searchRunes = toLowerRunes(searchRunes)
+	}
+
+	for _, terminal := range terminals {
+		if terminalId == "" || terminal.Id == terminalId {
+			for _, terminalImage := range terminal.Images {
+				for rowIndex, row := range images.GetScreenRows(terminalImage, isShowingHiddenFields) {
+					rowRunes := []rune(row)
+					if isIgnoringCase {
+						rowRunes = toLowerRunes(rowRunes)
+					}
+
+					for _, column := range findRunes(rowRunes, searchRunes) {
+						matches = append(matches, TerminalSearchMatch{
+							TerminalId: terminal.Id,
+							Sequence:   terminalImage.Sequence,
+							Row:        rowIndex,
+							Column:     column,
+							RowText:    strings.TrimRight(row, " "),
+						})
+					}
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].TerminalId != matches[j].TerminalId {
+			return matches[i].TerminalId < matches[j].TerminalId
+		}
+		return matches[i].Sequence < matches[j].Sequence
+	})
+	return matches
+}
+
+// Lower-cases each character on its own, so the position of each character stays the same.
+func toLowerRunes(runes []rune) []rune {
+	lowerRunes := make([]rune, len(runes))
+	for index, char := range runes {
+		lowerRunes[index] = unicode.ToLower(char)
+	}
+	return lowerRunes
+}
+
+// Returns the index of every place the wanted characters start, including places which overlap.
+func findRunes(runes []rune, wanted []rune) []int {
+	indexes := make([]int, 0)
+	if len(wanted) > 0 {
+		for start := 0; start+len(wanted) <= len(runes); start++ {
+			if string(runes[start:start+len(wanted)]) == string(wanted) {
+				indexes = append(indexes, start)
+			}
+		}
+	}
+	return indexes
+}
+
+// FormatTerminalSearchMatches renders the matches as a table, followed by a summary line.
+func FormatTerminalSearchMatches(matches []TerminalSearchMatch, screenCount int) string {
+	buff := strings.Builder{}
+
+	table := [][]string{{HEADER_TERMINAL_ID, HEADER_SCREEN_SEQUENCE, HEADER_SCREEN_ROW, HEADER_SCREEN_COLUMN, HEADER_SCREEN_ROW_TEXT}}
+	screensWithMatches := make(map[string]bool)
+	for _, match := range matches {
+		table = append(table, []string{
+			match.TerminalId,
+			strconv.Itoa(match.Sequence),
+			strconv.Itoa(match.Row),
+			strconv.Itoa(match.Column),
+			match.RowText,
+		})
+		screensWithMatches[fmt.Sprintf("%s-%d", match.TerminalId, match.Sequence)] = true
+	}
+
+	columnLengths := utils.CalculateMaxLengthOfEachColumn(table)
+	utils.WriteFormattedTableToStringBuilder(table, &buff, columnLengths)
+
+	buff.WriteString("\n")
+	buff.WriteString(fmt.Sprintf(TERMINALS_SEARCH_SUMMARY, len(matches), len(screensWithMatches), screenCount))
+	return buff.String()
+}
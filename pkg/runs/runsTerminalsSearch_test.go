@@ -0,0 +1,238 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/images"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// A real terminal, which has 10 screens.
+	EXAMPLE_TERMINAL_GZ_FILE_PATH = "../images/testdata/gzipExample/term1-00001.gz"
+)
+
+func createTerminalWithRows(terminalId string, sequence int, rows ...string) images.Terminal {
+	terminalImage := images.TerminalImage{
+		Sequence:  sequence,
+		ImageSize: images.TerminalSize{Rows: len(rows), Columns: 20},
+		Fields:    make([]images.TerminalField, 0),
+	}
+	for rowIndex, row := range rows {
+		terminalImage.Fields = append(terminalImage.Fields, images.TerminalField{
+			Row:          rowIndex,
+			FieldDisplay: true,
+			Contents:     []images.FieldContents{{Text: row}},
+		})
+	}
+	return images.Terminal{Id: terminalId, Images: []images.TerminalImage{terminalImage}}
+}
+
+func TestSearchTerminalScreensFindsEveryMatchInOrder(t *testing.T) {
+	// Given...
+	terminals := []images.Terminal{
+		createTerminalWithRows("term2", 1, "LOGON FAILED"),
+		createTerminalWithRows("term1", 2, "", "  INVALID PASSWORD"),
+		createTerminalWithRows("term1", 1, "PASSWORD: PASSWORD"),
+	}
+
+	// When...
+	matches := SearchTerminalScreens(terminals, "PASSWORD", "", false, false)
+
+	// Then...
+	assert.Equal(t, []TerminalSearchMatch{
+		{TerminalId: "term1", Sequence: 1, Row: 0, Column: 0, RowText: "PASSWORD: PASSWORD"},
+		{TerminalId: "term1", Sequence: 1, Row: 0, Column: 10, RowText: "PASSWORD: PASSWORD"},
+		{TerminalId: "term1", Sequence: 2, Row: 1, Column: 10, RowText: "  INVALID PASSWORD"},
+	}, matches)
+}
+
+func TestSearchTerminalScreensCanIgnoreCase(t *testing.T) {
+	terminals := []images.Terminal{createTerminalWithRows("term1", 1, "Invalid Password")}
+
+	assert.Empty(t, SearchTerminalScreens(terminals, "INVALID PASSWORD", "", false, false))
+	assert.Len(t, SearchTerminalScreens(terminals, "INVALID PASSWORD", "", true, false), 1)
+}
+
+func TestSearchTerminalScreensCanOnlySearchOneTerminal(t *testing.T) {
+	terminals := []images.Terminal{
+		createTerminalWithRows("term1", 1, "READY"),
+		createTerminalWithRows("term2", 1, "READY"),
+	}
+
+	matches := SearchTerminalScreens(terminals, "READY", "term2", false, false)
+
+	assert.Len(t, matches, 1)
+	assert.Equal(t, "term2", matches[0].TerminalId)
+}
+
+func TestSearchTerminalScreensOnlySearchesHiddenFieldsWhenTheyAreShown(t *testing.T) {
+	// Given...
+	terminals := []images.Terminal{createTerminalWithRows("term1", 1, "PASSWORD:")}
+	terminals[0].Images[0].Fields = append(terminals[0].Images[0].Fields, images.TerminalField{
+		Row:      0,
+		Column:   10,
+		Contents: []images.FieldContents{{Text: "SECRET"}},
+	})
+
+	// When...
+	maskedMatches := SearchTerminalScreens(terminals, "SECRET", "", false, false)
+	shownMatches := SearchTerminalScreens(terminals, "SECRET", "", false, true)
+
+	// Then...
+	assert.Empty(t, maskedMatches)
+	assert.Len(t, shownMatches, 1)
+}
+
+func TestFormatTerminalSearchMatchesShowsATableAndTotals(t *testing.T) {
+	matches := []TerminalSearchMatch{
+		{TerminalId: "term1", Sequence: 1, Row: 0, Column: 0, RowText: "PASSWORD: PASSWORD"},
+		{TerminalId: "term1", Sequence: 1, Row: 0, Column: 10, RowText: "PASSWORD: PASSWORD"},
+		{TerminalId: "term1", Sequence: 12, Row: 21, Column: 2, RowText: "  PASSWORD"},
+	}
+
+	output := FormatTerminalSearchMatches(matches, 20)
+
+	assert.Equal(t, "terminal sequence row column text\n"+
+		"term1    1        0   0      PASSWORD: PASSWORD\n"+
+		"term1    1        0   10     PASSWORD: PASSWORD\n"+
+		"term1    12       21  2        PASSWORD\n"+
+		"\n"+
+		"Total matches:3 in 2 of 20 screens\n", output)
+}
+
+func TestSearchDownloadedTerminalsReadsTheDownloadFolder(t *testing.T) {
+	// Given...
+	gzContents, err := files.NewOSFileSystem().ReadBinaryFile(EXAMPLE_TERMINAL_GZ_FILE_PATH)
+	assert.Nil(t, err)
+
+	fs := files.NewMockFileSystem()
+	fs.MkdirAll("/downloads/U123")
+	fs.WriteBinaryFile("/downloads/U123/zos3270/terminals/term1/term1-00001.gz", gzContents)
+	fs.WriteTextFile("/downloads/U123/run.log", "INVALID PASSWORD is not in a terminal")
+	console := utils.NewMockConsole()
+
+	folderPath, isDownloaded, err := GetDownloadedRunFolder(fs, "/downloads", "U123")
+	assert.Nil(t, err)
+	assert.True(t, isDownloaded)
+
+	// When...
+	err = SearchDownloadedTerminals(folderPath, "U123", "IBM BUSINESS ONLY", "", false, false, fs, console)
+
+	// Then...
+	assert.Nil(t, err)
+	output := console.ReadText()
+	// The row is shown as it is on the screen, so the column of the text can be seen.
+	assert.Contains(t, output, "term1    1        1   50                 IBM INTERNAL SYSTEMS MUST BE USED FOR IBM BUSINESS ONLY\n")
+	assert.Contains(t, output, "Total matches:4 in 4 of 10 screens\n")
+}
+
+func TestSearchDownloadedTerminalsWithNoMatchesSaysSo(t *testing.T) {
+	// Given...
+	gzContents, _ := files.NewOSFileSystem().ReadBinaryFile(EXAMPLE_TERMINAL_GZ_FILE_PATH)
+	fs := files.NewMockFileSystem()
+	fs.WriteBinaryFile("/downloads/U123/zos3270/terminals/term1/term1-00001.gz", gzContents)
+	console := utils.NewMockConsole()
+
+	// When...
+	err := SearchDownloadedTerminals("/downloads/U123", "U123", "INVALID PASSWORD", "", false, false, fs, console)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "GAL2522I: The text 'INVALID PASSWORD' was not found in any of the 10 3270 terminal screens of run 'U123'.\n", console.ReadText())
+}
+
+func TestGetDownloadedRunFolderWithoutTerminalsIsNotDownloaded(t *testing.T) {
+	fs := files.NewMockFileSystem()
+	fs.MkdirAll("/downloads/U123")
+	fs.WriteTextFile("/downloads/U123/run.log", "no terminals here")
+
+	folderPath, isDownloaded, err := GetDownloadedRunFolder(fs, "/downloads", "U123")
+
+	assert.Nil(t, err)
+	assert.False(t, isDownloaded)
+	assert.Equal(t, "/downloads/U123", folderPath)
+}
+
+func TestSearchDownloadedTerminalsWithBlankTextReturnsError(t *testing.T) {
+	fs := files.NewMockFileSystem()
+	console := utils.NewMockConsole()
+
+	err := SearchDownloadedTerminals("/downloads/U123", "U123", "  ", "", false, false, fs, console)
+
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1283E")
+}
+
+func TestSearchTerminalsFromRestApiFetchesTheTerminalArtifacts(t *testing.T) {
+	// Given...
+	runName := "U27"
+	runId := "xxx987xxx"
+	gzContents, err := files.NewOSFileSystem().ReadBinaryFile(EXAMPLE_TERMINAL_GZ_FILE_PATH)
+	assert.Nil(t, err)
+
+	getRunsInteraction := utils.NewHttpInteraction("/ras/runs", http.MethodGet)
+	getRunsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		WriteMockRasRunsResponse(t, writer, req, runName, []string{RUN_U27V2})
+	}
+
+	getArtifactsInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/artifacts", http.MethodGet)
+	getArtifactsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.Write([]byte(`[
+			{ "path": "/run.log", "contentType": "text/plain" },
+			{ "path": "/zos3270/terminals/term1/term1-00001.gz", "contentType": "application/x-gzip" }
+		]`))
+	}
+
+	getTerminalInteraction := utils.NewHttpInteraction("/ras/runs/"+runId+"/files/zos3270/terminals/term1/term1-00001.gz", http.MethodGet)
+	getTerminalInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		writer.Header().Set("Content-Disposition", "attachment")
+		writer.Write(gzContents)
+	}
+
+	server := utils.NewMockHttpServer(t, []utils.HttpInteraction{getRunsInteraction, getArtifactsInteraction, getTerminalInteraction})
+	defer server.Server.Close()
+
+	console := utils.NewMockConsole()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err = SearchTerminalsFromRestApi(runName, "hit enter", "term1", true, false, utils.NewMockTimeService(), console, commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+	output := console.ReadText()
+	assert.Contains(t, output, "term1    1        0   22     WINMVS2D VAMP         HIT ENTER FOR LATEST STATUS")
+	assert.Contains(t, output, "Total matches:4 in 4 of 10 screens\n")
+}
+
+func TestSearchTerminalsFromRestApiWhenRunNotFoundReturnsError(t *testing.T) {
+	// Given...
+	getRunsInteraction := utils.NewHttpInteraction("/ras/runs", http.MethodGet)
+	getRunsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		WriteMockRasRunsResponse(t, writer, req, "U27", []string{})
+	}
+
+	server := utils.NewMockHttpServer(t, []utils.HttpInteraction{getRunsInteraction})
+	defer server.Server.Close()
+
+	console := utils.NewMockConsole()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := SearchTerminalsFromRestApi("U27", "READY", "", false, false, utils.NewMockTimeService(), console, commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1282E")
+}
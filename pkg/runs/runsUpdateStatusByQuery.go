@@ -0,0 +1,228 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/runsformatter"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+)
+
+const (
+	HEADER_STATUS_UPDATE_OUTCOME = "outcome"
+	HEADER_STATUS_UPDATE_REASON  = "reason"
+
+	STATUS_UPDATE_OUTCOME_CANCELLED = "cancelled"
+	STATUS_UPDATE_OUTCOME_RESET     = "reset"
+	STATUS_UPDATE_OUTCOME_FAILED    = "failed"
+)
+
+// A change of status which can be made to many test runs at once.
+type runStatusUpdate struct {
+	// The verb used in messages, eg: "cancel"
+	verb string
+	// The outcome shown against each run which was updated, eg: "cancelled"
+	outcome string
+
+	status string
+	result string
+
+	updateFunc      func(runName string, runId string, request *galasaapi.UpdateRunStatusRequest, commsClient api.APICommsClient) error
+	failedErrorType *galasaErrors.MessageType
+}
+
+var (
+	cancelRunStatusUpdate = runStatusUpdate{
+		verb:            "cancel",
+		outcome:         STATUS_UPDATE_OUTCOME_CANCELLED,
+		status:          CANCEL_STATUS,
+		result:          CANCEL_RESULT,
+		updateFunc:      cancelRun,
+		failedErrorType: galasaErrors.GALASA_ERROR_BULK_CANCEL_FAILED,
+	}
+
+	resetRunStatusUpdate = runStatusUpdate{
+		verb:            "reset",
+		outcome:         STATUS_UPDATE_OUTCOME_RESET,
+		status:          RESET_STATUS,
+		result:          RESET_RESULT,
+		updateFunc:      resetRun,
+		failedErrorType: galasaErrors.GALASA_ERROR_BULK_RESET_FAILED,
+	}
+)
+
+// A single test run whose status is being updated, and how it went.
+type runStatusUpdateJob struct {
+	run galasaapi.Run
+	err error
+}
+
+// CancelRunsByQuery - performs all the logic to implement the `galasactl runs cancel`
+// command when test runs are selected by a query rather than by name, but in a unit-testable manner.
+func CancelRunsByQuery(
+	group string,
+	requestor string,
+	isActive bool,
+	isDryRun bool,
+	timeService spi.TimeService,
+	console spi.Console,
+	commsClient api.APICommsClient,
+) error {
+	log.Println("CancelRunsByQuery entered.")
+	err := updateRunsStatusByQuery(cancelRunStatusUpdate, group, requestor, isActive, isDryRun, timeService, console, commsClient)
+	log.Printf("CancelRunsByQuery exiting. err is %v\n", err)
+	return err
+}
+
+// ResetRunsByQuery - performs all the logic to implement the `galasactl runs reset`
+// command when test runs are selected by a query rather than by name, but in a unit-testable manner.
+func ResetRunsByQuery(
+	group string,
+	requestor string,
+	isActive bool,
+	isDryRun bool,
+	timeService spi.TimeService,
+	console spi.Console,
+	commsClient api.APICommsClient,
+) error {
+	log.Println("ResetRunsByQuery entered.")
+	err := updateRunsStatusByQuery(resetRunStatusUpdate, group, requestor, isActive, isDryRun, timeService, console, commsClient)
+	log.Printf("ResetRunsByQuery exiting. err is %v\n", err)
+	return err
+}
+
+// Only active runs can be cancelled or reset, so the query is always limited to active runs.
+// The isActive flag lets the user select every active run, without narrowing it down any further.
+//
+// A failure to update one test run does not stop the others being updated. A table showing
+// the outcome for each run is written out at the end.
+func updateRunsStatusByQuery(
+	statusUpdate runStatusUpdate,
+	group string,
+	requestor string,
+	isActive bool,
+	isDryRun bool,
+	timeService spi.TimeService,
+	console spi.Console,
+	commsClient api.APICommsClient,
+) error {
+	var err error
+	var runs []galasaapi.Run
+
+	if group == "" && requestor == "" && !isActive {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_NO_RUNS_SELECTED_FOR_STATUS_UPDATE, statusUpdate.verb)
+	}
+
+	if err == nil && group != "" {
+		group, err = validateGroupname(group)
+	}
+
+	if err == nil {
+		runName := ""
+		result := ""
+		fromAgeMins := 0
+		toAgeMins := 0
+		shouldGetActive := true
+		runs, err = GetRunsFromRestApi(runName, requestor, result, fromAgeMins, toAgeMins, shouldGetActive, timeService, commsClient, group)
+	}
+
+	if err == nil {
+		if len(runs) == 0 {
+			err = console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_BULK_STATUS_UPDATE_NO_RUNS.Template, statusUpdate.verb))
+		} else if isDryRun {
+			err = console.WriteString(formatRunsToUpdateStatus(runs))
+			if err == nil {
+				err = console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_BULK_STATUS_UPDATE_DRY_RUN.Template, len(runs), statusUpdate.outcome))
+			}
+		} else {
+			jobs := make([]*runStatusUpdateJob, 0, len(runs))
+			updateRunStatusRequest := createUpdateRunStatusRequest(statusUpdate.status, statusUpdate.result)
+
+			for _, run := range runs {
+				job := &runStatusUpdateJob{run: run}
+				runName := run.TestStructure.GetRunName()
+				job.err = statusUpdate.updateFunc(runName, run.GetRunId(), updateRunStatusRequest, commsClient)
+				if job.err != nil {
+					log.Printf("Failed to %s run '%s'. Reason: %v\n", statusUpdate.verb, runName, job.err)
+				}
+				jobs = append(jobs, job)
+			}
+
+			err = console.WriteString(formatRunStatusUpdateSummary(jobs, statusUpdate))
+			if err == nil {
+				failedCount := 0
+				for _, job := range jobs {
+					if job.err != nil {
+						failedCount++
+					}
+				}
+				if failedCount > 0 {
+					err = galasaErrors.NewGalasaError(statusUpdate.failedErrorType, failedCount, len(jobs))
+				}
+			}
+		}
+	}
+
+	return err
+}
+
+// formatRunsToUpdateStatus renders a table of the active runs which would be cancelled or reset.
+func formatRunsToUpdateStatus(runs []galasaapi.Run) string {
+	var table [][]string
+	buff := strings.Builder{}
+
+	headers := []string{runsformatter.HEADER_RUNNAME, runsformatter.HEADER_STATUS, runsformatter.HEADER_REQUESTOR, runsformatter.HEADER_GROUP, runsformatter.HEADER_TEST_NAME}
+	table = append(table, headers)
+
+	for _, run := range runs {
+		testStructure := run.GetTestStructure()
+		line := []string{testStructure.GetRunName(), testStructure.GetStatus(), testStructure.GetRequestor(), testStructure.GetGroup(), testStructure.GetTestName()}
+		table = append(table, line)
+	}
+
+	columnLengths := utils.CalculateMaxLengthOfEachColumn(table)
+	utils.WriteFormattedTableToStringBuilder(table, &buff, columnLengths)
+	buff.WriteString("\n")
+
+	return buff.String()
+}
+
+// formatRunStatusUpdateSummary renders a table of the outcome for each run, and why any failed.
+func formatRunStatusUpdateSummary(jobs []*runStatusUpdateJob, statusUpdate runStatusUpdate) string {
+	var table [][]string
+	buff := strings.Builder{}
+
+	headers := []string{runsformatter.HEADER_RUNNAME, HEADER_STATUS_UPDATE_OUTCOME, HEADER_STATUS_UPDATE_REASON}
+	table = append(table, headers)
+
+	failedCount := 0
+	for _, job := range jobs {
+		outcome := statusUpdate.outcome
+		reason := ""
+		if job.err != nil {
+			outcome = STATUS_UPDATE_OUTCOME_FAILED
+			reason = job.err.Error()
+			failedCount++
+		}
+		line := []string{job.run.TestStructure.GetRunName(), outcome, reason}
+		table = append(table, line)
+	}
+
+	columnLengths := utils.CalculateMaxLengthOfEachColumn(table)
+	utils.WriteFormattedTableToStringBuilder(table, &buff, columnLengths)
+
+	buff.WriteString("\n")
+	buff.WriteString(fmt.Sprintf("Total:%d Succeeded:%d Failed:%d\n", len(jobs), len(jobs)-failedCount, failedCount))
+
+	return buff.String()
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/api"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createMockActiveRunJson(runName string, runId string, status string) string {
+	run := createMockRun(runName, runId)
+	run.TestStructure.SetStatus(status)
+	run.TestStructure.SetRequestor("myuser")
+	run.TestStructure.SetGroup("badPortfolio")
+	run.TestStructure.SetTestName("dev.galasa.example.MyTest")
+	runBytes, _ := json.Marshal(run)
+	return string(runBytes)
+}
+
+func newGetActiveRunsInteraction(t *testing.T, runJsons []string) utils.HttpInteraction {
+	getRunsInteraction := utils.NewHttpInteraction("/ras/runs", http.MethodGet)
+	getRunsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		assert.Equal(t, "badPortfolio", query.Get("group"))
+		assert.Equal(t, "myuser", query.Get("requestor"))
+		assert.NotEmpty(t, query.Get("status"), "Only active runs should have been asked for")
+		WriteMockRasRunsResponse(t, writer, req, "", runJsons)
+	}
+	return getRunsInteraction
+}
+
+func newPutRunStatusInteraction(t *testing.T, runId string, expectedStatus string, isFailing bool) utils.HttpInteraction {
+	putStatusInteraction := utils.NewHttpInteraction("/ras/runs/"+runId, http.MethodPut)
+	putStatusInteraction.ValidateRequestFunc = func(t *testing.T, req *http.Request) {
+		var updateRequest galasaapi.UpdateRunStatusRequest
+		requestBody, _ := io.ReadAll(req.Body)
+		json.Unmarshal(requestBody, &updateRequest)
+		assert.Equal(t, expectedStatus, updateRequest.GetStatus())
+	}
+	putStatusInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		if isFailing {
+			writer.Header().Set("Content-Type", "application/json")
+			writer.WriteHeader(http.StatusBadRequest)
+			writer.Write([]byte(`{ "error_code": 5049, "error_message": "GAL5049E: The run has already completed." }`))
+		} else {
+			writer.WriteHeader(http.StatusAccepted)
+		}
+	}
+	return putStatusInteraction
+}
+
+func TestCancelRunsByQueryCancelsEveryMatchingRunAndShowsOutcomes(t *testing.T) {
+	// Given...
+	runJsons := []string{
+		createMockActiveRunJson("U1", "id-U1", "running"),
+		createMockActiveRunJson("U2", "id-U2", "queued"),
+	}
+	interactions := []utils.HttpInteraction{
+		newGetActiveRunsInteraction(t, runJsons),
+		newPutRunStatusInteraction(t, "id-U1", CANCEL_STATUS, false),
+		newPutRunStatusInteraction(t, "id-U2", CANCEL_STATUS, false),
+	}
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := CancelRunsByQuery("badPortfolio", "myuser", false, false, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "name outcome   reason\n"+
+		"U1   cancelled \n"+
+		"U2   cancelled \n"+
+		"\n"+
+		"Total:2 Succeeded:2 Failed:0\n", mockConsole.ReadText())
+}
+
+func TestResetRunsByQueryCarriesOnAfterAFailureAndReportsIt(t *testing.T) {
+	// Given...
+	runJsons := []string{
+		createMockActiveRunJson("U1", "id-U1", "running"),
+		createMockActiveRunJson("U2", "id-U2", "ending"),
+	}
+	interactions := []utils.HttpInteraction{
+		newGetActiveRunsInteraction(t, runJsons),
+		newPutRunStatusInteraction(t, "id-U1", RESET_STATUS, true),
+		newPutRunStatusInteraction(t, "id-U2", RESET_STATUS, false),
+	}
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := ResetRunsByQuery("badPortfolio", "myuser", false, false, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1266E: Failed to reset 1 out of 2 test runs.")
+
+	output := mockConsole.ReadText()
+	assert.Contains(t, output, "U1   failed  GAL1133E: Error resetting run 'U1'.")
+	assert.Contains(t, output, "GAL5049E: The run has already completed.")
+	assert.Contains(t, output, "U2   reset   \n")
+	assert.Contains(t, output, "Total:2 Succeeded:1 Failed:1\n")
+}
+
+func TestCancelRunsByQueryDryRunListsRunsWithoutCancellingThem(t *testing.T) {
+	// Given...
+	runJsons := []string{
+		createMockActiveRunJson("U1", "id-U1", "running"),
+		createMockActiveRunJson("U2", "id-U2", "queued"),
+	}
+
+	// The mock server fails the test if any run is cancelled.
+	interactions := []utils.HttpInteraction{newGetActiveRunsInteraction(t, runJsons)}
+
+	server := utils.NewMockHttpServer(t, interactions)
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := CancelRunsByQuery("badPortfolio", "myuser", false, true, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "name status  requestor group        test-name\n"+
+		"U1   running myuser    badPortfolio dev.galasa.example.MyTest\n"+
+		"U2   queued  myuser    badPortfolio dev.galasa.example.MyTest\n"+
+		"\n"+
+		"GAL2519I: 2 active test runs would be cancelled. Nothing was changed, because --dry-run was used.\n", mockConsole.ReadText())
+}
+
+func TestCancelRunsByQueryWithActiveOnlyAsksForAllActiveRuns(t *testing.T) {
+	// Given...
+	getRunsInteraction := utils.NewHttpInteraction("/ras/runs", http.MethodGet)
+	getRunsInteraction.WriteHttpResponseFunc = func(writer http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		assert.Empty(t, query.Get("group"))
+		assert.Empty(t, query.Get("requestor"))
+		assert.NotEmpty(t, query.Get("status"))
+		WriteMockRasRunsResponse(t, writer, req, "", []string{})
+	}
+
+	server := utils.NewMockHttpServer(t, []utils.HttpInteraction{getRunsInteraction})
+	defer server.Server.Close()
+
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient(server.Server.URL)
+
+	// When...
+	err := CancelRunsByQuery("", "", true, false, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "GAL2518I: No active test runs matched the query, so there is nothing to cancel.\n", mockConsole.ReadText())
+}
+
+func TestResetRunsByQueryWithNoSelectorReturnsError(t *testing.T) {
+	// Given...
+	mockConsole := utils.NewMockConsole()
+	mockTimeService := utils.NewMockTimeService()
+	commsClient := api.NewMockAPICommsClient("http://dummy.server")
+
+	// When...
+	err := ResetRunsByQuery("", "", false, false, mockTimeService, mockConsole, commsClient)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1264E: The --name, --group, --requestor or --active flag must be used to identify which test runs to reset.")
+}
@@ -0,0 +1,429 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/galasa-dev/cli/pkg/embedded"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/galasaapi"
+	"github.com/galasa-dev/cli/pkg/images"
+	"github.com/galasa-dev/cli/pkg/spi"
+)
+
+const (
+	// Files the RAS puts at the top of every test run's artifacts.
+	RUN_LOG_FILE_NAME   = "run.log"
+	STRUCTURE_FILE_NAME = "structure.json"
+
+	// The web page of the viewer, which is built into galasactl so it works offline.
+	RUN_VIEW_PAGE_PATH = "templates/runsview/index.html"
+
+	MAX_PORT_NUMBER = 65535
+)
+
+// What the viewer shows about the test run as a whole.
+type RunViewSummary struct {
+	RunName   string                   `json:"runName"`
+	RunId     string                   `json:"runId"`
+	Structure *galasaapi.TestStructure `json:"structure,omitempty"`
+	Artifacts []RunViewArtifact        `json:"artifacts"`
+}
+
+type RunViewArtifact struct {
+	Path string `json:"path"`
+	// Only known for artifacts recorded in the download manifest.
+	Size *int64 `json:"size,omitempty"`
+}
+
+type RunViewLog struct {
+	TotalLineCount int              `json:"totalLineCount"`
+	Lines          []RunViewLogLine `json:"lines"`
+}
+
+type RunViewLogLine struct {
+	Number int    `json:"number"`
+	Text   string `json:"text"`
+}
+
+// A terminal's screens, in the order they were seen.
+type RunViewTerminal struct {
+	Id       string                 `json:"id"`
+	Sequence int                    `json:"sequence"`
+	Images   []RunViewTerminalImage `json:"images"`
+}
+
+type RunViewTerminalImage struct {
+	Id       string `json:"id"`
+	Sequence int    `json:"sequence"`
+	Inbound  bool   `json:"inbound"`
+	Type     string `json:"type"`
+	Aid      string `json:"aid"`
+	// The rendered PNG of the screen, or blank if 'runs download' did not render one.
+	ImagePath string `json:"imagePath"`
+}
+
+// RunsView serves a web page on localhost which shows the test run downloaded into a folder.
+// It only reads the folder, so works without any access to the Galasa service.
+// This blocks until the server stops.
+func RunsView(
+	folderPath string,
+	runName string,
+	downloadFolderPath string,
+	port int,
+	fileSystem spi.FileSystem,
+	console spi.Console,
+) error {
+	var err error
+
+	if (folderPath == "") == (runName == "") {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_VIEW_NEEDS_FOLDER_OR_NAME)
+	} else if port < 0 || port > MAX_PORT_NUMBER {
+		err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_VIEW_INVALID_PORT, port)
+	}
+
+	if err == nil {
+		if folderPath == "" {
+			// 'runs download' puts each run into a folder named after the run.
+			folderPath = getRunDownloadFolderPath(downloadFolderPath, runName)
+		}
+
+		var isExisting bool
+		isExisting, err = fileSystem.DirExists(folderPath)
+		if err == nil && !isExisting {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_VIEW_FOLDER_NOT_FOUND, folderPath)
+		}
+	}
+
+	if err == nil {
+		handler := NewRunViewHandler(fileSystem, folderPath, embedded.GetReadOnlyFileSystem())
+
+		// Only listen on the loopback interface, as the artifacts are not meant to be shared.
+		var listener net.Listener
+		listener, err = net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+		if err != nil {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_VIEW_SERVER_FAILED, port, err.Error())
+		} else {
+			url := fmt.Sprintf("http://localhost:%d/", listener.Addr().(*net.TCPAddr).Port)
+			summary := handler.getSummary()
+			err = console.WriteString(fmt.Sprintf(galasaErrors.GALASA_INFO_VIEW_SERVING.Template, summary.RunName, folderPath, url))
+
+			if err == nil {
+				err = http.Serve(listener, handler)
+				if err != nil {
+					err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_VIEW_SERVER_FAILED, port, err.Error())
+				}
+			}
+		}
+	}
+
+	return err
+}
+
+// RunViewHandler answers the requests made by the viewer's web page.
+type RunViewHandler struct {
+	fileSystem     spi.FileSystem
+	folderPath     string
+	pageFileSystem embedded.ReadOnlyFileSystem
+	mux            *http.ServeMux
+}
+
+func NewRunViewHandler(fileSystem spi.FileSystem, folderPath string, pageFileSystem embedded.ReadOnlyFileSystem) *RunViewHandler {
+	handler := &RunViewHandler{
+		fileSystem: fileSystem,
+		// Cleaned up, so it matches the start of the file paths found inside it.
+		folderPath:     filepath.Clean(folderPath),
+		pageFileSystem: pageFileSystem,
+		mux:            http.NewServeMux(),
+	}
+
+	handler.mux.HandleFunc("/", handler.servePage)
+	handler.mux.HandleFunc("/api/run", handler.serveSummary)
+	handler.mux.HandleFunc("/api/runlog", handler.serveRunLog)
+	handler.mux.HandleFunc("/api/terminals", handler.serveTerminals)
+	handler.mux.HandleFunc("/files/", handler.serveArtifact)
+
+	return handler
+}
+
+func (handler *RunViewHandler) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(writer, "Only GET requests are supported.", http.StatusMethodNotAllowed)
+	} else {
+		handler.mux.ServeHTTP(writer, req)
+	}
+}
+
+func (handler *RunViewHandler) servePage(writer http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/" {
+		http.NotFound(writer, req)
+	} else {
+		page, err := handler.pageFileSystem.ReadFile(RUN_VIEW_PAGE_PATH)
+		if err != nil {
+			log.Printf("Could not read the viewer page. Reason: %v\n", err)
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+		} else {
+			writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+			writer.Write(page)
+		}
+	}
+}
+
+func (handler *RunViewHandler) serveSummary(writer http.ResponseWriter, req *http.Request) {
+	writeJsonResponse(writer, handler.getSummary())
+}
+
+func (handler *RunViewHandler) serveRunLog(writer http.ResponseWriter, req *http.Request) {
+	runLogPath := filepath.Join(handler.folderPath, RUN_LOG_FILE_NAME)
+
+	runLog, err := handler.fileSystem.ReadTextFile(runLogPath)
+	if err != nil {
+		log.Printf("Could not read the run log '%s'. Reason: %v\n", runLogPath, err)
+		http.Error(writer, "The run log was not downloaded.", http.StatusNotFound)
+	} else {
+		writeJsonResponse(writer, SearchRunLog(runLog, req.URL.Query().Get("search")))
+	}
+}
+
+func (handler *RunViewHandler) serveTerminals(writer http.ResponseWriter, req *http.Request) {
+	terminals, err := handler.getTerminals()
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+	} else {
+		writeJsonResponse(writer, terminals)
+	}
+}
+
+func (handler *RunViewHandler) serveArtifact(writer http.ResponseWriter, req *http.Request) {
+	artifactPath := path.Clean(strings.TrimPrefix(req.URL.Path, "/files/"))
+
+	// Nothing outside of the run's folder is served.
+	if artifactPath == ".." || strings.HasPrefix(artifactPath, "../") || path.IsAbs(artifactPath) {
+		http.NotFound(writer, req)
+	} else {
+		filePath := filepath.Join(handler.folderPath, filepath.FromSlash(artifactPath))
+
+		content, err := handler.fileSystem.ReadBinaryFile(filePath)
+		if err != nil {
+			log.Printf("Could not read artifact '%s'. Reason: %v\n", filePath, err)
+			http.NotFound(writer, req)
+		} else {
+			writer.Header().Set("Content-Type", getArtifactContentType(artifactPath, content))
+			writer.Write(content)
+		}
+	}
+}
+
+func getArtifactContentType(artifactPath string, content []byte) string {
+	contentType := mime.TypeByExtension(path.Ext(artifactPath))
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+	return contentType
+}
+
+func writeJsonResponse(writer http.ResponseWriter, value interface{}) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+	} else {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.Write(content)
+	}
+}
+
+// getSummary collects what is known about the run from its structure and the download manifest.
+// Either may be missing, so the run's name falls back to the name of its folder.
+func (handler *RunViewHandler) getSummary() RunViewSummary {
+	summary := RunViewSummary{
+		RunName:   filepath.Base(handler.folderPath),
+		Artifacts: make([]RunViewArtifact, 0),
+	}
+
+	manifest := readDownloadManifest(handler.fileSystem, filepath.Join(handler.folderPath, DOWNLOAD_MANIFEST_FILE_NAME))
+	if manifest != nil {
+		summary.RunName = manifest.RunName
+		summary.RunId = manifest.RunId
+	}
+
+	structure := handler.readStructure()
+	if structure != nil {
+		summary.Structure = structure
+		if structure.GetRunName() != "" {
+			summary.RunName = structure.GetRunName()
+		}
+	}
+
+	for _, artifactPath := range handler.getArtifactPaths() {
+		artifact := RunViewArtifact{Path: artifactPath}
+		entry, isFound := manifest.GetEntry("/" + artifactPath)
+		if isFound {
+			size := entry.Size
+			artifact.Size = &size
+		}
+		summary.Artifacts = append(summary.Artifacts, artifact)
+	}
+
+	return summary
+}
+
+func (handler *RunViewHandler) readStructure() *galasaapi.TestStructure {
+	var structure *galasaapi.TestStructure
+	structurePath := filepath.Join(handler.folderPath, STRUCTURE_FILE_NAME)
+
+	isExisting, err := handler.fileSystem.Exists(structurePath)
+	if err == nil && isExisting {
+		var content []byte
+		content, err = handler.fileSystem.ReadBinaryFile(structurePath)
+		if err == nil {
+			structure = galasaapi.NewTestStructure()
+			err = json.Unmarshal(content, structure)
+		}
+	}
+
+	if err != nil {
+		log.Printf("Could not read the run structure '%s', so it is not shown. Reason: %v\n", structurePath, err)
+		structure = nil
+	}
+	return structure
+}
+
+// Gets the paths of all the files in the run's folder, relative to the folder, using '/' separators.
+// The download manifest is left out, as it is not one of the run's artifacts.
+func (handler *RunViewHandler) getArtifactPaths() []string {
+	artifactPaths := make([]string, 0)
+
+	filePaths, err := handler.fileSystem.GetAllFilePaths(handler.folderPath)
+	if err != nil {
+		log.Printf("Could not list the files in folder '%s'. Reason: %v\n", handler.folderPath, err)
+	} else {
+		folderPrefix := strings.TrimSuffix(handler.folderPath, handler.fileSystem.GetFilePathSeparator()) + handler.fileSystem.GetFilePathSeparator()
+		for _, filePath := range filePaths {
+			artifactPath := filepath.ToSlash(strings.TrimPrefix(filePath, folderPrefix))
+			if artifactPath != DOWNLOAD_MANIFEST_FILE_NAME {
+				artifactPaths = append(artifactPaths, artifactPath)
+			}
+		}
+		sort.Strings(artifactPaths)
+	}
+	return artifactPaths
+}
+
+// getTerminals reads the 3270 terminal descriptions in the run's folder.
+// A terminal's screens can be spread over several files, so they are gathered up and put back into sequence order.
+func (handler *RunViewHandler) getTerminals() ([]RunViewTerminal, error) {
+	var err error
+	terminalsById := make(map[string]*RunViewTerminal)
+
+	for _, artifactPath := range handler.getArtifactPaths() {
+		imageFolderPath, isTerminalFile := getTerminalImageFolderPath(artifactPath)
+		if isTerminalFile && err == nil {
+			gzipFile := files.NewGzipFile(handler.fileSystem, filepath.Join(handler.folderPath, filepath.FromSlash(artifactPath)))
+
+			var content []byte
+			content, err = gzipFile.ReadBytes()
+			if err == nil {
+				var terminal images.Terminal
+				terminal, err = images.ConvertJsonBytesToTerminal(content)
+				if err == nil {
+					handler.addTerminalImages(terminalsById, terminal, imageFolderPath)
+				}
+			}
+		}
+	}
+
+	terminals := make([]RunViewTerminal, 0, len(terminalsById))
+	for _, terminal := range terminalsById {
+		sort.SliceStable(terminal.Images, func(i, j int) bool {
+			return terminal.Images[i].Sequence < terminal.Images[j].Sequence
+		})
+		terminals = append(terminals, *terminal)
+	}
+	sort.Slice(terminals, func(i, j int) bool {
+		if terminals[i].Sequence != terminals[j].Sequence {
+			return terminals[i].Sequence < terminals[j].Sequence
+		}
+		return terminals[i].Id < terminals[j].Id
+	})
+
+	return terminals, err
+}
+
+func (handler *RunViewHandler) addTerminalImages(terminalsById map[string]*RunViewTerminal, terminal images.Terminal, imageFolderPath string) {
+	viewedTerminal, isKnown := terminalsById[terminal.Id]
+	if !isKnown {
+		viewedTerminal = &RunViewTerminal{
+			Id:       terminal.Id,
+			Sequence: terminal.Sequence,
+			Images:   make([]RunViewTerminalImage, 0),
+		}
+		terminalsById[terminal.Id] = viewedTerminal
+	}
+
+	for _, terminalImage := range terminal.Images {
+		// Named the same way as the images rendered by 'runs download'.
+		imagePath := fmt.Sprintf("%s/%s-%05d.png", imageFolderPath, terminal.Id, terminalImage.Sequence)
+		isRendered, _ := handler.fileSystem.Exists(filepath.Join(handler.folderPath, filepath.FromSlash(imagePath)))
+		if !isRendered {
+			imagePath = ""
+		}
+
+		viewedTerminal.Images = append(viewedTerminal.Images, RunViewTerminalImage{
+			Id:        terminalImage.Id,
+			Sequence:  terminalImage.Sequence,
+			Inbound:   terminalImage.Inbound,
+			Type:      terminalImage.Type,
+			Aid:       terminalImage.Aid,
+			ImagePath: imagePath,
+		})
+	}
+}
+
+// The json descriptions of the screens appear in zos3270/terminals/term1/term1-00001.gz
+// and their rendered images in zos3270/images/term1/term1-00001.png
+// So returns the folder the images of a terminal file are in, and whether the path is a terminal file at all.
+func getTerminalImageFolderPath(artifactPath string) (string, bool) {
+	var imageFolderPath string
+	isTerminalFile := false
+
+	pathParts := strings.Split(artifactPath, "/")
+	if strings.HasSuffix(artifactPath, ".gz") && len(pathParts) >= 3 && pathParts[len(pathParts)-3] == "terminals" {
+		isTerminalFile = true
+		pathParts[len(pathParts)-3] = "images"
+		imageFolderPath = strings.Join(pathParts[:len(pathParts)-1], "/")
+	}
+	return imageFolderPath, isTerminalFile
+}
+
+// SearchRunLog finds the lines of a run log which contain some text, ignoring case.
+// All of the lines are found if the text is blank.
+func SearchRunLog(runLog string, searchText string) RunViewLog {
+	result := RunViewLog{Lines: make([]RunViewLogLine, 0)}
+
+	if runLog != "" {
+		lines := strings.Split(strings.TrimSuffix(runLog, "\n"), "\n")
+		result.TotalLineCount = len(lines)
+
+		lowerSearchText := strings.ToLower(searchText)
+		for index, line := range lines {
+			if strings.Contains(strings.ToLower(line), lowerSearchText) {
+				result.Lines = append(result.Lines, RunViewLogLine{Number: index + 1, Text: line})
+			}
+		}
+	}
+	return result
+}
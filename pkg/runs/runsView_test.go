@@ -0,0 +1,258 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package runs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/galasa-dev/cli/pkg/embedded"
+	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/spi"
+	"github.com/galasa-dev/cli/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeMockTerminalFile(t *testing.T, fs spi.FileSystem, filePath string, terminalJson string) {
+	gzipFile := files.NewGzipFile(fs, filePath)
+	err := gzipFile.WriteBytes([]byte(terminalJson))
+	assert.Nil(t, err)
+}
+
+func createMockRunViewHandler(fs spi.FileSystem) *RunViewHandler {
+	pageFileSystem := embedded.NewMockReadOnlyFileSystem()
+	pageFileSystem.WriteFile(RUN_VIEW_PAGE_PATH, "<html>the viewer</html>")
+	return NewRunViewHandler(fs, "/downloads/U123", pageFileSystem)
+}
+
+func getFromRunViewHandler(handler *RunViewHandler, url string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, url, nil))
+	return recorder
+}
+
+func TestRunViewHandlerServesTheViewerPage(t *testing.T) {
+	// Given...
+	handler := createMockRunViewHandler(files.NewMockFileSystem())
+
+	// When...
+	response := getFromRunViewHandler(handler, "/")
+
+	// Then...
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "<html>the viewer</html>", response.Body.String())
+	assert.Contains(t, response.Header().Get("Content-Type"), "text/html")
+}
+
+func TestRunViewPageIsBuiltIn(t *testing.T) {
+	page, err := embedded.GetReadOnlyFileSystem().ReadFile(RUN_VIEW_PAGE_PATH)
+	assert.Nil(t, err)
+	assert.Contains(t, string(page), "api/terminals")
+}
+
+func TestRunViewHandlerOnlyAllowsGetRequests(t *testing.T) {
+	// Given...
+	handler := createMockRunViewHandler(files.NewMockFileSystem())
+	recorder := httptest.NewRecorder()
+
+	// When...
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/run", nil))
+
+	// Then...
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestRunViewSummaryUsesStructureAndManifest(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	fs.WriteTextFile("/downloads/U123/structure.json", `{"runName":"U123","testName":"dev.galasa.MyTest","result":"Passed",`+
+		`"methods":[{"methodName":"testIt","result":"Passed"}]}`)
+	fs.WriteTextFile("/downloads/U123/run.log", "the log")
+	fs.WriteTextFile("/downloads/U123/framework/cps_record.properties", "a=b")
+	fs.WriteTextFile("/downloads/U123/"+DOWNLOAD_MANIFEST_FILE_NAME,
+		`{"runName":"U123","runId":"xxx543xxx","artifacts":[{"path":"/run.log","size":7,"sha256":"abc"}]}`)
+	handler := createMockRunViewHandler(fs)
+
+	// When...
+	response := getFromRunViewHandler(handler, "/api/run")
+
+	// Then...
+	assert.Equal(t, http.StatusOK, response.Code)
+	var summary RunViewSummary
+	err := json.Unmarshal(response.Body.Bytes(), &summary)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "U123", summary.RunName)
+	assert.Equal(t, "xxx543xxx", summary.RunId)
+	assert.Equal(t, "dev.galasa.MyTest", summary.Structure.GetTestName())
+	assert.Equal(t, "testIt", summary.Structure.Methods[0].GetMethodName())
+
+	assert.Len(t, summary.Artifacts, 3)
+	assert.Equal(t, "framework/cps_record.properties", summary.Artifacts[0].Path)
+	assert.Nil(t, summary.Artifacts[0].Size)
+	assert.Equal(t, "run.log", summary.Artifacts[1].Path)
+	assert.Equal(t, int64(7), *summary.Artifacts[1].Size)
+	assert.Equal(t, "structure.json", summary.Artifacts[2].Path)
+}
+
+func TestRunViewSummaryWithoutStructureOrManifestIsNamedAfterTheFolder(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	fs.WriteTextFile("/downloads/U123/run.log", "the log")
+	handler := createMockRunViewHandler(fs)
+
+	// When...
+	summary := handler.getSummary()
+
+	// Then...
+	assert.Equal(t, "U123", summary.RunName)
+	assert.Nil(t, summary.Structure)
+	assert.Len(t, summary.Artifacts, 1)
+}
+
+func TestRunViewRunLogCanBeSearched(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	fs.WriteTextFile("/downloads/U123/run.log", "line one\nan ERROR here\nline three\nanother error\n")
+	handler := createMockRunViewHandler(fs)
+
+	// When...
+	response := getFromRunViewHandler(handler, "/api/runlog?search=error")
+
+	// Then...
+	assert.Equal(t, http.StatusOK, response.Code)
+	var runLog RunViewLog
+	err := json.Unmarshal(response.Body.Bytes(), &runLog)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, runLog.TotalLineCount)
+	assert.Equal(t, []RunViewLogLine{{Number: 2, Text: "an ERROR here"}, {Number: 4, Text: "another error"}}, runLog.Lines)
+}
+
+func TestRunViewMissingRunLogIsNotFound(t *testing.T) {
+	// Given...
+	handler := createMockRunViewHandler(files.NewMockFileSystem())
+
+	// When...
+	response := getFromRunViewHandler(handler, "/api/runlog")
+
+	// Then...
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestSearchRunLogWithBlankTextFindsAllLines(t *testing.T) {
+	runLog := SearchRunLog("a\nb\n", "")
+	assert.Equal(t, 2, runLog.TotalLineCount)
+	assert.Len(t, runLog.Lines, 2)
+}
+
+func TestRunViewTerminalsAreInSequenceOrder(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	// The second file of term1 holds the earlier screens, and term2 was connected after term1.
+	writeMockTerminalFile(t, fs, "/downloads/U123/zos3270/terminals/term1/term1-00002.gz",
+		`{"id":"term1","sequence":1,"images":[{"id":"term1-3","sequence":3,"inbound":true}]}`)
+	writeMockTerminalFile(t, fs, "/downloads/U123/zos3270/terminals/term1/term1-00001.gz",
+		`{"id":"term1","sequence":1,"images":[{"id":"term1-2","sequence":2,"inbound":false,"aid":"ENTER"},{"id":"term1-1","sequence":1,"inbound":true}]}`)
+	writeMockTerminalFile(t, fs, "/downloads/U123/zos3270/terminals/term2/term2-00001.gz",
+		`{"id":"term2","sequence":2,"images":[{"id":"term2-1","sequence":1,"inbound":true}]}`)
+	fs.WriteBinaryFile("/downloads/U123/zos3270/images/term1/term1-00001.png", []byte("png"))
+	handler := createMockRunViewHandler(fs)
+
+	// When...
+	response := getFromRunViewHandler(handler, "/api/terminals")
+
+	// Then...
+	assert.Equal(t, http.StatusOK, response.Code)
+	var terminals []RunViewTerminal
+	err := json.Unmarshal(response.Body.Bytes(), &terminals)
+	assert.Nil(t, err)
+
+	assert.Len(t, terminals, 2)
+	assert.Equal(t, "term1", terminals[0].Id)
+	assert.Len(t, terminals[0].Images, 3)
+	assert.Equal(t, "term1-1", terminals[0].Images[0].Id)
+	assert.Equal(t, "zos3270/images/term1/term1-00001.png", terminals[0].Images[0].ImagePath)
+	assert.Equal(t, "term1-2", terminals[0].Images[1].Id)
+	assert.False(t, terminals[0].Images[1].Inbound)
+	assert.Equal(t, "ENTER", terminals[0].Images[1].Aid)
+	assert.Equal(t, "", terminals[0].Images[1].ImagePath)
+	assert.Equal(t, "term1-3", terminals[0].Images[2].Id)
+	assert.Equal(t, "term2", terminals[1].Id)
+}
+
+func TestRunViewServesArtifactFiles(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	fs.WriteTextFile("/downloads/U123/framework/cps_record.properties", "a=b")
+	handler := createMockRunViewHandler(fs)
+
+	// When...
+	response := getFromRunViewHandler(handler, "/files/framework/cps_record.properties")
+
+	// Then...
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "a=b", response.Body.String())
+	assert.Contains(t, response.Header().Get("Content-Type"), "text/plain")
+}
+
+func TestRunViewDoesNotServeFilesOutsideTheRunFolder(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	fs.WriteTextFile("/downloads/secret.txt", "secret")
+	handler := createMockRunViewHandler(fs)
+
+	// When...
+	// The request goes straight to the artifact handler, as the server would otherwise tidy up the path first.
+	response := httptest.NewRecorder()
+	handler.serveArtifact(response, httptest.NewRequest(http.MethodGet, "/files/../secret.txt", nil))
+
+	// Then...
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.NotContains(t, response.Body.String(), "secret")
+}
+
+func TestRunsViewWithFolderAndNameFails(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	console := utils.NewMockConsole()
+
+	// When...
+	err := RunsView("/downloads/U123", "U123", ".", 0, fs, console)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1275E")
+}
+
+func TestRunsViewWithNeitherFolderNorNameFails(t *testing.T) {
+	err := RunsView("", "", ".", 0, files.NewMockFileSystem(), utils.NewMockConsole())
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1275E")
+}
+
+func TestRunsViewWithBadPortFails(t *testing.T) {
+	err := RunsView("", "U123", ".", 70000, files.NewMockFileSystem(), utils.NewMockConsole())
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1277E")
+}
+
+func TestRunsViewOfRunWhichWasNotDownloadedFails(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	console := utils.NewMockConsole()
+
+	// When...
+	err := RunsView("", "U123", "/downloads", 0, fs, console)
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GAL1274E")
+	assert.Contains(t, err.Error(), "/downloads/U123")
+}
@@ -13,7 +13,7 @@ import (
 	"github.com/galasa-dev/cli/pkg/launcher"
 )
 
-func GetStreams(launcher launcher.Launcher) ([]string, error) {
+func GetStreams(launcher launcher.TestCatalogProvider) ([]string, error) {
 	log.Println("Getting streams list.")
 	streams, err := launcher.GetStreams()
 	return streams, err
@@ -352,6 +352,7 @@ func (submitter *Submitter) submitRun(
 					nextRun.SubmissionId = *submittedRun.SubmissionId
 				}
 				nextRun.Name = *submittedRun.Name
+				nextRun.Jvm = submitter.launcher.GetJvmConfiguration()
 
 				submittedRuns[nextRun.Name] = &nextRun
 
@@ -657,6 +658,27 @@ func (submitter *Submitter) validateAndCorrectParams(
 
 	var err error
 
+	submitter.correctPollingParams(params)
+
+	//  Dont mix portfolio and test selection on the same command
+	if params.PortfolioFileName != "" {
+		if AreSelectionFlagsProvided(submitSelectionFlags) {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_SUBMIT_MIX_FLAGS_AND_PORTFOLIO)
+		}
+	} else {
+		if !AreSelectionFlagsProvided(submitSelectionFlags) {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_SUBMIT_MISSING_ACTION_FLAGS)
+		}
+	}
+
+	if err == nil {
+		err = submitter.correctGroupAndFileParams(params)
+	}
+
+	return err
+}
+
+func (submitter *Submitter) correctPollingParams(params *utils.RunsSubmitCmdValues) {
 	// Guard against the poll time being less than 1 second
 	if params.PollIntervalSeconds < 1 {
 		log.Printf("poll value is invalid. Less than 1. Defaulting value to %v seconds.\n", DEFAULT_POLL_INTERVAL_SECONDS)
@@ -672,27 +694,18 @@ func (submitter *Submitter) validateAndCorrectParams(
 	if params.Throttle <= 0 {
 		params.Throttle = MAX_INT // set to maximum size of the int
 	}
+}
 
-	//  Dont mix portfolio and test selection on the same command
-	if params.PortfolioFileName != "" {
-		if AreSelectionFlagsProvided(submitSelectionFlags) {
-			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_SUBMIT_MIX_FLAGS_AND_PORTFOLIO)
-		}
-	} else {
-		if !AreSelectionFlagsProvided(submitSelectionFlags) {
-			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_SUBMIT_MISSING_ACTION_FLAGS)
-		}
-	}
-
-	if err == nil {
-		// generate a group name if required
-		if params.GroupName == "" {
-			params.GroupName = randomGenerator.NewString()
-		}
-		log.Printf("Using group name '%v' for test run submission\n", params.GroupName)
+func (submitter *Submitter) correctGroupAndFileParams(params *utils.RunsSubmitCmdValues) error {
+	var err error
 
-		_, err = submitter.checkIfGroupAlreadyInUse(params.GroupName)
+	// generate a group name if required
+	if params.GroupName == "" {
+		params.GroupName = randomGenerator.NewString()
 	}
+	log.Printf("Using group name '%v' for test run submission\n", params.GroupName)
+
+	_, err = submitter.checkIfGroupAlreadyInUse(params.GroupName)
 
 	if err == nil {
 		err = submitter.correctOverrideFilePathParameter(params)
@@ -65,35 +65,40 @@ func NewStreamBasedValidator() TestSelectionFlagValidator {
 func (*StreamBasedValidator) Validate(flags *utils.TestSelectionFlagValues) error {
 	var err error
 	if flags.Stream == "" {
-		if len(*flags.Packages) > 0 || len(*flags.Bundles) > 0 || len(*flags.Tests) > 0 || len(*flags.Classes) > 0 {
+		if isCatalogSelectionWanted(flags) || len(*flags.Classes) > 0 {
 			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_STREAM_FLAG_REQUIRED)
 		}
 	}
 	return err
 }
 
+// Local runs select their tests from the OBRs they are given, rather than from a stream.
 type ObrBasedValidator struct {
+	obrs []string
 }
 
-func NewObrBasedValidator() TestSelectionFlagValidator {
-	return new(ObrBasedValidator)
+func NewObrBasedValidator(obrs []string) TestSelectionFlagValidator {
+	validator := new(ObrBasedValidator)
+	validator.obrs = obrs
+	return validator
 }
 
-func (*ObrBasedValidator) Validate(flags *utils.TestSelectionFlagValues) error {
+func (validator *ObrBasedValidator) Validate(flags *utils.TestSelectionFlagValues) error {
 	var err error
+	if len(validator.obrs) < 1 {
+		if isCatalogSelectionWanted(flags) || len(*flags.Classes) > 0 {
+			err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_OBR_FLAG_REQUIRED)
+		}
+	}
 	return err
 }
 
 // Adds a ton of flags to a cobra command like 'runs prepare' or 'runs submit'.
 // The flags are consistently added as a result.
 func AddCommandFlags(command *cobra.Command, flags *utils.TestSelectionFlagValues) {
-	flags.Packages = command.Flags().StringSlice("package", make([]string, 0), "packages of which tests will be selected from, packages are selected if the name contains this string, or if --regex is specified then matches the regex")
-	flags.Bundles = command.Flags().StringSlice("bundle", make([]string, 0), "bundles of which tests will be selected from, bundles are selected if the name contains this string, or if --regex is specified then matches the regex")
-	flags.Tests = command.Flags().StringSlice("test", make([]string, 0), "test names which will be selected if the name contains this string, or if --regex is specified then matches the regex")
-	flags.Tags = command.Flags().StringSlice("tag", make([]string, 0), "tags of which tests will be selected from, tags are selected if the name contains this string, or if --regex is specified then matches the regex")
+	AddCatalogSelectionFlags(command, flags)
 
 	command.Flags().StringVarP(&flags.Stream, "stream", "s", "", "test stream to extract the tests from")
-	flags.RegexSelect = command.Flags().Bool("regex", false, "Test selection is performed by using regex")
 
 	AddClassFlag(command, flags, false, "test class names to run from the specified stream or portfolio."+
 		" The format of each entry is {osgi-bundle-name}/{java-class-name}. "+
@@ -103,6 +108,16 @@ func AddCommandFlags(command *cobra.Command, flags *utils.TestSelectionFlagValue
 	AddGherkinFlag(command, flags, false, "Gherkin feature file URL. Should start with 'file://'. ")
 }
 
+// Adds the flags which select tests from a test catalog.
+func AddCatalogSelectionFlags(command *cobra.Command, flags *utils.TestSelectionFlagValues) {
+	flags.Packages = command.Flags().StringSlice("package", make([]string, 0), "packages of which tests will be selected from, packages are selected if the name contains this string, or if --regex is specified then matches the regex")
+	flags.Bundles = command.Flags().StringSlice("bundle", make([]string, 0), "bundles of which tests will be selected from, bundles are selected if the name contains this string, or if --regex is specified then matches the regex")
+	flags.Tests = command.Flags().StringSlice("test", make([]string, 0), "test names which will be selected if the name contains this string, or if --regex is specified then matches the regex")
+	flags.Tags = command.Flags().StringSlice("tag", make([]string, 0), "tags of which tests will be selected from, tags are selected if the name contains this string, or if --regex is specified then matches the regex")
+
+	flags.RegexSelect = command.Flags().Bool("regex", false, "Test selection is performed by using regex")
+}
+
 func AddClassFlag(command *cobra.Command, flags *utils.TestSelectionFlagValues, isRequired bool, helpText string) {
 	flags.Classes = command.Flags().StringSlice("class", make([]string, 0), helpText)
 	if isRequired {
@@ -149,7 +164,12 @@ func AreSelectionFlagsProvided(flags *utils.TestSelectionFlagValues) bool {
 	return false
 }
 
-func SelectTests(launcherInstance launcher.Launcher, flags *utils.TestSelectionFlagValues) (TestSelection, error) {
+// Tests can only be selected by their package, bundle, name or tags using a test catalog.
+func isCatalogSelectionWanted(flags *utils.TestSelectionFlagValues) bool {
+	return len(*flags.Packages) > 0 || len(*flags.Bundles) > 0 || len(*flags.Tests) > 0 || len(*flags.Tags) > 0
+}
+
+func SelectTests(launcherInstance launcher.TestCatalogProvider, flags *utils.TestSelectionFlagValues) (TestSelection, error) {
 
 	var testSelection TestSelection
 	var err error
@@ -170,6 +190,12 @@ func SelectTests(launcherInstance launcher.Launcher, flags *utils.TestSelectionF
 				}
 			}
 		}
+	} else if isCatalogSelectionWanted(flags) {
+		// Local runs have no stream, so the test catalogs of all their OBRs are used.
+		testCatalog, err = launcherInstance.GetTestCatalog("")
+		if err == nil {
+			log.Println("Test catalog retrieved from the OBRs")
+		}
 	}
 
 	if err == nil {
@@ -330,7 +356,7 @@ func selectTestsByClass(
 			break
 		}
 
-		selectClass(testSelection, bundle, name, flags)
+		selectClass(testSelection, bundle, name, "", flags)
 	}
 	return err
 }
@@ -375,10 +401,13 @@ func selectClassByCatalog(testSelection *TestSelection, appendClass map[string]i
 	bundle := appendClass["bundle"].(string)
 	name := appendClass["name"].(string)
 
-	selectClass(testSelection, bundle, name, flags)
+	// Classes from the test catalog of an OBR are launched from that OBR.
+	obr, _ := appendClass[launcher.TEST_CATALOG_CLASS_OBR_KEY].(string)
+
+	selectClass(testSelection, bundle, name, obr, flags)
 }
 
-func selectClass(testSelection *TestSelection, bundle string, name string, flags *utils.TestSelectionFlagValues) {
+func selectClass(testSelection *TestSelection, bundle string, name string, obr string, flags *utils.TestSelectionFlagValues) {
 
 	for _, selectedClass := range testSelection.Classes {
 		if bundle == selectedClass.Bundle &&
@@ -394,6 +423,7 @@ func selectClass(testSelection *TestSelection, bundle string, name string, flags
 		Bundle: bundle,
 		Class:  name,
 		Stream: flags.Stream,
+		Obr:    obr,
 	}
 
 	testSelection.Classes = append(testSelection.Classes, newSelectedClass)
@@ -11,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/galasa-dev/cli/pkg/files"
 	"github.com/galasa-dev/cli/pkg/launcher"
 )
 
@@ -118,3 +119,57 @@ func TestSelectTestMultiplesFromGherkinUrlArrayReturnsTests(t *testing.T) {
 	assert.Equal(t, testSelection.Classes[1].GherkinUrl, "test.feature")
 	assert.Equal(t, testSelection.Classes[2].GherkinUrl, "excellent.feature")
 }
+
+func TestObrBasedValidatorNoObrButClassSpecifiedCausesError(t *testing.T) {
+	flags := NewTestSelectionFlagValues()
+	validator := NewObrBasedValidator([]string{})
+
+	*flags.Classes = []string{"mybundle/myclass"}
+
+	err := validator.Validate(flags)
+
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, err, "GAL1292E")
+}
+
+func TestObrBasedValidatorWithObrAndTagSpecifiedIsOk(t *testing.T) {
+	flags := NewTestSelectionFlagValues()
+	validator := NewObrBasedValidator([]string{"mvn:my.group/my.obr/0.0.1/obr"})
+
+	*flags.Tags = []string{"myTag"}
+
+	err := validator.Validate(flags)
+
+	assert.Nil(t, err)
+}
+
+func TestSelectTestsWithNoStreamSelectsFromTheTestCatalogsOfTheObrs(t *testing.T) {
+	// Given...
+	obr := "mvn:my.group/my.obr/0.0.1/obr"
+	fs := files.NewMockFileSystem()
+	fs.WriteTextFile("/localrepo/my/group/my.obr/0.0.1/my.obr-0.0.1-testcatalog.json", `{
+		"classes": {
+			"my.bundle/my.pkg.MyTest": {"name": "my.pkg.MyTest", "bundle": "my.bundle", "package": "my.pkg"},
+			"my.bundle/my.pkg.OtherTest": {"name": "my.pkg.OtherTest", "bundle": "my.bundle", "package": "my.pkg"}
+		},
+		"packages": {
+			"my.pkg": ["my.pkg.MyTest", "my.pkg.OtherTest"]
+		}
+	}`)
+	provider := launcher.NewObrTestCatalogProvider(fs, []string{obr}, "file:///localrepo", "")
+
+	flags := NewTestSelectionFlagValues()
+	*flags.Packages = []string{"my.pkg"}
+
+	// When...
+	testSelection, err := SelectTests(provider, flags)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Len(t, testSelection.Classes, 2)
+	for _, selectedClass := range testSelection.Classes {
+		assert.Equal(t, "my.bundle", selectedClass.Bundle)
+		assert.Equal(t, obr, selectedClass.Obr)
+		assert.Empty(t, selectedClass.Stream)
+	}
+}
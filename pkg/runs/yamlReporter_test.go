@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/galasa-dev/cli/pkg/files"
+	"github.com/galasa-dev/cli/pkg/launcher"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -95,3 +96,42 @@ func TestYamlReportWorks(t *testing.T) {
 
 	assert.EqualValues(t, expected3, actual3)
 }
+
+func TestYamlReportRecordsTheJvmConfigurationOfALocalRun(t *testing.T) {
+	// Given...
+	mockFileSystem := files.NewMockFileSystem()
+
+	finishedRuns := TestRun{
+		Name:      "L1",
+		Bundle:    "myBundle",
+		Class:     "com.myco.MyClass",
+		Obr:       "myObr",
+		Status:    "finished",
+		Result:    "Passed",
+		Overrides: make(map[string]string, 0),
+		Jvm: &launcher.JvmConfiguration{
+			Args:             []string{"-Xmx1g"},
+			SystemProperties: map[string]string{"my.prop": "myValue"},
+			Environment:      map[string]string{"MY_VAR": "myValue"},
+		},
+	}
+
+	finishedRunsMap := make(map[string]*TestRun, 1)
+	finishedRunsMap["L1"] = &finishedRuns
+
+	// When...
+	err := ReportYaml(mockFileSystem, "myReportYamlFilename", finishedRunsMap, nil)
+
+	// Then...
+	assert.Nil(t, err)
+	actualContents, _ := mockFileSystem.ReadTextFile("myReportYamlFilename")
+	assert.Contains(t, actualContents, `
+      jvm:
+        args:
+            - -Xmx1g
+        systemProperties:
+            my.prop: myValue
+        environment:
+            MY_VAR: myValue
+`)
+}
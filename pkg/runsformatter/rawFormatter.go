@@ -43,7 +43,7 @@ func (*RawFormatter) FormatRuns(runs []FormattableTest) (string, error) {
 
 		duration := getDuration(startTimeStringRaw, endTimeStringRaw)
 
-		runLog := run.ApiServerUrl + RAS_RUNS_URL + run.RunId + "/runlog"
+		runLog := run.getRunLog()
 
 		buff.WriteString(run.Name + "|" +
 			run.Status + "|" +
@@ -30,6 +30,7 @@ const (
 	RUN_RESULT_UNKNOWN             = "UNKNOWN"
 	RUN_RESULT_ACTIVE              = "Active"
 	RUN_RESULT_IGNORED             = "Ignored"
+	RUN_RESULT_HUNG                = "Hung"
 
 	HEADER_RUNNAME        = "name"
 	HEADER_STATUS         = "status"
@@ -66,6 +67,9 @@ type FormattableTest struct {
 	Group         string
 	Methods       []galasaapi.TestMethod
 	Lost          bool
+
+	// The path to the run log of a run which ran locally, which has no API server to fetch it from.
+	RunLogPath string
 }
 
 func NewFormattableTest() FormattableTest {
@@ -73,7 +77,16 @@ func NewFormattableTest() FormattableTest {
 	return this
 }
 
-var RESULT_LABELS = []string{RUN_RESULT_PASSED, RUN_RESULT_PASSED_WITH_DEFECTS, RUN_RESULT_FAILED, RUN_RESULT_FAILED_WITH_DEFECTS, RUN_RESULT_LOST, RUN_RESULT_ENVFAIL, RUN_RESULT_UNKNOWN, RUN_RESULT_ACTIVE, RUN_RESULT_IGNORED}
+// getRunLog returns where the run log of a test can be found.
+func (run FormattableTest) getRunLog() string {
+	runLog := run.RunLogPath
+	if runLog == "" {
+		runLog = run.ApiServerUrl + RAS_RUNS_URL + run.RunId + "/runlog"
+	}
+	return runLog
+}
+
+var RESULT_LABELS = []string{RUN_RESULT_PASSED, RUN_RESULT_PASSED_WITH_DEFECTS, RUN_RESULT_FAILED, RUN_RESULT_FAILED_WITH_DEFECTS, RUN_RESULT_LOST, RUN_RESULT_ENVFAIL, RUN_RESULT_UNKNOWN, RUN_RESULT_ACTIVE, RUN_RESULT_IGNORED, RUN_RESULT_HUNG}
 
 type RunsFormatter interface {
 	FormatRuns(testResultsData []FormattableTest) (string, error)
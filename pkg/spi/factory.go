@@ -22,6 +22,7 @@ type Factory interface {
 	GetFinalWordHandler() FinalWordHandler
 	GetStdOutConsole() Console
 	GetStdErrConsole() Console
+	GetStdIn() StdIn
 	GetTimeService() TimeService
 	GetAuthenticator(apiServerUrl string, galasaHome GalasaHome) Authenticator
 	GetByteReader() ByteReader
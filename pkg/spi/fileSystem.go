@@ -26,6 +26,9 @@ type FileSystem interface {
 	// Creates a file in the file system if it can.
 	Create(path string) (io.WriteCloser, error)
 
+	// Opens a file in the file system, so it can be read a bit at a time.
+	Open(path string) (io.ReadCloser, error)
+
 	// Returns the normal extension used for executable files.
 	// ie: The .exe suffix in windows, or "" in unix-like systems.
 	GetExecutableExtension() string
@@ -36,4 +39,10 @@ type FileSystem interface {
 
 	// Gets all the file paths recursively from a starting folder.
 	GetAllFilePaths(rootPath string) ([]string, error)
+
+	// Gets the names of the folders directly within a folder, without looking any deeper.
+	GetSubFolderNames(folderPath string) ([]string, error)
+
+	// Gets the size of a file in bytes.
+	GetFileSize(filePath string) (int64, error)
 }
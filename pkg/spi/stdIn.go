@@ -0,0 +1,14 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package spi
+
+// -------------------------------------------------
+// Somewhere the user can type answers to questions
+// the tool asks them.
+type StdIn interface {
+	// ReadLine reads the next line of text typed by the user, without the line ending.
+	ReadLine() (string, error)
+}
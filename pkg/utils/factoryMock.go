@@ -16,6 +16,7 @@ type MockFactory struct {
 	Env              spi.Environment
 	StdOutConsole    spi.Console
 	StdErrConsole    spi.Console
+	StdIn            spi.StdIn
 	TimeService      spi.TimeService
 	Authenticator    spi.Authenticator
 	ByteReader       spi.ByteReader
@@ -60,6 +61,13 @@ func (factory *MockFactory) GetStdErrConsole() spi.Console {
 	return factory.StdErrConsole
 }
 
+func (factory *MockFactory) GetStdIn() spi.StdIn {
+	if factory.StdIn == nil {
+		factory.StdIn = NewMockStdIn()
+	}
+	return factory.StdIn
+}
+
 func (factory *MockFactory) GetTimeService() spi.TimeService {
 	if factory.TimeService == nil {
 		factory.TimeService = NewMockTimeService()
@@ -7,6 +7,7 @@ package utils
 
 import (
 	"github.com/galasa-dev/cli/pkg/embedded"
+	galasaErrors "github.com/galasa-dev/cli/pkg/errors"
 	"github.com/galasa-dev/cli/pkg/spi"
 )
 
@@ -212,3 +213,91 @@ func GetGalasaBootJarPath(fs spi.FileSystem, home spi.GalasaHome) (string, error
 
 	return galasaBootJarPath, err
 }
+
+// InstallJacocoJars unpacks the JaCoCo agent and command-line jars into the lib folder of the Galasa home folder,
+// next to the boot jar, so the code coverage of local test runs can be collected and reported on.
+// Jars which are already unpacked are left alone.
+func InstallJacocoJars(home spi.GalasaHome, fileSystem spi.FileSystem, embeddedFileSystem embedded.ReadOnlyFileSystem) error {
+	var err error
+	var jacocoVersion string
+
+	fileGenerator := NewFileGenerator(fileSystem, embeddedFileSystem)
+
+	var libFolderPath string
+	libFolderPath, err = getGalasaLibFolderPath(fileSystem, home)
+	if err == nil {
+		err = fileGenerator.CreateFolder(libFolderPath)
+	}
+
+	if err == nil {
+		jacocoVersion, err = embedded.GetJacocoVersion()
+	}
+
+	if err == nil {
+		for _, jarName := range []string{getJacocoAgentJarName(jacocoVersion), getJacocoCliJarName(jacocoVersion)} {
+			installedJar := GeneratedFileDef{
+				FileType:                 "jar",
+				TargetFilePath:           libFolderPath + fileSystem.GetFilePathSeparator() + jarName,
+				EmbeddedTemplateFilePath: "templates/galasahome/lib/" + jarName,
+				TemplateParameters:       nil,
+			}
+
+			err = fileGenerator.CreateFile(
+				installedJar,
+				false, // don't force overwrite
+				false) // don't error if it already exists.
+
+			if err != nil {
+				err = galasaErrors.NewGalasaError(galasaErrors.GALASA_ERROR_JACOCO_JAR_NOT_INSTALLED, jarName, err.Error())
+				break
+			}
+		}
+	}
+	return err
+}
+
+// GetJacocoAgentJarPath gets where the JaCoCo agent jar is unpacked to in the Galasa home folder.
+func GetJacocoAgentJarPath(fs spi.FileSystem, home spi.GalasaHome) (string, error) {
+	jacocoVersion, err := embedded.GetJacocoVersion()
+	var jarPath string
+	if err == nil {
+		jarPath, err = getGalasaLibFilePath(fs, home, getJacocoAgentJarName(jacocoVersion))
+	}
+	return jarPath, err
+}
+
+// GetJacocoCliJarPath gets where the JaCoCo command-line jar is unpacked to in the Galasa home folder.
+func GetJacocoCliJarPath(fs spi.FileSystem, home spi.GalasaHome) (string, error) {
+	jacocoVersion, err := embedded.GetJacocoVersion()
+	var jarPath string
+	if err == nil {
+		jarPath, err = getGalasaLibFilePath(fs, home, getJacocoCliJarName(jacocoVersion))
+	}
+	return jarPath, err
+}
+
+func getJacocoAgentJarName(jacocoVersion string) string {
+	return "org.jacoco.agent-" + jacocoVersion + "-runtime.jar"
+}
+
+func getJacocoCliJarName(jacocoVersion string) string {
+	return "org.jacoco.cli-" + jacocoVersion + "-nodeps.jar"
+}
+
+// Gets the path of a file in the lib folder of the Galasa home folder, for the level of Galasa being used.
+func getGalasaLibFilePath(fs spi.FileSystem, home spi.GalasaHome, fileName string) (string, error) {
+	libFolderPath, err := getGalasaLibFolderPath(fs, home)
+	return libFolderPath + fs.GetFilePathSeparator() + fileName, err
+}
+
+func getGalasaLibFolderPath(fs spi.FileSystem, home spi.GalasaHome) (string, error) {
+	galasaVersion, err := embedded.GetGalasaVersion()
+	var folderPath string
+	if err == nil {
+		separator := fs.GetFilePathSeparator()
+		folderPath = home.GetNativeFolderPath() +
+			separator + "lib" +
+			separator + galasaVersion
+	}
+	return folderPath, err
+}
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"log"
+	"path/filepath"
 
 	"github.com/galasa-dev/cli/pkg/embedded"
 	"github.com/galasa-dev/cli/pkg/files"
@@ -93,3 +94,45 @@ func TestCanGetGalasaBootJarPath(t *testing.T) {
 	assert.True(t, strings.HasSuffix(path, ".jar"))
 	assert.Contains(t, path, "galasa-boot-")
 }
+
+func TestInstallJacocoJarsUnpacksThemNextToTheBootJar(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	galasaHome, _ := NewGalasaHome(fs, NewMockEnv(), "")
+	jacocoVersion, _ := embedded.GetJacocoVersion()
+	embeddedFileSystem := embedded.NewMockReadOnlyFileSystem()
+	embeddedFileSystem.WriteFile("templates/galasahome/lib/org.jacoco.agent-"+jacocoVersion+"-runtime.jar", "agent jar")
+	embeddedFileSystem.WriteFile("templates/galasahome/lib/org.jacoco.cli-"+jacocoVersion+"-nodeps.jar", "cli jar")
+
+	// When...
+	err := InstallJacocoJars(galasaHome, fs, embeddedFileSystem)
+
+	// Then...
+	assert.Nil(t, err)
+
+	agentJarPath, _ := GetJacocoAgentJarPath(fs, galasaHome)
+	bootJarPath, _ := GetGalasaBootJarPath(fs, galasaHome)
+	assert.Equal(t, filepath.Dir(bootJarPath), filepath.Dir(agentJarPath))
+	agentJar, _ := fs.ReadTextFile(agentJarPath)
+	assert.Equal(t, "agent jar", agentJar)
+
+	cliJarPath, _ := GetJacocoCliJarPath(fs, galasaHome)
+	cliJar, _ := fs.ReadTextFile(cliJarPath)
+	assert.Equal(t, "cli jar", cliJar)
+}
+
+func TestInstallJacocoJarsLeavesJarsWhichAreAlreadyUnpacked(t *testing.T) {
+	// Given...
+	fs := files.NewMockFileSystem()
+	galasaHome, _ := NewGalasaHome(fs, NewMockEnv(), "")
+	agentJarPath, _ := GetJacocoAgentJarPath(fs, galasaHome)
+	fs.WriteTextFile(agentJarPath, "unpacked before")
+
+	// When...
+	err := InstallJacocoJars(galasaHome, fs, embedded.NewMockReadOnlyFileSystem())
+
+	// Then...
+	assert.Nil(t, err)
+	agentJar, _ := fs.ReadTextFile(agentJarPath)
+	assert.Equal(t, "unpacked before", agentJar)
+}
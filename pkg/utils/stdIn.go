@@ -0,0 +1,60 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package utils
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// -------------------------------------------------
+// A real implementation which reads lines typed into stdin.
+type RealStdIn struct {
+	reader *bufio.Reader
+}
+
+func NewRealStdIn() *RealStdIn {
+	stdIn := new(RealStdIn)
+	stdIn.reader = bufio.NewReader(os.Stdin)
+	return stdIn
+}
+
+func (stdIn *RealStdIn) ReadLine() (string, error) {
+	line, err := stdIn.reader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		// The last line doesn't need a line ending.
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// -------------------------------------------------
+// A mock implementation which replays lines set up by a unit test.
+// Once all the lines have been read, io.EOF is returned,
+// as it would be if stdin was not a terminal.
+type MockStdIn struct {
+	lines []string
+}
+
+func NewMockStdIn(lines ...string) *MockStdIn {
+	stdIn := new(MockStdIn)
+	stdIn.lines = lines
+	return stdIn
+}
+
+func (stdIn *MockStdIn) ReadLine() (string, error) {
+	var err error
+	var line string
+	if len(stdIn.lines) == 0 {
+		err = io.EOF
+	} else {
+		line = stdIn.lines[0]
+		stdIn.lines = stdIn.lines[1:]
+	}
+	return line, err
+}
@@ -0,0 +1,239 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+type DiffOperation int
+
+const (
+	DIFF_EQUAL DiffOperation = iota
+	DIFF_DELETE
+	DIFF_INSERT
+
+	// How many unchanged lines are shown around each change in a unified diff.
+	DEFAULT_DIFF_CONTEXT_LINES = 3
+)
+
+// One line of a diff. Deleted lines come from the first text, inserted lines from the second.
+type DiffLine struct {
+	Operation DiffOperation
+	Text      string
+}
+
+// DiffLines works out the shortest list of deletions and insertions which turn lines a into lines b,
+// using the linear space version of Myers' diff algorithm, so that long texts such as run logs can be compared
+// without keeping the state of every step of the search.
+func DiffLines(a []string, b []string) []DiffLine {
+	lines := make([]DiffLine, 0, len(a)+len(b))
+	return appendDiffLines(lines, a, b)
+}
+
+// Lines which are the same at the start and end of both texts are taken off, then what is left is split
+// either side of the middle snake, a run of equal lines half way along the shortest edit path, and each side
+// is diffed in turn.
+func appendDiffLines(lines []DiffLine, a []string, b []string) []DiffLine {
+	prefixLength := 0
+	for prefixLength < len(a) && prefixLength < len(b) && a[prefixLength] == b[prefixLength] {
+		prefixLength++
+	}
+	suffixLength := 0
+	for suffixLength < len(a)-prefixLength && suffixLength < len(b)-prefixLength &&
+		a[len(a)-1-suffixLength] == b[len(b)-1-suffixLength] {
+		suffixLength++
+	}
+
+	lines = appendEqualDiffLines(lines, a[:prefixLength])
+	middleA := a[prefixLength : len(a)-suffixLength]
+	middleB := b[prefixLength : len(b)-suffixLength]
+
+	if len(middleA) == 0 || len(middleB) == 0 {
+		for _, text := range middleA {
+			lines = append(lines, DiffLine{Operation: DIFF_DELETE, Text: text})
+		}
+		for _, text := range middleB {
+			lines = append(lines, DiffLine{Operation: DIFF_INSERT, Text: text})
+		}
+	} else {
+		x, y, u, v := findMiddleSnake(middleA, middleB)
+		lines = appendDiffLines(lines, middleA[:x], middleB[:y])
+		lines = appendEqualDiffLines(lines, middleA[x:u])
+		lines = appendDiffLines(lines, middleA[u:], middleB[v:])
+	}
+
+	return appendEqualDiffLines(lines, a[len(a)-suffixLength:])
+}
+
+func appendEqualDiffLines(lines []DiffLine, texts []string) []DiffLine {
+	for _, text := range texts {
+		lines = append(lines, DiffLine{Operation: DIFF_EQUAL, Text: text})
+	}
+	return lines
+}
+
+// Finds the middle snake of the shortest edit path which turns a into b, going forwards from the start
+// and backwards from the end at the same time until the two searches meet. The snake runs from line x of a
+// and line y of b up to, but not including, line u of a and line v of b.
+// Neither a nor b may be empty, and they must differ.
+func findMiddleSnake(a []string, b []string) (int, int, int, int) {
+	n := len(a)
+	m := len(b)
+	delta := n - m
+	isDeltaOdd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+
+	// Each holds the furthest x reached on each diagonal k, indexed by k+offset.
+	// The backward search measures x and y from the ends of the texts, so its diagonal k is diagonal delta-k going forwards.
+	forwardV := make([]int, 2*maxD+3)
+	backwardV := make([]int, 2*maxD+3)
+
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forwardV[offset+k-1] < forwardV[offset+k+1]) {
+				x = forwardV[offset+k+1]
+			} else {
+				x = forwardV[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forwardV[offset+k] = x
+
+			backwardK := delta - k
+			if isDeltaOdd && backwardK >= -(d-1) && backwardK <= d-1 && x+backwardV[offset+backwardK] >= n {
+				return startX, startY, x, y
+			}
+		}
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && backwardV[offset+k-1] < backwardV[offset+k+1]) {
+				x = backwardV[offset+k+1]
+			} else {
+				x = backwardV[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backwardV[offset+k] = x
+
+			forwardK := delta - k
+			if !isDeltaOdd && forwardK >= -d && forwardK <= d && x+forwardV[offset+forwardK] >= n {
+				return n - x, m - y, n - startX, m - startY
+			}
+		}
+	}
+
+	// The searches always meet before here, as the texts can always be turned into each other.
+	return 0, 0, 0, 0
+}
+
+// FormatUnifiedDiff renders a diff in the unified format used by 'diff -u' and git.
+// An empty string is returned if there are no differences.
+func FormatUnifiedDiff(lines []DiffLine, nameA string, nameB string, contextLineCount int) string {
+	buff := strings.Builder{}
+
+	hunks := findDiffHunks(lines, contextLineCount)
+	if len(hunks) > 0 {
+		buff.WriteString("--- " + nameA + "\n")
+		buff.WriteString("+++ " + nameB + "\n")
+
+		for _, hunk := range hunks {
+			writeDiffHunk(lines, hunk, &buff)
+		}
+	}
+	return buff.String()
+}
+
+// The range of diff lines, from start up to but not including end, which are shown together.
+type diffHunk struct {
+	start int
+	end   int
+}
+
+func findDiffHunks(lines []DiffLine, contextLineCount int) []diffHunk {
+	hunks := make([]diffHunk, 0)
+
+	for index, line := range lines {
+		if line.Operation != DIFF_EQUAL {
+			start := index - contextLineCount
+			if start < 0 {
+				start = 0
+			}
+			end := index + contextLineCount + 1
+			if end > len(lines) {
+				end = len(lines)
+			}
+
+			lastHunkIndex := len(hunks) - 1
+			if lastHunkIndex >= 0 && start <= hunks[lastHunkIndex].end {
+				// Close enough to the last change to be shown with it.
+				hunks[lastHunkIndex].end = end
+			} else {
+				hunks = append(hunks, diffHunk{start: start, end: end})
+			}
+		}
+	}
+	return hunks
+}
+
+func writeDiffHunk(lines []DiffLine, hunk diffHunk, buff *strings.Builder) {
+	// Work out the line numbers in each text where the hunk starts.
+	lineNumberA := 1
+	lineNumberB := 1
+	for _, line := range lines[:hunk.start] {
+		if line.Operation != DIFF_INSERT {
+			lineNumberA++
+		}
+		if line.Operation != DIFF_DELETE {
+			lineNumberB++
+		}
+	}
+
+	lineCountA := 0
+	lineCountB := 0
+	for _, line := range lines[hunk.start:hunk.end] {
+		if line.Operation != DIFF_INSERT {
+			lineCountA++
+		}
+		if line.Operation != DIFF_DELETE {
+			lineCountB++
+		}
+	}
+
+	// By convention, an empty range starts at the line before it.
+	if lineCountA == 0 {
+		lineNumberA--
+	}
+	if lineCountB == 0 {
+		lineNumberB--
+	}
+
+	buff.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", lineNumberA, lineCountA, lineNumberB, lineCountB))
+
+	for _, line := range lines[hunk.start:hunk.end] {
+		switch line.Operation {
+		case DIFF_DELETE:
+			buff.WriteString("-")
+		case DIFF_INSERT:
+			buff.WriteString("+")
+		default:
+			buff.WriteString(" ")
+		}
+		buff.WriteString(line.Text + "\n")
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package utils
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffLinesOfSameTextIsAllEqual(t *testing.T) {
+	lines := DiffLines([]string{"a", "b"}, []string{"a", "b"})
+	assert.Equal(t, []DiffLine{{DIFF_EQUAL, "a"}, {DIFF_EQUAL, "b"}}, lines)
+}
+
+func TestDiffLinesOfEmptyTextsIsEmpty(t *testing.T) {
+	lines := DiffLines([]string{}, []string{})
+	assert.Empty(t, lines)
+}
+
+func TestDiffLinesFindsInsertsAndDeletes(t *testing.T) {
+	lines := DiffLines([]string{"a", "b", "c", "d"}, []string{"a", "c", "d", "e"})
+	assert.Equal(t, []DiffLine{
+		{DIFF_EQUAL, "a"},
+		{DIFF_DELETE, "b"},
+		{DIFF_EQUAL, "c"},
+		{DIFF_EQUAL, "d"},
+		{DIFF_INSERT, "e"},
+	}, lines)
+}
+
+func TestDiffLinesOfChangedLineIsDeleteThenInsert(t *testing.T) {
+	lines := DiffLines([]string{"a", "b", "c"}, []string{"a", "x", "c"})
+	assert.Equal(t, []DiffLine{
+		{DIFF_EQUAL, "a"},
+		{DIFF_DELETE, "b"},
+		{DIFF_INSERT, "x"},
+		{DIFF_EQUAL, "c"},
+	}, lines)
+}
+
+func TestDiffLinesOfLongTextsWithManyChangesFindsEveryChange(t *testing.T) {
+	// Given...
+	a := make([]string, 20000)
+	b := make([]string, 20000)
+	for index := range a {
+		a[index] = "line " + strconv.Itoa(index)
+		b[index] = a[index]
+		if index%4 == 0 {
+			b[index] = "changed line " + strconv.Itoa(index)
+		}
+	}
+
+	// When...
+	lines := DiffLines(a, b)
+
+	// Then...
+	linesOfA := make([]string, 0, len(a))
+	linesOfB := make([]string, 0, len(b))
+	changeCount := 0
+	for _, line := range lines {
+		if line.Operation != DIFF_INSERT {
+			linesOfA = append(linesOfA, line.Text)
+		}
+		if line.Operation != DIFF_DELETE {
+			linesOfB = append(linesOfB, line.Text)
+		}
+		if line.Operation != DIFF_EQUAL {
+			changeCount++
+		}
+	}
+	assert.Equal(t, a, linesOfA)
+	assert.Equal(t, b, linesOfB)
+	assert.Equal(t, 10000, changeCount)
+}
+
+func TestFormatUnifiedDiffOfSameTextIsEmpty(t *testing.T) {
+	lines := DiffLines([]string{"a"}, []string{"a"})
+	assert.Equal(t, "", FormatUnifiedDiff(lines, "A", "B", DEFAULT_DIFF_CONTEXT_LINES))
+}
+
+func TestFormatUnifiedDiffShowsContextAroundChanges(t *testing.T) {
+	a := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}
+	b := []string{"1", "2", "3", "4", "five", "6", "7", "8", "9", "10", "11", "12", "13"}
+
+	diff := FormatUnifiedDiff(DiffLines(a, b), "A", "B", 1)
+
+	assert.Equal(t, "--- A\n"+
+		"+++ B\n"+
+		"@@ -4,3 +4,3 @@\n"+
+		" 4\n"+
+		"-5\n"+
+		"+five\n"+
+		" 6\n"+
+		"@@ -12,1 +12,2 @@\n"+
+		" 12\n"+
+		"+13\n", diff)
+}
+
+func TestFormatUnifiedDiffJoinsChangesWhichAreCloseTogether(t *testing.T) {
+	a := []string{"1", "2", "3", "4"}
+	b := []string{"one", "2", "3", "four"}
+
+	diff := FormatUnifiedDiff(DiffLines(a, b), "A", "B", 1)
+
+	assert.Equal(t, "--- A\n"+
+		"+++ B\n"+
+		"@@ -1,4 +1,4 @@\n"+
+		"-1\n"+
+		"+one\n"+
+		" 2\n"+
+		" 3\n"+
+		"-4\n"+
+		"+four\n", diff)
+}
+
+func TestFormatUnifiedDiffAgainstEmptyText(t *testing.T) {
+	diff := FormatUnifiedDiff(DiffLines([]string{}, []string{"a"}), "A", "B", DEFAULT_DIFF_CONTEXT_LINES)
+
+	assert.Equal(t, "--- A\n+++ B\n@@ -0,0 +1,1 @@\n+a\n", diff)
+}